@@ -0,0 +1,141 @@
+/**
+ * This file adds helper functions for WebAuthn passkeys.  The server sends
+ * (and receives) binary values as base64url strings, so these functions
+ * convert them before calling the browser's navigator.credentials API.
+ * Errors are thrown with a message that can be displayed to the user.
+ */
+
+// passkeySupported returns TRUE if this browser can use passkeys
+function passkeySupported() {
+	return (window.PublicKeyCredential !== undefined) && (navigator.credentials !== undefined)
+}
+
+// passkeySignIn asks the browser for a passkey, and sends it to the server to sign in.
+// The endpoint returns the request options on GET, and verifies the passkey on POST.
+async function passkeySignIn(endpoint) {
+
+	const options = await passkeyFetch(endpoint)
+
+	options.challenge = passkeyDecode(options.challenge)
+	options.allowCredentials.forEach(function(credential) {
+		credential.id = passkeyDecode(credential.id)
+	})
+
+	const credential = await passkeyCall(function() {
+		return navigator.credentials.get({publicKey: options})
+	})
+
+	await passkeyFetch(endpoint, {
+		id: credential.id,
+		rawId: passkeyEncode(credential.rawId),
+		type: credential.type,
+		response: {
+			clientDataJSON: passkeyEncode(credential.response.clientDataJSON),
+			authenticatorData: passkeyEncode(credential.response.authenticatorData),
+			signature: passkeyEncode(credential.response.signature),
+			userHandle: credential.response.userHandle ? passkeyEncode(credential.response.userHandle) : ""
+		}
+	})
+}
+
+// passkeyRegister asks the browser to create a new passkey, and sends it to the server to save.
+// The endpoint returns the creation options on GET, and verifies the new passkey on POST.
+async function passkeyRegister(endpoint, label) {
+
+	const options = await passkeyFetch(endpoint)
+
+	options.challenge = passkeyDecode(options.challenge)
+	options.user.id = passkeyDecode(options.user.id)
+	options.excludeCredentials.forEach(function(credential) {
+		credential.id = passkeyDecode(credential.id)
+	})
+
+	const credential = await passkeyCall(function() {
+		return navigator.credentials.create({publicKey: options})
+	})
+
+	await passkeyFetch(endpoint, {
+		label: label,
+		credential: {
+			id: credential.id,
+			rawId: passkeyEncode(credential.rawId),
+			type: credential.type,
+			response: {
+				clientDataJSON: passkeyEncode(credential.response.clientDataJSON),
+				attestationObject: passkeyEncode(credential.response.attestationObject),
+				transports: credential.response.getTransports ? credential.response.getTransports() : []
+			}
+		}
+	})
+}
+
+// passkeyCall runs a navigator.credentials function, and replaces browser errors with friendlier messages
+async function passkeyCall(fn) {
+
+	if (!passkeySupported()) {
+		throw new Error("This browser does not support passkeys.")
+	}
+
+	try {
+		const credential = await fn()
+
+		if (credential === null) {
+			throw new Error("No passkey was selected.")
+		}
+
+		return credential
+
+	} catch (error) {
+
+		if (error.name == "NotAllowedError" || error.name == "AbortError") {
+			throw new Error("The passkey request was cancelled.")
+		}
+
+		if (error.name == "InvalidStateError") {
+			throw new Error("This passkey has already been registered.")
+		}
+
+		throw error
+	}
+}
+
+// passkeyFetch GETs (or POSTs the body to) the endpoint, and returns the JSON response (if any)
+async function passkeyFetch(endpoint, body) {
+
+	var init = {credentials: "same-origin", headers: {"Accept": "application/json"}}
+
+	if (body !== undefined) {
+		init.method = "POST"
+		init.headers["Content-Type"] = "application/json"
+		init.body = JSON.stringify(body)
+	}
+
+	const response = await fetch(endpoint, init)
+
+	if (!response.ok) {
+		const message = await response.text()
+		throw new Error(message || "Passkey request failed.")
+	}
+
+	if (response.status == 204) {
+		return null
+	}
+
+	return response.json()
+}
+
+// passkeyEncode converts an ArrayBuffer into a base64url string
+function passkeyEncode(buffer) {
+	var binary = ""
+	new Uint8Array(buffer).forEach(function(byte) {
+		binary += String.fromCharCode(byte)
+	})
+	return btoa(binary).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "")
+}
+
+// passkeyDecode converts a base64url string into an ArrayBuffer
+function passkeyDecode(value) {
+	const base64 = value.replace(/-/g, "+").replace(/_/g, "/")
+	const binary = atob(base64 + "===".slice((base64.length + 3) % 4))
+	return Uint8Array.from(binary, function(char) { return char.charCodeAt(0) }).buffer
+}
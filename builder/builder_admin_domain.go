@@ -186,6 +186,11 @@ func (w Domain) Themes() []model.Theme {
 	return result
 }
 
+// TemplateProblems returns all Templates that had problems (like incomplete microformats) when they were loaded
+func (w Domain) TemplateProblems() []model.Template {
+	return w._factory.Template().ListProblems()
+}
+
 func (w Domain) Providers() []form.LookupCode {
 
 	providers := w._factory.Providers()
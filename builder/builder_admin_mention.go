@@ -0,0 +1,173 @@
+package builder
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Mention is a builder for the admin/mentions page, where
+// Domain Owners approve or reject incoming WebMentions.
+// It can only be accessed by a Domain Owner
+type Mention struct {
+	_mention *model.Mention
+	Common
+}
+
+// NewMention returns a fully initialized `Mention` builder.
+func NewMention(factory Factory, request *http.Request, response http.ResponseWriter, mention *model.Mention, template model.Template, actionID string) (Mention, error) {
+
+	const location = "build.NewMention"
+
+	// Create the underlying Common builder
+	common, err := NewCommon(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Mention{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Mention{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the Mention builder
+	return Mention{
+		_mention: mention,
+		Common:   common,
+	}, nil
+}
+
+/******************************************
+ * RENDERER INTERFACE
+ ******************************************/
+
+// Render generates the string value for this Stream
+func (w Mention) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w.action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Mention.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Mention
+func (w Mention) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Mention.View"
+
+	builder, err := NewMention(w._factory, w._request, w._response, w._mention, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Mention builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Mention) NavigationID() string {
+	return "admin"
+}
+
+func (w Mention) Permalink() string {
+	return w.Hostname() + "/admin/mentions/" + w.MentionID()
+}
+
+func (w Mention) BasePath() string {
+	return "/admin/mentions/" + w.MentionID()
+}
+
+func (w Mention) Token() string {
+	return "mentions"
+}
+
+func (w Mention) PageTitle() string {
+	return "Settings"
+}
+
+func (w Mention) object() data.Object {
+	return w._mention
+}
+
+func (w Mention) objectID() primitive.ObjectID {
+	return w._mention.MentionID
+}
+
+func (w Mention) objectType() string {
+	return "Mention"
+}
+
+func (w Mention) schema() schema.Schema {
+	return schema.New(model.MentionSchema())
+}
+
+func (w Mention) service() service.ModelService {
+	return w._factory.Mention()
+}
+
+func (w Mention) executeTemplate(writer io.Writer, name string, data any) error {
+	return w._template.HTMLTemplate.ExecuteTemplate(writer, name, data)
+}
+
+func (w Mention) clone(action string) (Builder, error) {
+	return NewMention(w._factory, w._request, w._response, w._mention, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w Mention) MentionID() string {
+	if w._mention == nil {
+		return ""
+	}
+	return w._mention.MentionID.Hex()
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// PendingMentions returns all Mentions that are waiting for the owner's approval
+func (w Mention) PendingMentions() *QueryBuilder[model.Mention] {
+
+	criteria := exp.Equal("stateId", model.MentionStatusPending)
+
+	result := NewQueryBuilder[model.Mention](w._factory.Mention(), criteria)
+
+	return &result
+}
+
+// ApprovedMentions returns all Mentions that have been approved for display
+func (w Mention) ApprovedMentions() *QueryBuilder[model.Mention] {
+
+	criteria := exp.Equal("stateId", model.MentionStatusValidated)
+
+	result := NewQueryBuilder[model.Mention](w._factory.Mention(), criteria)
+
+	return &result
+}
+
+func (w Mention) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_mention")
+}
@@ -171,6 +171,30 @@ func (w User) ImageURL() string {
 	return w._user.ActivityPubAvatarURL()
 }
 
+// IsPending returns TRUE if the User is waiting for a domain owner's approval
+func (w User) IsPending() bool {
+	if w._user == nil {
+		return false
+	}
+	return w._user.IsPending
+}
+
+// SignupNote returns the note that the User included when they signed up
+func (w User) SignupNote() string {
+	if w._user == nil {
+		return ""
+	}
+	return w._user.SignupNote
+}
+
+// IsTwoFactorEnabled returns TRUE if the User has set up an authenticator app
+func (w User) IsTwoFactorEnabled() bool {
+	if w._user == nil {
+		return false
+	}
+	return w._user.TwoFactor.IsEnabled()
+}
+
 /******************************************
  * Query Builders
  ******************************************/
@@ -184,6 +208,7 @@ func (w User) Users() *QueryBuilder[model.UserSummary] {
 	criteria := exp.And(
 		query.Evaluate(w._request.URL.Query()),
 		exp.Equal("deleteDate", 0),
+		exp.NotEqual("isPending", true),
 	)
 
 	result := NewQueryBuilder[model.UserSummary](w._factory.User(), criteria)
@@ -191,10 +216,33 @@ func (w User) Users() *QueryBuilder[model.UserSummary] {
 	return &result
 }
 
+// PendingUsers returns a query builder for all Users who are waiting for a domain owner's approval
+func (w User) PendingUsers() *QueryBuilder[model.UserSummary] {
+
+	criteria := exp.And(
+		exp.Equal("isPending", true),
+		exp.Equal("deleteDate", 0),
+	)
+
+	result := NewQueryBuilder[model.UserSummary](w._factory.User(), criteria)
+
+	return &result
+}
+
+// Invitations returns all of the Invitations on this domain
+func (w User) Invitations() ([]model.Invitation, error) {
+	return w._factory.Invitation().QueryAll()
+}
+
 /******************************************
  * ADDITIONAL DATA
  ******************************************/
 
+// Sessions returns all of the browsers that the User is signed in with
+func (w User) Sessions() ([]model.UserSession, error) {
+	return w._factory.UserSession().QueryByUser(w._user.UserID)
+}
+
 // AssignedGroups lists all groups to which the current user is assigned.
 func (w User) AssignedGroups() ([]model.Group, error) {
 	groupService := w.factory().Group()
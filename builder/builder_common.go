@@ -268,10 +268,40 @@ func (w Common) DomainHasSignupForm() (bool, error) {
 	}
 }
 
+// InvitationURL returns the link that new people use to sign up with an Invitation
+func (w Common) InvitationURL(invitation model.Invitation) string {
+	return w._factory.Invitation().URL(&invitation)
+}
+
+// OAuthClient returns the OAuth application with the provided ID.  If the application
+// cannot be found, then an empty OAuthClient is returned.
+func (w Common) OAuthClient(clientID primitive.ObjectID) model.OAuthClient {
+
+	result := model.NewOAuthClient()
+
+	if err := w._factory.OAuthClient().LoadByClientID(clientID, &result); err != nil {
+		derp.Report(derp.Wrap(err, "build.Common.OAuthClient", "Error loading OAuthClient", clientID))
+	}
+
+	return result
+}
+
+// CurrentSessionID returns the ID of the UserSession that is making this request
+func (w Common) CurrentSessionID() string {
+	authorization := w.authorization()
+	return authorization.ID
+}
+
 /***************************
  * Access Permissions
  **************************/
 
+// CanInvite returns TRUE if the user is allowed to create Invitations
+func (w Common) CanInvite() bool {
+	authorization := w.authorization()
+	return w._factory.Invitation().CanCreate(&authorization)
+}
+
 // IsAuthenticated returns TRUE if the user is signed in
 func (w Common) IsAuthenticated() bool {
 	authorization := w.authorization()
@@ -317,7 +347,7 @@ func (w Common) UserImage() (string, error) {
  ******************************************/
 
 // SubBuilder creates a new builder for a child object.  This function works
-// with Rule, Folder, Follower, Following, and Stream objects.  It will return
+// with Rule, Folder, Follower, Following, SyndicationTarget, and Stream objects.  It will return
 // an error if the object is not one of those types.
 func (w Common) SubBuilder(object any) (Builder, error) {
 
@@ -338,6 +368,9 @@ func (w Common) SubBuilder(object any) (Builder, error) {
 	case model.Following:
 		result, err = NewModel(w._factory, w._request, w._response, &typed, w._template, w.actionID)
 
+	case model.SyndicationTarget:
+		result, err = NewModel(w._factory, w._request, w._response, &typed, w._template, w.actionID)
+
 	case model.Stream:
 		result, err = NewModel(w._factory, w._request, w._response, &typed, w._template, w.actionID)
 
@@ -496,6 +529,23 @@ func (w Common) Navigation() (sliceof.Object[model.StreamSummary], error) {
 	return result, err
 }
 
+// SyndicationTargets returns all of the external services where the current user can syndicate their Streams
+func (w Common) SyndicationTargets() ([]model.SyndicationTarget, error) {
+
+	// If the user is not signed in, then they can't have syndication targets.
+	if !w.IsAuthenticated() {
+		return make([]model.SyndicationTarget, 0), nil
+	}
+
+	result, err := w._factory.SyndicationTarget().QueryByUser(w.AuthenticatedID())
+
+	if err != nil {
+		return nil, derp.Wrap(err, "build.Common.SyndicationTargets", "Error loading syndication targets")
+	}
+
+	return result, nil
+}
+
 func (w Common) GetResponseID(responseType string, url string) string {
 
 	// If the user is not signed in, then they can't have responded.
@@ -576,5 +626,9 @@ func (w Common) AdminSections() []form.LookupCode {
 			Value: "rules",
 			Label: "Rules",
 		},
+		{
+			Value: "mentions",
+			Label: "Mentions",
+		},
 	}
 }
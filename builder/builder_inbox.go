@@ -187,6 +187,11 @@ func (w Inbox) RuleCount() int {
 	return w._user.RuleCount
 }
 
+// ShowSensitive returns TRUE if the User always expands content warnings
+func (w Inbox) ShowSensitive() bool {
+	return w._user.ShowSensitive
+}
+
 func (w Inbox) DisplayName() string {
 	return w._user.DisplayName
 }
@@ -199,6 +204,67 @@ func (w Inbox) ImageURL() string {
 	return w._user.ActivityPubAvatarURL()
 }
 
+// IsTwoFactorEnabled returns TRUE if the User has set up an authenticator app
+func (w Inbox) IsTwoFactorEnabled() bool {
+	return w._user.TwoFactor.IsEnabled()
+}
+
+// IsTwoFactorRequired returns TRUE if the domain's policies require this User to use two-factor authentication
+func (w Inbox) IsTwoFactorRequired() (bool, error) {
+	return w._factory.User().IsTwoFactorRequired(w._user)
+}
+
+// TwoFactorEnabledDate returns the Unix epoch seconds when two-factor authentication was enabled
+func (w Inbox) TwoFactorEnabledDate() int64 {
+	return w._user.TwoFactor.EnabledDate
+}
+
+// Passkeys returns all of the passkeys that the User has registered
+func (w Inbox) Passkeys() sliceof.Object[model.Passkey] {
+	return w._user.Passkeys
+}
+
+// Sessions returns all of the browsers that the User is signed in with
+func (w Inbox) Sessions() ([]model.UserSession, error) {
+	return w._factory.UserSession().QueryByUser(w._user.UserID)
+}
+
+// OAuthUserTokens returns all of the applications that the User has authorized to access their account
+func (w Inbox) OAuthUserTokens() ([]model.OAuthUserToken, error) {
+	return w._factory.OAuthUserToken().QueryByUser(w._user.UserID)
+}
+
+// SigningKeys returns all of the keys that other servers accept for the User's activities,
+// including rotated keys that are still within their grace period
+func (w Inbox) SigningKeys() ([]model.EncryptionKey, error) {
+	return w._factory.EncryptionKey().QueryValidByParentID(model.EncryptionKeyTypeUser, w._user.UserID)
+}
+
+// HasEd25519Key returns TRUE if the User publishes an Ed25519 key (FEP-521a)
+func (w Inbox) HasEd25519Key() bool {
+	return w._factory.EncryptionKey().HasEd25519(model.EncryptionKeyTypeUser, w._user.UserID)
+}
+
+// DataExport returns the status of the User's personal data archive
+func (w Inbox) DataExport() model.DataExport {
+	return w._user.DataExport
+}
+
+// DataImports returns the User's most recent imports, along with their progress
+func (w Inbox) DataImports() ([]model.DataImport, error) {
+	return w._factory.DataImport().QueryByUser(w._user.UserID)
+}
+
+// Invitations returns all of the Invitations that the User has created
+func (w Inbox) Invitations() ([]model.Invitation, error) {
+	return w._factory.Invitation().QueryByUser(w._user.UserID)
+}
+
+// TwoFactorRecoveryCodeCount returns the number of unused two-factor recovery codes
+func (w Inbox) TwoFactorRecoveryCodeCount() int {
+	return w._user.TwoFactor.RecoveryCodeCount()
+}
+
 /******************************************
  * Inbox Methods
  ******************************************/
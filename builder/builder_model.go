@@ -113,6 +113,9 @@ func (w Model) Label() string {
 	case *model.Stream:
 		return object.Label
 
+	case *model.SyndicationTarget:
+		return object.Label
+
 	default:
 		return ""
 	}
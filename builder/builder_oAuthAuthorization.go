@@ -6,7 +6,6 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/service"
 	"github.com/benpate/derp"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // OAuthAuthorization is a lightweight builder that
@@ -22,23 +21,27 @@ func NewOAuthAuthorization(factory Factory, request model.OAuthAuthorizationRequ
 
 	const location = "build.NewOAuthAuthorization"
 
-	// Create the result object
-	result := OAuthAuthorization{
-		_service: factory.OAuthClient(),
-		_client:  model.NewOAuthClient(),
-		_request: request,
+	// Try to load the OAuthClient object
+	client := model.NewOAuthClient()
+
+	if err := factory.OAuthClient().LoadByPublicID(request.ClientID, &client); err != nil {
+		return OAuthAuthorization{}, derp.Wrap(err, location, "Error loading OAuth Application")
 	}
 
-	// Convert clientID
-	clientID, err := primitive.ObjectIDFromHex(request.ClientID)
+	return NewOAuthAuthorizationWithClient(factory, client, request)
+}
 
-	if err != nil {
-		return OAuthAuthorization{}, derp.Wrap(err, location, "Invalid ClientID")
-	}
+// NewOAuthAuthorizationWithClient returns a fully initialized `OAuthAuthorization` builder
+// for a client that has already been loaded (or discovered, but not yet saved)
+func NewOAuthAuthorizationWithClient(factory Factory, client model.OAuthClient, request model.OAuthAuthorizationRequest) (OAuthAuthorization, error) {
 
-	// Try to load the OAuthClient object
-	if err := result._service.LoadByClientID(clientID, &result._client); err != nil {
-		return OAuthAuthorization{}, derp.Wrap(err, location, "Error loading OAuth Application")
+	const location = "build.NewOAuthAuthorizationWithClient"
+
+	// Create the result object
+	result := OAuthAuthorization{
+		_service: factory.OAuthClient(),
+		_client:  client,
+		_request: request,
 	}
 
 	// Validate the transaction
@@ -51,7 +54,16 @@ func NewOAuthAuthorization(factory Factory, request model.OAuthAuthorizationRequ
 }
 
 func (r OAuthAuthorization) ClientID() string {
-	return r._client.ClientID.Hex()
+	return r._client.PublicClientID()
+}
+
+// IsIndieAuth returns TRUE if this request comes from an IndieAuth client
+func (r OAuthAuthorization) IsIndieAuth() bool {
+	return r._client.IsIndieAuth()
+}
+
+func (r OAuthAuthorization) LogoURL() string {
+	return r._client.LogoURL
 }
 
 func (r OAuthAuthorization) Name() string {
@@ -70,12 +82,29 @@ func (r OAuthAuthorization) ResponseType() string {
 	return r._request.ResponseType
 }
 
+func (r OAuthAuthorization) State() string {
+	return r._request.State
+}
+
+func (r OAuthAuthorization) CodeChallenge() string {
+	return r._request.CodeChallenge
+}
+
+func (r OAuthAuthorization) CodeChallengeMethod() string {
+	return r._request.CodeChallengeMethod
+}
+
 func (r OAuthAuthorization) Scope() string {
 	return r._request.Scope
 }
 
 func (r OAuthAuthorization) Scopes() []string {
 
+	// IndieAuth requests without scopes only authenticate the user
+	if r._client.IsIndieAuth() && (r._request.Scope == "") {
+		return []string{}
+	}
+
 	if r._request.Scope == "" {
 		return []string{"read"}
 	}
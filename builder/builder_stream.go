@@ -263,11 +263,26 @@ func (w Stream) ShortSummary() string {
 	return htmlconv.Summary(w._stream.Summary)
 }
 
+// Sensitive returns TRUE if the stream being built is hidden behind a content warning
+func (w Stream) Sensitive() bool {
+	return w._stream.Sensitive
+}
+
+// ContentWarning returns the content warning (if any) for the stream being built
+func (w Stream) ContentWarning() string {
+	return w._stream.ContentWarning()
+}
+
 // ImageURL returns the thumbnail image URL of the stream being built
 func (w Stream) ImageURL() string {
 	return w._stream.ImageURL
 }
 
+// Syndication returns the URLs of all copies of this stream that have been syndicated to other services
+func (w Stream) Syndication() sliceof.String {
+	return w._stream.Syndication
+}
+
 // Permalink returns a complete URL for this stream
 func (w Stream) Permalink() string {
 	return w._stream.Permalink()
@@ -297,6 +312,16 @@ func (w Stream) InReplyTo() streams.Document {
 	return w.ActivityStream(w._stream.InReplyTo)
 }
 
+// IsQuote returns TRUE if this stream quotes another stream or resource
+func (w Stream) IsQuote() bool {
+	return (w._stream.QuoteURL != "")
+}
+
+// QuoteOf returns an ActivityStream reference to the URL that this stream quotes
+func (w Stream) QuoteOf() streams.Document {
+	return w.ActivityStream(w._stream.QuoteURL)
+}
+
 // Returns the body content as an HTML template
 func (w Stream) ContentHTML() template.HTML {
 	return template.HTML(w._stream.Content.HTML)
@@ -532,10 +557,34 @@ func (w Stream) getFirstStream(criteria exp.Expression, sortOption option.Option
 	return Stream{}
 }
 
-// Mentions returns a slice of all Mentions for this Stream
+// Mentions returns a slice of all approved Mentions for this Stream
 func (w Stream) Mentions() ([]model.Mention, error) {
 	mentionService := w.factory().Mention()
-	return mentionService.QueryByObjectID(w._stream.StreamID)
+	return mentionService.QueryValidatedByObjectID(w._stream.StreamID)
+}
+
+// MentionReplies returns a slice of all approved replies and plain Mentions for this Stream
+func (w Stream) MentionReplies() ([]model.Mention, error) {
+	mentionService := w.factory().Mention()
+	return mentionService.QueryValidatedByObjectID(w._stream.StreamID, model.OriginTypeReply, model.OriginTypePrimary, "")
+}
+
+// MentionLikes returns a slice of all approved "like" Mentions for this Stream
+func (w Stream) MentionLikes() ([]model.Mention, error) {
+	mentionService := w.factory().Mention()
+	return mentionService.QueryValidatedByObjectID(w._stream.StreamID, model.OriginTypeLike)
+}
+
+// MentionReposts returns a slice of all approved "repost" Mentions for this Stream
+func (w Stream) MentionReposts() ([]model.Mention, error) {
+	mentionService := w.factory().Mention()
+	return mentionService.QueryValidatedByObjectID(w._stream.StreamID, model.OriginTypeAnnounce)
+}
+
+// MentionBookmarks returns a slice of all approved "bookmark" Mentions for this Stream
+func (w Stream) MentionBookmarks() ([]model.Mention, error) {
+	mentionService := w.factory().Mention()
+	return mentionService.QueryValidatedByObjectID(w._stream.StreamID, model.OriginTypeBookmark)
 }
 
 // RepliesBefore returns a slice of all ActivityStreams before the specified date
@@ -720,6 +769,25 @@ func (w Stream) Attachments() ([]model.Attachment, error) {
 	return w.factory().Attachment().QueryByObjectID(model.AttachmentTypeStream, w._stream.StreamID)
 }
 
+/******************************************
+ * Edit History
+ ******************************************/
+
+// IsEdited returns TRUE if this stream has been edited since it was published
+func (w Stream) IsEdited() bool {
+	return w._stream.IsEdited()
+}
+
+// EditDate returns the Unix epoch (in seconds) when this stream was last edited
+func (w Stream) EditDate() int64 {
+	return w._stream.EditDate
+}
+
+// Revisions lists the edit history of this stream, newest first
+func (w Stream) Revisions() ([]model.StreamRevision, error) {
+	return w.factory().StreamRevision().QueryByStream(w._stream.StreamID)
+}
+
 /******************************************
  * Content Actors
  ******************************************/
@@ -29,22 +29,29 @@ type Factory interface {
 	Follower() *service.Follower
 	Group() *service.Group
 	Inbox() *service.Inbox
+	Invitation() *service.Invitation
 	Mention() *service.Mention
 	Outbox() *service.Outbox
 	Provider() *service.Provider
 	Response() *service.Response
 	Stream() *service.Stream
 	StreamDraft() *service.StreamDraft
+	StreamRevision() *service.StreamRevision
+	SyndicationTarget() *service.SyndicationTarget
 	Template() *service.Template
 	Theme() *service.Theme
 	User() *service.User
+	UserSession() *service.UserSession
 	Widget() *service.Widget
 
 	// Other data services
 	Config() config.Domain
 	Content() *service.Content
+	DataExport() *service.DataExport
+	DataImport() *service.DataImport
 	Domain() *service.Domain
 	Email() *service.DomainEmail
+	EncryptionKey() *service.EncryptionKey
 	Host() string
 	Hostname() string
 	Icons() icon.Provider
@@ -15,6 +15,8 @@ import (
 	"github.com/benpate/rosetta/html"
 	"github.com/davecgh/go-spew/spew"
 
+	"github.com/EmissarySocial/emissary/tools/qrcode"
+	"github.com/EmissarySocial/emissary/tools/textdiff"
 	"github.com/EmissarySocial/emissary/tools/tinyDate"
 	"github.com/benpate/icon"
 	"github.com/benpate/rosetta/convert"
@@ -135,6 +137,10 @@ func FuncMap(icons icon.Provider) template.FuncMap {
 
 		"textOnly": html.RemoveTags,
 
+		"diff": func(before string, after string) template.HTML {
+			return template.HTML(textdiff.HTML(html.ToText(before), html.ToText(after)))
+		},
+
 		"summary": html.Summary,
 
 		"html": func(value string) template.HTML {
@@ -234,6 +240,11 @@ func FuncMap(icons icon.Provider) template.FuncMap {
 			return url.QueryEscape(value)
 		},
 
+		"qrCode": func(value string) (template.URL, error) {
+			result, err := qrcode.DataURL(value)
+			return template.URL(result), err // nolint:gosec // data: URL is generated by the server
+		},
+
 		"dump": func(values ...any) string {
 			for _, value := range values {
 				spew.Dump(value)
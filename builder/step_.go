@@ -26,6 +26,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.AddStream:
 		return StepAddStream(s)
 
+	case step.ApproveUser:
+		return StepApproveUser(s)
+
 	case step.AsConfirmation:
 		return StepAsConfirmation(s)
 
@@ -35,12 +38,24 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.AsTooltip:
 		return StepAsTooltip(s)
 
+	case step.CreateInvitation:
+		return StepCreateInvitation(s)
+
 	case step.Delete:
 		return StepDelete(s)
 
 	case step.DeleteAttachments:
 		return StepDeleteAttachments(s)
 
+	case step.DeleteInvitation:
+		return StepDeleteInvitation(s)
+
+	case step.DeletePasskey:
+		return StepDeletePasskey(s)
+
+	case step.DisableTwoFactor:
+		return StepDisableTwoFactor(s)
+
 	case step.Do:
 		return StepDo(s)
 
@@ -59,6 +74,15 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.EditWidget:
 		return StepEditWidget(s)
 
+	case step.EnableTwoFactor:
+		return StepEnableTwoFactor(s)
+
+	case step.ExportData:
+		return StepExportData(s)
+
+	case step.ExportOPML:
+		return StepExportOPML(s)
+
 	case step.ForwardTo:
 		return StepForwardTo(s)
 
@@ -68,6 +92,12 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.IfCondition:
 		return StepIfCondition(s)
 
+	case step.ImportData:
+		return StepImportData(s)
+
+	case step.ImportOPML:
+		return StepImportOPML(s)
+
 	case step.InlineError:
 		return StepInlineError(s)
 
@@ -77,6 +107,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.InlineSuccess:
 		return StepInlineSuccess(s)
 
+	case step.NewRecoveryCodes:
+		return StepNewRecoveryCodes(s)
+
 	case step.ProcessContent:
 		return StepProcessContent(s)
 
@@ -95,6 +128,27 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.RemoveEvent:
 		return StepRemoveEvent(s)
 
+	case step.ResetTwoFactor:
+		return StepResetTwoFactor(s)
+
+	case step.RestoreRevision:
+		return StepRestoreRevision(s)
+
+	case step.RevokeOAuthToken:
+		return StepRevokeOAuthToken(s)
+
+	case step.RevokeSession:
+		return StepRevokeSession(s)
+
+	case step.RevokeSessions:
+		return StepRevokeSessions(s)
+
+	case step.RotateKeys:
+		return StepRotateKeys(s)
+
+	case step.RSSCloud:
+		return StepRSSCloud(s)
+
 	case step.Save:
 		return StepSave(s)
 
@@ -107,6 +161,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.SetData:
 		return StepSetData(s)
 
+	case step.SetEd25519Key:
+		return StepSetEd25519Key(s)
+
 	case step.SetHeader:
 		return StepSetHeader(s)
 
@@ -197,6 +254,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.WithRule:
 		return StepWithRule(s)
 
+	case step.WithSyndicationTarget:
+		return StepWithSyndicationTarget(s)
+
 	}
 
 	return StepError{Original: stepInfo}
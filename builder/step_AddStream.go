@@ -207,6 +207,11 @@ func (step StepAddStream) Post(builder Builder, buffer io.Writer) PipelineBehavi
 		newStream.SetAttributedTo(user.PersonLink())
 	}
 
+	// RULE: New quotes must be allowed by the author of the quoted document
+	if err := streamService.ValidateQuote(&newStream); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Quote is not allowed", newStream.QuoteURL))
+	}
+
 	// Create a builder for the new Stream
 	newBuilder, err := NewStream(factory, builder.request(), builder.response(), template, &newStream, "create")
 	newBuilder.setArguments(builder.getArguments())
@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepApproveUser represents an action-step that lets a User who is waiting for a
+// domain owner's approval sign in.
+type StepApproveUser struct{}
+
+// Get does nothing
+func (step StepApproveUser) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post approves the User being edited
+func (step StepApproveUser) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepApproveUser.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	if err := builder.factory().User().Approve(user); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error approving user", user.UserID))
+	}
+
+	return Continue()
+}
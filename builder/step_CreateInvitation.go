@@ -0,0 +1,53 @@
+package builder
+
+import (
+	"io"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// StepCreateInvitation represents an action-step that creates a new Invitation for the signed-in User.
+// The form includes a private "note", the maximum number of uses ("maxUses"), and the number of
+// days before the Invitation expires ("expireDays").  Zero values mean unlimited.
+type StepCreateInvitation struct{}
+
+// Get does nothing
+func (step StepCreateInvitation) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post creates the new Invitation
+func (step StepCreateInvitation) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepCreateInvitation.Post"
+
+	authorization := builder.authorization()
+	invitationService := builder.factory().Invitation()
+
+	if !invitationService.CanCreate(&authorization) {
+		return Halt().WithError(derp.NewForbiddenError(location, "User cannot create invitations", authorization.UserID))
+	}
+
+	transaction := struct {
+		Note       string `form:"note"`
+		MaxUses    int    `form:"maxUses"`
+		ExpireDays int    `form:"expireDays"`
+	}{}
+
+	if err := bind(builder.request(), &transaction); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error binding form data"))
+	}
+
+	if transaction.ExpireDays < 0 {
+		return Halt().WithError(derp.NewBadRequestError(location, "Expiration cannot be negative", transaction.ExpireDays))
+	}
+
+	duration := time.Duration(transaction.ExpireDays) * 24 * time.Hour
+
+	if _, err := invitationService.Create(authorization.UserID, transaction.Note, transaction.MaxUses, duration); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error creating invitation"))
+	}
+
+	return Continue()
+}
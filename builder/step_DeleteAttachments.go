@@ -3,6 +3,7 @@ package builder
 import (
 	"io"
 
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -27,10 +28,17 @@ func (step StepDeleteAttachments) Post(builder Builder, _ io.Writer) PipelineBeh
 	objectType := builder.service().ObjectType()
 	objectID := builder.objectID()
 
+	// Stream attachments are archived (not deleted) so that they can be restored with a previous revision
+	deleteAll, deleteByID := attachmentService.DeleteAll, attachmentService.DeleteByID
+
+	if objectType == model.AttachmentTypeStream {
+		deleteAll, deleteByID = attachmentService.ArchiveAll, attachmentService.ArchiveByID
+	}
+
 	if step.All {
 
 		// Delete all attachments for this stream
-		if err := attachmentService.DeleteAll(objectType, objectID, "Deleted by {{.Author}}"); err != nil {
+		if err := deleteAll(objectType, objectID, "Deleted by {{.Author}}"); err != nil {
 			return Halt().WithError(derp.Wrap(err, location, "Error deleting all attachments"))
 		}
 
@@ -43,7 +51,7 @@ func (step StepDeleteAttachments) Post(builder Builder, _ io.Writer) PipelineBeh
 			return Halt().WithError(derp.Wrap(err, location, "Invalid attachment ID", attachmentIDString))
 		}
 
-		if err := attachmentService.DeleteByID(objectType, objectID, attachmentID, "Deleted by Workflow Step"); err != nil {
+		if err := deleteByID(objectType, objectID, attachmentID, "Deleted by Workflow Step"); err != nil {
 			return Halt().WithError(derp.Wrap(err, location, "Error deleting attachment", attachmentID))
 		}
 	}
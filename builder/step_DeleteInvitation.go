@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepDeleteInvitation represents an action-step that removes an Invitation, identified by the
+// "invitationId" query parameter.  Domain owners can remove any Invitation.  Other users can
+// only remove the Invitations that they created.
+type StepDeleteInvitation struct{}
+
+// Get does nothing
+func (step StepDeleteInvitation) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post removes the Invitation
+func (step StepDeleteInvitation) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepDeleteInvitation.Post"
+
+	invitationID, err := primitive.ObjectIDFromHex(builder.QueryParam("invitationId"))
+
+	if err != nil {
+		return Halt().WithError(derp.NewBadRequestError(location, "Invalid invitationId", builder.QueryParam("invitationId")))
+	}
+
+	invitationService := builder.factory().Invitation()
+	invitation := model.NewInvitation()
+
+	if err := invitationService.LoadByID(invitationID, &invitation); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading invitation", invitationID))
+	}
+
+	authorization := builder.authorization()
+
+	if !authorization.DomainOwner && (invitation.UserID != authorization.UserID) {
+		return Halt().WithError(derp.NewForbiddenError(location, "User cannot remove this invitation", invitationID))
+	}
+
+	if err := invitationService.Delete(&invitation, "Removed"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error removing invitation", invitationID))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,41 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepDeletePasskey represents an action-step that removes one of the signed-in User's passkeys.
+// The passkey is identified by the "passkeyId" query parameter.
+type StepDeletePasskey struct{}
+
+// Get does nothing
+func (step StepDeletePasskey) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post removes the passkey from the User
+func (step StepDeletePasskey) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepDeletePasskey.Post"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	passkeyID, err := primitive.ObjectIDFromHex(builder.QueryParam("passkeyId"))
+
+	if err != nil {
+		return Halt().WithError(derp.NewBadRequestError(location, "Invalid passkeyId", builder.QueryParam("passkeyId")))
+	}
+
+	if err := builder.factory().User().DeletePasskey(&user, passkeyID); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error removing passkey", user.UserID, passkeyID))
+	}
+
+	return Continue()
+}
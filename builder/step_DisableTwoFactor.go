@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepDisableTwoFactor represents an action-step that turns off two-factor authentication
+// for the signed-in User, after verifying a current code.
+type StepDisableTwoFactor struct{}
+
+// Get does nothing
+func (step StepDisableTwoFactor) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post verifies the code and turns off two-factor authentication
+func (step StepDisableTwoFactor) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepDisableTwoFactor.Post"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	transaction := struct {
+		Code string `form:"code"`
+	}{}
+
+	if err := bind(builder.request(), &transaction); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form"))
+	}
+
+	if err := builder.factory().User().DisableTwoFactor(&user, transaction.Code); err != nil {
+		return inlineClientError(buffer, derp.Wrap(err, location, "Error disabling two-factor authentication", user.UserID))
+	}
+
+	return Continue()
+}
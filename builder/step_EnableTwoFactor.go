@@ -0,0 +1,67 @@
+package builder
+
+import (
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/derp"
+)
+
+// StepEnableTwoFactor represents an action-step that sets up two-factor authentication for the signed-in User.
+// On GET, it makes the new secret available to templates as "twoFactorSecret" and "twoFactorURI".
+// On POST, it confirms the secret with a code from the User's authenticator app, and makes the
+// new recovery codes available to templates as "recoveryCodes".
+type StepEnableTwoFactor struct{}
+
+// Get prepares a new secret for the User's authenticator app
+func (step StepEnableTwoFactor) Get(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepEnableTwoFactor.Get"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	secret, uri, err := builder.factory().User().BeginTwoFactor(&user)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error starting two-factor setup", user.UserID))
+	}
+
+	builder.setString("twoFactorSecret", totp.FormatSecret(secret))
+	builder.setString("twoFactorURI", uri)
+
+	return Continue()
+}
+
+// Post confirms the new secret and enables two-factor authentication
+func (step StepEnableTwoFactor) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepEnableTwoFactor.Post"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	transaction := struct {
+		Code string `form:"code"`
+	}{}
+
+	if err := bind(builder.request(), &transaction); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form"))
+	}
+
+	recoveryCodes, err := builder.factory().User().EnableTwoFactor(&user, transaction.Code)
+
+	if err != nil {
+		return inlineClientError(buffer, derp.Wrap(err, location, "Error enabling two-factor authentication", user.UserID))
+	}
+
+	builder.setString("recoveryCodes", strings.Join(recoveryCodes, "\n"))
+	return Continue()
+}
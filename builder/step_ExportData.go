@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepExportData represents an action-step that builds an archive of a User's personal data
+// in the background.  The User receives an email with a download link when it is ready.
+type StepExportData struct{}
+
+// Get does nothing
+func (step StepExportData) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post requests a new archive for the current User
+func (step StepExportData) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepExportData.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	if err := builder.factory().DataExport().Request(user); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error requesting data export", user.UserID))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepExportOPML represents an action-step that downloads the User's Followings as an OPML document
+type StepExportOPML struct{}
+
+// Get writes the OPML document
+func (step StepExportOPML) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepExportOPML.Get"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	if err := builder.factory().Following().ExportOPML(&user, buffer); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error exporting OPML", user.UserID))
+	}
+
+	return Halt().
+		AsFullPage().
+		WithContentType(model.MimeTypeOPML).
+		WithHeader("Content-Disposition", `attachment; filename="subscriptions.opml"`)
+}
+
+// Post does nothing
+func (step StepExportOPML) Post(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
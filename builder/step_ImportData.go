@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepImportData represents an action-step that imports followings, blocks, mutes, or bookmarks
+// from a CSV file that was exported from Mastodon.  Rows are imported in the background.
+type StepImportData struct{}
+
+// Get does nothing
+func (step StepImportData) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post reads the uploaded CSV file and queues its rows to be imported
+func (step StepImportData) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepImportData.Post"
+
+	// Read the multipart form from the request
+	form, err := multipartForm(builder.request())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error reading multipart form"))
+	}
+
+	files := form.File["file"]
+
+	if len(files) == 0 {
+		return Halt().WithError(derp.NewBadRequestError(location, "No CSV file was uploaded"))
+	}
+
+	importType := ""
+
+	if values := form.Value["type"]; len(values) > 0 {
+		importType = values[0]
+	}
+
+	source, err := files[0].Open()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error reading uploaded file", files[0].Filename))
+	}
+
+	defer source.Close()
+
+	// Queue the rows to be imported in the background
+	if _, err := builder.factory().DataImport().Create(builder.AuthenticatedID(), importType, files[0].Filename, source); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error importing CSV file", files[0].Filename))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepImportOPML represents an action-step that creates Followings from an uploaded OPML document
+type StepImportOPML struct{}
+
+// Get does nothing
+func (step StepImportOPML) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post reads the uploaded OPML document and follows each of its feeds
+func (step StepImportOPML) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepImportOPML.Post"
+
+	// Read the multipart form from the request
+	form, err := multipartForm(builder.request())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error reading multipart form"))
+	}
+
+	files := form.File["file"]
+
+	if len(files) == 0 {
+		return Halt().WithError(derp.NewBadRequestError(location, "No OPML file was uploaded"))
+	}
+
+	source, err := files[0].Open()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error reading uploaded file", files[0].Filename))
+	}
+
+	defer source.Close()
+
+	// Create Followings for every feed in the document
+	if _, err := builder.factory().Following().ImportOPML(builder.AuthenticatedID(), source); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error importing OPML", files[0].Filename))
+	}
+
+	return Continue()
+}
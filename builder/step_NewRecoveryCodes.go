@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"io"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// StepNewRecoveryCodes represents an action-step that replaces the signed-in User's two-factor
+// recovery codes, after verifying a current code.  The new codes are made available to
+// templates as "recoveryCodes".
+type StepNewRecoveryCodes struct{}
+
+// Get does nothing
+func (step StepNewRecoveryCodes) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post verifies the code and generates new recovery codes
+func (step StepNewRecoveryCodes) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepNewRecoveryCodes.Post"
+
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading user"))
+	}
+
+	transaction := struct {
+		Code string `form:"code"`
+	}{}
+
+	if err := bind(builder.request(), &transaction); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form"))
+	}
+
+	recoveryCodes, err := builder.factory().User().NewRecoveryCodes(&user, transaction.Code)
+
+	if err != nil {
+		return inlineClientError(buffer, derp.Wrap(err, location, "Error replacing recovery codes", user.UserID))
+	}
+
+	builder.setString("recoveryCodes", strings.Join(recoveryCodes, "\n"))
+	return Continue()
+}
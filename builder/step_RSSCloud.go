@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/EmissarySocial/emissary/tools/clientip"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/list"
+)
+
+// StepRSSCloud represents an action-step that accepts rssCloud subscription requests
+// https://www.rssboard.org/rsscloud-interface
+type StepRSSCloud struct {
+}
+
+// Get is not required by rssCloud.  So let's redirect to the primary action.
+func (step StepRSSCloud) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	newLocation := list.RemoveLast(builder.URL(), list.DelimiterSlash)
+	if err := redirect(builder.response(), http.StatusSeeOther, newLocation); err != nil {
+		return Halt().WithError(derp.Wrap(err, "build.StepRSSCloud.Get", "Error writing redirection", newLocation))
+	}
+	return nil
+}
+
+// Post accepts an rssCloud "pleaseNotify" request, verifies it, and potentially creates a new Follower record.
+func (step StepRSSCloud) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepRSSCloud.Post"
+
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return step.result(buffer, derp.Wrap(err, location, "Error parsing form data"))
+	}
+
+	// Calculate the callback URL for this subscriber
+	callback, err := rssCloudCallbackURL(request.Form, clientip.FromRequest(request))
+
+	if err != nil {
+		return step.result(buffer, derp.Wrap(err, location, "Invalid subscription request"))
+	}
+
+	// RULE: Callbacks must be on the public internet
+	if err := ssrf.ValidateURL(callback); err != nil {
+		return step.result(buffer, derp.Wrap(err, location, "Invalid callback URL", callback))
+	}
+
+	urls := rssCloudURLs(request.Form)
+
+	if len(urls) == 0 {
+		return step.result(buffer, derp.NewBadRequestError(location, "Missing feed URL"))
+	}
+
+	// Create a Follower record for each feed.  rssCloud expects a synchronous
+	// response, so these tasks are run immediately instead of in the queue.
+	factory := builder.factory()
+	useChallenge := request.Form.Get("domain") != ""
+
+	for _, feedURL := range urls {
+
+		task := service.NewTaskCreateRSSCloudFollower(
+			factory.Follower(),
+			factory.Locator(),
+			builder.objectType(),
+			builder.objectID(),
+			rssCloudFormat(feedURL),
+			feedURL,
+			callback,
+			useChallenge,
+		)
+
+		if err := task.Run(); err != nil {
+			return step.result(buffer, derp.Wrap(err, location, "Error creating rssCloud follower", feedURL))
+		}
+	}
+
+	return step.result(buffer, nil)
+}
+
+// result writes an rssCloud <notifyResult> document to the response
+func (step StepRSSCloud) result(buffer io.Writer, err error) PipelineBehavior {
+
+	response := struct {
+		XMLName xml.Name `xml:"notifyResult"`
+		Success bool     `xml:"success,attr"`
+		Message string   `xml:"msg,attr"`
+	}{
+		Success: true,
+		Message: "Thanks for the registration. It worked. When the feed changes we'll notify you.",
+	}
+
+	if err != nil {
+		derp.Report(err)
+		response.Success = false
+		response.Message = derp.Message(err)
+	}
+
+	// nolint:errcheck
+	buffer.Write([]byte(xml.Header))
+
+	if err := xml.NewEncoder(buffer).Encode(response); err != nil {
+		return Halt().WithError(derp.Wrap(err, "build.StepRSSCloud.result", "Error writing response"))
+	}
+
+	return Halt().AsFullPage().WithContentType(model.MimeTypeXMLText)
+}
+
+// rssCloudCallbackURL calculates the URL that will receive notifications from the
+// parameters of a "pleaseNotify" request.  If no domain is provided, then the
+// IP address of the requester is used instead.
+func rssCloudCallbackURL(form url.Values, remoteIP string) (string, error) {
+
+	const location = "build.rssCloudCallbackURL"
+
+	// RULE: Only HTTP-POST notifications are supported
+	protocol := form.Get("protocol")
+
+	if (protocol != "http-post") && (protocol != "https-post") {
+		return "", derp.NewBadRequestError(location, "Unsupported protocol", protocol)
+	}
+
+	port := form.Get("port")
+	path := form.Get("path")
+	host := form.Get("domain")
+
+	if host == "" {
+		host = remoteIP
+	}
+
+	// RULE: Host and Port are required
+	if (host == "") || (port == "") {
+		return "", derp.NewBadRequestError(location, "Missing domain or port")
+	}
+
+	// RULE: Path must be absolute
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	scheme := "http"
+
+	if (protocol == "https-post") || (port == "443") {
+		scheme = "https"
+	}
+
+	// Omit default ports from the URL
+	if ((scheme == "http") && (port == "80")) || ((scheme == "https") && (port == "443")) {
+		return scheme + "://" + hostForURL(host) + path, nil
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, port) + path, nil
+}
+
+// hostForURL wraps IPv6 addresses in brackets so that they can be used in a URL
+func hostForURL(host string) string {
+
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+
+	return host
+}
+
+// rssCloudURLs returns all of the feed URLs (url1, url2, ...) in a "pleaseNotify" request
+func rssCloudURLs(form url.Values) []string {
+
+	names := make([]string, 0)
+
+	for name := range form {
+		if strings.HasPrefix(name, "url") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if value := form.Get(name); value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// rssCloudFormat returns the mime type of a feed URL, based on its "format" parameter
+func rssCloudFormat(feedURL string) string {
+
+	if parsedURL, err := url.Parse(feedURL); err == nil {
+		if parsedURL.Query().Get("format") == "atom" {
+			return model.MimeTypeAtom
+		}
+	}
+
+	return model.MimeTypeRSS
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSCloudCallbackURL(t *testing.T) {
+
+	test := func(values url.Values, remoteIP string, expected string) {
+		result, err := rssCloudCallbackURL(values, remoteIP)
+		require.Nil(t, err)
+		require.Equal(t, expected, result)
+	}
+
+	test(url.Values{"protocol": {"http-post"}, "domain": {"example.com"}, "port": {"443"}, "path": {"/notify"}}, "", "https://example.com/notify")
+	test(url.Values{"protocol": {"http-post"}, "domain": {"example.com"}, "port": {"80"}, "path": {"/notify"}}, "", "http://example.com/notify")
+	test(url.Values{"protocol": {"http-post"}, "domain": {"example.com"}, "port": {"5337"}, "path": {"notify"}}, "", "http://example.com:5337/notify")
+	test(url.Values{"protocol": {"https-post"}, "port": {"8443"}, "path": {"/notify"}}, "10.0.0.1", "https://10.0.0.1:8443/notify")
+	test(url.Values{"protocol": {"http-post"}, "port": {"80"}, "path": {"/notify"}}, "::1", "http://[::1]/notify")
+}
+
+func TestRSSCloudCallbackURL_Errors(t *testing.T) {
+
+	_, err := rssCloudCallbackURL(url.Values{"protocol": {"xml-rpc"}, "domain": {"example.com"}, "port": {"80"}}, "")
+	require.NotNil(t, err)
+
+	_, err = rssCloudCallbackURL(url.Values{"protocol": {"http-post"}, "domain": {"example.com"}}, "")
+	require.NotNil(t, err)
+
+	_, err = rssCloudCallbackURL(url.Values{"protocol": {"http-post"}, "port": {"80"}}, "")
+	require.NotNil(t, err)
+}
+
+func TestRSSCloudURLs(t *testing.T) {
+
+	values := url.Values{
+		"url2":     {"https://example.com/second"},
+		"url1":     {"https://example.com/first"},
+		"url3":     {""},
+		"port":     {"80"},
+		"protocol": {"http-post"},
+	}
+
+	require.Equal(t, []string{"https://example.com/first", "https://example.com/second"}, rssCloudURLs(values))
+	require.Equal(t, 0, len(rssCloudURLs(url.Values{})))
+}
+
+func TestRSSCloudFormat(t *testing.T) {
+	require.Equal(t, model.MimeTypeAtom, rssCloudFormat("https://example.com/@123/feed?format=atom"))
+	require.Equal(t, model.MimeTypeRSS, rssCloudFormat("https://example.com/@123/feed?format=rss"))
+	require.Equal(t, model.MimeTypeRSS, rssCloudFormat("https://example.com/@123/feed"))
+}
@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepResetTwoFactor represents an action-step that removes all two-factor authentication settings
+// from a User, so that domain owners can restore access for Users who have lost their authenticator app.
+type StepResetTwoFactor struct{}
+
+// Get does nothing
+func (step StepResetTwoFactor) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post resets two-factor authentication for the User being edited
+func (step StepResetTwoFactor) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepResetTwoFactor.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	if err := builder.factory().User().ResetTwoFactor(user, "Two-factor authentication reset by domain owner"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error resetting two-factor authentication", user.UserID))
+	}
+
+	return Continue()
+}
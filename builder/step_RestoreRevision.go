@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepRestoreRevision represents an action-step that copies a previous revision back into a Stream.
+// The revision is identified by the "revisionId" query parameter.
+type StepRestoreRevision struct{}
+
+func (step StepRestoreRevision) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post copies the values from the selected revision into the Stream in the builder.
+// Subsequent steps (e.g. save, publish) are responsible for saving the Stream.
+func (step StepRestoreRevision) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "builder.StepRestoreRevision.Post"
+
+	streamBuilder := builder.(*Stream)
+
+	// Parse the RevisionID from the query string
+	revisionID, err := primitive.ObjectIDFromHex(builder.QueryParam("revisionId"))
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "RevisionID must be a valid hex string"))
+	}
+
+	// Load the revision from the database
+	revision := model.NewStreamRevision()
+
+	if err := builder.factory().StreamRevision().LoadByID(streamBuilder._stream.StreamID, revisionID, &revision); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading revision", revisionID))
+	}
+
+	// Copy the revision (and its attachments) into the Stream
+	if err := builder.factory().StreamRevision().Restore(&revision, streamBuilder._stream, "Restored by Workflow Step"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error restoring revision", revisionID))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepRevokeOAuthToken represents an action-step that removes an application's access to
+// the current User's account.  The OAuthUserToken is identified by the "oauthUserTokenId"
+// query parameter.
+type StepRevokeOAuthToken struct{}
+
+// Get does nothing
+func (step StepRevokeOAuthToken) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post revokes the OAuthUserToken
+func (step StepRevokeOAuthToken) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRevokeOAuthToken.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	userTokenID, err := primitive.ObjectIDFromHex(builder.QueryParam("oauthUserTokenId"))
+
+	if err != nil {
+		return Halt().WithError(derp.NewBadRequestError(location, "Invalid oauthUserTokenId", builder.QueryParam("oauthUserTokenId")))
+	}
+
+	userTokenService := builder.factory().OAuthUserToken()
+	userToken := model.NewOAuthUserToken()
+
+	if err := userTokenService.LoadByUserAndID(user.UserID, userTokenID, &userToken); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading application", userTokenID))
+	}
+
+	if err := userTokenService.Revoke(&userToken); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error revoking application", userTokenID))
+	}
+
+	return Continue()
+}
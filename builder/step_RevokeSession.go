@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepRevokeSession represents an action-step that signs the current User out of a single
+// browser, identified by the "sessionId" query parameter.
+type StepRevokeSession struct{}
+
+// Get does nothing
+func (step StepRevokeSession) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post revokes the UserSession
+func (step StepRevokeSession) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRevokeSession.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(builder.QueryParam("sessionId"))
+
+	if err != nil {
+		return Halt().WithError(derp.NewBadRequestError(location, "Invalid sessionId", builder.QueryParam("sessionId")))
+	}
+
+	sessionService := builder.factory().UserSession()
+	session := model.NewUserSession()
+
+	if err := sessionService.LoadByID(user.UserID, sessionID, &session); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading session", sessionID))
+	}
+
+	if err := sessionService.Revoke(&session); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error revoking session", sessionID))
+	}
+
+	return Continue()
+}
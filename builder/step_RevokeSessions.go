@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepRevokeSessions represents an action-step that signs a User out of every browser.
+// The browser that is making this request stays signed in.
+type StepRevokeSessions struct{}
+
+// Get does nothing
+func (step StepRevokeSessions) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post revokes all of the User's UserSessions
+func (step StepRevokeSessions) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRevokeSessions.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User", builder.object()))
+	}
+
+	authorization := builder.authorization()
+
+	if err := builder.factory().UserSession().RevokeByUser(user.UserID, authorization.ID); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error revoking sessions", user.UserID))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepRotateKeys represents an action-step that replaces an Actor's signing keys
+// and announces the new public keys to its followers.  It works with Users, Streams,
+// and the Domain.
+type StepRotateKeys struct{}
+
+// Get does nothing
+func (step StepRotateKeys) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post rotates the signing keys for the current object
+func (step StepRotateKeys) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRotateKeys.Post"
+
+	parentType, parentID, ok := keyOwner(builder)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User, Stream, or Domain", builder.object()))
+	}
+
+	if err := builder.factory().EncryptionKey().Rotate(parentType, parentID); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error rotating keys", parentType, parentID))
+	}
+
+	return Continue()
+}
+
+// keyOwner returns the EncryptionKey parentType and parentID of the builder's current object
+func keyOwner(builder Builder) (string, primitive.ObjectID, bool) {
+
+	switch object := builder.object().(type) {
+
+	case *model.User:
+		return model.EncryptionKeyTypeUser, object.UserID, true
+
+	case *model.Stream:
+		return model.EncryptionKeyTypeStream, object.StreamID, true
+
+	case *model.Domain:
+		return model.EncryptionKeyTypeDomain, primitive.NilObjectID, true
+	}
+
+	return "", primitive.NilObjectID, false
+}
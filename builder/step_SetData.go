@@ -8,6 +8,7 @@ import (
 	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/compare"
 	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
 )
 
 // StepSetData represents an action-step that can update the custom data stored in a Stream
@@ -49,6 +50,16 @@ func (step StepSetData) Post(builder Builder, _ io.Writer) PipelineBehavior {
 			return Halt().WithError(result)
 		}
 
+		// The binder only keeps the first value of each field, so restore
+		// array fields (like groups of checkboxes) from the parsed form
+		postForm := builder.request().PostForm
+
+		for _, p := range step.FromForm {
+			if values := postForm[p]; (len(values) > 0) && isArrayElement(schema, p) {
+				transaction[p] = values
+			}
+		}
+
 		// Put approved form data into the stream
 		for _, p := range step.FromForm {
 			if err := schema.Set(object, p, transaction[p]); err != nil {
@@ -82,6 +93,19 @@ func (step StepSetData) Post(builder Builder, _ io.Writer) PipelineBehavior {
 	return nil
 }
 
+// isArrayElement returns TRUE if the path points to an array in the provided schema
+func isArrayElement(s schema.Schema, path string) bool {
+
+	element, ok := s.GetElement(path)
+
+	if !ok {
+		return false
+	}
+
+	_, isArray := element.(schema.Array)
+	return isArray
+}
+
 func (step StepSetData) setURLPaths(builder Builder) error {
 
 	if len(step.FromURL) > 0 {
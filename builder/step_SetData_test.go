@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsArrayElement(t *testing.T) {
+
+	s := schema.New(schema.Object{
+		Properties: schema.ElementMap{
+			"label": schema.String{},
+			"data": schema.Object{
+				Properties: schema.ElementMap{
+					"tags":  schema.Array{Items: schema.String{}},
+					"color": schema.String{},
+				},
+			},
+		},
+	})
+
+	require.True(t, isArrayElement(s, "data.tags"))
+	require.False(t, isArrayElement(s, "data.color"))
+	require.False(t, isArrayElement(s, "label"))
+	require.False(t, isArrayElement(s, "missing"))
+}
@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepSetEd25519Key represents an action-step that publishes (or removes) the
+// optional Ed25519 key (FEP-521a) for a User, Stream, or the Domain
+type StepSetEd25519Key struct {
+	Enabled bool
+}
+
+// Get does nothing
+func (step StepSetEd25519Key) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post creates or removes the Ed25519 key for the current object
+func (step StepSetEd25519Key) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepSetEd25519Key.Post"
+
+	parentType, parentID, ok := keyOwner(builder)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Object must be a User, Stream, or Domain", builder.object()))
+	}
+
+	keyService := builder.factory().EncryptionKey()
+
+	if step.Enabled {
+		if err := keyService.EnableEd25519(parentType, parentID); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error enabling Ed25519 key", parentType, parentID))
+		}
+		return Continue()
+	}
+
+	if err := keyService.DisableEd25519(parentType, parentID); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error disabling Ed25519 key", parentType, parentID))
+	}
+
+	return Continue()
+}
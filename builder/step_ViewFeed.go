@@ -56,16 +56,22 @@ func (step StepViewFeed) Get(builder Builder, buffer io.Writer) PipelineBehavior
 		var xml string
 		var err error
 
-		// Thank you gorilla/feeds for this awesome API.
+		links := feedLinks{
+			Hub: builder.Permalink() + "/websub",
+		}
+
 		switch mimeType {
 
 		case model.MimeTypeAtom:
-			mimeType = "application/atom+xml; charset=UTF=8"
-			xml, err = result.ToAtom()
-
-		case model.MimeTypeRSS:
-			mimeType = "application/rss+xml; charset=UTF=8"
-			xml, err = result.ToRss()
+			mimeType = "application/atom+xml; charset=UTF-8"
+			links.Self = builder.Permalink() + "/feed?format=atom"
+			xml, err = feedToAtom(&result, links)
+
+		default:
+			mimeType = "application/rss+xml; charset=UTF-8"
+			links.Self = builder.Permalink() + "/feed?format=rss"
+			links.Cloud = newRSSCloudXML(builder.Permalink() + "/rsscloud")
+			xml, err = feedToRSS(&result, links)
 		}
 
 		if err != nil {
@@ -74,7 +80,7 @@ func (step StepViewFeed) Get(builder Builder, buffer io.Writer) PipelineBehavior
 
 		// nolint:errcheck
 		buffer.Write([]byte(xml))
-		return Halt().WithContentType(mimeType)
+		return Halt().AsFullPage().WithContentType(mimeType)
 	}
 }
 
@@ -135,5 +141,5 @@ func (step StepViewFeed) asJSONFeed(builder Builder, buffer io.Writer, children
 	buffer.Write(bytes)
 
 	// Set ContentType
-	return Halt().WithContentType(model.MimeTypeJSONFeed)
+	return Halt().AsFullPage().WithContentType(model.MimeTypeJSONFeed)
 }
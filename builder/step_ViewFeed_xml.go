@@ -0,0 +1,144 @@
+package builder
+
+import (
+	"encoding/xml"
+	"net/url"
+
+	"github.com/benpate/rosetta/first"
+	"github.com/gorilla/feeds"
+)
+
+// feedLinks contains the discovery links that are published along with RSS and Atom feeds
+type feedLinks struct {
+	Self  string       // URL of the feed itself
+	Hub   string       // URL of the WebSub hub for this feed
+	Cloud *rssCloudXML // rssCloud endpoint for this feed (RSS only)
+}
+
+// rssFeedXML is a replacement for the gorilla/feeds RSS wrapper that includes
+// the rssCloud and Atom namespaces
+type rssFeedXML struct {
+	XMLName          xml.Name `xml:"rss"`
+	Version          string   `xml:"version,attr"`
+	ContentNamespace string   `xml:"xmlns:content,attr"`
+	AtomNamespace    string   `xml:"xmlns:atom,attr"`
+	Channel          rssChannelXML
+}
+
+// rssChannelXML extends the gorilla/feeds RSS channel with <cloud> and <atom:link> elements
+type rssChannelXML struct {
+	XMLName xml.Name `xml:"channel"`
+	*feeds.RssFeed
+	Cloud     *rssCloudXML  `xml:"cloud,omitempty"`
+	AtomLinks []atomLinkXML `xml:"atom:link"`
+}
+
+// rssCloudXML represents the <cloud> element of an RSS channel
+// https://www.rssboard.org/rsscloud-interface
+type rssCloudXML struct {
+	Domain            string `xml:"domain,attr"`
+	Port              string `xml:"port,attr"`
+	Path              string `xml:"path,attr"`
+	RegisterProcedure string `xml:"registerProcedure,attr"`
+	Protocol          string `xml:"protocol,attr"`
+}
+
+// atomFeedXML extends the gorilla/feeds Atom feed with additional <link> elements
+type atomFeedXML struct {
+	*feeds.AtomFeed
+	Links []atomLinkXML `xml:"link"`
+}
+
+// atomLinkXML represents a <link> element in an Atom feed (or an <atom:link> in an RSS feed)
+type atomLinkXML struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// newRSSCloudXML returns the <cloud> element for an rssCloud endpoint at the provided URL
+func newRSSCloudXML(endpoint string) *rssCloudXML {
+
+	endpointURL, err := url.Parse(endpoint)
+
+	if err != nil || endpointURL.Hostname() == "" {
+		return nil
+	}
+
+	port := endpointURL.Port()
+
+	if port == "" {
+		if endpointURL.Scheme == "https" {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+
+	return &rssCloudXML{
+		Domain:            endpointURL.Hostname(),
+		Port:              port,
+		Path:              endpointURL.Path,
+		RegisterProcedure: "",
+		Protocol:          "http-post",
+	}
+}
+
+// feedToRSS generates an RSS document for the feed, including rssCloud and WebSub discovery links
+func feedToRSS(feed *feeds.Feed, links feedLinks) (string, error) {
+
+	channel := rssChannelXML{
+		RssFeed: (&feeds.Rss{Feed: feed}).RssFeed(),
+		Cloud:   links.Cloud,
+	}
+
+	if links.Self != "" {
+		channel.AtomLinks = append(channel.AtomLinks, atomLinkXML{Href: links.Self, Rel: "self", Type: "application/rss+xml"})
+	}
+
+	if links.Hub != "" {
+		channel.AtomLinks = append(channel.AtomLinks, atomLinkXML{Href: links.Hub, Rel: "hub"})
+	}
+
+	return marshalFeedXML(rssFeedXML{
+		Version:          "2.0",
+		ContentNamespace: "http://purl.org/rss/1.0/modules/content/",
+		AtomNamespace:    "http://www.w3.org/2005/Atom",
+		Channel:          channel,
+	})
+}
+
+// feedToAtom generates an Atom document for the feed, including WebSub discovery links
+func feedToAtom(feed *feeds.Feed, links feedLinks) (string, error) {
+
+	result := atomFeedXML{
+		AtomFeed: (&feeds.Atom{Feed: feed}).AtomFeed(),
+	}
+
+	// The original "alternate" link is hidden by our Links field, so copy it over
+	if link := result.AtomFeed.Link; link != nil {
+		result.Links = append(result.Links, atomLinkXML{Href: link.Href, Rel: first.String(link.Rel, "alternate"), Type: link.Type})
+	}
+
+	if links.Self != "" {
+		result.Links = append(result.Links, atomLinkXML{Href: links.Self, Rel: "self", Type: "application/atom+xml"})
+	}
+
+	if links.Hub != "" {
+		result.Links = append(result.Links, atomLinkXML{Href: links.Hub, Rel: "hub"})
+	}
+
+	return marshalFeedXML(result)
+}
+
+// marshalFeedXML converts a feed into an indented XML document, including the XML header
+func marshalFeedXML(value any) (string, error) {
+
+	data, err := xml.MarshalIndent(value, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(data), nil
+}
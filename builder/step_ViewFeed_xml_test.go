@@ -0,0 +1,90 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/feeds"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeedXML() *feeds.Feed {
+	return &feeds.Feed{
+		Title:       "Test Feed",
+		Description: "A feed for testing",
+		Link:        &feeds.Link{Href: "https://example.com/feed-owner"},
+		Author:      &feeds.Author{Name: ""},
+		Created:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Items: []*feeds.Item{
+			{
+				Title:   "First Post",
+				Link:    &feeds.Link{Href: "https://example.com/first-post"},
+				Id:      "https://example.com/first-post",
+				Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestNewRSSCloudXML(t *testing.T) {
+
+	cloud := newRSSCloudXML("https://example.com/@123/rsscloud")
+	require.NotNil(t, cloud)
+	require.Equal(t, "example.com", cloud.Domain)
+	require.Equal(t, "443", cloud.Port)
+	require.Equal(t, "/@123/rsscloud", cloud.Path)
+	require.Equal(t, "http-post", cloud.Protocol)
+
+	cloud = newRSSCloudXML("http://localhost:8080/folder/rsscloud")
+	require.NotNil(t, cloud)
+	require.Equal(t, "localhost", cloud.Domain)
+	require.Equal(t, "8080", cloud.Port)
+
+	require.Nil(t, newRSSCloudXML("not a url"))
+}
+
+func TestFeedToRSS(t *testing.T) {
+
+	result, err := feedToRSS(testFeedXML(), feedLinks{
+		Self:  "https://example.com/feed-owner/feed?format=rss",
+		Hub:   "https://example.com/feed-owner/websub",
+		Cloud: newRSSCloudXML("https://example.com/feed-owner/rsscloud"),
+	})
+
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(result, "<?xml"))
+
+	// Parse the result back with a third-party parser to guarantee that it's readable
+	feed, err := (&rss.Parser{}).Parse(strings.NewReader(result))
+	require.Nil(t, err)
+	require.Equal(t, "Test Feed", feed.Title)
+	require.Equal(t, 1, len(feed.Items))
+	require.Equal(t, "First Post", feed.Items[0].Title)
+
+	require.NotNil(t, feed.Cloud)
+	require.Equal(t, "example.com", feed.Cloud.Domain)
+	require.Equal(t, "443", feed.Cloud.Port)
+	require.Equal(t, "/feed-owner/rsscloud", feed.Cloud.Path)
+	require.Equal(t, "http-post", feed.Cloud.Protocol)
+
+	require.Contains(t, result, `<atom:link href="https://example.com/feed-owner/websub" rel="hub"></atom:link>`)
+	require.Contains(t, result, `<atom:link href="https://example.com/feed-owner/feed?format=rss" rel="self" type="application/rss+xml"></atom:link>`)
+}
+
+func TestFeedToAtom(t *testing.T) {
+
+	result, err := feedToAtom(testFeedXML(), feedLinks{
+		Self: "https://example.com/feed-owner/feed?format=atom",
+		Hub:  "https://example.com/feed-owner/websub",
+	})
+
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(result, "<?xml"))
+	require.Contains(t, result, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	require.Contains(t, result, `<link href="https://example.com/feed-owner" rel="alternate"></link>`)
+	require.Contains(t, result, `<link href="https://example.com/feed-owner/websub" rel="hub"></link>`)
+	require.Contains(t, result, `<link href="https://example.com/feed-owner/feed?format=atom" rel="self" type="application/atom+xml"></link>`)
+	require.Contains(t, result, `<title>First Post</title>`)
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/model/step"
+	"github.com/benpate/derp"
+)
+
+// StepWithSyndicationTarget represents an action-step that runs sub-steps on a SyndicationTarget owned by the current user
+type StepWithSyndicationTarget struct {
+	SubSteps []step.Step
+}
+
+func (step StepWithSyndicationTarget) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodGet)
+}
+
+// Post updates the SyndicationTarget with approved data from the request body.
+func (step StepWithSyndicationTarget) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodPost)
+}
+
+func (step StepWithSyndicationTarget) execute(builder Builder, buffer io.Writer, actionMethod ActionMethod) PipelineBehavior {
+
+	const location = "build.StepWithSyndicationTarget.doStep"
+
+	if !builder.IsAuthenticated() {
+		return Halt().WithError(derp.NewUnauthorizedError(location, "Anonymous user is not authorized to perform this action"))
+	}
+
+	// Collect required services and values
+	factory := builder.factory()
+	syndicationTargetService := factory.SyndicationTarget()
+	token := builder.QueryParam("syndicationTargetId")
+	target := model.NewSyndicationTarget()
+	target.UserID = builder.AuthenticatedID()
+
+	if (token != "") && (token != "new") {
+		if err := syndicationTargetService.LoadByToken(builder.AuthenticatedID(), token, &target); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Unable to load SyndicationTarget", token))
+		}
+	}
+
+	// Create a new builder tied to the SyndicationTarget record
+	subBuilder, err := NewModel(factory, builder.request(), builder.response(), &target, builder.template(), builder.ActionID())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Unable to create sub-builder"))
+	}
+
+	// Execute the build pipeline on the child
+	result := Pipeline(step.SubSteps).Execute(factory, subBuilder, buffer, actionMethod)
+	result.Error = derp.Wrap(result.Error, location, "Error executing steps for child")
+
+	return UseResult(result)
+}
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"bytes"
+	"html/template"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -18,6 +19,23 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// inlineClientError sends the message of a client error (like an invalid form value) to the
+// #htmx-response-message element.  All other errors halt the pipeline as usual.
+func inlineClientError(buffer io.Writer, err error) PipelineBehavior {
+
+	if !derp.IsClientError(err) {
+		return Halt().WithError(err)
+	}
+
+	message := template.HTMLEscapeString(derp.Message(derp.RootCause(err)))
+
+	if _, err := io.WriteString(buffer, `<span class="text-red">`+message+`</span>`); err != nil {
+		return Halt().WithError(derp.Wrap(err, "build.inlineClientError", "Error writing response"))
+	}
+
+	return Halt().WithHeader("HX-Reswap", "innerHTML").WithHeader("HX-Retarget", "#htmx-response-message")
+}
+
 // WrapInlineSuccess sends a confirmation message to the #htmx-response-message element
 func WrapInlineSuccess(response http.ResponseWriter, message any) error {
 
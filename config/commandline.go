@@ -10,11 +10,12 @@ import (
 
 // CommandLineArgs represents the command line arguments passed to the server
 type CommandLineArgs struct {
-	Source   string // Type of configuration file (Command Line | Enviornment Variable | Default)
-	Protocol string // Protocol to use when loading the configuration (MONGODB | FILE)
-	Location string // URI of the configuration file
-	Setup    bool   // If TRUE, then the server will run in SETUP mode
-	HTTPPort int    // Port to use in setup mode (only)
+	Source    string // Type of configuration file (Command Line | Enviornment Variable | Default)
+	Protocol  string // Protocol to use when loading the configuration (MONGODB | FILE)
+	Location  string // URI of the configuration file
+	Setup     bool   // If TRUE, then the server will run in SETUP mode
+	RotateKEK bool   // If TRUE, then the server will rotate the key encrypting key for every domain, then exit
+	HTTPPort  int    // Port to use in setup mode (only)
 }
 
 // GetCommandLineArgs returns the location of the configuration file
@@ -23,11 +24,13 @@ func GetCommandLineArgs() CommandLineArgs {
 	var source string
 	var location string
 	var setup bool
+	var rotateKEK bool
 	var httpPort int
 
 	// Look for the configuration location in the command line arguments
 	pflag.StringVar(&location, "config", "", "Path to configuration file")
 	pflag.BoolVar(&setup, "setup", false, "Run setup server")
+	pflag.BoolVar(&rotateKEK, "rotate-kek", false, "Re-encrypt all keys with a new key encrypting key, then exit")
 	pflag.IntVar(&httpPort, "port", 0, "HTTP Port to use for setup mode.")
 	pflag.Parse()
 
@@ -53,11 +56,12 @@ func GetCommandLineArgs() CommandLineArgs {
 	}
 
 	return CommandLineArgs{
-		Source:   source,
-		Location: location,
-		Protocol: getConfigProtocol(location),
-		Setup:    setup,
-		HTTPPort: httpPort,
+		Source:    source,
+		Location:  location,
+		Protocol:  getConfigProtocol(location),
+		Setup:     setup,
+		RotateKEK: rotateKEK,
+		HTTPPort:  httpPort,
 	}
 }
 
@@ -31,6 +31,7 @@ type Config struct {
 	HTTPPort            int                          `json:"httpPort"`            // Port to listen on for HTTP requests
 	HTTPSPort           int                          `json:"httpsPort"`           // Port to listen on for HTTPS requests
 	DebugLevel          string                       `json:"debugLevel"`          // Amount of debugging information to log for the server, using zerolog levels (Trace, Debug, Info, Error, None)
+	RateLimit           RateLimit                    `json:"rateLimit"`           // Maximum number of requests that clients can make to this server
 	Source              string                       `json:"-"`                   // READONLY: Where did the initial config location come from?  (Command Line, Environment Variable, Default)
 	Location            string                       `json:"-"`                   // READONLY: Location where this config file is read from/to.  Not a part of the configuration itself.
 	MongoID             primitive.ObjectID           `json:"-" bson:"_id"`        // Used as unique key for MongoDB
@@ -57,6 +58,7 @@ func DefaultConfig() Config {
 		Certificates:        mapof.String{"adapter": "FILE", "location": "./.emissary/certificates"},
 		ActivityPubCache:    mapof.String{},
 		DebugLevel:          "None",
+		RateLimit:           NewRateLimit(),
 		HTTPPort:            8080,
 		HTTPSPort:           443,
 	}
@@ -25,6 +25,7 @@ func Schema() schema.Schema {
 				"httpPort":            schema.Integer{Maximum: null.NewInt64(65535), Default: null.NewInt64(80)},
 				"httpsPort":           schema.Integer{Maximum: null.NewInt64(65535), Default: null.NewInt64(443)},
 				"activityPubCache":    DatabaseConnectInfo(),
+				"rateLimit":           RateLimitSchema(),
 			},
 		},
 	}
@@ -74,6 +75,9 @@ func (config *Config) GetPointer(name string) (any, bool) {
 	case "activityPubCache":
 		return &config.ActivityPubCache, true
 
+	case "rateLimit":
+		return &config.RateLimit, true
+
 	}
 
 	return nil, false
@@ -78,6 +78,11 @@ func TestConfig(t *testing.T) {
 		{"httpsPort", "8443", 8443},
 		{"activityPubCache.connectString", "ACTIVITY_PUB_CACHE", nil},
 		{"activityPubCache.database", "ACTIVITY_PUB_CACHE", nil},
+		{"rateLimit.disabled", "true", true},
+		{"rateLimit.web", "600", 600},
+		{"rateLimit.auth", "10", 10},
+		{"rateLimit.api", "300", 300},
+		{"rateLimit.inbox", "1000", 1000},
 	}
 
 	tableTest_Schema(t, &s, &c, table)
@@ -8,14 +8,16 @@ import (
 
 // Domain contains all of the configuration data required to operate a single domain.
 type Domain struct {
-	DomainID         string         `json:"domainId"         bson:"domainId"`         // Unique ID for this domain
-	Label            string         `json:"label"            bson:"label"`            // Human-friendly label for administrators
-	Hostname         string         `json:"hostname"         bson:"hostname"`         // Domain name of a virtual server
-	ConnectString    string         `json:"connectString"    bson:"connectString"`    // MongoDB connect string
-	DatabaseName     string         `json:"databaseName"     bson:"databaseName"`     // Name of the MongoDB Database (can be empty string to use default db for the connect string)
-	SMTPConnection   SMTPConnection `json:"smtp"             bson:"smtp"`             // Information for connecting to an SMTP server to send email on behalf of the domain.
-	Owner            Owner          `json:"owner"            bson:"owner"`            // Information about the owner of this domain
-	KeyEncryptingKey string         `json:"keyEncryptingKey" bson:"keyEncryptingKey"` // Key used to encrypt/decrypt JWT keys stored in the database
+	DomainID                 string         `json:"domainId"         bson:"domainId"`                                             // Unique ID for this domain
+	Label                    string         `json:"label"            bson:"label"`                                                // Human-friendly label for administrators
+	Hostname                 string         `json:"hostname"         bson:"hostname"`                                             // Domain name of a virtual server
+	ConnectString            string         `json:"connectString"    bson:"connectString"`                                        // MongoDB connect string
+	DatabaseName             string         `json:"databaseName"     bson:"databaseName"`                                         // Name of the MongoDB Database (can be empty string to use default db for the connect string)
+	SMTPConnection           SMTPConnection `json:"smtp"             bson:"smtp"`                                                 // Information for connecting to an SMTP server to send email on behalf of the domain.
+	Owner                    Owner          `json:"owner"            bson:"owner"`                                                // Information about the owner of this domain
+	KeyEncryptingKey         string         `json:"keyEncryptingKey" bson:"keyEncryptingKey"`                                     // Key used to encrypt/decrypt JWT keys and private keys stored in the database
+	PreviousKeyEncryptingKey string         `json:"previousKeyEncryptingKey,omitempty" bson:"previousKeyEncryptingKey,omitempty"` // Previous key encrypting key, only kept while keys are being re-encrypted with a new one
+	SecureMode               bool           `json:"secureMode"       bson:"secureMode"`                                           // If TRUE, then ActivityPub collections require a valid HTTP Signature (aka "authorized fetch")
 }
 
 // NewDomain returns a fully initialized Domain object.
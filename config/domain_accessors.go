@@ -19,6 +19,7 @@ func DomainSchema() schema.Element {
 			"smtp":             SMTPConnectionSchema(),
 			"owner":            OwnerSchema(),
 			"keyEncryptingKey": schema.String{MinLength: 32, MaxLength: 32, Default: keyEncryptingKey},
+			"secureMode":       schema.Boolean{},
 		},
 	}
 }
@@ -51,6 +52,9 @@ func (domain *Domain) GetPointer(name string) (any, bool) {
 
 	case "keyEncryptingKey":
 		return &domain.KeyEncryptingKey, true
+
+	case "secureMode":
+		return &domain.SecureMode, true
 	}
 
 	return nil, false
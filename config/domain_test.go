@@ -27,6 +27,7 @@ func TestDomainSchema(t *testing.T) {
 		{"owner.phoneNumber", "123-456-7890", nil},
 		{"owner.mailingAddress", "1234 Owner Street, Ownerville, OW 00000", nil},
 		{"keyEncryptingKey", "12345678901234567890123456789012", nil},
+		{"secureMode", "true", true},
 	}
 
 	tableTest_Schema(t, &s, &d, table)
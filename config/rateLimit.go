@@ -0,0 +1,63 @@
+package config
+
+import "time"
+
+// RateLimitWindow is the period of time that every rate limit is counted over
+const RateLimitWindow = 5 * time.Minute
+
+// RateLimitMaxBuckets is the maximum number of clients that are counted at once, so that
+// requests from many different addresses cannot use up all of the server's memory
+const RateLimitMaxBuckets = 100000
+
+// Default rate limits, used when a limit is not set in the configuration.
+// These are based on Mastodon's defaults, so that clients behave the same way on both servers.
+const (
+	RateLimitDefaultWeb   = 1500 // Web requests per window, per IP address or signed-in account
+	RateLimitDefaultAuth  = 25   // Sign-in, registration, and password reset attempts per window, per IP address
+	RateLimitDefaultAPI   = 1500 // API requests per window, per signed-in account (or IP address when not signed in)
+	RateLimitDefaultInbox = 3000 // ActivityPub inbox deliveries per window, per IP address
+)
+
+// RateLimit defines the maximum number of requests that clients can make in each RateLimitWindow.
+// Zero values use the built-in defaults.
+type RateLimit struct {
+	Disabled bool `json:"disabled"` // If TRUE, then requests are not rate limited at all
+	Web      int  `json:"web"`      // Web requests per window, per IP address or signed-in account
+	Auth     int  `json:"auth"`     // Sign-in, registration, and password reset attempts per window, per IP address
+	API      int  `json:"api"`      // API requests per window, per signed-in account (or IP address when not signed in)
+	Inbox    int  `json:"inbox"`    // ActivityPub inbox deliveries per window, per IP address
+}
+
+// NewRateLimit returns a fully initialized RateLimit that uses the default limits
+func NewRateLimit() RateLimit {
+	return RateLimit{}
+}
+
+// WebLimit returns the maximum number of web requests per window
+func (rateLimit RateLimit) WebLimit() int {
+	return rateLimitOrDefault(rateLimit.Web, RateLimitDefaultWeb)
+}
+
+// AuthLimit returns the maximum number of authentication attempts per window
+func (rateLimit RateLimit) AuthLimit() int {
+	return rateLimitOrDefault(rateLimit.Auth, RateLimitDefaultAuth)
+}
+
+// APILimit returns the maximum number of API requests per window
+func (rateLimit RateLimit) APILimit() int {
+	return rateLimitOrDefault(rateLimit.API, RateLimitDefaultAPI)
+}
+
+// InboxLimit returns the maximum number of inbox deliveries per window
+func (rateLimit RateLimit) InboxLimit() int {
+	return rateLimitOrDefault(rateLimit.Inbox, RateLimitDefaultInbox)
+}
+
+func rateLimitOrDefault(value int, defaultValue int) int {
+
+	if value > 0 {
+		return value
+	}
+
+	return defaultValue
+}
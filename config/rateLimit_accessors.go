@@ -0,0 +1,42 @@
+package config
+
+import (
+	"github.com/benpate/rosetta/null"
+	"github.com/benpate/rosetta/schema"
+)
+
+func RateLimitSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"disabled": schema.Boolean{},
+			"web":      schema.Integer{Minimum: null.NewInt64(0)},
+			"auth":     schema.Integer{Minimum: null.NewInt64(0)},
+			"api":      schema.Integer{Minimum: null.NewInt64(0)},
+			"inbox":    schema.Integer{Minimum: null.NewInt64(0)},
+		},
+	}
+}
+
+func (rateLimit *RateLimit) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "disabled":
+		return &rateLimit.Disabled, true
+
+	case "web":
+		return &rateLimit.Web, true
+
+	case "auth":
+		return &rateLimit.Auth, true
+
+	case "api":
+		return &rateLimit.API, true
+
+	case "inbox":
+		return &rateLimit.Inbox, true
+
+	}
+
+	return nil, false
+}
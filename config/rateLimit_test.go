@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitSchema(t *testing.T) {
+
+	r := NewRateLimit()
+	s := schema.New(RateLimitSchema())
+
+	table := []tableTestItem{
+		{"disabled", "true", true},
+		{"web", "600", 600},
+		{"auth", "10", 10},
+		{"api", "300", 300},
+		{"inbox", "1000", 1000},
+	}
+
+	tableTest_Schema(t, &s, &r, table)
+}
+
+func TestRateLimit_Defaults(t *testing.T) {
+
+	r := NewRateLimit()
+	require.Equal(t, RateLimitDefaultWeb, r.WebLimit())
+	require.Equal(t, RateLimitDefaultAuth, r.AuthLimit())
+	require.Equal(t, RateLimitDefaultAPI, r.APILimit())
+	require.Equal(t, RateLimitDefaultInbox, r.InboxLimit())
+
+	r.Auth = 5
+	require.Equal(t, 5, r.AuthLimit())
+}
@@ -3,6 +3,9 @@ package domain
 // CollectionAttachment is the name of the database collection where Attachments are stored
 const CollectionAttachment = "Attachment"
 
+// CollectionDataImport is the name of the database collection where DataImport records are stored
+const CollectionDataImport = "DataImport"
+
 // CollectionEncryptionKey is the name of the database collection where EncryptionKey records are stored
 const CollectionEncryptionKey = "EncryptionKey"
 
@@ -24,6 +27,9 @@ const CollectionGroup = "Group"
 // CollectionInbox is the database colleciton where user's Inbox records are stored
 const CollectionInbox = "Inbox"
 
+// CollectionInvitation is the name of the database collection where Invitation records are stored
+const CollectionInvitation = "Invitation"
+
 // CollectionJWT is the database colleciton where user's JWTKey records are stored
 const CollectionJWT = "JWT"
 
@@ -48,14 +54,23 @@ const CollectionStream = "Stream"
 // CollectionStreamDraft is the name of the database collection where draft changes to streams are stored
 const CollectionStreamDraft = "StreamDraft"
 
+// CollectionStreamRevision is the name of the database collection where the edit history of streams is stored
+const CollectionStreamRevision = "StreamRevision"
+
 // CollectionStreamOutbox is the name of the database collection where users' StreamMessage records are stored
 const CollectionStreamOutbox = "StreamOutbox"
 
 // CollectionResponse is the name of the database collection where Responses are stored
 const CollectionResponse = "Response"
 
+// CollectionSyndicationTarget is the name of the database collection where SyndicationTargets are stored
+const CollectionSyndicationTarget = "SyndicationTarget"
+
 // CollectionTemplate is the name of the database collection where Templates are stored
 const CollectionTemplate = "Template"
 
 // CollectionUser is the name of the database collection where Users are stored
 const CollectionUser = "User"
+
+// CollectionUserSession is the name of the database collection where UserSession records are stored
+const CollectionUserSession = "UserSession"
@@ -37,14 +37,17 @@ type Factory struct {
 	contentService  *service.Content
 	providerService *service.Provider
 	taskQueue       queue.Queue
-	activityService *service.ActivityStream
+	activityCache   *service.ActivityStream
 
 	// Upload Directories (from server)
 	attachmentOriginals afero.Fs
 	attachmentCache     afero.Fs
 
 	// services (within this domain/factory)
+	activityService      service.ActivityStream
 	attachmentService    service.Attachment
+	dataExportService    service.DataExport
+	dataImportService    service.DataImport
 	ruleService          service.Rule
 	groupService         service.Group
 	domainService        service.Domain
@@ -54,6 +57,7 @@ type Factory struct {
 	followerService      service.Follower
 	followingService     service.Following
 	inboxService         service.Inbox
+	invitationService    service.Invitation
 	jwtService           service.JWT
 	mentionService       service.Mention
 	oauthClient          service.OAuthClient
@@ -62,8 +66,11 @@ type Factory struct {
 	responseService      service.Response
 	streamService        service.Stream
 	streamDraftService   service.StreamDraft
+	revisionService      service.StreamRevision
+	syndicationService   service.SyndicationTarget
 	realtimeBroker       RealtimeBroker
 	userService          service.User
+	userSessionService   service.UserSession
 
 	// real-time watchers
 	streamUpdateChannel chan model.Stream
@@ -84,7 +91,7 @@ func NewFactory(domain config.Domain, providers []config.Provider, activityServi
 		contentService:  contentService,
 		providerService: providerService,
 		taskQueue:       taskQueue,
-		activityService: activityService,
+		activityCache:   activityService,
 
 		attachmentOriginals: attachmentOriginals,
 		attachmentCache:     attachmentCache,
@@ -103,7 +110,10 @@ func NewFactory(domain config.Domain, providers []config.Provider, activityServi
 	factory.realtimeBroker = NewRealtimeBroker(&factory, factory.StreamUpdateChannel())
 
 	// Create empty service pointers.  These will be populated in the Refresh() step.
+	factory.activityService = service.NewActivityStream()
 	factory.attachmentService = service.NewAttachment()
+	factory.dataExportService = service.NewDataExport()
+	factory.dataImportService = service.NewDataImport()
 	factory.ruleService = service.NewRule()
 	factory.domainService = service.NewDomain()
 	factory.emailService = service.NewDomainEmail(serverEmail)
@@ -114,6 +124,8 @@ func NewFactory(domain config.Domain, providers []config.Provider, activityServi
 	factory.groupService = service.NewGroup()
 	factory.mentionService = service.NewMention()
 	factory.inboxService = service.NewInbox()
+	factory.invitationService = service.NewInvitation()
+	factory.userSessionService = service.NewUserSession()
 	factory.jwtService = service.NewJWT()
 	factory.oauthClient = service.NewOAuthClient()
 	factory.oauthUserToken = service.NewOAuthUserToken()
@@ -121,10 +133,19 @@ func NewFactory(domain config.Domain, providers []config.Provider, activityServi
 	factory.responseService = service.NewResponse()
 	factory.streamService = service.NewStream()
 	factory.streamDraftService = service.NewStreamDraft()
+	factory.revisionService = service.NewStreamRevision()
+	factory.syndicationService = service.NewSyndicationTarget()
 	factory.userService = service.NewUser()
 
 	// Start() is okay here because it will check for nil configuration before polling.
 	go factory.followingService.Start()
+	go factory.syndicationService.Start()
+	go factory.encryptionKeyService.Start()
+	go factory.dataExportService.Start()
+	go factory.attachmentService.Start()
+	go factory.followerService.Start()
+	go factory.userService.Start()
+	go factory.dataImportService.Start()
 
 	// Refresh the configuration with values that (may) change during the lifetime of the factory
 	if err := factory.Refresh(domain, providers, attachmentOriginals, attachmentCache); err != nil {
@@ -190,6 +211,36 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Host(),
 		)
 
+		// Populate DataExport Service
+		factory.dataExportService.Refresh(
+			factory.DataExportFiles(),
+			factory.AttachmentOriginals(),
+			factory.ActivityStream(),
+			factory.Attachment(),
+			factory.Email(),
+			factory.Folder(),
+			factory.Follower(),
+			factory.Following(),
+			factory.Outbox(),
+			factory.Response(),
+			factory.Rule(),
+			factory.Stream(),
+			factory.User(),
+			factory.Queue(),
+		)
+
+		// Populate DataImport Service
+		factory.dataImportService.Refresh(
+			factory.collection(CollectionDataImport),
+			factory.ActivityStream(),
+			factory.Folder(),
+			factory.Following(),
+			factory.Response(),
+			factory.Rule(),
+			factory.User(),
+			factory.Queue(),
+		)
+
 		// Populate Domain Service
 		factory.domainService.Refresh(
 			factory.collection(CollectionDomain),
@@ -204,6 +255,10 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 		// Populate EncryptionKey Service
 		factory.encryptionKeyService.Refresh(
 			factory.collection(CollectionEncryptionKey),
+			[]byte(domain.KeyEncryptingKey),
+			factory.Domain(),
+			factory.User(),
+			factory.Stream(),
 			factory.Host(),
 		)
 
@@ -234,6 +289,7 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Folder(),
 			factory.EncryptionKey(),
 			factory.ActivityStream(),
+			factory.Queue(),
 			factory.Host(),
 		)
 
@@ -250,6 +306,13 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Host(),
 		)
 
+		// Populate Invitation Service
+		factory.invitationService.Refresh(
+			factory.collection(CollectionInvitation),
+			factory.Domain(),
+			factory.Host(),
+		)
+
 		// Populate the JWT Key Service
 		factory.jwtService.Refresh(
 			factory.collection(CollectionJWT),
@@ -259,8 +322,11 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 		// Populate Mention Service
 		factory.mentionService.Refresh(
 			factory.collection(CollectionMention),
+			factory.Domain(),
 			factory.Rule(),
+			factory.Stream(),
 			factory.ActivityStream(),
+			factory.Queue(),
 			factory.Host(),
 		)
 
@@ -308,13 +374,16 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.collection(CollectionStream),
 			factory.Template(),
 			factory.StreamDraft(),
+			factory.StreamRevision(),
 			factory.Outbox(),
 			factory.Attachment(),
 			factory.ActivityStream(),
 			factory.Content(),
 			factory.EncryptionKey(),
 			factory.Follower(),
+			factory.Mention(),
 			factory.Rule(),
+			factory.SyndicationTarget(),
 			factory.User(),
 			factory.Host(),
 			factory.StreamUpdateChannel(),
@@ -326,6 +395,23 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Stream(),
 		)
 
+		// Populate StreamRevision Service
+		factory.revisionService.Refresh(
+			factory.collection(CollectionStreamRevision),
+			factory.Attachment(),
+		)
+
+		// Populate SyndicationTarget Service
+		factory.syndicationService.Refresh(
+			factory.collection(CollectionSyndicationTarget),
+			[]byte(domain.KeyEncryptingKey),
+			factory.Provider(),
+			factory.Stream(),
+			factory.Mention(),
+			factory.ActivityStream(),
+			factory.Queue(),
+		)
+
 		// Populate User Service
 		factory.userService.Refresh(
 			factory.collection(CollectionUser),
@@ -333,16 +419,34 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.collection(CollectionFollowing),
 			factory.collection(CollectionRule),
 			factory.Attachment(),
+			factory.DataExport(),
+			factory.DataImport(),
 			factory.Domain(),
 			factory.Email(),
 			factory.Folder(),
 			factory.Follower(),
+			factory.Following(),
+			factory.Group(),
+			factory.Inbox(),
+			factory.Invitation(),
 			factory.EncryptionKey(),
+			factory.Mention(),
+			factory.OAuthUserToken(),
+			factory.Outbox(),
+			factory.Response(),
 			factory.Rule(),
 			factory.Stream(),
+			factory.SyndicationTarget(),
+			factory.UserSession(),
+			factory.Queue(),
 			factory.Host(),
 		)
 
+		// Populate UserSession Service
+		factory.userSessionService.Refresh(
+			factory.collection(CollectionUserSession),
+		)
+
 		// Watch for updates to streams
 		if session, ok := factory.Session.(*mongodb.Session); ok {
 			if collection, ok := session.Collection("Stream").(*mongodb.Collection); ok {
@@ -357,6 +461,20 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 		domain,
 	)
 
+	// Re-Populate Key Encrypting Keys
+	// This is separate because keys are rotated without changing the database connection
+	factory.encryptionKeyService.SetKeyEncryptingKeys([]byte(domain.KeyEncryptingKey), []byte(domain.PreviousKeyEncryptingKey))
+	factory.jwtService.SetKeyEncryptingKeys([]byte(domain.KeyEncryptingKey), []byte(domain.PreviousKeyEncryptingKey))
+	factory.syndicationService.SetKeyEncryptingKeys([]byte(domain.KeyEncryptingKey), []byte(domain.PreviousKeyEncryptingKey))
+
+	// Re-Populate ActivityStream Service
+	// This is separate because the server-wide cache may change independently of this domain.
+	// Outbound requests are signed with the Domain/Application key (aka "authorized fetch")
+	factory.activityService.RefreshFromServer(
+		factory.activityCache,
+		factory.EncryptionKey(),
+	)
+
 	factory.config = domain
 	factory.providers = providers
 	return nil
@@ -376,8 +494,13 @@ func (factory *Factory) Close() {
 	factory.streamService.Close()
 	factory.followingService.Close()
 	factory.followerService.Close()
+	factory.syndicationService.Close()
 	factory.jwtService.Close()
 	factory.userService.Close()
+	factory.encryptionKeyService.Close()
+	factory.dataExportService.Close()
+	factory.dataImportService.Close()
+	factory.attachmentService.Close()
 }
 
 /******************************************
@@ -432,13 +555,16 @@ func (factory *Factory) Model(name string) (service.ModelService, error) {
 	case "activity":
 		return factory.Inbox(), nil
 
+	case "syndicationtarget":
+		return factory.SyndicationTarget(), nil
+
 	}
 
 	return nil, derp.NewInternalError("domain.Factory.Model", "Unknown model", name)
 }
 
 func (factory *Factory) ActivityStream() *service.ActivityStream {
-	return factory.activityService
+	return &factory.activityService
 }
 
 // Attachment returns a fully populated Attachment service
@@ -451,6 +577,16 @@ func (factory *Factory) Rule() *service.Rule {
 	return &factory.ruleService
 }
 
+// DataExport returns a fully populated DataExport service
+func (factory *Factory) DataExport() *service.DataExport {
+	return &factory.dataExportService
+}
+
+// DataImport returns a fully populated DataImport service
+func (factory *Factory) DataImport() *service.DataImport {
+	return &factory.dataImportService
+}
+
 // Domain returns a fully populated Domain service
 func (factory *Factory) Domain() *service.Domain {
 	return &factory.domainService
@@ -486,6 +622,11 @@ func (factory *Factory) Inbox() *service.Inbox {
 	return &factory.inboxService
 }
 
+// Invitation returns a fully populated Invitation service
+func (factory *Factory) Invitation() *service.Invitation {
+	return &factory.invitationService
+}
+
 // Mention returns a fully populated Mention service
 func (factory *Factory) Mention() *service.Mention {
 	return &factory.mentionService
@@ -516,6 +657,16 @@ func (factory *Factory) StreamDraft() *service.StreamDraft {
 	return &factory.streamDraftService
 }
 
+// StreamRevision returns a fully populated StreamRevision service
+func (factory *Factory) StreamRevision() *service.StreamRevision {
+	return &factory.revisionService
+}
+
+// SyndicationTarget returns a fully populated SyndicationTarget service
+func (factory *Factory) SyndicationTarget() *service.SyndicationTarget {
+	return &factory.syndicationService
+}
+
 // Response returns a fully populated Response service
 func (factory *Factory) Response() *service.Response {
 	return &factory.responseService
@@ -526,6 +677,11 @@ func (factory *Factory) User() *service.User {
 	return &factory.userService
 }
 
+// UserSession returns a fully populated UserSession service
+func (factory *Factory) UserSession() *service.UserSession {
+	return &factory.userSessionService
+}
+
 // Widget returns a fully populated Widget service
 func (factory *Factory) Widget() *service.Widget {
 	return factory.widgetService
@@ -578,6 +734,11 @@ func (factory *Factory) AttachmentCache() afero.Fs {
 	return factory.getSubFolder(factory.attachmentCache, factory.Hostname())
 }
 
+// DataExportFiles returns a reference to the Filesystem where personal data archives are stored
+func (factory *Factory) DataExportFiles() afero.Fs {
+	return factory.getSubFolder(factory.AttachmentOriginals(), "exports")
+}
+
 // getSubFolder guarantees that a subfolder exists within the provided afero.Fs, or panics
 func (factory *Factory) getSubFolder(base afero.Fs, path string) afero.Fs {
 
@@ -635,12 +796,13 @@ func (factory *Factory) Steranko() *steranko.Steranko {
 		service.NewSterankoUserService(factory.User(), factory.Email()),
 		factory.JWT(),
 		steranko.WithPasswordHasher(hash.BCrypt(15), hash.Plaintext{}),
+		steranko.WithPasswordRules(factory.Domain().Get().PasswordPolicy),
 	)
 }
 
 // LookupProvider returns a fully populated LookupProvider service
 func (factory *Factory) LookupProvider(userID primitive.ObjectID) form.LookupProvider {
-	return service.NewLookupProvider(factory.Theme(), factory.Group(), factory.Folder(), userID)
+	return service.NewLookupProvider(factory.Theme(), factory.Template(), factory.Group(), factory.Folder(), userID)
 }
 
 /******************************************
@@ -699,6 +861,9 @@ func (factory *Factory) ModelService(object data.Object) service.ModelService {
 	case *model.Stream:
 		return factory.Stream()
 
+	case *model.SyndicationTarget:
+		return factory.SyndicationTarget()
+
 	default:
 		return nil
 	}
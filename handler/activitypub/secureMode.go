@@ -0,0 +1,95 @@
+package activitypub
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSecureMode is route middleware that enforces secure mode on a User's ActivityPub collections.
+// The User is identified by the "userId" route parameter.
+func UserSecureMode(serverFactory *server.Factory) echo.MiddlewareFunc {
+
+	const location = "handler.activitypub.UserSecureMode"
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(ctx echo.Context) error {
+
+			factory, err := serverFactory.ByContext(ctx)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Unrecognized domain name")
+			}
+
+			// If secure mode is not enabled, then everyone is welcome.
+			if !factory.Config().SecureMode {
+				return next(ctx)
+			}
+
+			// Find the User who owns this collection.  Unknown Users are still checked
+			// against domain blocks, and the handler will return a "not found" error.
+			userID := primitive.NilObjectID
+			user := model.NewUser()
+
+			if err := factory.User().LoadByToken(ctx.Param("userId"), &user); err == nil {
+				userID = user.UserID
+			}
+
+			if err := ValidateRequest(factory, ctx.Request(), userID); err != nil {
+				return derp.Wrap(err, location, "Request not allowed")
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// StreamSecureMode is route middleware that enforces secure mode on a Stream's ActivityPub collections.
+// The Stream is identified by the "stream" route parameter.  Streams that are ActivityPub Actors
+// can always be read directly, so that other servers can retrieve their public keys.
+func StreamSecureMode(serverFactory *server.Factory) echo.MiddlewareFunc {
+
+	const location = "handler.activitypub.StreamSecureMode"
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(ctx echo.Context) error {
+
+			factory, err := serverFactory.ByContext(ctx)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Unrecognized domain name")
+			}
+
+			// If secure mode is not enabled, then everyone is welcome.
+			if !factory.Config().SecureMode {
+				return next(ctx)
+			}
+
+			// Find the User who owns this Stream.  Unknown Streams are still checked
+			// against domain blocks, and the handler will return a "not found" error.
+			userID := primitive.NilObjectID
+			stream := model.NewStream()
+
+			if err := factory.Stream().LoadByToken(ctx.Param("stream"), &stream); err == nil {
+				userID = stream.AttributedTo.UserID
+
+				// RULE: Actor documents are always public
+				if ctx.Path() == "/:stream/pub" {
+					if template, err := factory.Template().Load(stream.TemplateID); err == nil && template.Actor.NotNil() {
+						return next(ctx)
+					}
+				}
+			}
+
+			if err := ValidateRequest(factory, ctx.Request(), userID); err != nil {
+				return derp.Wrap(err, location, "Request not allowed")
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package activitypub
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/rosetta/list"
+	"github.com/benpate/sherlock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ValidateRequest enforces "secure mode" (aka "authorized fetch") on ActivityPub collections.
+// If the domain is running in secure mode, then every request must include a valid HTTP Signature,
+// and the signing Actor must not be blocked by the User (or by the Domain).  If secure mode is
+// not enabled, then all requests are allowed.
+func ValidateRequest(factory *domain.Factory, request *http.Request, userID primitive.ObjectID) error {
+
+	// If secure mode is not enabled, then everyone is welcome.
+	if !factory.Config().SecureMode {
+		return nil
+	}
+
+	filter := factory.Rule().Filter(userID, service.WithBlocksOnly())
+	return validateRequest(factory.ActivityStream(), request, filter.AllowSend)
+}
+
+// validateRequest verifies the HTTP Signature on a request, and confirms that the
+// signing Actor is allowed to read the collection.
+func validateRequest(activityService *service.ActivityStream, request *http.Request, allowSend func(string) bool) error {
+
+	const location = "handler.activitypub.validateRequest"
+
+	// RULE: Request must be signed
+	if request.Header.Get("Signature") == "" {
+		return derp.NewForbiddenError(location, "Secure Mode: Request must include an HTTP Signature")
+	}
+
+	// Verify the signature, collecting the ID of the Actor who signed it
+	actorID := ""
+	keyFinder := func(keyID string) (string, error) {
+		publicKeyPEM, ownerID, err := findPublicKey(activityService, keyID)
+		actorID = ownerID
+		return publicKeyPEM, err
+	}
+
+	// GET requests have no body, so Digest is not required
+	if err := sigs.Verify(
+		request,
+		keyFinder,
+		sigs.VerifierFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate),
+		sigs.VerifierIgnoreBodyDigest(),
+	); err != nil {
+		return derp.NewForbiddenError(location, "Secure Mode: Invalid HTTP Signature", err.Error())
+	}
+
+	// RULE: Blocked Actors (and Domains) cannot read this collection
+	if !allowSend(actorID) {
+		return derp.NewForbiddenError(location, "Secure Mode: Actor is blocked", actorID)
+	}
+
+	// Success.
+	return nil
+}
+
+// findPublicKey loads the public key (and the ID of the Actor who owns it) from the provided keyID.
+// Keys are usually embedded in an Actor document (e.g. https://example.com/@user#main-key)
+// but some servers publish them as separate documents that link back to their owner.
+func findPublicKey(activityService *service.ActivityStream, keyID string) (string, string, error) {
+
+	const location = "handler.activitypub.findPublicKey"
+
+	// Load the Actor (or standalone Key) document
+	actorID := list.First(keyID, '#')
+	actor, err := activityService.Load(actorID, sherlock.AsActor())
+
+	if err != nil {
+		return "", "", derp.Wrap(err, location, "Error loading Actor", keyID)
+	}
+
+	// If this is a standalone Key document, then load its owner
+	if !actor.IsActor() {
+		if owner := actor.Get("owner"); owner.NotNil() {
+			if actor, err = owner.Load(sherlock.AsActor()); err != nil {
+				return "", "", derp.Wrap(err, location, "Error loading Key owner", keyID)
+			}
+		}
+	}
+
+	// Search the Actor's public keys for the one that signed this request
+	for key := actor.PublicKey(); key.NotNil(); key = key.Tail() {
+		if key.ID() == keyID {
+			return key.PublicKeyPEM(), actor.ID(), nil
+		}
+	}
+
+	return "", "", derp.NewForbiddenError(location, "Public Key not found", keyID)
+}
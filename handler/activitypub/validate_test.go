@@ -0,0 +1,175 @@
+package activitypub
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateRequest_Disabled(t *testing.T) {
+
+	// Unsigned requests are fine when secure mode is not enabled
+	request, err := http.NewRequest(http.MethodGet, "https://local.test/@user/pub/outbox", nil)
+	require.Nil(t, err)
+	require.Nil(t, ValidateRequest(&domain.Factory{}, request, primitive.NewObjectID()))
+}
+
+func TestValidateRequest_Unsigned(t *testing.T) {
+
+	activityService, _ := testActivityService(t)
+	request := testRequest(t)
+
+	err := validateRequest(activityService, request, allowAll)
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(err))
+}
+
+func TestValidateRequest_Signed(t *testing.T) {
+
+	activityService, privateKey := testActivityService(t)
+	request := testRequest(t)
+	require.Nil(t, testSign(request, "https://remote.test/users/alice#main-key", privateKey))
+
+	require.Nil(t, validateRequest(activityService, request, allowAll))
+}
+
+func TestValidateRequest_WrongKey(t *testing.T) {
+
+	activityService, _ := testActivityService(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	request := testRequest(t)
+	require.Nil(t, testSign(request, "https://remote.test/users/alice#main-key", otherKey))
+
+	err = validateRequest(activityService, request, allowAll)
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(err))
+}
+
+func TestValidateRequest_Blocked(t *testing.T) {
+
+	activityService, privateKey := testActivityService(t)
+	request := testRequest(t)
+	require.Nil(t, testSign(request, "https://remote.test/users/alice#main-key", privateKey))
+
+	blocked := func(actorID string) bool {
+		return actorID != "https://remote.test/users/alice"
+	}
+
+	err := validateRequest(activityService, request, blocked)
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(err))
+}
+
+func TestFindPublicKey_Embedded(t *testing.T) {
+
+	activityService, privateKey := testActivityService(t)
+
+	publicKeyPEM, actorID, err := findPublicKey(activityService, "https://remote.test/users/alice#main-key")
+	require.Nil(t, err)
+	require.Equal(t, sigs.EncodePublicPEM(privateKey), publicKeyPEM)
+	require.Equal(t, "https://remote.test/users/alice", actorID)
+}
+
+func TestFindPublicKey_Standalone(t *testing.T) {
+
+	activityService, privateKey := testActivityService(t)
+
+	publicKeyPEM, actorID, err := findPublicKey(activityService, "https://remote.test/keys/bob")
+	require.Nil(t, err)
+	require.Equal(t, sigs.EncodePublicPEM(privateKey), publicKeyPEM)
+	require.Equal(t, "https://remote.test/users/bob", actorID)
+}
+
+func TestFindPublicKey_NotFound(t *testing.T) {
+
+	activityService, _ := testActivityService(t)
+
+	_, _, err := findPublicKey(activityService, "https://remote.test/users/alice#other-key")
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(err))
+
+	_, _, err = findPublicKey(activityService, "https://remote.test/users/missing#main-key")
+	require.NotNil(t, err)
+}
+
+/******************************************
+ * Test Helpers
+ ******************************************/
+
+// testClient is an in-memory streams.Client that serves a fixed set of documents
+type testClient map[string]mapof.Any
+
+func (client testClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	if value, ok := client[uri]; ok {
+		return streams.NewDocument(value, streams.WithClient(client)), nil
+	}
+
+	return streams.NilDocument(), derp.NewNotFoundError("testClient.Load", "Document not found", uri)
+}
+
+// testActivityService returns an ActivityStream service that knows about two remote Actors.
+// "alice" embeds her public key, and "bob" publishes his key as a separate document.
+func testActivityService(t *testing.T) (*service.ActivityStream, *rsa.PrivateKey) {
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	publicKeyPEM := sigs.EncodePublicPEM(privateKey)
+
+	client := testClient{
+		"https://remote.test/users/alice": {
+			"id":   "https://remote.test/users/alice",
+			"type": "Person",
+			"publicKey": mapof.Any{
+				"id":           "https://remote.test/users/alice#main-key",
+				"owner":        "https://remote.test/users/alice",
+				"publicKeyPem": publicKeyPEM,
+			},
+		},
+		"https://remote.test/keys/bob": {
+			"id":           "https://remote.test/keys/bob",
+			"owner":        "https://remote.test/users/bob",
+			"publicKeyPem": publicKeyPEM,
+		},
+		"https://remote.test/users/bob": {
+			"id":   "https://remote.test/users/bob",
+			"type": "Person",
+			"publicKey": mapof.Any{
+				"id":           "https://remote.test/keys/bob",
+				"owner":        "https://remote.test/users/bob",
+				"publicKeyPem": publicKeyPEM,
+			},
+		},
+	}
+
+	activityService := service.NewActivityStream()
+	activityService.Refresh(client, nil, nil)
+
+	return &activityService, privateKey
+}
+
+// testRequest returns an (unsigned) request for a local collection
+func testRequest(t *testing.T) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, "https://local.test/@user/pub/outbox", http.NoBody)
+	require.Nil(t, err)
+	return request
+}
+
+// testSign signs a request the way remote servers sign GET requests (with no body Digest)
+func testSign(request *http.Request, keyID string, privateKey *rsa.PrivateKey) error {
+	return sigs.Sign(request, keyID, privateKey, sigs.SignerFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate))
+}
+
+// allowAll is a block filter that allows every Actor
+func allowAll(string) bool {
+	return true
+}
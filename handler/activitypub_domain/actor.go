@@ -0,0 +1,62 @@
+package activitypub_domain
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetJSONLD returns the Application Actor for this Domain. This Actor
+// is used to sign outbound requests (aka "authorized fetch") that are
+// not made on behalf of a specific User or Stream.
+func GetJSONLD(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.activitypub_domain.GetJSONLD"
+
+	return func(ctx echo.Context) error {
+
+		// Validate the domain name
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized domain name")
+		}
+
+		// Try to load the Domain key from the database
+		keyService := factory.EncryptionKey()
+		key := model.NewEncryptionKey()
+
+		if err := keyService.LoadByParentID(model.EncryptionKeyTypeDomain, primitive.NilObjectID, &key); err != nil {
+			return derp.Wrap(err, location, "Error loading encryption key for domain")
+		}
+
+		// Build the Actor document
+		actorID := keyService.OwnerID(&key)
+
+		result := mapof.Any{
+			vocab.AtContext:                 []any{vocab.NamespaceActivityStreams, "https://w3id.org/security/v1"},
+			vocab.PropertyID:                actorID,
+			vocab.PropertyType:              vocab.ActorTypeApplication,
+			vocab.PropertyPreferredUsername: factory.Hostname(),
+			vocab.PropertyName:              factory.Domain().Get().Label,
+			vocab.PropertyURL:               factory.Host(),
+			vocab.PropertyInbox:             actorID + "/inbox",
+			vocab.PropertyOutbox:            actorID + "/outbox",
+		}
+
+		// Add the Domain's public keys
+		if err := keyService.JSONLD(model.EncryptionKeyTypeDomain, primitive.NilObjectID, result); err != nil {
+			return derp.Wrap(err, location, "Error loading public keys for domain")
+		}
+
+		// Return the Actor in JSON-LD format
+		ctx.Response().Header().Set(vocab.ContentType, vocab.ContentTypeActivityPub)
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
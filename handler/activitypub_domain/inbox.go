@@ -0,0 +1,17 @@
+package activitypub_domain
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/labstack/echo/v4"
+)
+
+// PostInbox accepts (and ignores) all messages sent to the Application Actor.
+// The Application Actor only exists to sign outbound requests, so it does not
+// follow anyone, or act on any incoming activities.
+func PostInbox(serverFactory *server.Factory) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusAccepted)
+	}
+}
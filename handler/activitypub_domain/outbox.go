@@ -0,0 +1,20 @@
+package activitypub_domain
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/labstack/echo/v4"
+)
+
+// GetOutboxCollection returns an empty outbox for the Application Actor,
+// which never publishes any activities of its own.
+func GetOutboxCollection(serverFactory *server.Factory) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		result := streams.NewOrderedCollection()
+		ctx.Response().Header().Set(vocab.ContentType, vocab.ContentTypeActivityPub)
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
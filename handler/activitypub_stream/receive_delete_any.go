@@ -19,6 +19,12 @@ func DeleteAny(context Context, activity streams.Document) error {
 	const location = "handler.activityPub_stream.DeleteAny"
 	log.Trace().Str("activityType", activity.Type()).Msg(location)
 
+	// Actors usually delete themselves by ID only (with no type information)
+	// so route these to the Actor handler instead.
+	if (activity.Type() == vocab.ActivityTypeDelete) && (activity.Actor().ID() == activity.Object().ID()) {
+		return DeleteActor(context, activity)
+	}
+
 	// Try to find the message in the cache
 	outboxService := context.factory.Outbox()
 	message := model.NewOutboxMessage()
@@ -7,21 +7,36 @@ import (
 )
 
 func init() {
-	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypePerson, func(context Context, activity streams.Document) error {
+	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeApplication, DeleteActor)
+	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeGroup, DeleteActor)
+	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeOrganization, DeleteActor)
+	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypePerson, DeleteActor)
+	streamRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeService, DeleteActor)
+}
+
+// DeleteActor removes a remote Actor from the cache, and removes
+// it from the Stream's list of Followers.
+func DeleteActor(context Context, activity streams.Document) error {
+
+	const location = "handler.activityPub_stream.DeleteActor"
+
+	actorID := activity.Actor().ID()
 
-		const location = "handler.activityPub_stream.DeletePerson"
+	// RULE: Actors can only delete themselves, not other actors
+	if actorID != activity.Object().ID() {
+		return derp.NewForbiddenError(location, "Actor and Object must be the same", actorID, activity.Object().ID())
+	}
 
-		// RULE: Actors can only delete themselves, not other actors
-		if activity.Actor().ID() != activity.Object().ID() {
-			return derp.NewForbiddenError(location, "Actor and Object must be the same", activity.Actor().ID(), activity.Object().ID())
-		}
+	// Delete from the cache
+	if err := context.factory.ActivityStream().Delete(actorID); err != nil {
+		return derp.Wrap(err, location, "Error deleting actor from cache", actorID)
+	}
 
-		// Delete from the cache
-		if err := context.factory.ActivityStream().Delete(activity.Object().ID()); err != nil {
-			return derp.Wrap(err, location, "Error deleting stream", activity.Object().ID())
-		}
+	// Remove Follower records
+	if err := context.factory.Follower().DeleteByActor(context.stream.StreamID, actorID); err != nil {
+		return derp.Wrap(err, location, "Error deleting follower records", actorID)
+	}
 
-		// Voila!
-		return nil
-	})
+	// Voila!
+	return nil
 }
@@ -0,0 +1,46 @@
+package activitypub_stream
+
+import (
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/sherlock"
+)
+
+func init() {
+	streamRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeApplication, UpdateActor)
+	streamRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeGroup, UpdateActor)
+	streamRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeOrganization, UpdateActor)
+	streamRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypePerson, UpdateActor)
+	streamRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeService, UpdateActor)
+}
+
+// UpdateActor refreshes the cached copy of a remote Actor, along
+// with the Stream's Follower records that include its name and avatar.
+func UpdateActor(context Context, activity streams.Document) error {
+
+	const location = "handler.activityPub_stream.UpdateActor"
+
+	actorID := activity.Actor().ID()
+
+	// RULE: Actors can only update themselves, not other actors
+	if actorID != activity.Object().ID() {
+		return derp.NewForbiddenError(location, "Actor and Object must be the same", actorID, activity.Object().ID())
+	}
+
+	// Reload the Actor from its home server, replacing the cached value
+	actor, err := context.factory.ActivityStream().Load(actorID, sherlock.AsActor(), ascache.WithForceReload())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error reloading actor", actorID)
+	}
+
+	// Update Follower records
+	if err := context.factory.Follower().RefreshActor(context.stream.StreamID, actor); err != nil {
+		return derp.Wrap(err, location, "Error refreshing follower records", actorID)
+	}
+
+	// Voila!
+	return nil
+}
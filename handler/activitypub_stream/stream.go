@@ -7,7 +7,6 @@ import (
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/vocab"
-	"github.com/benpate/rosetta/mapof"
 	"github.com/labstack/echo/v4"
 )
 
@@ -26,26 +25,17 @@ func GetJSONLD(serverFactory *server.Factory) echo.HandlerFunc {
 
 		// If this Stream is not an Actor, then just return a standard JSON-LD response.
 		if actor.IsNil() {
+
 			jsonld := streamService.JSONLD(&stream)
 			ctx.Response().Header().Set("Content-Type", vocab.ContentTypeActivityPub)
 			return ctx.JSON(http.StatusOK, jsonld)
 		}
 
-		// Try to locate the domain
-		// Try to load the Encryption Key for this Actor
-		keyService := factory.EncryptionKey()
-		key := model.NewEncryptionKey()
-		if err := keyService.LoadByParentID(model.EncryptionKeyTypeStream, stream.StreamID, &key); err != nil {
-			return derp.Wrap(err, location, "Error loading Public Key", stream.StreamID)
-		}
-
-		// Combine the Actor and the Public Key
+		// Combine the Actor and its Public Keys
 		result := actor.JSONLD(&stream)
-		result[vocab.PropertyPublicKey] = mapof.Any{
-			vocab.PropertyID:   stream.Permalink() + "#main-key",
-			vocab.PropertyType: "Key",
-			"owner":            stream.Permalink(),
-			"publicKeyPem":     key.PublicPEM,
+
+		if err := factory.EncryptionKey().JSONLD(model.EncryptionKeyTypeStream, stream.StreamID, result); err != nil {
+			return derp.Wrap(err, location, "Error loading Public Keys", stream.StreamID)
 		}
 
 		// Return an ActivityPub response
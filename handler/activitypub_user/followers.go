@@ -3,14 +3,34 @@ package activitypub_user
 import (
 	"net/http"
 
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
 	"github.com/labstack/echo/v4"
 )
 
 func GetFollowersCollection(serverFactory *server.Factory) echo.HandlerFunc {
 
+	const location = "handler.activitypub_user.GetFollowersCollection"
+
 	return func(ctx echo.Context) error {
+
+		// Validate the domain name
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized domain name")
+		}
+
+		// Try to load the User from the database
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByToken(ctx.Param("userId"), &user); err != nil {
+			return derp.NewNotFoundError(location, "User not found", err)
+		}
+
 		result := streams.NewOrderedCollection()
 		ctx.Response().Header().Set("Content-Type", "application/activity+json")
 		return ctx.JSON(http.StatusOK, result)
@@ -13,7 +13,25 @@ import (
 
 func GetFollowingCollection(serverFactory *server.Factory) echo.HandlerFunc {
 
+	const location = "handler.activitypub_user.GetFollowingCollection"
+
 	return func(ctx echo.Context) error {
+
+		// Validate the domain name
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized domain name")
+		}
+
+		// Try to load the User from the database
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByToken(ctx.Param("userId"), &user); err != nil {
+			return derp.NewNotFoundError(location, "User not found", err)
+		}
+
 		result := streams.NewOrderedCollection()
 		ctx.Response().Header().Set("Content-Type", "application/activity+json")
 		return ctx.JSON(http.StatusOK, result)
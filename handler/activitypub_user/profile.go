@@ -7,7 +7,6 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/vocab"
-	"github.com/benpate/rosetta/mapof"
 	"github.com/labstack/echo/v4"
 )
 
@@ -15,21 +14,11 @@ func RenderProfileJSONLD(context echo.Context, factory *domain.Factory, user *mo
 
 	const location = "handler.activitypub.buildProfileJSONLD"
 
-	// Try to load the key from the Datbase
-	keyService := factory.EncryptionKey()
-	key := model.NewEncryptionKey()
+	// Combine the Profile and the User's public keys
+	userJSON, err := factory.User().ActivityPubJSONLD(user)
 
-	if err := keyService.LoadByParentID(model.EncryptionKeyTypeUser, user.UserID, &key); err != nil {
-		return derp.Wrap(err, location, "Error loading encryption key for user", user.UserID)
-	}
-
-	// Combine the Profile and the EncryptionKey
-	userJSON := user.GetJSONLD()
-	userJSON[vocab.PropertyPublicKey] = mapof.Any{
-		vocab.PropertyID:   user.ActivityPubURL() + "#main-key",
-		vocab.PropertyType: "Key",
-		"owner":            user.ActivityPubURL(),
-		"publicKeyPem":     key.PublicPEM,
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading encryption keys for user", user.UserID)
 	}
 
 	// Return the user's profile in JSON-LD format
@@ -2,6 +2,7 @@ package activitypub_user
 
 import (
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
@@ -32,7 +33,14 @@ func receive_CreateOrUpdate(context Context, activity streams.Document) error {
 	}
 
 	// Guarantee that we can load the object from the Interwebs.
-	if _, err := object.Load(); err != nil {
+	// Updates always reload the object so that the cache keeps the previous version.
+	loadOptions := []any{}
+
+	if activity.Type() == vocab.ActivityTypeUpdate {
+		loadOptions = append(loadOptions, ascache.WithForceReload())
+	}
+
+	if _, err := object.Load(loadOptions...); err != nil {
 		return derp.Wrap(err, location, "Error loading activity.Object")
 	}
 
@@ -9,6 +9,12 @@ import (
 func init() {
 	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.Any, func(context Context, document streams.Document) error {
 
+		// Actors usually delete themselves by ID only (with no type information)
+		// so route these to the Actor handler instead.
+		if document.Actor().ID() == document.Object().ID() {
+			return receive_DeleteActor(context, document)
+		}
+
 		// Force reload of the cache.  If the document is still there, then it will be refreshed.
 		// If the document is gone, then it will be removed from the cache.
 		_, _ = context.factory.ActivityStream().Load(document.Object().ID(), ascache.WithForceReload())
@@ -4,23 +4,45 @@ import (
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
+	"github.com/rs/zerolog/log"
 )
 
 func init() {
-	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypePerson, func(context Context, activity streams.Document) error {
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeApplication, receive_DeleteActor)
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeGroup, receive_DeleteActor)
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeOrganization, receive_DeleteActor)
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypePerson, receive_DeleteActor)
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActorTypeService, receive_DeleteActor)
+}
+
+// receive_DeleteActor removes a remote Actor from the cache, and removes all
+// Followers, Followings, and inbox Messages that are connected to it.
+func receive_DeleteActor(context Context, activity streams.Document) error {
+
+	const location = "handler.activityPub_user.DeletePerson"
+
+	actorID := activity.Actor().ID()
+	log.Debug().Str("actor", actorID).Msg("User Inbox: Received Actor Delete")
+
+	// RULE: Actors can only delete themselves, not other actors
+	if actorID != activity.Object().ID() {
+		return derp.NewForbiddenError(location, "Actor and Object must be the same", actorID, activity.Object().ID())
+	}
 
-		const location = "handler.activityPub_user.DeletePerson"
+	// Delete from the cache
+	if err := context.factory.ActivityStream().Delete(actorID); err != nil {
+		return derp.Wrap(err, location, "Error deleting actor from cache", actorID)
+	}
 
-		// RULE: Actors can only delete themselves, not other actors
-		if activity.Actor().ID() != activity.Object().ID() {
-			return derp.NewForbiddenError(location, "Actor and Object must be the same", activity.Actor().ID(), activity.Object().ID())
-		}
+	// Remove Following records (and their messages)
+	if err := context.factory.Following().DeleteByActor(context.user.UserID, actorID); err != nil {
+		return derp.Wrap(err, location, "Error deleting following records", actorID)
+	}
 
-		// Delete from the cache
-		if err := context.factory.ActivityStream().Delete(activity.Object().ID()); err != nil {
-			return derp.Wrap(err, location, "Error deleting stream", activity.Object().ID())
-		}
+	// Remove Follower records
+	if err := context.factory.Follower().DeleteByActor(context.user.UserID, actorID); err != nil {
+		return derp.Wrap(err, location, "Error deleting follower records", actorID)
+	}
 
-		return nil
-	})
+	return nil
 }
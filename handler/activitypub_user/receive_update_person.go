@@ -0,0 +1,53 @@
+package activitypub_user
+
+import (
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/sherlock"
+	"github.com/rs/zerolog/log"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeApplication, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeGroup, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeOrganization, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypePerson, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeService, receive_UpdateActor)
+}
+
+// receive_UpdateActor refreshes the cached copy of a remote Actor, along with
+// every local record that includes a copy of the Actor's name or avatar.
+func receive_UpdateActor(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_UpdateActor"
+
+	actorID := activity.Actor().ID()
+	log.Debug().Str("actor", actorID).Msg("User Inbox: Received Actor Update")
+
+	// RULE: Actors can only update themselves, not other actors
+	if actorID != activity.Object().ID() {
+		return derp.NewForbiddenError(location, "Actor and Object must be the same", actorID, activity.Object().ID())
+	}
+
+	// Reload the Actor from its home server, replacing the cached value
+	actor, err := context.factory.ActivityStream().Load(actorID, sherlock.AsActor(), ascache.WithForceReload())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error reloading actor", actorID)
+	}
+
+	// Update Following records (and their messages)
+	if err := context.factory.Following().RefreshActor(context.user.UserID, actor); err != nil {
+		return derp.Wrap(err, location, "Error refreshing following records", actorID)
+	}
+
+	// Update Follower records
+	if err := context.factory.Follower().RefreshActor(context.user.UserID, actor); err != nil {
+		return derp.Wrap(err, location, "Error refreshing follower records", actorID)
+	}
+
+	// Lookin' good.
+	return nil
+}
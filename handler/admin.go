@@ -103,6 +103,19 @@ func buildAdmin_GetBuilder(factory *domain.Factory, ctx *steranko.Context, templ
 
 		return builder.NewRule(factory, ctx.Request(), ctx.Response(), &rule, template, actionID)
 
+	case "mention":
+
+		mentionService := factory.Mention()
+		mention := model.NewMention()
+
+		if !objectID.IsZero() {
+			if err := mentionService.LoadByID(objectID, &mention); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading Mention", objectID)
+			}
+		}
+
+		return builder.NewMention(factory, ctx.Request(), ctx.Response(), &mention, template, actionID)
+
 	case "domain":
 		return builder.NewDomain(factory, ctx.Request(), ctx.Response(), template, actionID)
 
@@ -143,6 +156,6 @@ func buildAdmin_GetBuilder(factory *domain.Factory, ctx *steranko.Context, templ
 		return builder.NewUser(factory, ctx.Request(), ctx.Response(), template, &user, actionID)
 
 	default:
-		return nil, derp.NewNotFoundError(location, "Template MODEL must be one of: 'rule', 'domain', 'group', 'stream', or 'user'", template.Model)
+		return nil, derp.NewNotFoundError(location, "Template MODEL must be one of: 'rule', 'mention', 'domain', 'group', 'stream', or 'user'", template.Model)
 	}
 }
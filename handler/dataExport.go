@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// GetDataExport generates an echo.HandlerFunc that handles GET /@me/export requests.
+// It downloads the signed-in User's personal data archive, if the code in the URL is still valid.
+func GetDataExport(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetDataExport"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		user := model.NewUser()
+
+		if err := loadSignedInUser(ctx.(*steranko.Context), factory.User(), &user); err != nil {
+			return derp.Wrap(err, location, "Error loading signed-in user")
+		}
+
+		file, err := factory.DataExport().Open(&user, ctx.QueryParam("code"))
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error opening archive")
+		}
+
+		defer file.Close()
+
+		ctx.Response().Header().Set("Content-Disposition", `attachment; filename="`+user.Username+`-archive.zip"`)
+		ctx.Response().Header().Set("Cache-Control", "private, no-store")
+		return ctx.Stream(http.StatusOK, "application/zip", file)
+	}
+}
@@ -3,11 +3,15 @@ package handler
 import (
 	"bytes"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/EmissarySocial/emissary/builder"
 	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/labstack/echo/v4"
+	"willnorris.com/go/microformats"
 )
 
 // buildHTML collects the logic to build complete vs. partial HTML pages.
@@ -30,6 +34,11 @@ func buildHTML(factory *domain.Factory, ctx echo.Context, b builder.Builder, act
 
 	// Partial page requests can be completed here.
 	if b.IsPartialRequest() || status.FullPage {
+
+		if isMicroformatsRequest(ctx, actionMethod) && strings.HasPrefix(status.GetContentType(), model.MimeTypeHTML) {
+			return buildMicroformatsJSON(ctx, b, partialPage.String())
+		}
+
 		return ctx.HTML(status.GetStatusCode(), partialPage.String())
 	}
 
@@ -42,5 +51,34 @@ func buildHTML(factory *domain.Factory, ctx echo.Context, b builder.Builder, act
 		return derp.Wrap(err, location, "Error building full-page content")
 	}
 
+	if isMicroformatsRequest(ctx, actionMethod) {
+		return buildMicroformatsJSON(ctx, b, fullPage.String())
+	}
+
 	return ctx.HTML(http.StatusOK, fullPage.String())
 }
+
+// isMicroformatsRequest returns TRUE if the client has requested the parsed
+// microformats of a page (?format=mf2json) instead of the page itself.
+func isMicroformatsRequest(ctx echo.Context, actionMethod builder.ActionMethod) bool {
+	return (actionMethod == builder.ActionMethodGet) && (ctx.QueryParam("format") == "mf2json")
+}
+
+// buildMicroformatsJSON parses the microformats from a rendered HTML page and returns them as mf2 JSON.
+// https://microformats.org/wiki/microformats2-parsing
+func buildMicroformatsJSON(ctx echo.Context, b builder.Builder, html string) error {
+
+	const location = "handler.buildMicroformatsJSON"
+
+	// Relative URLs in the page are resolved against the requested URL
+	pageURL := b.Host() + b.URL()
+	baseURL, err := url.Parse(pageURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error parsing page URL", pageURL)
+	}
+
+	result := microformats.Parse(strings.NewReader(html), baseURL)
+
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -35,23 +35,45 @@ func PostAccount(serverFactory *server.Factory) func(model.Authorization, txn.Po
 			return object.Token{}, derp.NewForbiddenError(location, "Signup is not allowed on this domain")
 		}
 
+		// The Mastodon API cannot include an invitation code
+		if domainService.Get().SignupForm.IsInviteOnly() {
+			return object.Token{}, derp.NewForbiddenError(location, "An invitation is required to sign up on this domain")
+		}
+
 		if !t.Agreement {
 			return object.Token{}, derp.NewForbiddenError(location, "You must agree to the terms of service")
 		}
 
-		// Create a new User account
+		// RULE: Usernames must be unique, including those reserved by deleted accounts
 		userService := factory.User()
+
+		if taken, err := userService.IsUsernameTaken(t.Username); err != nil {
+			return object.Token{}, derp.Wrap(err, location, "Error searching for username")
+		} else if taken {
+			return object.Token{}, derp.NewBadRequestError(location, "Username is already in use")
+		}
+
+		// Create a new User account
 		user := model.NewUser()
+		user.DisplayName = t.Username
 		user.Username = t.Username
 		user.EmailAddress = t.Email
 		user.Locale = t.Locale
 		user.SignupNote = t.Reason
-		user.SetPassword(t.Password)
 
-		if err := userService.Save(&user, "Created via Mastodon API"); err != nil {
+		if err := userService.Register(&user, t.Password, nil, "Created via Mastodon API"); err != nil {
 			return object.Token{}, derp.Wrap(err, location, "Error saving user")
 		}
 
+		// Users who must confirm their email address, or wait for approval, cannot sign in yet
+		if user.EmailConfirmation.IsPending() {
+			return object.Token{}, derp.NewForbiddenError(location, "Please confirm your email address before signing in")
+		}
+
+		if user.IsPending {
+			return object.Token{}, derp.NewForbiddenError(location, "Your account is waiting for approval")
+		}
+
 		// Create a new OAuth token
 		oauthUserTokenService := factory.OAuthUserToken()
 		token, err := oauthUserTokenService.CreateFromUser(&user, auth.ClientID, auth.Scope)
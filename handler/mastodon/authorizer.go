@@ -45,6 +45,16 @@ func Authorizer(serverFactory *server.Factory) toot.Authorizer[model.Authorizati
 			return model.Authorization{}, derp.NewForbiddenError(location, "Invalid token: Invalid Claims", token)
 		}
 
+		// IndieAuth tokens are limited to their scopes, so they cannot use the Mastodon API
+		if authorization.IndieAuth {
+			return model.Authorization{}, derp.NewForbiddenError(location, "Invalid token: IndieAuth tokens cannot use the Mastodon API")
+		}
+
+		// Confirm that the token has not been revoked
+		if err := factory.OAuthUserToken().ValidateToken(jwtService.TokenString(request)); err != nil {
+			return model.Authorization{}, derp.Wrap(err, location, "Invalid token: Revoked")
+		}
+
 		// Return the token to the caller.
 		return *authorization, nil
 	}
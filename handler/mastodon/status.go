@@ -39,7 +39,7 @@ func PostStatus(serverFactory *server.Factory) func(model.Authorization, txn.Pos
 		stream.AttributedTo = user.PersonLink()
 		stream.SocialRole = vocab.ObjectTypeNote
 		stream.InReplyTo = transaction.InReplyToID
-		stream.Label = transaction.SpoilerText
+		stream.SetContentWarning(transaction.SpoilerText, transaction.Sensitive)
 
 		if scheduledAt, err := iso8601.ParseString(transaction.ScheduledAt); err == nil {
 			stream.PublishDate = scheduledAt.Unix()
@@ -390,8 +390,7 @@ func PutStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutS
 
 		// Edit stream values
 		stream.Content.Raw = t.Status
-		stream.Label = t.SpoilerText
-		// t.Sensitive
+		stream.SetContentWarning(t.SpoilerText, t.Sensitive)
 		// t.Language
 
 		// t.MediaIDs
@@ -409,8 +408,45 @@ func PutStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutS
 // https://docs.joinmastodon.org/methods/statuses/#history
 func GetStatus_History(serverFactory *server.Factory) func(model.Authorization, txn.GetStatus_History) ([]object.StatusEdit, error) {
 
+	const location = "handler.mastodon.GetStatus_History"
+
 	return func(auth model.Authorization, t txn.GetStatus_History) ([]object.StatusEdit, error) {
-		return []object.StatusEdit{}, nil
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Load the stream from the database
+		streamService := factory.Stream()
+		stream := model.NewStream()
+
+		if err := streamService.LoadByURL(t.ID, &stream); err != nil {
+			return nil, derp.Wrap(err, location, "Error loading stream")
+		}
+
+		// Validate permissions
+		if err := streamService.UserCan(&auth, &stream, "view"); err != nil {
+			return nil, derp.NewForbiddenError(location, "User is not authorized to view this stream")
+		}
+
+		// Load all revisions for this stream
+		revisions, err := factory.StreamRevision().QueryByStream(stream.StreamID)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error loading revisions")
+		}
+
+		// Mastodon lists revisions in chronological order
+		result := make([]object.StatusEdit, len(revisions))
+
+		for index, revision := range revisions {
+			result[len(revisions)-index-1] = revision.Toot()
+		}
+
+		return result, nil
 	}
 }
 
@@ -439,7 +475,7 @@ func GetStatus_Source(serverFactory *server.Factory) func(model.Authorization, t
 		result := object.StatusSource{
 			ID:          stream.ActivityPubURL(),
 			Text:        stream.Content.Raw,
-			SpoilerText: stream.Label,
+			SpoilerText: stream.ContentWarning(),
 		}
 
 		return result, nil
@@ -0,0 +1,812 @@
+package handler
+
+import (
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/micropub"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/list"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetMicropub handles Micropub queries (config, source, and syndicate-to)
+// https://www.w3.org/TR/micropub/#querying
+func GetMicropub(fm *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetMicropub"
+
+	return func(ctx echo.Context) error {
+
+		// Try to locate the requested domain
+		factory, err := fm.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Queries require a valid access token, but no specific scope
+		authorization, err := getMicropubAuthorization(factory, ctx.Request(), ctx.QueryParam("access_token"), "")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid access token")
+		}
+
+		switch ctx.QueryParam("q") {
+
+		case "config":
+
+			syndicateTo, err := getMicropubSyndicateTo(factory, authorization.UserID)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error loading syndication targets")
+			}
+
+			return ctx.JSON(http.StatusOK, mapof.Any{
+				"media-endpoint": factory.Host() + "/.micropub/media",
+				"syndicate-to":   syndicateTo,
+				"q":              []string{"config", "source", "syndicate-to"},
+				"post-types": []mapof.String{
+					{"type": "note", "name": "Note"},
+					{"type": "article", "name": "Article"},
+					{"type": "reply", "name": "Reply"},
+					{"type": "photo", "name": "Photo"},
+				},
+			})
+
+		case "syndicate-to":
+
+			syndicateTo, err := getMicropubSyndicateTo(factory, authorization.UserID)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error loading syndication targets")
+			}
+
+			return ctx.JSON(http.StatusOK, mapof.Any{
+				"syndicate-to": syndicateTo,
+			})
+
+		case "source":
+
+			// Load the requested Stream
+			streamService := factory.Stream()
+			stream := model.NewStream()
+
+			if err := streamService.LoadByURL(ctx.QueryParam("url"), &stream); err != nil {
+				return derp.Wrap(err, location, "Error loading stream", ctx.QueryParam("url"))
+			}
+
+			if err := streamService.UserCan(&authorization, &stream, "view"); err != nil {
+				return derp.Wrap(err, location, "User is not authorized to view this stream", derp.WithForbidden())
+			}
+
+			properties := getMicropubProperties(factory, &stream)
+
+			// Return only the requested properties, if any were specified
+			if names := ctx.QueryParams()["properties[]"]; len(names) > 0 {
+
+				result := micropub.NewProperties()
+				for _, name := range names {
+					if properties.Has(name) {
+						result[name] = properties[name]
+					}
+				}
+
+				return ctx.JSON(http.StatusOK, mapof.Any{"properties": result})
+			}
+
+			return ctx.JSON(http.StatusOK, mapof.Any{
+				"type":       []string{"h-entry"},
+				"properties": properties,
+			})
+		}
+
+		return derp.NewBadRequestError(location, "Unsupported query", ctx.QueryParam("q"))
+	}
+}
+
+// PostMicropub handles Micropub create, update, delete, and undelete requests
+// https://www.w3.org/TR/micropub/
+func PostMicropub(fm *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostMicropub"
+
+	return func(ctx echo.Context) error {
+
+		// Try to locate the requested domain
+		factory, err := fm.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Parse the request body
+		request, err := micropub.Parse(ctx.Request())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Micropub request")
+		}
+
+		// Validate the access token
+		authorization, err := getMicropubAuthorization(factory, ctx.Request(), request.AccessToken, request.Action)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid access token")
+		}
+
+		// Load the User who is making this request
+		user := model.NewUser()
+		if err := factory.User().LoadByID(authorization.UserID, &user); err != nil {
+			return derp.Wrap(err, location, "Error loading user", authorization.UserID)
+		}
+
+		switch request.Action {
+
+		case micropub.ActionCreate:
+			stream, err := postMicropub_Create(factory, &authorization, &user, request)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error creating stream")
+			}
+
+			ctx.Response().Header().Set("Location", stream.URL)
+			return ctx.NoContent(http.StatusCreated)
+
+		case micropub.ActionUpdate:
+			if err := postMicropub_Update(factory, &authorization, &user, request); err != nil {
+				return derp.Wrap(err, location, "Error updating stream")
+			}
+
+			return ctx.NoContent(http.StatusNoContent)
+
+		case micropub.ActionDelete:
+			if err := postMicropub_Delete(factory, &authorization, &user, request); err != nil {
+				return derp.Wrap(err, location, "Error deleting stream")
+			}
+
+			return ctx.NoContent(http.StatusNoContent)
+
+		case micropub.ActionUndelete:
+			if err := postMicropub_Undelete(factory, &authorization, &user, request); err != nil {
+				return derp.Wrap(err, location, "Error restoring stream")
+			}
+
+			return ctx.NoContent(http.StatusNoContent)
+		}
+
+		return derp.NewBadRequestError(location, "Unsupported action", request.Action)
+	}
+}
+
+// PostMicropubMedia handles file uploads to the Micropub media endpoint.
+// Uploaded files are held by the User until they are claimed by a new post.
+// https://www.w3.org/TR/micropub/#media-endpoint
+func PostMicropubMedia(fm *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostMicropubMedia"
+
+	return func(ctx echo.Context) error {
+
+		// Try to locate the requested domain
+		factory, err := fm.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Validate the access token
+		authorization, err := getMicropubAuthorization(factory, ctx.Request(), ctx.FormValue("access_token"), "media")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid access token")
+		}
+
+		// Get the uploaded file
+		fileHeader, err := ctx.FormFile("file")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Missing 'file' in request", derp.WithBadRequest())
+		}
+
+		// Pending attachments are owned by the User until a post claims them
+		attachment, err := saveMicropubAttachment(factory, model.AttachmentTypeMicropub, authorization.UserID, fileHeader)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error saving attachment")
+		}
+
+		ctx.Response().Header().Set("Location", attachment.CalcURL(factory.Host()))
+		return ctx.NoContent(http.StatusCreated)
+	}
+}
+
+// GetMicropubMedia returns a file that was uploaded to the Micropub media endpoint,
+// so that clients can preview it before it is claimed by a new post.
+func GetMicropubMedia(fm *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetMicropubMedia"
+
+	return func(ctx echo.Context) error {
+
+		// Try to locate the requested domain
+		factory, err := fm.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		attachmentIDString := list.Dot(ctx.Param("attachment")).First()
+		attachmentID, err := primitive.ObjectIDFromHex(attachmentIDString)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid attachmentID", attachmentIDString, derp.WithNotFound())
+		}
+
+		// Only unclaimed uploads are available here.  Claimed uploads are served by their Stream.
+		attachment := model.NewAttachment(model.AttachmentTypeMicropub, primitive.NilObjectID)
+
+		if err := factory.Attachment().LoadUnclaimed(attachmentID, &attachment); err != nil {
+			return derp.Wrap(err, location, "Error loading attachment")
+		}
+
+		// Retrieve the file from the mediaserver
+		ms := factory.MediaServer()
+		filespec := ms.FileSpec(ctx.Request().URL, attachment.DownloadExtension())
+
+		header := ctx.Response().Header()
+		header.Set("Mime-Type", filespec.MimeType)
+		header.Set("ETag", attachment.ETag())
+		header.Set("Cache-Control", "private")
+
+		if err := ms.Get(filespec, ctx.Response().Writer); err != nil {
+			return derp.Wrap(err, location, "Error accessing attachment file")
+		}
+
+		return nil
+	}
+}
+
+/******************************************
+ * Actions
+ ******************************************/
+
+// postMicropub_Create creates a new Stream from a Micropub h-entry
+func postMicropub_Create(factory *domain.Factory, authorization *model.Authorization, user *model.User, request micropub.Request) (model.Stream, error) {
+
+	const location = "handler.postMicropub_Create"
+
+	// RULE: Only h-entry posts are supported
+	if request.Type != "h-entry" {
+		return model.Stream{}, derp.NewBadRequestError(location, "Unsupported post type", request.Type)
+	}
+
+	// Find the Template to use for new posts
+	templateID := factory.Domain().Get().MicropubTemplateID
+
+	if templateID == "" {
+		templateID = "outbox-message"
+	}
+
+	template, err := factory.Template().Load(templateID)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error loading Micropub template", templateID)
+	}
+
+	// Create the new Stream in the User's outbox
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.SetLocationOutbox(&template, &stream, authorization.UserID); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error setting stream location")
+	}
+
+	stream.AttributedTo = user.PersonLink()
+	stream.SocialRole = template.SocialRole
+
+	// Use the requested slug (if any) only after it has been cleaned and made unique
+	if slug := request.Properties.String("mp-slug"); slug != "" {
+
+		token, err := streamService.UniqueToken(slug, stream.StreamID)
+
+		if err != nil {
+			return model.Stream{}, derp.Wrap(err, location, "Error checking mp-slug", slug)
+		}
+
+		stream.Token = token
+	}
+
+	setMicropubProperties(factory, &stream, request.Properties)
+
+	// Syndicate to the selected targets once the Stream is published
+	syndicateTo, err := setMicropubSyndicateTo(factory, authorization.UserID, request.Properties.Strings("mp-syndicate-to"))
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error reading mp-syndicate-to")
+	}
+
+	stream.SyndicateTo = syndicateTo
+
+	// Verify user permissions
+	if err := streamService.UserCan(authorization, &stream, "create"); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "User is not authorized to create this stream", derp.WithForbidden())
+	}
+
+	// Save the Stream
+	if err := streamService.Save(&stream, "Created via Micropub"); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error saving stream")
+	}
+
+	// Attach photos (uploaded directly, or previously uploaded to the media endpoint)
+	if err := setMicropubPhotos(factory, authorization, &stream, request); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error attaching photos")
+	}
+
+	// Drafts are saved, but not published
+	if request.Properties.String("post-status") == "draft" {
+		return stream, nil
+	}
+
+	// Publish the Stream to the User's outbox
+	if err := streamService.Publish(user, &stream); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Error publishing stream")
+	}
+
+	return stream, nil
+}
+
+// postMicropub_Update applies replace/add/delete operations to an existing Stream
+func postMicropub_Update(factory *domain.Factory, authorization *model.Authorization, user *model.User, request micropub.Request) error {
+
+	const location = "handler.postMicropub_Update"
+
+	// Load the Stream
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.LoadByURL(request.URL, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading stream", request.URL)
+	}
+
+	if err := streamService.UserCan(authorization, &stream, "edit"); err != nil {
+		return derp.Wrap(err, location, "User is not authorized to edit this stream", derp.WithForbidden())
+	}
+
+	// Apply changes to the current values
+	properties := getMicropubProperties(factory, &stream)
+	properties.Update(request)
+	setMicropubProperties(factory, &stream, properties)
+
+	// Add any newly selected syndication targets
+	syndicateTo, err := setMicropubSyndicateTo(factory, authorization.UserID, micropubSyndicateToValues(request))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error reading mp-syndicate-to")
+	}
+
+	for _, syndicationTargetID := range syndicateTo {
+		if !stream.SyndicateTo.Contains(syndicationTargetID) {
+			stream.SyndicateTo = append(stream.SyndicateTo, syndicationTargetID)
+		}
+	}
+
+	if !stream.IsPublished() {
+		return streamService.Save(&stream, "Updated via Micropub")
+	}
+
+	// Re-publishing saves the Stream and sends an Update to followers
+	if err := streamService.Publish(user, &stream); err != nil {
+		return derp.Wrap(err, location, "Error publishing stream")
+	}
+
+	return nil
+}
+
+// postMicropub_Delete removes an existing Stream
+func postMicropub_Delete(factory *domain.Factory, authorization *model.Authorization, user *model.User, request micropub.Request) error {
+
+	const location = "handler.postMicropub_Delete"
+
+	// Load the Stream
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.LoadByURL(request.URL, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading stream", request.URL)
+	}
+
+	if err := streamService.UserCan(authorization, &stream, "delete"); err != nil {
+		return derp.Wrap(err, location, "User is not authorized to delete this stream", derp.WithForbidden())
+	}
+
+	// Tell followers that the Stream is gone
+	if stream.IsPublished() {
+		if err := streamService.UnPublish(user, &stream); err != nil {
+			return derp.Wrap(err, location, "Error unpublishing stream")
+		}
+	}
+
+	if err := streamService.Delete(&stream, "Deleted via Micropub"); err != nil {
+		return derp.Wrap(err, location, "Error deleting stream")
+	}
+
+	return nil
+}
+
+// postMicropub_Undelete restores a previously deleted Stream
+func postMicropub_Undelete(factory *domain.Factory, authorization *model.Authorization, user *model.User, request micropub.Request) error {
+
+	const location = "handler.postMicropub_Undelete"
+
+	// Load the deleted Stream
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.LoadDeletedByURL(request.URL, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading stream", request.URL)
+	}
+
+	if err := streamService.UserCan(authorization, &stream, "delete"); err != nil {
+		return derp.Wrap(err, location, "User is not authorized to restore this stream", derp.WithForbidden())
+	}
+
+	if err := streamService.UnDelete(&stream, "Restored via Micropub"); err != nil {
+		return derp.Wrap(err, location, "Error restoring stream")
+	}
+
+	// Re-publish the Stream to followers
+	if err := streamService.Publish(user, &stream); err != nil {
+		return derp.Wrap(err, location, "Error publishing stream")
+	}
+
+	return nil
+}
+
+/******************************************
+ * Helper Functions
+ ******************************************/
+
+// getMicropubAuthorization validates the access token for a Micropub request.  Tokens may be sent in
+// the Authorization header or in the request body.  If a scope is provided, then the token must
+// include that scope (or the generic "write" scope).
+func getMicropubAuthorization(factory *domain.Factory, request *http.Request, accessToken string, scope string) (model.Authorization, error) {
+
+	const location = "handler.getMicropubAuthorization"
+
+	// Prefer the Authorization header, falling back to the access token in the request body
+	if header := request.Header.Get("Authorization"); header != "" {
+		accessToken = strings.TrimPrefix(header, "Bearer ")
+	}
+
+	if accessToken == "" {
+		return model.Authorization{}, derp.NewUnauthorizedError(location, "Access token is required")
+	}
+
+	token, err := factory.JWT().ParseString(accessToken)
+
+	if err != nil {
+		return model.Authorization{}, derp.Wrap(err, location, "Invalid JWT token", derp.WithCode(http.StatusUnauthorized))
+	}
+
+	if !token.Valid {
+		return model.Authorization{}, derp.NewUnauthorizedError(location, "Invalid token: Invalid JWT")
+	}
+
+	authorization, ok := token.Claims.(*model.Authorization)
+
+	if !ok {
+		return model.Authorization{}, derp.NewUnauthorizedError(location, "Invalid token: Invalid Claims")
+	}
+
+	if authorization.UserID.IsZero() {
+		return model.Authorization{}, derp.NewForbiddenError(location, "Token is not associated with a User")
+	}
+
+	// Confirm that the token has not been revoked
+	if err := factory.OAuthUserToken().ValidateToken(accessToken); err != nil {
+		return model.Authorization{}, derp.Wrap(err, location, "Invalid token: Revoked")
+	}
+
+	// Verify token scopes
+	if (scope != "") && !slice.ContainsAny(authorization.Scopes(), scope, "write") {
+		return model.Authorization{}, derp.NewForbiddenError(location, "Token does not have the required scope", scope)
+	}
+
+	return *authorization, nil
+}
+
+// getMicropubProperties returns the microformats2 properties of a Stream
+func getMicropubProperties(factory *domain.Factory, stream *model.Stream) micropub.Properties {
+
+	result := micropub.NewProperties()
+
+	if stream.Label != "" {
+		result.Set("name", stream.Label)
+	}
+
+	if stream.Summary != "" {
+		result.Set("summary", stream.Summary)
+	}
+
+	if stream.Content.Format == model.ContentFormatHTML {
+		result.Set("content", mapof.Any{"html": stream.Content.HTML})
+	} else if stream.Content.Raw != "" {
+		result.Set("content", stream.Content.Raw)
+	}
+
+	for _, tag := range stream.Tags {
+		if strings.HasPrefix(tag.Name, "#") {
+			result.Append("category", strings.TrimPrefix(tag.Name, "#"))
+		}
+	}
+
+	if stream.InReplyTo != "" {
+		result.Set("in-reply-to", stream.InReplyTo)
+	}
+
+	if stream.URL != "" {
+		result.Set("url", stream.URL)
+	}
+
+	for _, syndication := range stream.Syndication {
+		result.Append("syndication", syndication)
+	}
+
+	if stream.IsPublished() {
+		result.Set("published", time.Unix(stream.PublishDate, 0).Format(time.RFC3339))
+		result.Set("post-status", "published")
+	} else {
+		result.Set("post-status", "draft")
+	}
+
+	// Include all attachments as photos
+	if attachments, err := factory.Attachment().QueryByObjectID(model.AttachmentTypeStream, stream.StreamID); err == nil {
+		for _, attachment := range attachments {
+			result.Append("photo", attachment.CalcURL(factory.Host()))
+		}
+	}
+
+	if (stream.ImageURL != "") && !slice.Contains(result.Strings("photo"), stream.ImageURL) {
+		result.Append("photo", stream.ImageURL)
+	}
+
+	return result
+}
+
+// setMicropubProperties applies microformats2 properties to a Stream
+func setMicropubProperties(factory *domain.Factory, stream *model.Stream, properties micropub.Properties) {
+
+	stream.Label = properties.String("name")
+	stream.Summary = properties.String("summary")
+	stream.InReplyTo = properties.String("in-reply-to")
+	stream.Syndication = properties.Strings("syndication")
+
+	// Plain text content is treated as Markdown
+	contentService := factory.Content()
+
+	if content, isHTML := properties.Content(); isHTML {
+		stream.Content = contentService.New(model.ContentFormatHTML, content)
+	} else {
+		stream.Content = contentService.New(model.ContentFormatMarkdown, content)
+	}
+
+	// Use the first photo as the cover image
+	stream.ImageURL = properties.String("photo")
+
+	// Calculate @mentions and #hashtags, then add categories as additional hashtags
+	streamService := factory.Stream()
+	streamService.CalcTags(stream)
+
+	for _, category := range properties.Strings("category") {
+
+		tag := model.NewTag()
+		tag.Type = "Hashtag"
+		tag.Name = "#" + strings.TrimPrefix(category, "#")
+
+		if !hasMicropubTag(stream.Tags, tag.Name) {
+			stream.Tags = append(stream.Tags, tag)
+		}
+	}
+
+	contentService.ApplyTags(&stream.Content, stream.Tags)
+}
+
+// getMicropubSyndicateTo returns the syndication targets that a Micropub client can offer to the User
+// https://www.w3.org/TR/micropub/#syndication-targets
+func getMicropubSyndicateTo(factory *domain.Factory, userID primitive.ObjectID) ([]mapof.Any, error) {
+
+	targets, err := factory.SyndicationTarget().QueryByUser(userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "handler.getMicropubSyndicateTo", "Error loading syndication targets", userID)
+	}
+
+	result := make([]mapof.Any, 0, len(targets))
+
+	for _, target := range targets {
+		result = append(result, mapof.Any{
+			"uid":  target.SyndicationTargetID.Hex(),
+			"name": target.Label,
+			"service": mapof.String{
+				"name": target.TypeLabel(),
+				"url":  target.URL,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// setMicropubSyndicateTo converts the values of an mp-syndicate-to property into SyndicationTargetIDs.
+// Values that do not match one of the User's syndication targets are ignored.
+func setMicropubSyndicateTo(factory *domain.Factory, userID primitive.ObjectID, values []string) (sliceof.String, error) {
+
+	result := sliceof.NewString()
+
+	if len(values) == 0 {
+		return result, nil
+	}
+
+	targets, err := factory.SyndicationTarget().QueryByUser(userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "handler.setMicropubSyndicateTo", "Error loading syndication targets", userID)
+	}
+
+	for _, target := range targets {
+		syndicationTargetID := target.SyndicationTargetID.Hex()
+		if slice.Contains(values, syndicationTargetID) && !result.Contains(syndicationTargetID) {
+			result = append(result, syndicationTargetID)
+		}
+	}
+
+	return result, nil
+}
+
+// micropubSyndicateToValues returns the mp-syndicate-to values included in an update request
+func micropubSyndicateToValues(request micropub.Request) []string {
+	return append(request.Replace.Strings("mp-syndicate-to"), request.Add.Strings("mp-syndicate-to")...)
+}
+
+// setMicropubPhotos attaches photos to a newly created Stream.  Photos can be uploaded as files
+// in the same request, or referenced by URL after being uploaded to the media endpoint.
+func setMicropubPhotos(factory *domain.Factory, authorization *model.Authorization, stream *model.Stream, request micropub.Request) error {
+
+	const location = "handler.setMicropubPhotos"
+
+	attachmentService := factory.Attachment()
+	host := factory.Host()
+	changed := false
+
+	// Claim photos that were uploaded to the media endpoint by this User
+	for _, photoURL := range request.Properties.Strings("photo") {
+
+		attachment, err := loadMicropubPendingAttachment(factory, authorization, photoURL)
+
+		if err != nil {
+			continue
+		}
+
+		attachment.ObjectType = model.AttachmentTypeStream
+		attachment.ObjectID = stream.StreamID
+
+		if err := attachmentService.Save(&attachment, "Attached via Micropub"); err != nil {
+			return derp.Wrap(err, location, "Error saving attachment", attachment)
+		}
+
+		if stream.ImageURL == photoURL {
+			stream.ImageURL = attachment.CalcURL(host)
+			changed = true
+		}
+	}
+
+	// Save photos that were uploaded with this request
+	for _, fileHeader := range request.Files["photo"] {
+
+		attachment, err := saveMicropubAttachment(factory, model.AttachmentTypeStream, stream.StreamID, fileHeader)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error saving attachment")
+		}
+
+		if stream.ImageURL == "" {
+			stream.ImageURL = attachment.CalcURL(host)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := factory.Stream().Save(stream, "Attached photos via Micropub"); err != nil {
+		return derp.Wrap(err, location, "Error saving stream")
+	}
+
+	return nil
+}
+
+// loadMicropubPendingAttachment loads an attachment that this User uploaded to the media endpoint,
+// but that has not yet been claimed by a Stream.  Pending attachments are owned by the User's ID,
+// and are removed if they are not claimed within a day.
+func loadMicropubPendingAttachment(factory *domain.Factory, authorization *model.Authorization, photoURL string) (model.Attachment, error) {
+
+	const location = "handler.loadMicropubPendingAttachment"
+
+	// Pending URLs look like {host}/.micropub/media/{attachmentId}
+	prefix := factory.Host() + "/.micropub/media/"
+
+	if !strings.HasPrefix(photoURL, prefix) {
+		return model.Attachment{}, derp.NewNotFoundError(location, "Not a pending attachment", photoURL)
+	}
+
+	attachmentID, err := primitive.ObjectIDFromHex(strings.TrimPrefix(photoURL, prefix))
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Invalid attachment ID", photoURL)
+	}
+
+	result := model.NewAttachment(model.AttachmentTypeMicropub, authorization.UserID)
+
+	if err := factory.Attachment().LoadByID(model.AttachmentTypeMicropub, authorization.UserID, attachmentID, &result); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error loading attachment", photoURL)
+	}
+
+	return result, nil
+}
+
+// saveMicropubAttachment adds an uploaded file to the media server and saves it as
+// an attachment of the provided object (a Stream, or a User for pending uploads).
+func saveMicropubAttachment(factory *domain.Factory, objectType string, objectID primitive.ObjectID, fileHeader *multipart.FileHeader) (model.Attachment, error) {
+
+	const location = "handler.saveMicropubAttachment"
+
+	attachment := model.NewAttachment(objectType, objectID)
+	attachment.Original = fileHeader.Filename
+
+	// Open the source (from the POST request)
+	source, err := fileHeader.Open()
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error reading file from multi-part header", fileHeader.Filename)
+	}
+
+	defer source.Close()
+
+	// Add the file into the media server
+	width, height, err := factory.MediaServer().Put(attachment.AttachmentID.Hex(), source)
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error saving attachment to mediaserver", attachment)
+	}
+
+	attachment.Width = width
+	attachment.Height = height
+
+	if err := factory.Attachment().Save(&attachment, "Uploaded via Micropub: "+fileHeader.Filename); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error saving attachment", attachment)
+	}
+
+	return attachment, nil
+}
+
+// hasMicropubTag returns TRUE if the list of tags already includes the named tag (case-insensitive)
+func hasMicropubTag(tags []model.Tag, name string) bool {
+
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
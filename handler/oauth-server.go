@@ -3,12 +3,16 @@ package handler
 import (
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/EmissarySocial/emissary/builder"
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/indieauth"
 	"github.com/benpate/derp"
 	"github.com/benpate/html"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/steranko"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -34,17 +38,37 @@ func GetOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewInternalError(location, "Invalid Domain.")
 		}
 
-		// Load the OAuth Builder
-		builder, err := builder.NewOAuthAuthorization(factory, transaction)
+		// Load the OAuth Builder.  IndieAuth clients are discovered here,
+		// but are not saved until the User allows access.
+		var oauthBuilder builder.OAuthAuthorization
 
-		if err != nil {
-			return derp.Wrap(err, location, "Error Generating Builder")
+		if indieauth.IsClientID(transaction.ClientID) {
+
+			client, err := factory.OAuthClient().DiscoverByURL(transaction.ClientID)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error discovering IndieAuth client", transaction.ClientID)
+			}
+
+			oauthBuilder, err = builder.NewOAuthAuthorizationWithClient(factory, client, transaction)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error Generating Builder")
+			}
+
+		} else {
+
+			oauthBuilder, err = builder.NewOAuthAuthorization(factory, transaction)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error Generating Builder")
+			}
 		}
 
 		// Render the template
 		template := factory.Domain().Theme().HTMLTemplate
 
-		if err := template.ExecuteTemplate(ctx.Response(), "oauth", builder); err != nil {
+		if err := template.ExecuteTemplate(ctx.Response(), "oauth", oauthBuilder); err != nil {
 			return derp.Wrap(err, location, "Error executing template")
 		}
 
@@ -58,20 +82,6 @@ func PostOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 
 	return func(ctx echo.Context) error {
 
-		// Collect Form parameters
-		transaction := model.NewOAuthAuthorizationRequest()
-
-		if err := ctx.Bind(&transaction); err != nil {
-			return derp.Wrap(err, location, "Invalid form parameters")
-		}
-
-		// Convert the ClientID
-		clientID, err := primitive.ObjectIDFromHex(transaction.ClientID)
-
-		if err != nil {
-			return derp.Wrap(err, location, "Invalid client_id")
-		}
-
 		// Locate the current domain
 		factory, err := serverFactory.ByContext(ctx)
 
@@ -79,15 +89,39 @@ func PostOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewInternalError(location, "Invalid Domain.")
 		}
 
+		// IndieAuth clients that only need to authenticate a user redeem their
+		// authorization code here, instead of at the token endpoint.
+		// https://indieauth.spec.indieweb.org/#redeeming-the-authorization-code
+		if ctx.FormValue("grant_type") == "authorization_code" {
+			return postOAuthAuthorization_redeem(ctx, factory)
+		}
+
 		// Get Authorization
 		sterankoContext := ctx.(*steranko.Context)
 		authorization := getAuthorization(sterankoContext)
 
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "User is not signed in")
+		}
+
+		// Collect Form parameters
+		transaction := model.NewOAuthAuthorizationRequest()
+
+		if err := ctx.Bind(&transaction); err != nil {
+			return derp.Wrap(err, location, "Invalid form parameters")
+		}
+
 		// Get Application
 		clientService := factory.OAuthClient()
 		application := model.NewOAuthClient()
 
-		if err := clientService.LoadByClientID(clientID, &application); err != nil {
+		if indieauth.IsClientID(transaction.ClientID) {
+			application, err = clientService.DiscoverByURL(transaction.ClientID)
+		} else {
+			err = clientService.LoadByPublicID(transaction.ClientID, &application)
+		}
+
+		if err != nil {
 			return derp.Wrap(err, location, "Error loading OAuth Application")
 		}
 
@@ -96,6 +130,13 @@ func PostOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Invalid transaction")
 		}
 
+		// IndieAuth clients are saved only once the User has allowed access
+		if application.IsIndieAuth() {
+			if err := clientService.Save(&application, "Discovered via IndieAuth"); err != nil {
+				return derp.Wrap(err, location, "Error saving IndieAuth client", transaction.ClientID)
+			}
+		}
+
 		// Create a UserToken
 		userTokenService := factory.OAuthUserToken()
 		userToken, err := userTokenService.Create(application, authorization, transaction)
@@ -108,7 +149,7 @@ func PostOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 		switch transaction.ResponseType {
 
 		case "code":
-			return postOAuthAuthorization_code(ctx, userToken, transaction)
+			return postOAuthAuthorization_code(ctx, factory.Host()+"/", userToken, transaction)
 
 		case "token":
 			return postOAuthAuthorization_token(ctx, userToken, transaction)
@@ -119,7 +160,7 @@ func PostOAuthAuthorization(serverFactory *server.Factory) echo.HandlerFunc {
 }
 
 // postOAuthAuthorization_code handles `code` grant types used by server authentication flow
-func postOAuthAuthorization_code(ctx echo.Context, userToken model.OAuthUserToken, transaction model.OAuthAuthorizationRequest) error {
+func postOAuthAuthorization_code(ctx echo.Context, issuer string, userToken model.OAuthUserToken, transaction model.OAuthAuthorizationRequest) error {
 
 	// If this magic value is passed as the redirect URI, then we just return the token in the <title> tag of the HTML
 	// https://docs.joinmastodon.org/methods/apps/#form-data-parameters
@@ -139,9 +180,16 @@ func postOAuthAuthorization_code(ctx echo.Context, userToken model.OAuthUserToke
 		return derp.Wrap(err, "handler.postOAuthAuthorization_code", "Invalid redirect_uri", transaction.RedirectURI)
 	}
 
-	// Add the code to the URI
+	// Add the code (along with the original state and issuer) to the URI
+	// https://indieauth.spec.indieweb.org/#authorization-response
 	queryString := redirectURI.Query()
 	queryString.Set("code", userToken.Code())
+	queryString.Set("iss", issuer)
+
+	if transaction.State != "" {
+		queryString.Set("state", transaction.State)
+	}
+
 	redirectURI.RawQuery = queryString.Encode()
 
 	return ctx.Redirect(http.StatusFound, redirectURI.String())
@@ -170,7 +218,15 @@ func postOAuthAuthorization_token(ctx echo.Context, userToken model.OAuthUserTok
 		return derp.Wrap(err, location, "Error parsing redirect_uri")
 	}
 
-	redirectURI.Fragment = "access_token=" + userToken.Token + "&token_type=Bearer"
+	fragment := url.Values{}
+	fragment.Set("access_token", userToken.Token)
+	fragment.Set("token_type", "Bearer")
+
+	if transaction.State != "" {
+		fragment.Set("state", transaction.State)
+	}
+
+	redirectURI.Fragment = fragment.Encode()
 
 	// Otherwise, we redirect to the redirect_uri
 	return ctx.Redirect(http.StatusFound, redirectURI.String())
@@ -189,6 +245,18 @@ func PostOAuthToken(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Invalid form parameters")
 		}
 
+		// Locate the domain factory
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain.")
+		}
+
+		// IndieAuth clients do not have a client secret, and are handled separately
+		if indieauth.IsClientID(transaction.ClientID) {
+			return postOAuthToken_IndieAuth(ctx, factory, transaction)
+		}
+
 		// Convert client ID
 		clientID, err := primitive.ObjectIDFromHex(transaction.ClientID)
 
@@ -203,13 +271,6 @@ func PostOAuthToken(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Invalid code")
 		}
 
-		// Locate the domain factory
-		factory, err := serverFactory.ByContext(ctx)
-
-		if err != nil {
-			return derp.Wrap(err, location, "Invalid Domain.")
-		}
-
 		// Load the UserToken
 		userTokenService := factory.OAuthUserToken()
 		userToken := model.NewOAuthUserToken()
@@ -218,11 +279,154 @@ func PostOAuthToken(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Error loading OAuthUserToken")
 		}
 
+		// Confirm that the code is still valid
+		if err := userTokenService.RedeemCode(&userToken, transaction.RedirectURI, transaction.CodeVerifier); err != nil {
+			return derp.Wrap(err, location, "Invalid authorization code")
+		}
+
 		// Return the Token as JSON
 		return ctx.JSON(http.StatusOK, userToken.JSONResponse())
 	}
 }
 
+// postOAuthToken_IndieAuth exchanges an authorization code for an access token, along with the
+// user's canonical profile URL.  Codes that were issued without any scopes cannot be exchanged
+// for an access token.
+// https://indieauth.spec.indieweb.org/#redeeming-the-authorization-code
+func postOAuthToken_IndieAuth(ctx echo.Context, factory *domain.Factory, transaction model.OAuthUserTokenRequest) error {
+
+	const location = "handler.postOAuthToken_IndieAuth"
+
+	userToken, user, err := redeemIndieAuthCode(factory, transaction)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error redeeming authorization code")
+	}
+
+	// RULE: Tokens are only issued when the user granted at least one scope
+	if len(userToken.Scopes) == 0 {
+		return derp.NewBadRequestError(location, "Authorization code was not issued with any scopes")
+	}
+
+	result := indieAuthProfileResponse(&user, &userToken)
+
+	for key, value := range userToken.JSONResponse() {
+		result[key] = value
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// postOAuthAuthorization_redeem exchanges an authorization code for the user's
+// canonical profile URL (and profile information, if requested)
+func postOAuthAuthorization_redeem(ctx echo.Context, factory *domain.Factory) error {
+
+	const location = "handler.postOAuthAuthorization_redeem"
+
+	// Collect transaction data
+	transaction := model.NewOAuthUserTokenRequest()
+
+	if err := ctx.Bind(&transaction); err != nil {
+		return derp.Wrap(err, location, "Invalid form parameters")
+	}
+
+	userToken, user, err := redeemIndieAuthCode(factory, transaction)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error redeeming authorization code")
+	}
+
+	return ctx.JSON(http.StatusOK, indieAuthProfileResponse(&user, &userToken))
+}
+
+// GetOAuthToken verifies an access token sent in the Authorization header.
+// This is the legacy IndieAuth token verification method, which predates introspection.
+func GetOAuthToken(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetOAuthToken"
+
+	return func(ctx echo.Context) error {
+
+		// Locate the domain factory
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain.")
+		}
+
+		token := strings.TrimPrefix(ctx.Request().Header.Get("Authorization"), "Bearer ")
+		result := introspectOAuthToken(factory, token)
+
+		if active, _ := result["active"].(bool); !active {
+			return derp.NewUnauthorizedError(location, "Invalid access token")
+		}
+
+		delete(result, "active")
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
+
+// PostOAuthIntrospect returns information about an access token.  Callers must
+// include a valid access token of their own in the Authorization header.
+// https://indieauth.spec.indieweb.org/#access-token-verification
+func PostOAuthIntrospect(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostOAuthIntrospect"
+
+	return func(ctx echo.Context) error {
+
+		// Locate the domain factory
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain.")
+		}
+
+		// RULE: Introspection requests must be authorized by a live (unrevoked) token
+		if _, err := factory.JWT().Parse(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Invalid authorization", derp.WithCode(http.StatusUnauthorized))
+		}
+
+		if err := factory.OAuthUserToken().ValidateToken(factory.JWT().TokenString(ctx.Request())); err != nil {
+			return derp.Wrap(err, location, "Invalid authorization", derp.WithCode(http.StatusUnauthorized))
+		}
+
+		return ctx.JSON(http.StatusOK, introspectOAuthToken(factory, ctx.FormValue("token")))
+	}
+}
+
+// GetOAuthMetadata returns the OAuth/IndieAuth server metadata for this domain
+// https://indieauth.spec.indieweb.org/#indieauth-server-metadata
+func GetOAuthMetadata(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetOAuthMetadata"
+
+	return func(ctx echo.Context) error {
+
+		// Locate the domain factory
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain.")
+		}
+
+		host := factory.Host()
+
+		return ctx.JSON(http.StatusOK, mapof.Any{
+			"issuer":                                         host + "/",
+			"authorization_endpoint":                         host + "/oauth/authorize",
+			"token_endpoint":                                 host + "/oauth/token",
+			"introspection_endpoint":                         host + "/oauth/introspect",
+			"revocation_endpoint":                            host + "/oauth/revoke",
+			"scopes_supported":                               model.IndieAuthScopes(),
+			"response_types_supported":                       []string{"code"},
+			"grant_types_supported":                          []string{"authorization_code"},
+			"code_challenge_methods_supported":               []string{indieauth.CodeChallengeMethodS256},
+			"authorization_response_iss_parameter_supported": true,
+		})
+	}
+}
+
 func PostOAuthRevoke(serverFactory *server.Factory) echo.HandlerFunc {
 
 	const location = "handler.PostOAuthRevoke"
@@ -236,13 +440,6 @@ func PostOAuthRevoke(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Invalid form parameters")
 		}
 
-		// Convert clientID
-		clientID, err := primitive.ObjectIDFromHex(transaction.ClientID)
-
-		if err != nil {
-			return derp.Wrap(err, location, "Invalid client_id")
-		}
-
 		// Locate the domain factory
 		factory, err := serverFactory.ByContext(ctx)
 
@@ -254,7 +451,22 @@ func PostOAuthRevoke(serverFactory *server.Factory) echo.HandlerFunc {
 		userTokenService := factory.OAuthUserToken()
 		userToken := model.NewOAuthUserToken()
 
-		err = userTokenService.LoadByClientAndToken(clientID, transaction.ClientSecret, transaction.Token, &userToken)
+		if (transaction.ClientID == "") || indieauth.IsClientID(transaction.ClientID) {
+
+			// Public (IndieAuth) clients have no secret, so the token itself is sufficient
+			err = userTokenService.LoadByToken(transaction.Token, &userToken)
+
+		} else {
+
+			// Convert clientID
+			clientID, clientErr := primitive.ObjectIDFromHex(transaction.ClientID)
+
+			if clientErr != nil {
+				return derp.Wrap(clientErr, location, "Invalid client_id")
+			}
+
+			err = userTokenService.LoadByClientAndToken(clientID, transaction.ClientSecret, transaction.Token, &userToken)
+		}
 
 		if derp.NotFound(err) {
 			return nil
@@ -271,3 +483,137 @@ func PostOAuthRevoke(serverFactory *server.Factory) echo.HandlerFunc {
 		return ctx.JSON(http.StatusOK, map[string]any{})
 	}
 }
+
+/******************************************
+ * IndieAuth Helpers
+ ******************************************/
+
+// redeemIndieAuthCode validates an authorization code for an IndieAuth client, and
+// returns the corresponding token and user.  Codes can only be redeemed once.
+func redeemIndieAuthCode(factory *domain.Factory, transaction model.OAuthUserTokenRequest) (model.OAuthUserToken, model.User, error) {
+
+	const location = "handler.redeemIndieAuthCode"
+
+	// RULE: redirect_uri is required for IndieAuth clients
+	if transaction.RedirectURI == "" {
+		return model.OAuthUserToken{}, model.User{}, derp.NewBadRequestError(location, "redirect_uri is required")
+	}
+
+	// Load the client
+	client := model.NewOAuthClient()
+
+	if err := factory.OAuthClient().LoadByClientURL(transaction.ClientID, &client); err != nil {
+		return model.OAuthUserToken{}, model.User{}, derp.Wrap(err, location, "Invalid client_id", transaction.ClientID, derp.WithBadRequest())
+	}
+
+	// Convert transaction.Code => userToken
+	userTokenID, err := primitive.ObjectIDFromHex(transaction.Code)
+
+	if err != nil {
+		return model.OAuthUserToken{}, model.User{}, derp.Wrap(err, location, "Invalid code", derp.WithBadRequest())
+	}
+
+	// Load the UserToken
+	userTokenService := factory.OAuthUserToken()
+	userToken := model.NewOAuthUserToken()
+
+	if err := userTokenService.LoadByCode(userTokenID, client.ClientID, &userToken); err != nil {
+		return model.OAuthUserToken{}, model.User{}, derp.Wrap(err, location, "Error loading OAuthUserToken", derp.WithBadRequest())
+	}
+
+	// RULE: Public clients MUST use PKCE
+	if userToken.CodeChallenge == "" {
+		return model.OAuthUserToken{}, model.User{}, derp.NewBadRequestError(location, "Authorization code was not issued with a code_challenge")
+	}
+
+	if err := userTokenService.RedeemCode(&userToken, transaction.RedirectURI, transaction.CodeVerifier); err != nil {
+		return model.OAuthUserToken{}, model.User{}, derp.Wrap(err, location, "Invalid authorization code")
+	}
+
+	// Load the User
+	user := model.NewUser()
+
+	if err := factory.User().LoadByID(userToken.UserID, &user); err != nil {
+		return model.OAuthUserToken{}, model.User{}, derp.Wrap(err, location, "Error loading user", userToken.UserID)
+	}
+
+	return userToken, user, nil
+}
+
+// indieAuthProfileResponse returns the user's canonical profile URL, along with
+// profile information for tokens that include the "profile" and "email" scopes
+func indieAuthProfileResponse(user *model.User, userToken *model.OAuthUserToken) mapof.Any {
+
+	me, err := indieauth.CanonicalURL(user.ProfileURL)
+
+	if err != nil {
+		me = user.ProfileURL
+	}
+
+	result := mapof.Any{
+		"me": me,
+	}
+
+	if userToken.HasScope("profile") {
+
+		profile := mapof.Any{
+			"name":  user.DisplayName,
+			"url":   user.ProfileURL,
+			"photo": user.ActivityPubAvatarURL(),
+		}
+
+		if userToken.HasScope("email") {
+			profile["email"] = user.EmailAddress
+		}
+
+		result["profile"] = profile
+	}
+
+	return result
+}
+
+// introspectOAuthToken returns the token introspection response for an access token.
+// Tokens are only active if they are valid JWTs that have not been revoked.
+func introspectOAuthToken(factory *domain.Factory, token string) mapof.Any {
+
+	inactive := mapof.Any{"active": false}
+
+	if token == "" {
+		return inactive
+	}
+
+	// Validate the JWT
+	jwtToken, err := factory.JWT().ParseString(token)
+
+	if (err != nil) || !jwtToken.Valid {
+		return inactive
+	}
+
+	// Confirm that the token has not been revoked
+	userToken := model.NewOAuthUserToken()
+
+	if err := factory.OAuthUserToken().LoadByToken(token, &userToken); err != nil {
+		return inactive
+	}
+
+	// Load the client and user that the token was issued for
+	client := model.NewOAuthClient()
+
+	if err := factory.OAuthClient().LoadByClientID(userToken.ClientID, &client); err != nil {
+		return inactive
+	}
+
+	user := model.NewUser()
+
+	if err := factory.User().LoadByID(userToken.UserID, &user); err != nil {
+		return inactive
+	}
+
+	result := indieAuthProfileResponse(&user, &userToken)
+	result["active"] = true
+	result["client_id"] = client.PublicClientID()
+	result["scope"] = strings.Join(userToken.Scopes, " ")
+	result["iat"] = userToken.CreateDate / 1000
+
+	return result
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/mediaserver"
 	"github.com/benpate/rosetta/first"
 	"github.com/benpate/steranko"
@@ -118,6 +119,14 @@ func buildOutbox(serverFactory *server.Factory, actionMethod builder.ActionMetho
 		user := model.NewUser()
 
 		if err := userService.LoadByToken(username, &user); err != nil {
+
+			// Deleted Users leave a Tombstone behind
+			if derp.NotFound(err) {
+				if tombstone := model.NewUser(); userService.LoadTombstoneByToken(username, &tombstone) == nil {
+					return renderTombstone(context, &tombstone)
+				}
+			}
+
 			return derp.Wrap(err, location, "Error loading user", username)
 		}
 
@@ -136,6 +145,16 @@ func buildOutbox(serverFactory *server.Factory, actionMethod builder.ActionMetho
 			return derp.Wrap(err, location, "Error building JSON-LD")
 		}
 
+		// Add IndieAuth discovery link headers so that this profile URL can be used to sign in elsewhere
+		// https://indieauth.spec.indieweb.org/#discovery-by-clients
+		if actionMethod == builder.ActionMethodGet {
+			header := context.Response().Header()
+			header.Add("Link", "</.well-known/oauth-authorization-server>; rel=\"indieauth-metadata\"")
+			header.Add("Link", "</oauth/authorize>; rel=\"authorization_endpoint\"")
+			header.Add("Link", "</oauth/token>; rel=\"token_endpoint\"")
+			header.Add("Link", "</.micropub>; rel=\"micropub\"")
+		}
+
 		builder, err := builder.NewOutbox(factory, context.Request(), context.Response(), &user, actionID)
 
 		if err != nil {
@@ -147,6 +166,18 @@ func buildOutbox(serverFactory *server.Factory, actionMethod builder.ActionMetho
 	}
 }
 
+// renderTombstone returns "410 Gone" for a User that has been deleted, so that remote
+// servers know to stop looking for them.  ActivityPub requests also receive a Tombstone document.
+func renderTombstone(context echo.Context, tombstone *model.User) error {
+
+	if isJSONLDRequest(context) {
+		context.Response().Header().Set(vocab.ContentType, vocab.ContentTypeActivityPub)
+		return context.JSON(http.StatusGone, tombstone.GetTombstoneJSONLD())
+	}
+
+	return derp.New(http.StatusGone, "handler.renderTombstone", "User has been deleted", tombstone.UserID)
+}
+
 // profileUsername returns a string version of the UserID.
 // if the username is "me" then this function returns the currently authenticated user's ID.
 func profileUsername(context echo.Context) (string, error) {
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// GetPasskeyRegistration generates an echo.HandlerFunc that handles GET /@me/passkeys/register requests.
+// It returns the WebAuthn options for registering a new passkey for the signed-in User.
+func GetPasskeyRegistration(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetPasskeyRegistration"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		user := model.NewUser()
+
+		if err := loadSignedInUser(ctx.(*steranko.Context), factory.User(), &user); err != nil {
+			return derp.Wrap(err, location, "Error loading signed-in user")
+		}
+
+		challenge, err := setPasskeyChallenge(ctx, factory, passkeyRegisterAudience, user.UserID.Hex())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error creating passkey challenge")
+		}
+
+		return ctx.JSON(http.StatusOK, factory.User().PasskeyCreationOptions(&user, challenge))
+	}
+}
+
+// PostPasskeyRegistration generates an echo.HandlerFunc that handles POST /@me/passkeys/register requests.
+// It verifies the new passkey created by the User's browser, and adds it to their profile.
+func PostPasskeyRegistration(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostPasskeyRegistration"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		var transaction struct {
+			Label      string                        `json:"label"`
+			Credential webauthn.RegistrationResponse `json:"credential"`
+		}
+
+		if err := ctx.Bind(&transaction); err != nil {
+			return derp.Wrap(err, location, "Error binding passkey response", derp.WithCode(http.StatusBadRequest))
+		}
+
+		user := model.NewUser()
+
+		if err := loadSignedInUser(ctx.(*steranko.Context), factory.User(), &user); err != nil {
+			return derp.Wrap(err, location, "Error loading signed-in user")
+		}
+
+		// The challenge must have been issued to the same User
+		claims, err := loadPasskeyChallenge(ctx, factory, passkeyRegisterAudience)
+
+		if (err != nil) || (claims.Subject != user.UserID.Hex()) {
+			return ctx.String(http.StatusUnauthorized, "Passkey registration expired.  Please try again.")
+		}
+
+		if err := factory.User().AddPasskey(&user, claims.ID, transaction.Credential, transaction.Label); err != nil {
+			return passkeyError(ctx, err)
+		}
+
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
@@ -4,10 +4,9 @@ import (
 	"strings"
 
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/qrcode"
 	"github.com/benpate/derp"
 	"github.com/labstack/echo/v4"
-	"github.com/yeqown/go-qrcode/v2"
-	"github.com/yeqown/go-qrcode/writer/standard"
 )
 
 // StepQRCode represents an action-step that returns a QR Code for the current stream URL.
@@ -29,19 +28,10 @@ func GetQRCode(fm *server.Factory) echo.HandlerFunc {
 
 		url = url + ctx.Request().Host + strings.TrimSuffix(ctx.Request().URL.String(), "/qrcode")
 
-		qrc, err := qrcode.New(url)
-
-		if err != nil {
+		if err := qrcode.Write(ctx.Response().Writer, url); err != nil {
 			return derp.Wrap(err, "build.StepQRCode.Get", "Error generating QR Code")
 		}
 
-		w := standard.NewWithWriter(AsWriteCloser{ctx.Response().Writer})
-
-		// "save" file to the writer
-		if err := qrc.Save(w); err != nil {
-			return derp.Wrap(err, "build.StepQRCode.Get", "Error writing image")
-		}
-
 		return nil
 	}
 }
@@ -9,9 +9,15 @@ import (
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
 	"github.com/labstack/echo/v4"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// registerPage contains the data used to render the "register" template
+type registerPage struct {
+	model.Domain
+	InviteCode  string // Invitation code from the registration link
+	InviteValid bool   // TRUE if the InviteCode matches an Invitation that can still be used
+}
+
 // GetRegister generates an echo.HandlerFunc that handles GET /register requests
 func GetRegister(factoryManager *server.Factory) echo.HandlerFunc {
 
@@ -31,11 +37,27 @@ func GetRegister(factoryManager *server.Factory) echo.HandlerFunc {
 			return ctx.NoContent(http.StatusNotFound)
 		}
 
+		page := registerPage{
+			Domain:     domain,
+			InviteCode: ctx.QueryParam("invite"),
+		}
+
+		// Check the invitation (if any) so that the template can explain problems before the form is submitted
+		if page.InviteCode != "" {
+			invitation := model.NewInvitation()
+
+			if err := factory.Invitation().LoadByCode(page.InviteCode, &invitation); err == nil {
+				page.InviteValid = true
+			} else if !derp.NotFound(err) {
+				return derp.Wrap(err, location, "Error loading invitation")
+			}
+		}
+
 		// Find and execute the template
 		var buffer bytes.Buffer
 		template := factory.Domain().Theme().HTMLTemplate
 
-		if err := template.ExecuteTemplate(&buffer, "register", domain); err != nil {
+		if err := template.ExecuteTemplate(&buffer, "register", page); err != nil {
 			return derp.Wrap(err, location, "Error executing template")
 		}
 
@@ -67,9 +89,11 @@ func PostRegister(factoryManager *server.Factory) echo.HandlerFunc {
 		userService := factory.User()
 
 		transaction := struct {
-			DisplayName string `form:"displayName"`
-			Username    string `form:"username"`
-			Password    string `form:"password"`
+			DisplayName  string `form:"displayName"`
+			Username     string `form:"username"`
+			EmailAddress string `form:"emailAddress"`
+			Password     string `form:"password"`
+			InviteCode   string `form:"invite"`
 		}{}
 
 		if err := ctx.Bind(&transaction); err != nil {
@@ -79,17 +103,42 @@ func PostRegister(factoryManager *server.Factory) echo.HandlerFunc {
 		errorMessages := map[string]string{}
 		user := model.NewUser()
 
-		// Validate Username is Unique
-		if err := userService.LoadByUsername(transaction.Username, &user); err == nil {
+		// Validate Username is Unique (including usernames reserved by deleted accounts)
+		if taken, err := userService.IsUsernameTaken(transaction.Username); err != nil {
+			return derp.Wrap(err, location, "Error searching for username")
+		} else if taken {
 			errorMessages["username"] = "Pick a different username.  This one is already in use."
+		}
+
+		// Validate Email Address is present and unique
+		if transaction.EmailAddress == "" {
+			errorMessages["emailAddress"] = "Email address is required."
+		} else if err := userService.LoadByUsernameOrEmail(transaction.EmailAddress, &user); err == nil {
+			errorMessages["emailAddress"] = "This email address is already in use."
 		} else if !derp.NotFound(err) {
-			return derp.Wrap(err, location, "Error searching for username")
+			return derp.Wrap(err, location, "Error searching for email address")
 		}
 
-		// Otherwise, we got a 404 error, which is actually what we want here.
-		// It means that the username is unique.
+		// Validate Password follows the domain's password policy
+		if err := userService.ValidatePassword(transaction.Password); err != nil {
+			errorMessages["password"] = derp.Message(err)
+		}
 
-		// TODO: MEDIUM: Other validations here? Password quality?
+		// Validate Invitation (required on invite-only domains)
+		var invitation *model.Invitation
+
+		if (transaction.InviteCode != "") || domain.SignupForm.IsInviteOnly() {
+
+			found := model.NewInvitation()
+
+			if err := factory.Invitation().LoadByCode(transaction.InviteCode, &found); err == nil {
+				invitation = &found
+			} else if derp.NotFound(err) {
+				errorMessages["invite"] = "This invitation has expired.  Please ask for a new one."
+			} else {
+				return derp.Wrap(err, location, "Error loading invitation")
+			}
+		}
 
 		// Report errors
 		if len(errorMessages) > 0 {
@@ -100,28 +149,80 @@ func PostRegister(factoryManager *server.Factory) echo.HandlerFunc {
 		}
 
 		// Try to save the new user record
+		user = model.NewUser()
 		user.DisplayName = transaction.DisplayName
-		user.GroupIDs = []primitive.ObjectID{domain.SignupForm.GroupID}
+		user.EmailAddress = transaction.EmailAddress
 		user.SetUsername(transaction.Username)
-		user.SetPassword(transaction.Password)
 
-		if err := userService.Save(&user, "Created by signup form"); err != nil {
+		if err := userService.Register(&user, transaction.Password, invitation, "Created by signup form"); err != nil {
 			return derp.Wrap(err, location, "Error saving new user record")
 		}
 
-		// Try to sign-in with the new user's account
-		s := factory.Steranko()
+		// If the new user must confirm their email address or wait for approval, then explain on the sign-in page
+		if !user.IsActive() {
+			ctx.Response().Header().Add("HX-Redirect", "/signin?message="+inactiveMessage(&user))
+			return ctx.NoContent(http.StatusOK)
+		}
 
-		cookie, err := s.CreateCertificate(ctx.Request(), &user)
+		// Try to sign-in with the new user's account
+		challenged, err := signInUser(ctx, factory, &user)
 
 		if err != nil {
 			return derp.Wrap(err, location, "Error signing in user")
 		}
 
-		ctx.SetCookie(&cookie)
+		// If the signup group requires two-factor authentication, then set it up now
+		if challenged {
+			ctx.Response().Header().Add("HX-Redirect", twoFactorURL(""))
+			return ctx.NoContent(http.StatusOK)
+		}
 
 		// Report success to the client
 		ctx.Response().Header().Add("HX-Trigger", "RegistrationSuccess")
 		return ctx.NoContent(200)
 	}
 }
+
+// GetConfirmEmail generates an echo.HandlerFunc that handles GET /register/confirm requests.
+// New users open this link from their confirmation email to activate their account.
+func GetConfirmEmail(factoryManager *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetConfirmEmail"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := factoryManager.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		user := model.NewUser()
+
+		if err := factory.User().ConfirmEmail(ctx.QueryParam("userId"), ctx.QueryParam("code"), &user); err != nil {
+
+			// Invalid or expired links can be replaced by signing in again
+			if derp.IsClientError(err) {
+				return ctx.Redirect(http.StatusSeeOther, "/signin?message=confirm-expired")
+			}
+
+			return derp.Wrap(err, location, "Error confirming email address")
+		}
+
+		if !user.IsActive() {
+			return ctx.Redirect(http.StatusSeeOther, "/signin?message="+inactiveMessage(&user))
+		}
+
+		return ctx.Redirect(http.StatusSeeOther, "/signin?message=email-confirmed")
+	}
+}
+
+// inactiveMessage returns the sign-in page message that explains why a User cannot sign in yet
+func inactiveMessage(user *model.User) string {
+
+	if user.EmailConfirmation.IsPending() {
+		return "confirm-email"
+	}
+
+	return "approval-pending"
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetRSSCloudClient is called by an external rssCloud server to confirm a subscription request.
+// https://www.rssboard.org/rsscloud-interface
+func GetRSSCloudClient(serverFactory *server.Factory) echo.HandlerFunc {
+
+	return func(ctx echo.Context) error {
+
+		const location = "handler.GetRSSCloudClient"
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading server factory")
+		}
+
+		// Load the following record from the database.  The Following's Method is not checked here
+		// because it is not updated until the rssCloud server has accepted our subscription.
+		following := model.NewFollowing()
+
+		if err := rssCloudClient_LoadFollowing(factory.Following(), ctx, &following); err != nil {
+			return derp.Wrap(err, location, "Error loading following record")
+		}
+
+		// RULE: Require a challenge
+		challenge := ctx.QueryParam("challenge")
+
+		if challenge == "" {
+			return derp.NewBadRequestError(location, "Missing rssCloud challenge", following.FollowingID)
+		}
+
+		// Win!
+		return ctx.String(http.StatusOK, challenge)
+	}
+}
+
+// PostRSSCloudClient is called by an external rssCloud server to notify us of a change.
+func PostRSSCloudClient(serverFactory *server.Factory) echo.HandlerFunc {
+
+	return func(ctx echo.Context) error {
+
+		const location = "handler.PostRSSCloudClient"
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading server factory")
+		}
+
+		// Load the following record from the database
+		followingService := factory.Following()
+		following := model.NewFollowing()
+
+		if err := rssCloudClient_LoadFollowing(followingService, ctx, &following); err != nil {
+			return derp.Wrap(err, location, "Error loading following record")
+		}
+
+		// rssCloud servers send a test notification to confirm subscriptions that do not include a
+		// domain.  This is acknowledged without reloading the feed.
+		if following.Method != model.FollowMethodRssCloud {
+			return ctx.NoContent(http.StatusOK)
+		}
+
+		// Reload the feed in the background so that the rssCloud server is not kept waiting
+		go func() {
+			if err := followingService.ReceiveRSSCloud(following); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error receiving rssCloud notification", following.FollowingID))
+			}
+		}()
+
+		// Woot woot!
+		return ctx.NoContent(http.StatusOK)
+	}
+}
+
+// rssCloudClient_LoadFollowing loads the Following record identified in the request path
+func rssCloudClient_LoadFollowing(followingService *service.Following, ctx echo.Context, following *model.Following) error {
+
+	const location = "handler.rssCloudClient_LoadFollowing"
+
+	// Parse the UserID from the query string
+	userID, err := primitive.ObjectIDFromHex(ctx.Param("userId"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid UserID", ctx.Param("userId"))
+	}
+
+	// Parse the Following from the query string
+	followingID, err := primitive.ObjectIDFromHex(ctx.Param("followingId"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid FollowingID", ctx.Param("followingId"))
+	}
+
+	if err := followingService.LoadByID(userID, followingID, following); err != nil {
+		return derp.Wrap(err, location, "Error loading following record", userID, followingID)
+	}
+
+	return nil
+}
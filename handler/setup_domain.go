@@ -124,13 +124,11 @@ func SetupDomainSigninPost(fm *server.Factory) echo.HandlerFunc {
 		}
 
 		// Create a fake "User" record for the system administrator and sign in
-		s := factory.Steranko()
-
 		administrator := model.NewUser()
 		administrator.DisplayName = "Server Administrator"
 		administrator.IsOwner = true
 
-		cookie, err := s.CreateCertificate(ctx.Request(), &administrator)
+		cookie, err := newSessionCertificate(ctx, factory, &administrator)
 
 		if err != nil {
 			return derp.Wrap(err, location, "Error creating certificate")
@@ -176,6 +174,11 @@ func setupDomainForm(header string) form.Element {
 				Path:        "keyEncryptingKey",
 				Label:       "Master Key",
 				Description: "32 Random Characters",
+			}, {
+				Type:        "toggle",
+				Path:        "secureMode",
+				Label:       "Secure Mode?",
+				Description: "Require signed requests (authorized fetch) for ActivityPub collections",
 			}},
 		}, {
 			Label: "Account Owner",
@@ -227,6 +227,19 @@ func getSetupForm(name string) (form.Element, bool, error) {
 				{Type: "text", Label: "Path", Path: "certificates.path", Options: mapof.Any{"show-if": "certificates.adapter eq S3"}},
 			},
 		}, false, nil
+
+	case "ratelimits":
+		return form.Element{
+			Type:        "layout-vertical",
+			Description: "Maximum number of requests allowed in each 5 minute window.  Leave blank (or 0) to use the default.",
+			Children: []form.Element{
+				{Type: "toggle", Label: "Disable Rate Limits", Path: "rateLimit.disabled"},
+				{Type: "text", Label: "Sign In", Description: "Sign-in, registration, and OAuth attempts per IP address (default: 25)", Path: "rateLimit.auth", Options: mapof.Any{"format": "number", "min": 0}},
+				{Type: "text", Label: "API", Description: "API calls per signed-in account or IP address (default: 1500)", Path: "rateLimit.api", Options: mapof.Any{"format": "number", "min": 0}},
+				{Type: "text", Label: "Inbox", Description: "ActivityPub deliveries per IP address (default: 3000)", Path: "rateLimit.inbox", Options: mapof.Any{"format": "number", "min": 0}},
+				{Type: "text", Label: "Web", Description: "All other requests per signed-in account or IP address (default: 1500)", Path: "rateLimit.web", Options: mapof.Any{"format": "number", "min": 0}},
+			},
+		}, false, nil
 	}
 
 	return form.Element{}, false, derp.NewBadRequestError("handler.getSetupForm", "Invalid form name", name)
@@ -7,6 +7,7 @@ import (
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/steranko"
 	"github.com/labstack/echo/v4"
 )
 
@@ -49,15 +50,55 @@ func PostSignIn(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewInternalError("handler.PostSignIn", "Invalid Domain.")
 		}
 
-		// Try to sign in using Steranko
-		s := factory.Steranko()
-		if err := s.SignIn(ctx); err != nil {
+		// Collect values from the request body
+		var transaction steranko.SigninTransaction
+
+		if err := ctx.Bind(&transaction); err != nil {
+			return derp.Wrap(err, "handler.PostSignIn", "Error binding form data", derp.WithCode(http.StatusBadRequest))
+		}
+
+		// (short) random sleep to thwart timing attacks
+		sleepRandom(500, 1500)
+
+		// Try to authenticate the username/password using Steranko
+		user := model.NewUser()
+
+		if err := factory.Steranko().Authenticate(transaction.Username, transaction.Password, &user); err != nil {
+			sleepRandom(1000, 3000) // (medium) random sleep to punish invalid signin attempts
 			ctx.Response().Header().Add("HX-Trigger", "SigninError")
-			return ctx.HTML(derp.ErrorCode(err), derp.Message(err))
+			return ctx.HTML(http.StatusForbidden, "Invalid username/password.")
+		}
+
+		// Users who signed up on their own may need to confirm their email address,
+		// or wait for a domain owner's approval, before they can sign in.
+		if !user.IsActive() {
+
+			if user.EmailConfirmation.IsPending() {
+				if err := factory.User().ResendEmailConfirmation(&user); err != nil {
+					derp.Report(derp.Wrap(err, "handler.PostSignIn", "Error resending email confirmation"))
+				}
+			}
+
+			ctx.Response().Header().Add("Hx-Redirect", "/signin?message="+inactiveMessage(&user))
+			return ctx.NoContent(http.StatusNoContent)
+		}
+
+		// Sign in, or continue to the two-factor authentication page
+		challenged, err := signInUser(ctx, factory, &user)
+
+		if err != nil {
+			return derp.Wrap(err, "handler.PostSignIn", "Error signing in user")
+		}
+
+		next := ctx.QueryParam("next")
+
+		if challenged {
+			ctx.Response().Header().Add("Hx-Redirect", twoFactorURL(next))
+			return ctx.NoContent(http.StatusNoContent)
 		}
 
 		// If there is a "next" parameter, then redirect to that URL.
-		if next := ctx.QueryParam("next"); next != "" {
+		if next != "" {
 			ctx.Response().Header().Add("Hx-Redirect", next)
 			return ctx.NoContent(http.StatusNoContent)
 		}
@@ -81,6 +122,13 @@ func PostSignOut(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Invalid Domain", derp.WithCode(http.StatusBadRequest))
 		}
 
+		// Revoke the UserSession for this browser
+		if sterankoContext, ok := ctx.(*steranko.Context); ok {
+			if err := revokeCurrentSession(factory, sterankoContext); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error revoking UserSession"))
+			}
+		}
+
 		s := factory.Steranko()
 
 		if err := s.SignOut(ctx); err != nil {
@@ -216,6 +264,11 @@ func PostResetCode(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, "handler.GetResetCode", "Error loading user")
 		}
 
+		// RULE: Passwords must follow the domain's password policy
+		if err := userService.ValidatePassword(txn.Password); err != nil {
+			return derp.Wrap(err, "handler.PostResetCode", "Invalid password")
+		}
+
 		// Update the user with the new password
 		user.SetPassword(txn.Password)
 
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/derp"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// passkeyCookie is the name of the cookie that remembers the WebAuthn challenge
+// while the User's browser talks to their authenticator
+const passkeyCookie = "passkey-challenge"
+
+// Audiences identify the JWT tokens used for each kind of WebAuthn challenge,
+// so that a challenge issued for one ceremony cannot be used for another
+const (
+	passkeySigninAudience    = "passkey-signin"
+	passkeyTwoFactorAudience = "passkey-two-factor"
+	passkeyRegisterAudience  = "passkey-register"
+)
+
+// GetSignInPasskey generates an echo.HandlerFunc that handles GET /signin/passkey requests.
+// It returns the WebAuthn options for signing in with any passkey, without a username or password.
+func GetSignInPasskey(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetSignInPasskey"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		challenge, err := setPasskeyChallenge(ctx, factory, passkeySigninAudience, "")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error creating passkey challenge")
+		}
+
+		return ctx.JSON(http.StatusOK, factory.User().PasskeyRequestOptions(nil, challenge))
+	}
+}
+
+// PostSignInPasskey generates an echo.HandlerFunc that handles POST /signin/passkey requests.
+// It verifies the passkey that the User chose, and signs them in.  Passkeys verify the User's
+// PIN or biometrics, so they do not need a separate two-factor code.
+func PostSignInPasskey(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostSignInPasskey"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		var response webauthn.AssertionResponse
+
+		if err := ctx.Bind(&response); err != nil {
+			return derp.Wrap(err, location, "Error binding passkey response", derp.WithCode(http.StatusBadRequest))
+		}
+
+		claims, err := loadPasskeyChallenge(ctx, factory, passkeySigninAudience)
+
+		if err != nil {
+			return ctx.String(http.StatusUnauthorized, "Passkey sign-in expired.  Please try again.")
+		}
+
+		// Find the User who registered this passkey
+		user := model.NewUser()
+
+		if err := factory.User().LoadByPasskey(response.CredentialID(), &user); err != nil {
+
+			if derp.NotFound(err) {
+				return ctx.String(http.StatusForbidden, "This passkey is not registered on this website.")
+			}
+
+			return derp.Wrap(err, location, "Error loading User")
+		}
+
+		if err := factory.User().VerifyPasskey(&user, claims.ID, response, true); err != nil {
+			return passkeyError(ctx, err)
+		}
+
+		if err := signInWithCertificate(ctx, factory, &user); err != nil {
+			return derp.Wrap(err, location, "Error signing in user")
+		}
+
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetSignInTwoFactorPasskey generates an echo.HandlerFunc that handles GET /signin/two-factor/passkey requests.
+// After a successful password, it returns the WebAuthn options for using one of the User's passkeys as their second factor.
+func GetSignInTwoFactorPasskey(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetSignInTwoFactorPasskey"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		// Users must enter their password first
+		user := model.NewUser()
+
+		if err := loadTwoFactorChallenge(ctx, factory, &user); err != nil {
+			return ctx.String(http.StatusUnauthorized, "Sign-in expired.  Please try again.")
+		}
+
+		if !user.HasPasskeys() {
+			return derp.NewBadRequestError(location, "User does not have any passkeys", user.UserID)
+		}
+
+		challenge, err := setPasskeyChallenge(ctx, factory, passkeyTwoFactorAudience, user.UserID.Hex())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error creating passkey challenge")
+		}
+
+		return ctx.JSON(http.StatusOK, factory.User().PasskeyRequestOptions(&user, challenge))
+	}
+}
+
+// PostSignInTwoFactorPasskey generates an echo.HandlerFunc that handles POST /signin/two-factor/passkey requests.
+// It verifies the User's passkey as their second factor, and signs them in.
+func PostSignInTwoFactorPasskey(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostSignInTwoFactorPasskey"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		var response webauthn.AssertionResponse
+
+		if err := ctx.Bind(&response); err != nil {
+			return derp.Wrap(err, location, "Error binding passkey response", derp.WithCode(http.StatusBadRequest))
+		}
+
+		// Users must enter their password first
+		user := model.NewUser()
+
+		if err := loadTwoFactorChallenge(ctx, factory, &user); err != nil {
+			return ctx.String(http.StatusUnauthorized, "Sign-in expired.  Please try again.")
+		}
+
+		// The passkey challenge must have been issued to the same User
+		claims, err := loadPasskeyChallenge(ctx, factory, passkeyTwoFactorAudience)
+
+		if (err != nil) || (claims.Subject != user.UserID.Hex()) {
+			return ctx.String(http.StatusUnauthorized, "Passkey sign-in expired.  Please try again.")
+		}
+
+		if err := factory.User().VerifyPasskey(&user, claims.ID, response, false); err != nil {
+			return passkeyError(ctx, err)
+		}
+
+		if err := signInWithCertificate(ctx, factory, &user); err != nil {
+			return derp.Wrap(err, location, "Error signing in user")
+		}
+
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
+
+// setPasskeyChallenge creates a new WebAuthn challenge, and stores it (along with the
+// audience and subject it was issued for) in a short-lived challenge cookie.
+func setPasskeyChallenge(ctx echo.Context, factory *domain.Factory, audience string, subject string) (string, error) {
+
+	const location = "handler.setPasskeyChallenge"
+
+	challenge, err := webauthn.NewChallenge()
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error creating challenge")
+	}
+
+	token, err := factory.Steranko().CreateJWT(jwt.RegisteredClaims{
+		ID:        challenge,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(webauthn.Timeout)),
+	})
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error creating JWT")
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     passkeyCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(webauthn.Timeout.Seconds()),
+		Secure:   ctx.IsTLS(),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return challenge, nil
+}
+
+// loadPasskeyChallenge returns the claims from a valid challenge cookie, and removes the
+// cookie so that each challenge can only be used once.
+func loadPasskeyChallenge(ctx echo.Context, factory *domain.Factory, audience string) (jwt.RegisteredClaims, error) {
+
+	const location = "handler.loadPasskeyChallenge"
+
+	cookie, err := ctx.Cookie(passkeyCookie)
+
+	if err != nil {
+		return jwt.RegisteredClaims{}, derp.NewUnauthorizedError(location, "Missing passkey challenge")
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     passkeyCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   ctx.IsTLS(),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	claims := jwt.RegisteredClaims{}
+
+	token, err := jwt.ParseWithClaims(
+		cookie.Value,
+		&claims,
+		factory.JWT().FindJWTKey,
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	if (err != nil) || !token.Valid || (claims.ID == "") {
+		return jwt.RegisteredClaims{}, derp.NewUnauthorizedError(location, "Invalid passkey challenge")
+	}
+
+	return claims, nil
+}
+
+// passkeyError sends the message of a client error back to the browser, which
+// displays it to the User.  All other errors are returned to the error handler.
+func passkeyError(ctx echo.Context, err error) error {
+
+	if derp.IsClientError(err) {
+		derp.Report(err)
+		return ctx.String(derp.ErrorCode(err), derp.Message(derp.RootCause(err)))
+	}
+
+	return derp.Wrap(err, "handler.passkeyError", "Error verifying passkey")
+}
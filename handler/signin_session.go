@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newSessionCertificate starts a new UserSession for this browser, and returns the Steranko
+// certificate that identifies it.  The UserSession ID is stored in the JWT "jti" claim so that
+// the session can be revoked later.
+func newSessionCertificate(ctx echo.Context, factory *domain.Factory, user *model.User) (http.Cookie, error) {
+
+	const location = "handler.newSessionCertificate"
+
+	session, err := factory.UserSession().Create(user.UserID, ctx.Request().UserAgent(), ctx.RealIP())
+
+	if err != nil {
+		return http.Cookie{}, derp.Wrap(err, location, "Error creating UserSession", user.UserID)
+	}
+
+	// Steranko creates the cookie, but the certificate itself must include the UserSession ID
+	certificate, err := factory.Steranko().CreateCertificate(ctx.Request(), user)
+
+	if err != nil {
+		return http.Cookie{}, derp.Wrap(err, location, "Error creating certificate")
+	}
+
+	claims, ok := user.Claims().(model.Authorization)
+
+	if !ok {
+		return http.Cookie{}, derp.NewInternalError(location, "User claims must be a model.Authorization")
+	}
+
+	claims.ID = session.ID()
+
+	if certificate.Value, err = factory.Steranko().CreateJWT(claims); err != nil {
+		return http.Cookie{}, derp.Wrap(err, location, "Error creating JWT")
+	}
+
+	return certificate, nil
+}
+
+// revokeCurrentSession revokes the UserSession that is making this request (if any)
+func revokeCurrentSession(factory *domain.Factory, ctx *steranko.Context) error {
+
+	authorization := getAuthorization(ctx)
+
+	sessionID, err := primitive.ObjectIDFromHex(authorization.ID)
+
+	if err != nil {
+		return nil
+	}
+
+	sessionService := factory.UserSession()
+	session := model.NewUserSession()
+
+	if err := sessionService.LoadByID(authorization.UserID, sessionID, &session); err != nil {
+
+		if derp.NotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, "handler.revokeCurrentSession", "Error loading UserSession", sessionID)
+	}
+
+	return sessionService.Revoke(&session)
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/qrcode"
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// twoFactorCookie is the name of the cookie that remembers a successful password
+// while the User enters their two-factor code
+const twoFactorCookie = "signin-two-factor"
+
+// twoFactorAudience identifies the JWT tokens used for two-factor challenges,
+// so that they cannot be confused with the tokens issued for signed-in Users
+const twoFactorAudience = "two-factor"
+
+// twoFactorTimeout is the amount of time that Users have to enter their two-factor code
+const twoFactorTimeout = 10 * time.Minute
+
+// GetSignInTwoFactor generates an echo.HandlerFunc that handles GET /signin/two-factor requests.
+// It asks for a code from the User's authenticator app (or a passkey), or walks them through setting
+// up a new authenticator app when two-factor authentication is required but not yet enabled.
+func GetSignInTwoFactor(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetSignInTwoFactor"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		// Users must enter their password first
+		user := model.NewUser()
+
+		if err := loadTwoFactorChallenge(ctx, factory, &user); err != nil {
+			return ctx.Redirect(http.StatusSeeOther, "/signin")
+		}
+
+		// Users who have neither an authenticator app nor a passkey must set up an authenticator app now
+		enroll := !user.TwoFactor.IsEnabled() && !user.HasPasskeys()
+
+		data := mapof.Any{
+			"next":    ctx.QueryParam("next"),
+			"enroll":  enroll,
+			"code":    enroll || user.TwoFactor.IsEnabled(),
+			"passkey": user.HasPasskeys(),
+		}
+
+		// Users who are enrolling need to add a new secret to their authenticator app
+		if enroll {
+
+			secret, uri, err := factory.User().BeginTwoFactor(&user)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error starting two-factor setup")
+			}
+
+			qrCode, err := qrcode.DataURL(uri)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error generating QR Code")
+			}
+
+			data["secret"] = totp.FormatSecret(secret)
+			data["qrCode"] = template.URL(qrCode) // nolint:gosec // data: URL is generated by the server
+		}
+
+		htmlTemplate := factory.Domain().Theme().HTMLTemplate
+
+		if err := htmlTemplate.ExecuteTemplate(ctx.Response(), "signin-two-factor", data); err != nil {
+			return derp.Wrap(err, location, "Error executing template")
+		}
+
+		return nil
+	}
+}
+
+// PostSignInTwoFactor generates an echo.HandlerFunc that handles POST /signin/two-factor requests.
+// It verifies the User's two-factor code (or confirms their new authenticator app) and signs them in.
+func PostSignInTwoFactor(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostSignInTwoFactor"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.NewInternalError(location, "Invalid Domain.")
+		}
+
+		var transaction struct {
+			Code string `form:"code"`
+		}
+
+		if err := ctx.Bind(&transaction); err != nil {
+			return derp.Wrap(err, location, "Error binding form data", derp.WithCode(http.StatusBadRequest))
+		}
+
+		// Users must enter their password first
+		user := model.NewUser()
+
+		if err := loadTwoFactorChallenge(ctx, factory, &user); err != nil {
+			ctx.Response().Header().Add("HX-Redirect", "/signin")
+			return ctx.NoContent(http.StatusNoContent)
+		}
+
+		// (short) random sleep to thwart timing attacks
+		sleepRandom(500, 1500)
+
+		userService := factory.User()
+		next := ctx.QueryParam("next")
+
+		// Confirm a new authenticator app, then display recovery codes
+		if !user.TwoFactor.IsEnabled() && !user.HasPasskeys() {
+
+			recoveryCodes, err := userService.EnableTwoFactor(&user, transaction.Code)
+
+			if err != nil {
+				ctx.Response().Header().Add("HX-Trigger", "SigninError")
+				return ctx.HTML(derp.ErrorCode(err), derp.Message(err))
+			}
+
+			if err := signInWithCertificate(ctx, factory, &user); err != nil {
+				return derp.Wrap(err, location, "Error signing in user")
+			}
+
+			data := mapof.Any{
+				"next":          next,
+				"recoveryCodes": strings.Join(recoveryCodes, "\n"),
+			}
+
+			ctx.Response().Header().Add("HX-Retarget", "main")
+			ctx.Response().Header().Add("HX-Reswap", "innerHTML")
+
+			htmlTemplate := factory.Domain().Theme().HTMLTemplate
+
+			if err := htmlTemplate.ExecuteTemplate(ctx.Response(), "signin-recovery-codes", data); err != nil {
+				return derp.Wrap(err, location, "Error executing template")
+			}
+
+			return nil
+		}
+
+		// Verify the code from the User's authenticator app (or a recovery code)
+		if err := userService.VerifyTwoFactor(&user, transaction.Code); err != nil {
+			sleepRandom(1000, 3000) // (medium) random sleep to punish invalid codes
+			ctx.Response().Header().Add("HX-Trigger", "SigninError")
+			return ctx.HTML(derp.ErrorCode(err), derp.Message(err))
+		}
+
+		if err := signInWithCertificate(ctx, factory, &user); err != nil {
+			return derp.Wrap(err, location, "Error signing in user")
+		}
+
+		if next != "" {
+			ctx.Response().Header().Add("Hx-Redirect", next)
+			return ctx.NoContent(http.StatusNoContent)
+		}
+
+		ctx.Response().Header().Add("Hx-Trigger", "SigninSuccess")
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
+
+// signInUser completes a password sign-in.  Users who need two-factor authentication receive a
+// short-lived challenge cookie (and this function returns TRUE) and all others receive the
+// Steranko certificate that signs them in.
+func signInUser(ctx echo.Context, factory *domain.Factory, user *model.User) (bool, error) {
+
+	const location = "handler.signInUser"
+
+	needsTwoFactor, err := factory.User().NeedsTwoFactor(user)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Error checking two-factor policy", user.UserID)
+	}
+
+	if !needsTwoFactor {
+		return false, signInWithCertificate(ctx, factory, user)
+	}
+
+	// Create a JWT that identifies the User for the next step
+	token, err := factory.Steranko().CreateJWT(jwt.RegisteredClaims{
+		Subject:   user.UserID.Hex(),
+		Audience:  jwt.ClaimStrings{twoFactorAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(twoFactorTimeout)),
+	})
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Error creating two-factor challenge")
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     twoFactorCookie,
+		Value:    token,
+		Path:     "/signin",
+		MaxAge:   int(twoFactorTimeout.Seconds()),
+		Secure:   ctx.IsTLS(),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return true, nil
+}
+
+// signInWithCertificate sets the Steranko certificate that signs the User in,
+// and removes any two-factor challenge cookie
+func signInWithCertificate(ctx echo.Context, factory *domain.Factory, user *model.User) error {
+
+	// RULE: Inactive users cannot sign in (no matter how they authenticated)
+	if !user.IsActive() {
+		return derp.NewForbiddenError("handler.signInWithCertificate", "User account is not active", user.UserID)
+	}
+
+	certificate, err := newSessionCertificate(ctx, factory, user)
+
+	if err != nil {
+		return derp.Wrap(err, "handler.signInWithCertificate", "Error creating JWT certificate")
+	}
+
+	ctx.SetCookie(&certificate)
+
+	if _, err := ctx.Cookie(twoFactorCookie); err == nil {
+		ctx.SetCookie(&http.Cookie{
+			Name:     twoFactorCookie,
+			Path:     "/signin",
+			MaxAge:   -1,
+			Secure:   ctx.IsTLS(),
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+
+	return nil
+}
+
+// loadTwoFactorChallenge loads the User identified by a valid two-factor challenge cookie
+func loadTwoFactorChallenge(ctx echo.Context, factory *domain.Factory, user *model.User) error {
+
+	const location = "handler.loadTwoFactorChallenge"
+
+	cookie, err := ctx.Cookie(twoFactorCookie)
+
+	if err != nil {
+		return derp.NewUnauthorizedError(location, "Missing two-factor challenge")
+	}
+
+	claims := jwt.RegisteredClaims{}
+
+	token, err := jwt.ParseWithClaims(
+		cookie.Value,
+		&claims,
+		factory.JWT().FindJWTKey,
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithAudience(twoFactorAudience),
+		jwt.WithExpirationRequired(),
+	)
+
+	if (err != nil) || !token.Valid {
+		return derp.NewUnauthorizedError(location, "Invalid two-factor challenge")
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+
+	if err != nil {
+		return derp.NewUnauthorizedError(location, "Invalid two-factor challenge", claims.Subject)
+	}
+
+	if err := factory.User().LoadByID(userID, user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", userID)
+	}
+
+	return nil
+}
+
+// twoFactorURL returns the URL of the two-factor sign-in page, passing along the "next" parameter
+func twoFactorURL(next string) string {
+
+	if next == "" {
+		return "/signin/two-factor"
+	}
+
+	return "/signin/two-factor?next=" + url.QueryEscape(next)
+}
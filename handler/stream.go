@@ -76,10 +76,12 @@ func buildStream(serverFactory *server.Factory, actionMethod builder.ActionMetho
 			return derp.Wrap(err, location, "Error creating Builder")
 		}
 
-		// Add webmention link header per:
+		// Add webmention and micropub link headers per:
 		// https://www.w3.org/TR/webmention/#sender-discovers-receiver-webmention-endpoint
+		// https://www.w3.org/TR/micropub/#endpoint-discovery
 		if actionMethod == builder.ActionMethodGet {
-			ctx.Response().Header().Set("Link", "/.webmention; rel=\"webmention\"")
+			ctx.Response().Header().Add("Link", "/.webmention; rel=\"webmention\"")
+			ctx.Response().Header().Add("Link", "/.micropub; rel=\"micropub\"")
 		}
 
 		if err := buildHTML(factory, ctx, &b, actionMethod); err != nil {
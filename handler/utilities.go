@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"math/rand"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/steranko"
 	"github.com/golang-jwt/jwt/v5"
@@ -38,6 +42,22 @@ func getAuthorization(ctx *steranko.Context) model.Authorization {
 	return model.NewAuthorization()
 }
 
+// loadSignedInUser loads the User who is signed in to this request
+func loadSignedInUser(ctx *steranko.Context, userService *service.User, user *model.User) error {
+
+	authorization := getAuthorization(ctx)
+
+	if !authorization.IsAuthenticated() {
+		return derp.NewUnauthorizedError("handler.loadSignedInUser", "You must be signed in to use this feature")
+	}
+
+	if err := userService.LoadByID(authorization.UserID, user); err != nil {
+		return derp.Wrap(err, "handler.loadSignedInUser", "Error loading User", authorization.UserID)
+	}
+
+	return nil
+}
+
 func isUserVisible(context *steranko.Context, user *model.User) bool {
 
 	authorization := getAuthorization(context)
@@ -80,3 +100,10 @@ func cleanQueryParams(values url.Values) mapof.String {
 
 	return result
 }
+
+// sleepRandom pauses for a random number of milliseconds between min and max,
+// which makes it harder to time (or to rapidly repeat) sign-in attempts.
+func sleepRandom(min int, max int) {
+	sleepTime := rand.Intn(max-min+1) + min // nolint:gosec // timing jitter does not need a secure random number
+	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
+}
@@ -1,7 +1,8 @@
 package handler
 
 import (
-	"bytes"
+	"encoding/hex"
+	"io"
 	"net/http"
 
 	"github.com/EmissarySocial/emissary/model"
@@ -13,6 +14,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// webSubMaxBodySize is the largest "fat ping" that we will accept from a WebSub hub
+const webSubMaxBodySize = 10 * 1024 * 1024
+
 type webSubConfirmation struct {
 	Mode      string `query:"hub.mode"`
 	Topic     string `query:"hub.topic"`
@@ -97,11 +101,12 @@ func PostWebSubClient(serverFactory *server.Factory) echo.HandlerFunc {
 
 	return func(ctx echo.Context) error {
 
-		const location = "handler.GetWebSubClient"
+		const location = "handler.PostWebSubClient"
 
-		var body bytes.Buffer
+		// Read the (possibly empty) request body
+		body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, webSubMaxBodySize))
 
-		if err := ctx.Bind(&body); err != nil {
+		if err != nil {
 			return derp.Wrap(err, location, "Error reading request body")
 		}
 
@@ -139,24 +144,30 @@ func PostWebSubClient(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewBadRequestError(location, "Not a WebSub follow", following)
 		}
 
-		// Validate the HMAC signature
-		if following.Secret != "" {
+		// Validate the HMAC signature.  Per the WebSub spec, notifications with
+		// invalid signatures are acknowledged, but otherwise ignored.
+		if following.Secret == "" {
+
+			// Without a secret, the body cannot be trusted, so re-poll the feed instead
+			body = nil
+
+		} else {
 			header := ctx.Request().Header.Get("X-Hub-Signature")
 			method, signature := list.Equal(header).Split()
+			signatureBytes, err := hex.DecodeString(signature.String())
 
-			hmac.Validate(method, following.Secret, body.Bytes(), signature.Bytes())
+			if (err != nil) || !hmac.Validate(method, following.Secret, body, signatureBytes) {
+				derp.Report(derp.NewForbiddenError(location, "Invalid WebSub signature", following.FollowingID, header))
+				return ctx.NoContent(http.StatusOK)
+			}
 		}
 
-		// TODO: MEDIUM: WebSub - Handle FatPings.
-		// Right now, we re-poll the entire feed. But we could save a round-trip by
-		// inspecting the body and parsing any additional data that's been "Fat Ping-ed" to us.
-
-		// Connect to the the WebSub server
-		if err := followingService.Connect(following); err != nil {
-			return derp.Wrap(err, location, "Error connecting to following", following)
+		// Save any items that were "fat pinged" to us, or re-poll the feed if there are none.
+		if err := followingService.ReceiveWebSub(following, ctx.Request().Header.Get("Content-Type"), body); err != nil {
+			return derp.Wrap(err, location, "Error receiving WebSub notification", following)
 		}
 
 		// Woot woot!
-		return nil
+		return ctx.NoContent(http.StatusOK)
 	}
 }
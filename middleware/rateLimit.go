@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/config"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/ratelimit"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimit middleware turns away clients that make too many requests.  Requests are sorted into
+// separate buckets for authentication attempts, API calls, inbox deliveries, and everything else.
+// Clients are identified by their verified account when signed in, or by their IP address otherwise,
+// so that they cannot get a fresh bucket just by changing an unverified header.
+// Limits are read from the server configuration on every request, so changes apply immediately.
+func RateLimit(factory *server.Factory) echo.MiddlewareFunc {
+
+	limiter := ratelimit.New(config.RateLimitWindow, config.RateLimitMaxBuckets)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(ctx echo.Context) error {
+
+			limits := factory.Config().RateLimit
+
+			if limits.Disabled {
+				return next(ctx)
+			}
+
+			bucket, key, limit := rateLimitBucket(ctx, factory, limits)
+			result := limiter.Allow(bucket+":"+key, limit)
+
+			// Mastodon clients use these headers to slow themselves down before they hit the limit
+			header := ctx.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", result.Reset.UTC().Format(time.RFC3339))
+
+			if result.Allowed {
+				return next(ctx)
+			}
+
+			// Tell the client when to try again (rounded up to the next second)
+			retryAfter := int(time.Until(result.Reset).Seconds()) + 1
+			header.Set("Retry-After", strconv.Itoa(retryAfter))
+
+			if bucket == rateLimitBucketAPI {
+				return ctx.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many requests"})
+			}
+
+			return ctx.String(http.StatusTooManyRequests, "Too many requests.  Please try again later.")
+		}
+	}
+}
+
+const (
+	rateLimitBucketWeb   = "web"
+	rateLimitBucketAuth  = "auth"
+	rateLimitBucketAPI   = "api"
+	rateLimitBucketInbox = "inbox"
+)
+
+// rateLimitBucket returns the bucket that a request is counted in, the key that identifies
+// the client within that bucket, and the maximum number of requests allowed.
+func rateLimitBucket(ctx echo.Context, factory *server.Factory, limits config.RateLimit) (string, string, int) {
+
+	request := ctx.Request()
+	path := request.URL.Path
+
+	// Sign-in, registration, password resets, and OAuth token exchanges
+	if (request.Method == http.MethodPost) && isAuthPath(path) {
+		return rateLimitBucketAuth, ctx.RealIP(), limits.AuthLimit()
+	}
+
+	// ActivityPub inbox deliveries from other servers.  Signatures are not verified until the
+	// activity is processed, so they cannot be trusted here.
+	if (request.Method == http.MethodPost) && isInboxPath(path) {
+		return rateLimitBucketInbox, ctx.RealIP(), limits.InboxLimit()
+	}
+
+	// Mastodon API calls are counted per account when the access token is valid
+	if strings.HasPrefix(path, "/api/") {
+
+		if userID, ok := apiUserID(ctx, factory); ok {
+			return rateLimitBucketAPI, "user:" + userID, limits.APILimit()
+		}
+
+		return rateLimitBucketAPI, ctx.RealIP(), limits.APILimit()
+	}
+
+	// Everything else is counted per signed-in account, or per IP address
+	if sterankoContext, ok := ctx.(*steranko.Context); ok {
+		if claims, err := sterankoContext.Authorization(); err == nil {
+			if authorization, ok := claims.(*model.Authorization); ok && authorization.IsAuthenticated() {
+				return rateLimitBucketWeb, "user:" + authorization.UserID.Hex(), limits.WebLimit()
+			}
+		}
+	}
+
+	return rateLimitBucketWeb, ctx.RealIP(), limits.WebLimit()
+}
+
+// isAuthPath returns TRUE if the path accepts passwords, codes, or other credentials
+func isAuthPath(path string) bool {
+	return (path == "/signin") ||
+		strings.HasPrefix(path, "/signin/") ||
+		(path == "/register") ||
+		strings.HasPrefix(path, "/register/") ||
+		strings.HasPrefix(path, "/oauth/")
+}
+
+// isInboxPath returns TRUE if the path is an ActivityPub inbox for a User, Stream, or the Domain
+func isInboxPath(path string) bool {
+	return strings.HasSuffix(path, "/pub/inbox") || (path == "/@application/inbox")
+}
+
+// apiUserID returns the ID of the User who signed the request's access token.
+// It returns FALSE if the request does not include a valid access token.
+func apiUserID(ctx echo.Context, factory *server.Factory) (string, bool) {
+
+	request := ctx.Request()
+
+	if !strings.HasPrefix(request.Header.Get("Authorization"), "Bearer ") {
+		return "", false
+	}
+
+	domainFactory, err := factory.ByContext(ctx)
+
+	if err != nil {
+		return "", false
+	}
+
+	token, err := domainFactory.JWT().Parse(request)
+
+	if (err != nil) || !token.Valid {
+		return "", false
+	}
+
+	if authorization, ok := token.Claims.(*model.Authorization); ok && authorization.IsAuthenticated() {
+		return authorization.UserID.Hex(), true
+	}
+
+	return "", false
+}
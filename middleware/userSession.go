@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+)
+
+// UserSession middleware signs out browsers whose UserSession has been revoked.  It removes
+// the Steranko certificate from the request (so that the rest of the application treats it as
+// anonymous) and expires the certificate cookie in the browser.
+func UserSession(factory *server.Factory) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(ctx echo.Context) error {
+
+			request := ctx.Request()
+			cookieName := certificateCookieName(request)
+			cookie, err := request.Cookie(cookieName)
+
+			// Anonymous requests do not have a session to validate
+			if err != nil {
+				return next(ctx)
+			}
+
+			domainFactory, err := factory.ByContext(ctx)
+
+			if err != nil {
+				return derp.NewForbiddenError("middleware.UserSession", "Unrecognized domain", request.URL.Hostname(), err)
+			}
+
+			// Invalid certificates are rejected by Steranko, so they do not need to be handled here
+			claims, err := domainFactory.Steranko().GetAuthorizationFromToken(cookie.Value)
+
+			if err != nil {
+				return next(ctx)
+			}
+
+			authorization, ok := claims.(*model.Authorization)
+
+			if !ok {
+				return next(ctx)
+			}
+
+			if err := domainFactory.UserSession().Validate(authorization, ctx.RealIP()); err != nil {
+				removeCookie(request, cookieName)
+
+				// nolint:errcheck
+				domainFactory.Steranko().SignOut(ctx)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// certificateCookieName returns the name of the cookie that Steranko uses for this request
+func certificateCookieName(request *http.Request) string {
+
+	if request.TLS != nil {
+		return "__Host-Authorization"
+	}
+
+	return "Authorization"
+}
+
+// removeCookie removes a cookie from the request headers
+func removeCookie(request *http.Request, name string) {
+
+	cookies := request.Cookies()
+	request.Header.Del("Cookie")
+
+	for _, cookie := range cookies {
+		if cookie.Name != name {
+			request.AddCookie(cookie)
+		}
+	}
+}
@@ -49,7 +49,12 @@ func (attachment *Attachment) ID() string {
 
 func (attachment *Attachment) CalcURL(host string) string {
 
-	if attachment.ObjectType == AttachmentTypeUser {
+	switch attachment.ObjectType {
+
+	case AttachmentTypeMicropub:
+		return host + "/.micropub/media/" + attachment.AttachmentID.Hex()
+
+	case AttachmentTypeUser:
 		return host + "/@" + attachment.ObjectID.Hex() + "/pub/avatar/" + attachment.AttachmentID.Hex()
 	}
 
@@ -10,7 +10,7 @@ func AttachmentSchema() schema.Element {
 		Properties: schema.ElementMap{
 			"attachmentId": schema.String{Format: "objectId"},
 			"objectId":     schema.String{Format: "objectId"},
-			"objectType":   schema.String{Enum: []string{AttachmentTypeStream, AttachmentTypeUser}},
+			"objectType":   schema.String{Enum: []string{AttachmentTypeMicropub, AttachmentTypeStream, AttachmentTypeUser}},
 			"original":     schema.String{},
 			"rank":         schema.Integer{},
 			"height":       schema.Integer{},
@@ -3,5 +3,9 @@ package model
 // AttachmentTypeStream represents an attachment that is owned by a Stream
 const AttachmentTypeStream = "Stream"
 
+// AttachmentTypeMicropub represents a file that a User uploaded to the Micropub media endpoint,
+// which has not yet been claimed by a Stream.  Its ObjectID is the ID of the User.
+const AttachmentTypeMicropub = "Micropub"
+
 // AttachmentTypeUser represents an attachment that is owned by a User
 const AttachmentTypeUser = "User"
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -24,3 +25,16 @@ func TestAttachmentSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &attachment, table)
 }
+
+func TestAttachmentCalcURL(t *testing.T) {
+
+	objectID, _ := primitive.ObjectIDFromHex("876543218765432187654321")
+
+	attachment := NewAttachment(AttachmentTypeStream, objectID)
+	attachment.AttachmentID, _ = primitive.ObjectIDFromHex("123456781234567812345678")
+	require.Equal(t, "https://local.test/876543218765432187654321/attachments/123456781234567812345678", attachment.CalcURL("https://local.test"))
+
+	// Unclaimed Micropub uploads are served from the media endpoint
+	attachment.ObjectType = AttachmentTypeMicropub
+	require.Equal(t, "https://local.test/.micropub/media/123456781234567812345678", attachment.CalcURL("https://local.test"))
+}
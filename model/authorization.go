@@ -15,6 +15,7 @@ type Authorization struct {
 	DomainOwner bool                 `json:"O,omitempty"` // If TRUE, then this user is an owner of this domain
 	APIUser     bool                 `json:"A,omitempty"` // If TRUE, then this user is an API user
 	Scope       string               `json:"S,omitempty"` // OAuth Scopes that this user has access to
+	IndieAuth   bool                 `json:"I,omitempty"` // If TRUE, then this token was issued to an IndieAuth client, and cannot be used with the Mastodon API
 
 	jwt.RegisteredClaims // By embedding the "RegisteredClaims" object, this record can support standard behaviors, like token expiration, etc.
 }
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/labstack/gommon/random"
+)
+
+// DataExportTimeout is how long an archive can take to build.  Archives that are still
+// pending after this time are treated as failed, so that they can be requested again.
+const DataExportTimeout = 12 * time.Hour
+
+// DataExport tracks the most recent archive of personal data that a User has requested.
+// Only one archive is kept per User, and it is removed once it expires.
+type DataExport struct {
+	AuthCode   string `bson:"authCode,omitempty"`   // Secret code included in the download link
+	CreateDate int64  `bson:"createDate,omitempty"` // Unix epoch seconds when the archive was requested
+	ReadyDate  int64  `bson:"readyDate,omitempty"`  // Unix epoch seconds when the archive finished building
+	ExpireDate int64  `bson:"expireDate,omitempty"` // Unix epoch seconds when the archive will be removed
+	FailDate   int64  `bson:"failDate,omitempty"`   // Unix epoch seconds when the archive could not be built
+}
+
+// NewDataExport returns a new (pending) DataExport
+func NewDataExport() DataExport {
+	return DataExport{
+		AuthCode:   random.String(64),
+		CreateDate: time.Now().Unix(),
+	}
+}
+
+// IsEmpty returns TRUE if no archive has been requested
+func (export DataExport) IsEmpty() bool {
+	return export.CreateDate == 0
+}
+
+// IsPending returns TRUE if the archive has been requested, and is still being built
+func (export DataExport) IsPending() bool {
+	return export.isBuilding() && (export.CreateDate > time.Now().Add(-DataExportTimeout).Unix())
+}
+
+// IsFailed returns TRUE if the archive could not be built, or has taken too long to build
+func (export DataExport) IsFailed() bool {
+	return (export.FailDate > 0) || (export.isBuilding() && !export.IsPending())
+}
+
+// isBuilding returns TRUE if the archive has been requested, but has not finished or failed
+func (export DataExport) isBuilding() bool {
+	return (export.CreateDate > 0) && (export.ReadyDate == 0) && (export.FailDate == 0)
+}
+
+// IsReady returns TRUE if the archive can be downloaded
+func (export DataExport) IsReady() bool {
+	return (export.ReadyDate > 0) && !export.IsExpired()
+}
+
+// IsExpired returns TRUE if the archive has been built, but is too old to download
+func (export DataExport) IsExpired() bool {
+	return (export.ExpireDate > 0) && (export.ExpireDate < time.Now().Unix())
+}
+
+// IsValid returns TRUE if the code matches an archive that can be downloaded
+func (export DataExport) IsValid(code string) bool {
+
+	if !export.IsReady() {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(export.AuthCode), []byte(code)) == 1
+}
+
+// Fail marks the archive as failed, so that it can be requested again
+func (export *DataExport) Fail() {
+	export.FailDate = time.Now().Unix()
+}
+
+// Ready marks the archive as ready to download for the provided duration
+func (export *DataExport) Ready(duration time.Duration) {
+	export.ReadyDate = time.Now().Unix()
+	export.ExpireDate = time.Now().Add(duration).Unix()
+}
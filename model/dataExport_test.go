@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataExport(t *testing.T) {
+
+	export := DataExport{}
+	require.True(t, export.IsEmpty())
+	require.False(t, export.IsPending())
+	require.False(t, export.IsReady())
+
+	// Requested, but not built yet
+	export = NewDataExport()
+	require.False(t, export.IsEmpty())
+	require.True(t, export.IsPending())
+	require.False(t, export.IsReady())
+	require.False(t, export.IsValid(export.AuthCode))
+
+	// Ready to download
+	export.Ready(time.Hour)
+	require.False(t, export.IsPending())
+	require.True(t, export.IsReady())
+	require.True(t, export.IsValid(export.AuthCode))
+	require.False(t, export.IsValid(""))
+	require.False(t, export.IsValid("wrong"))
+
+	// Failed
+	export = NewDataExport()
+	export.Fail()
+	require.False(t, export.IsPending())
+	require.True(t, export.IsFailed())
+	require.False(t, export.IsReady())
+
+	// Taking too long to build
+	export = NewDataExport()
+	export.CreateDate = time.Now().Add(-DataExportTimeout - time.Minute).Unix()
+	require.False(t, export.IsPending())
+	require.True(t, export.IsFailed())
+
+	// Expired
+	export = NewDataExport()
+	export.Ready(-time.Hour)
+	require.True(t, export.IsExpired())
+	require.False(t, export.IsReady())
+	require.False(t, export.IsValid(export.AuthCode))
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataImport tracks the progress of a CSV file (exported from Mastodon) that a User is
+// importing in the background.  Rows are stored until the import is complete, along
+// with a list of the rows that could not be imported.
+type DataImport struct {
+	DataImportID primitive.ObjectID `json:"dataImportId" bson:"_id"`                    // Unique identifier for this DataImport
+	UserID       primitive.ObjectID `json:"userId"       bson:"userId"`                 // User who uploaded the file
+	Type         string             `json:"type"         bson:"type"`                   // Type of records being imported (e.g. "FOLLOWING", "BLOCK", "MUTE")
+	Filename     string             `json:"filename"     bson:"filename"`               // Name of the uploaded file
+	Rows         []DataImportRow    `json:"rows"         bson:"rows,omitempty"`         // Rows that are waiting to be imported.  Removed once the import is complete.
+	TotalRows    int                `json:"totalRows"    bson:"totalRows"`              // Number of rows in the file
+	Processed    int                `json:"processed"    bson:"processed"`              // Number of rows that have been processed so far
+	Imported     int                `json:"imported"     bson:"imported"`               // Number of new records that were created
+	Errors       []DataImportError  `json:"errors"       bson:"errors,omitempty"`       // Rows that could not be imported
+	CompleteDate int64              `json:"completeDate" bson:"completeDate,omitempty"` // Unix epoch seconds when the import finished
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// DataImportRow is a single value to import, along with its line number in the original file
+type DataImportRow struct {
+	Line  int    `json:"line"  bson:"line"`
+	Value string `json:"value" bson:"value"`
+}
+
+// DataImportError describes a row that could not be imported
+type DataImportError struct {
+	Line    int    `json:"line"    bson:"line"`
+	Value   string `json:"value"   bson:"value"`
+	Message string `json:"message" bson:"message"`
+}
+
+// NewDataImport returns a fully initialized DataImport object
+func NewDataImport() DataImport {
+	return DataImport{
+		DataImportID: primitive.NewObjectID(),
+		Rows:         make([]DataImportRow, 0),
+		Errors:       make([]DataImportError, 0),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (dataImport *DataImport) ID() string {
+	return dataImport.DataImportID.Hex()
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// IsComplete returns TRUE if every row has been processed
+func (dataImport DataImport) IsComplete() bool {
+	return dataImport.CompleteDate > 0
+}
+
+// Skipped returns the number of rows that were processed successfully, but did not
+// create a new record (for instance, because the User already follows an account)
+func (dataImport DataImport) Skipped() int {
+	return dataImport.Processed - dataImport.Imported - len(dataImport.Errors)
+}
+
+// Percent returns the percentage of rows that have been processed so far
+func (dataImport DataImport) Percent() int {
+
+	if dataImport.TotalRows == 0 {
+		return 100
+	}
+
+	return dataImport.Processed * 100 / dataImport.TotalRows
+}
+
+// AddError records a row that could not be imported
+func (dataImport *DataImport) AddError(row DataImportRow, message string) {
+	dataImport.Errors = append(dataImport.Errors, DataImportError{
+		Line:    row.Line,
+		Value:   row.Value,
+		Message: message,
+	})
+}
@@ -0,0 +1,16 @@
+package model
+
+// DataImportTypeFollowing imports accounts to follow from Mastodon's following_accounts.csv
+const DataImportTypeFollowing = "FOLLOWING"
+
+// DataImportTypeBlock imports blocked accounts from Mastodon's blocked_accounts.csv
+const DataImportTypeBlock = "BLOCK"
+
+// DataImportTypeMute imports muted accounts from Mastodon's muted_accounts.csv
+const DataImportTypeMute = "MUTE"
+
+// DataImportTypeDomainBlock imports blocked domains from Mastodon's blocked_domains.csv
+const DataImportTypeDomainBlock = "DOMAIN-BLOCK"
+
+// DataImportTypeBookmark imports bookmarked posts from Mastodon's bookmarks.csv
+const DataImportTypeBookmark = "BOOKMARK"
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataImport_Progress(t *testing.T) {
+
+	dataImport := NewDataImport()
+	require.Equal(t, 100, dataImport.Percent())
+
+	dataImport.TotalRows = 4
+	dataImport.Processed = 3
+	dataImport.Imported = 1
+	dataImport.AddError(DataImportRow{Line: 2, Value: "nobody@example.com"}, "Account not found")
+
+	require.Equal(t, 75, dataImport.Percent())
+	require.Equal(t, 1, dataImport.Skipped())
+	require.False(t, dataImport.IsComplete())
+	require.Equal(t, DataImportError{Line: 2, Value: "nobody@example.com", Message: "Account not found"}, dataImport.Errors[0])
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"github.com/EmissarySocial/emissary/tools/set"
 	"github.com/benpate/data/journal"
 	"github.com/benpate/rosetta/mapof"
@@ -9,23 +11,30 @@ import (
 
 // Domain represents an account or node on this server.
 type Domain struct {
-	DomainID        primitive.ObjectID `bson:"_id"`             // This is the internal ID for the domain.  It should not be available via the web service.
-	Label           string             `bson:"label"`           // Human-friendly name displayed at the top of this domain
-	Description     string             `bson:"description"`     // Human-friendly description of this domain
-	ThemeID         string             `bson:"themeId"`         // ID of the theme to use for this domain
-	Forward         string             `bson:"forward"`         // If present, then all requests for this domain should be forwarded to the designated new domain.
-	Clients         set.Map[Client]    `bson:"clients"`         // External connections (e.g. Facebook, Twitter, etc.)
-	ThemeData       mapof.Any          `bson:"themeData"`       // Custom data stored in this domain
-	SignupForm      SignupForm         `bson:"signupForm"`      // Valid signup forms to make new accounts.
-	DatabaseVersion uint               `bson:"databaseVersion"` // Version of the database schema
-	journal.Journal `json:"-" bson:",inline"`
+	DomainID              primitive.ObjectID `bson:"_id"`                   // This is the internal ID for the domain.  It should not be available via the web service.
+	Label                 string             `bson:"label"`                 // Human-friendly name displayed at the top of this domain
+	Description           string             `bson:"description"`           // Human-friendly description of this domain
+	ThemeID               string             `bson:"themeId"`               // ID of the theme to use for this domain
+	Forward               string             `bson:"forward"`               // If present, then all requests for this domain should be forwarded to the designated new domain.
+	Clients               set.Map[Client]    `bson:"clients"`               // External connections (e.g. Facebook, Twitter, etc.)
+	ThemeData             mapof.Any          `bson:"themeData"`             // Custom data stored in this domain
+	SignupForm            SignupForm         `bson:"signupForm"`            // Valid signup forms to make new accounts.
+	PasswordPolicy        PasswordPolicy     `bson:"passwordPolicy"`        // Rules that new passwords must follow
+	MicropubTemplateID    string             `bson:"micropubTemplateId"`    // Template to use for new posts created via Micropub
+	MentionAllowlist      string             `bson:"mentionAllowlist"`      // Domains whose WebMentions are approved automatically (one per line)
+	RequireOwnerTwoFactor bool               `bson:"requireOwnerTwoFactor"` // If TRUE, then domain owners must use two-factor authentication to sign in
+	KeyRotationDays       int                `bson:"keyRotationDays"`       // Number of days between automatic rotations of ActivityPub signing keys (zero to disable)
+	DatabaseVersion       uint               `bson:"databaseVersion"`       // Version of the database schema
+	journal.Journal       `json:"-" bson:",inline"`
 }
 
 // NewDomain returns a fully initialized Domain object
 func NewDomain() Domain {
 	return Domain{
-		Clients:   set.NewMap[Client](),
-		ThemeData: mapof.NewAny(),
+		Clients:        set.NewMap[Client](),
+		ThemeData:      mapof.NewAny(),
+		SignupForm:     NewSignupForm(),
+		PasswordPolicy: NewPasswordPolicy(),
 	}
 }
 
@@ -50,6 +59,29 @@ func (domain Domain) NotEmpty() bool {
 	return !domain.IsEmpty()
 }
 
+// AllowsMentionsFrom returns TRUE if WebMentions from the provided hostname
+// are approved automatically (without waiting for the owner to review them).
+// Each entry in the MentionAllowlist also matches all of its subdomains.
+func (domain Domain) AllowsMentionsFrom(hostname string) bool {
+
+	hostname = strings.ToLower(hostname)
+
+	if hostname == "" {
+		return false
+	}
+
+	for _, allowed := range strings.Fields(strings.ReplaceAll(domain.MentionAllowlist, ",", " ")) {
+
+		allowed = strings.ToLower(allowed)
+
+		if (hostname == allowed) || strings.HasSuffix(hostname, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HasSignupForm returns TRUE if this domain includes a valid signup form.
 func (domain *Domain) HasSignupForm() bool {
 	return domain.SignupForm.Active
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/benpate/rosetta/null"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -8,12 +9,17 @@ import (
 func DomainSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"domainId":    schema.String{Format: "objectId", Required: true},
-			"themeId":     schema.String{MaxLength: 128, Required: true},
-			"label":       schema.String{MinLength: 1, MaxLength: 128, Required: true},
-			"description": schema.String{MinLength: 1, MaxLength: 1024, Required: false},
-			"forward":     schema.String{Format: "url", Required: false},
-			"signupForm":  SignupFormSchema(),
+			"domainId":              schema.String{Format: "objectId", Required: true},
+			"themeId":               schema.String{MaxLength: 128, Required: true},
+			"label":                 schema.String{MinLength: 1, MaxLength: 128, Required: true},
+			"description":           schema.String{MinLength: 1, MaxLength: 1024, Required: false},
+			"forward":               schema.String{Format: "url", Required: false},
+			"signupForm":            SignupFormSchema(),
+			"passwordPolicy":        PasswordPolicySchema(),
+			"micropubTemplateId":    schema.String{MaxLength: 128},
+			"mentionAllowlist":      schema.String{MaxLength: 4096},
+			"requireOwnerTwoFactor": schema.Boolean{},
+			"keyRotationDays":       schema.Integer{Minimum: null.NewInt64(0), Maximum: null.NewInt64(3650)},
 		},
 	}
 }
@@ -29,6 +35,9 @@ func (domain *Domain) GetPointer(name string) (any, bool) {
 	case "signupForm":
 		return &domain.SignupForm, true
 
+	case "passwordPolicy":
+		return &domain.PasswordPolicy, true
+
 	case "themeId":
 		return &domain.ThemeID, true
 
@@ -40,6 +49,18 @@ func (domain *Domain) GetPointer(name string) (any, bool) {
 
 	case "forward":
 		return &domain.Forward, true
+
+	case "micropubTemplateId":
+		return &domain.MicropubTemplateID, true
+
+	case "mentionAllowlist":
+		return &domain.MentionAllowlist, true
+
+	case "requireOwnerTwoFactor":
+		return &domain.RequireOwnerTwoFactor, true
+
+	case "keyRotationDays":
+		return &domain.KeyRotationDays, true
 	}
 
 	return nil, false
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDomainSchema(t *testing.T) {
@@ -21,7 +22,33 @@ func TestDomainSchema(t *testing.T) {
 		{"signupForm.message", "SIGNUP MESSAGE", nil},
 		{"signupForm.groupId", "123456781234567812345678", nil},
 		{"signupForm.active", "true", true},
+		{"signupForm.mode", SignupModeApproval, nil},
+		{"signupForm.requireEmail", true, nil},
+		{"passwordPolicy.minLength", 16, nil},
+		{"passwordPolicy.requireDigit", "true", true},
+		{"micropubTemplateId", "outbox-message", nil},
+		{"mentionAllowlist", "example.com\nother.site", nil},
+		{"requireOwnerTwoFactor", true, nil},
+		{"keyRotationDays", 90, nil},
+		{"keyRotationDays", "365", 365},
 	}
 
 	tableTest_Schema(t, &s, &domain, table)
 }
+
+func TestDomain_AllowsMentionsFrom(t *testing.T) {
+
+	domain := NewDomain()
+	domain.MentionAllowlist = "example.com\nFriend.Blog, other.site"
+
+	require.True(t, domain.AllowsMentionsFrom("example.com"))
+	require.True(t, domain.AllowsMentionsFrom("www.example.com"))
+	require.True(t, domain.AllowsMentionsFrom("friend.blog"))
+	require.True(t, domain.AllowsMentionsFrom("other.site"))
+	require.False(t, domain.AllowsMentionsFrom("notexample.com"))
+	require.False(t, domain.AllowsMentionsFrom("example.com.evil.net"))
+	require.False(t, domain.AllowsMentionsFrom(""))
+
+	domain.MentionAllowlist = ""
+	require.False(t, domain.AllowsMentionsFrom("example.com"))
+}
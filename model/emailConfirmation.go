@@ -0,0 +1,54 @@
+package model
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/labstack/gommon/random"
+)
+
+// EmailConfirmation tracks whether a new User has confirmed their email address.
+// Users with a pending confirmation cannot sign in.
+type EmailConfirmation struct {
+	AuthCode    string `bson:"authCode,omitempty"`    // Secret code sent to the User's email address.  If empty, then there is no pending confirmation.
+	CreateDate  int64  `bson:"createDate,omitempty"`  // Unix epoch seconds when the current code was created
+	ExpireDate  int64  `bson:"expireDate,omitempty"`  // Unix epoch seconds when the current code expires
+	ConfirmDate int64  `bson:"confirmDate,omitempty"` // Unix epoch seconds when the User confirmed their email address
+}
+
+// NewEmailConfirmation returns a new (pending) EmailConfirmation that expires after the provided duration
+func NewEmailConfirmation(duration time.Duration) EmailConfirmation {
+
+	return EmailConfirmation{
+		AuthCode:   random.String(64),
+		CreateDate: time.Now().Unix(),
+		ExpireDate: time.Now().Add(duration).Unix(),
+	}
+}
+
+// IsPending returns TRUE if the User has not yet confirmed their email address
+func (confirmation EmailConfirmation) IsPending() bool {
+	return confirmation.AuthCode != ""
+}
+
+// IsExpired returns TRUE if the current confirmation code has expired
+func (confirmation EmailConfirmation) IsExpired() bool {
+	return confirmation.ExpireDate < time.Now().Unix()
+}
+
+// IsValid returns TRUE if the code matches the pending confirmation, and has not expired
+func (confirmation EmailConfirmation) IsValid(code string) bool {
+
+	if !confirmation.IsPending() || confirmation.IsExpired() {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(confirmation.AuthCode), []byte(code)) == 1
+}
+
+// Confirm marks the email address as confirmed
+func (confirmation *EmailConfirmation) Confirm() {
+	confirmation.AuthCode = ""
+	confirmation.ExpireDate = 0
+	confirmation.ConfirmDate = time.Now().Unix()
+}
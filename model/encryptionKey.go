@@ -1,20 +1,30 @@
 package model
 
 import (
+	"time"
+
 	"github.com/benpate/data/journal"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// EncryptionKeyEncodingPlaintext means that the private key is stored as a plaintext PEM
 const EncryptionKeyEncodingPlaintext = "plaintext"
 
+// EncryptionKeyEncodingAESGCM means that the private key PEM is encrypted with the domain's
+// key encrypting key (using AES-GCM) and stored as a base64 string
+const EncryptionKeyEncodingAESGCM = "AES-GCM"
+
 type EncryptionKey struct {
 	EncryptionKeyID primitive.ObjectID `json:"encryptionKeyId" bson:"_id"`
 	ParentType      string             `json:"parentType"      bson:"parentType"`
 	ParentID        primitive.ObjectID `json:"parentId"        bson:"parentId"`
-	Encoding        string             `json:"encoding"        bson:"encoding"`
-	PublicPEM       string             `json:"publicPEM"       bson:"publicPEM"`
-	PrivatePEM      string             `json:"privatePEM"      bson:"privatePEM"`
+	Algorithm       string             `json:"algorithm"       bson:"algorithm"`  // Type of key (RSA or Ed25519)
+	Fragment        string             `json:"fragment"        bson:"fragment"`   // URL fragment that identifies this key in the Actor document
+	Encoding        string             `json:"encoding"        bson:"encoding"`   // How the private key is stored (plaintext or AES-GCM)
+	PublicPEM       string             `json:"publicPEM"       bson:"publicPEM"`  // Public key, in PEM format
+	PrivatePEM      string             `json:"privatePEM"      bson:"privatePEM"` // Private key, in PEM format, possibly encrypted (see Encoding)
+	ExpireDate      int64              `json:"expireDate"      bson:"expireDate"` // Unix epoch seconds when a rotated key stops being valid.  Zero for the current key.
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -22,6 +32,8 @@ type EncryptionKey struct {
 func NewEncryptionKey() EncryptionKey {
 	return EncryptionKey{
 		EncryptionKeyID: primitive.NewObjectID(),
+		Algorithm:       EncryptionKeyAlgorithmRSA,
+		Fragment:        EncryptionKeyMainFragment,
 	}
 }
 
@@ -31,9 +43,12 @@ func EncryptionKeySchema() schema.Element {
 			"encryptionKeyId": schema.String{Format: "objectId", Required: true},
 			"parentId":        schema.String{Format: "objectId", Required: true},
 			"parentType":      schema.String{Required: true},
+			"algorithm":       schema.String{Enum: []string{EncryptionKeyAlgorithmRSA, EncryptionKeyAlgorithmEd25519}, Required: true},
+			"fragment":        schema.String{Required: true},
 			"encoding":        schema.String{Required: true},
 			"publicPEM":       schema.String{Required: true},
 			"privatePEM":      schema.String{Required: true},
+			"expireDate":      schema.Integer{BitSize: 64},
 		},
 	}
 }
@@ -45,3 +60,42 @@ func EncryptionKeySchema() schema.Element {
 func (encryptionKey *EncryptionKey) ID() string {
 	return encryptionKey.EncryptionKeyID.Hex()
 }
+
+/******************************
+ * Other Methods
+ ******************************/
+
+// IsEncrypted returns TRUE if the private key is encrypted with the domain's key encrypting key
+func (encryptionKey *EncryptionKey) IsEncrypted() bool {
+	return encryptionKey.Encoding == EncryptionKeyEncodingAESGCM
+}
+
+// IsRSA returns TRUE if this is an RSA key
+func (encryptionKey *EncryptionKey) IsRSA() bool {
+	return encryptionKey.Algorithm != EncryptionKeyAlgorithmEd25519
+}
+
+// IsEd25519 returns TRUE if this is an Ed25519 key
+func (encryptionKey *EncryptionKey) IsEd25519() bool {
+	return encryptionKey.Algorithm == EncryptionKeyAlgorithmEd25519
+}
+
+// IsCurrent returns TRUE if this key has not been rotated
+func (encryptionKey *EncryptionKey) IsCurrent() bool {
+	return encryptionKey.ExpireDate == 0
+}
+
+// IsExpired returns TRUE if this key has been rotated, and its grace period has ended
+func (encryptionKey *EncryptionKey) IsExpired() bool {
+	return !encryptionKey.IsCurrent() && (encryptionKey.ExpireDate < time.Now().Unix())
+}
+
+// KeyFragment returns the URL fragment that identifies this key in the Actor document
+func (encryptionKey *EncryptionKey) KeyFragment() string {
+
+	if encryptionKey.Fragment == "" {
+		return EncryptionKeyMainFragment
+	}
+
+	return encryptionKey.Fragment
+}
@@ -5,3 +5,16 @@ const EncryptionKeyTypeUser = "User"
 
 // EncryptionKeyTypeStream identifies an EncryptionKey that is owned by a Stream/Actor
 const EncryptionKeyTypeStream = "Stream"
+
+// EncryptionKeyTypeDomain identifies an EncryptionKey that is owned by the Domain/Application Actor
+const EncryptionKeyTypeDomain = "Domain"
+
+// EncryptionKeyAlgorithmRSA identifies an RSA EncryptionKey, which is published as the Actor's "publicKey"
+const EncryptionKeyAlgorithmRSA = "RSA"
+
+// EncryptionKeyAlgorithmEd25519 identifies an Ed25519 EncryptionKey, which is published as one
+// of the Actor's "assertionMethod" values (FEP-521a)
+const EncryptionKeyAlgorithmEd25519 = "Ed25519"
+
+// EncryptionKeyMainFragment is the URL fragment of an Actor's original RSA key
+const EncryptionKeyMainFragment = "main-key"
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptionKey_Rotation(t *testing.T) {
+
+	key := NewEncryptionKey()
+	require.True(t, key.IsRSA())
+	require.True(t, key.IsCurrent())
+	require.False(t, key.IsExpired())
+	require.Equal(t, "main-key", key.KeyFragment())
+
+	// Keys stay valid during their grace period
+	key.ExpireDate = time.Now().Add(time.Hour).Unix()
+	require.False(t, key.IsCurrent())
+	require.False(t, key.IsExpired())
+
+	// ...and expire once it has ended
+	key.ExpireDate = time.Now().Add(-time.Hour).Unix()
+	require.True(t, key.IsExpired())
+}
+
+func TestEncryptionKey_Algorithm(t *testing.T) {
+
+	key := NewEncryptionKey()
+	key.Algorithm = EncryptionKeyAlgorithmEd25519
+	key.Fragment = "key-1"
+
+	require.True(t, key.IsEd25519())
+	require.False(t, key.IsRSA())
+	require.Equal(t, "key-1", key.KeyFragment())
+
+	// Legacy keys do not have an algorithm or fragment
+	key.Algorithm = ""
+	key.Fragment = ""
+	require.True(t, key.IsRSA())
+	require.Equal(t, "main-key", key.KeyFragment())
+}
@@ -30,6 +30,8 @@ func (summary FollowerSummary) MethodIcon() string {
 		return "rss"
 	case FollowMethodWebSub:
 		return "websub"
+	case FollowMethodRssCloud:
+		return "rss-cloud"
 	case FollowMethodActivityPub:
 		return "activitypub"
 	}
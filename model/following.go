@@ -3,6 +3,7 @@ package model
 import (
 	"github.com/benpate/data/journal"
 	"github.com/benpate/digit"
+	"github.com/benpate/rosetta/sliceof"
 	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,29 +11,30 @@ import (
 // Following is a model object that represents a user's following to an external data feed.
 // Currently, the only supported feed types are: RSS, Atom, and JSON Feed.  Others may be added in the future.
 type Following struct {
-	FollowingID     primitive.ObjectID `json:"followingId"     bson:"_id"`             // Unique Identifier of this record
-	UserID          primitive.ObjectID `json:"userId"          bson:"userId"`          // ID of the stream that owns this "following"
-	FolderID        primitive.ObjectID `json:"folderId"        bson:"folderId"`        // ID of the folder to put new messages into
-	Folder          string             `json:"folder"          bson:"folder"`          // Name of the folder to put new messages into
-	Label           string             `json:"label"           bson:"label"`           // Label of this "following" record
-	Notes           string             `json:"notes"         bson:"notes"`             // Notes about this "following" record, entered by the user.
-	URL             string             `json:"url"             bson:"url"`             // Human-Facing URL that is being followed.
-	ProfileURL      string             `json:"profileUrl"      bson:"profileUrl"`      // Updated, computer-facing URL that is being followed.
-	ImageURL        string             `json:"imageUrl"        bson:"imageUrl"`        // URL of an image that represents this "following"
-	Behavior        string             `json:"behavior"        bson:"behavior"`        // Behavior determines the types of records to import from this Actor [POSTS+REPLIES]
-	RuleAction      string             `json:"ruleAction"      bson:"ruleAction"`      // RuleAction determines the types of records to rule from this Actor [IGNORE, LABEL, MUTE, BLOCK ]
-	CollapseThreads bool               `json:"collapseThreads" bson:"collapseThreads"` // If TRUE, traverse responses and import the initial post that initiated a thread
-	IsPublic        bool               `json:"isPublic"        bson:"isPublic"`        // If TRUE, this following is visible to the public
-	Links           digit.LinkSet      `json:"links"           bson:"links"`           // List of links can be used to update this following.
-	Method          string             `json:"method"          bson:"method"`          // Method used to update this feed (POLL, WEBSUB, RSS-CLOUD, ACTIVITYPUB)
-	Secret          string             `json:"secret"          bson:"secret"`          // Secret used to authenticate this feed (if required)
-	Status          string             `json:"status"          bson:"status"`          // Status of the last poll of Following (NEW, CONNECTING, POLLING, SUCCESS, FAILURE)
-	StatusMessage   string             `json:"statusMessage"   bson:"statusMessage"`   // Optional message describing the status of the last poll
-	LastPolled      int64              `json:"lastPolled"      bson:"lastPolled"`      // Unix Timestamp of the last date that this resource was retrieved.
-	PollDuration    int                `json:"pollDuration"    bson:"pollDuration"`    // Time (in hours) to wait between polling this resource.
-	NextPoll        int64              `json:"nextPoll"        bson:"nextPoll"`        // Unix Timestamp of the next time that this resource should be polled.
-	PurgeDuration   int                `json:"purgeDuration"   bson:"purgeDuration"`   // Time (in days) to wait before purging old messages
-	ErrorCount      int                `json:"errorCount"      bson:"errorCount"`      // Number of times that this "following" has failed to load (for exponential backoff)
+	FollowingID      primitive.ObjectID `json:"followingId"     bson:"_id"`                         // Unique Identifier of this record
+	UserID           primitive.ObjectID `json:"userId"          bson:"userId"`                      // ID of the stream that owns this "following"
+	FolderID         primitive.ObjectID `json:"folderId"        bson:"folderId"`                    // ID of the folder to put new messages into
+	Folder           string             `json:"folder"          bson:"folder"`                      // Name of the folder to put new messages into
+	Label            string             `json:"label"           bson:"label"`                       // Label of this "following" record
+	Notes            string             `json:"notes"         bson:"notes"`                         // Notes about this "following" record, entered by the user.
+	URL              string             `json:"url"             bson:"url"`                         // Human-Facing URL that is being followed.
+	ProfileURL       string             `json:"profileUrl"      bson:"profileUrl"`                  // Updated, computer-facing URL that is being followed.
+	ImageURL         string             `json:"imageUrl"        bson:"imageUrl"`                    // URL of an image that represents this "following"
+	Behavior         string             `json:"behavior"        bson:"behavior"`                    // Behavior determines the types of records to import from this Actor [POSTS+REPLIES]
+	RuleAction       string             `json:"ruleAction"      bson:"ruleAction"`                  // RuleAction determines the types of records to rule from this Actor [IGNORE, LABEL, MUTE, BLOCK ]
+	CollapseThreads  bool               `json:"collapseThreads" bson:"collapseThreads"`             // If TRUE, traverse responses and import the initial post that initiated a thread
+	IsPublic         bool               `json:"isPublic"        bson:"isPublic"`                    // If TRUE, this following is visible to the public
+	Links            digit.LinkSet      `json:"links"           bson:"links"`                       // List of links can be used to update this following.
+	Method           string             `json:"method"          bson:"method"`                      // Method used to update this feed (POLL, WEBSUB, RSS-CLOUD, ACTIVITYPUB)
+	Secret           string             `json:"secret"          bson:"secret"`                      // Secret used to authenticate this feed (if required)
+	Status           string             `json:"status"          bson:"status"`                      // Status of the last poll of Following (NEW, CONNECTING, POLLING, SUCCESS, FAILURE)
+	StatusMessage    string             `json:"statusMessage"   bson:"statusMessage"`               // Optional message describing the status of the last poll
+	LastPolled       int64              `json:"lastPolled"      bson:"lastPolled"`                  // Unix Timestamp of the last date that this resource was retrieved.
+	PollDuration     int                `json:"pollDuration"    bson:"pollDuration"`                // Time (in hours) to wait between polling this resource.
+	NextPoll         int64              `json:"nextPoll"        bson:"nextPoll"`                    // Unix Timestamp of the next time that this resource should be polled.
+	PurgeDuration    int                `json:"purgeDuration"   bson:"purgeDuration"`               // Time (in days) to wait before purging old messages
+	ErrorCount       int                `json:"errorCount"      bson:"errorCount"`                  // Number of times that this "following" has failed to load (for exponential backoff)
+	ImportProperties sliceof.String     `json:"importProperties" bson:"importProperties,omitempty"` // Content properties to import from h-feed web pages (name, summary, content, photo).  Empty imports everything.
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -40,15 +42,16 @@ type Following struct {
 // NewFollowing returns a fully initialized Following object
 func NewFollowing() Following {
 	return Following{
-		FollowingID:     primitive.NewObjectID(),
-		Status:          FollowingStatusNew,
-		Method:          FollowMethodPoll,
-		Behavior:        FollowingBehaviorPostsAndReplies,
-		RuleAction:      RuleActionLabel,
-		Links:           make(digit.LinkSet, 0),
-		CollapseThreads: true, // default behavior is to collapse threads
-		PollDuration:    24,   // default poll interval is 24 hours
-		PurgeDuration:   14,   // default purge interval is 14 days
+		FollowingID:      primitive.NewObjectID(),
+		Status:           FollowingStatusNew,
+		Method:           FollowMethodPoll,
+		Behavior:         FollowingBehaviorPostsAndReplies,
+		RuleAction:       RuleActionLabel,
+		Links:            make(digit.LinkSet, 0),
+		ImportProperties: make(sliceof.String, 0),
+		CollapseThreads:  true, // default behavior is to collapse threads
+		PollDuration:     24,   // default poll interval is 24 hours
+		PurgeDuration:    14,   // default purge interval is 14 days
 	}
 }
 
@@ -125,6 +128,17 @@ func (following *Following) SetLinks(newLinks ...digit.Link) {
 	}
 }
 
+// ImportsProperty returns TRUE if the named content property (name, summary, content, photo)
+// should be imported from h-feed web pages.  If no properties are selected, then all are imported.
+func (following *Following) ImportsProperty(name string) bool {
+
+	if len(following.ImportProperties) == 0 {
+		return true
+	}
+
+	return following.ImportProperties.Contains(name)
+}
+
 func (following Following) IsZero() bool {
 	return (following.UserID == primitive.NilObjectID) && (following.FolderID == primitive.NilObjectID)
 }
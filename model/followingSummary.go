@@ -36,6 +36,8 @@ func (summary FollowingSummary) Icon() string {
 		icon = "rss"
 	case FollowMethodWebSub:
 		icon = "websub"
+	case FollowMethodRssCloud:
+		icon = "rss-cloud"
 	}
 
 	switch summary.Status {
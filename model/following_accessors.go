@@ -10,26 +10,27 @@ import (
 func FollowingSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"followingId":     schema.String{Format: "objectId"},
-			"userId":          schema.String{Format: "objectId"},
-			"folderId":        schema.String{Format: "objectId", Required: true},
-			"label":           schema.String{MaxLength: 128},
-			"notes":           schema.String{MaxLength: 1024},
-			"url":             schema.String{Required: true, MaxLength: 1024},
-			"profileUrl":      schema.String{Format: "url", MaxLength: 1024},
-			"imageUrl":        schema.String{Format: "url", MaxLength: 1024},
-			"behavior":        schema.String{Enum: []string{FollowingBehaviorPosts, FollowingBehaviorPostsAndReplies}, Default: FollowingBehaviorPostsAndReplies, Required: true},
-			"ruleAction":      schema.String{Enum: []string{FollowingRuleActionIgnore, RuleActionMute, RuleActionLabel, RuleActionBlock}, Default: RuleActionLabel, Required: true},
-			"collapseThreads": schema.Boolean{Default: null.NewBool(true)},
-			"isPublic":        schema.Boolean{Default: null.NewBool(false)},
-			"method":          schema.String{Enum: []string{FollowMethodPoll, FollowMethodWebSub, FollowMethodActivityPub}},
-			"status":          schema.String{Enum: []string{FollowingStatusNew, FollowingStatusLoading, FollowingStatusSuccess, FollowingStatusFailure}},
-			"statusMessage":   schema.String{MaxLength: 1024},
-			"lastPolled":      schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
-			"pollDuration":    schema.Integer{Minimum: null.NewInt64(1)},
-			"purgeDuration":   schema.Integer{Minimum: null.NewInt64(0)},
-			"nextPoll":        schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
-			"errorCount":      schema.Integer{Minimum: null.NewInt64(0)},
+			"followingId":      schema.String{Format: "objectId"},
+			"userId":           schema.String{Format: "objectId"},
+			"folderId":         schema.String{Format: "objectId", Required: true},
+			"label":            schema.String{MaxLength: 128},
+			"notes":            schema.String{MaxLength: 1024},
+			"url":              schema.String{Required: true, MaxLength: 1024},
+			"profileUrl":       schema.String{Format: "url", MaxLength: 1024},
+			"imageUrl":         schema.String{Format: "url", MaxLength: 1024},
+			"behavior":         schema.String{Enum: []string{FollowingBehaviorPosts, FollowingBehaviorPostsAndReplies}, Default: FollowingBehaviorPostsAndReplies, Required: true},
+			"ruleAction":       schema.String{Enum: []string{FollowingRuleActionIgnore, RuleActionMute, RuleActionLabel, RuleActionBlock}, Default: RuleActionLabel, Required: true},
+			"collapseThreads":  schema.Boolean{Default: null.NewBool(true)},
+			"isPublic":         schema.Boolean{Default: null.NewBool(false)},
+			"method":           schema.String{Enum: []string{FollowMethodPoll, FollowMethodWebSub, FollowMethodRssCloud, FollowMethodActivityPub}},
+			"status":           schema.String{Enum: []string{FollowingStatusNew, FollowingStatusLoading, FollowingStatusSuccess, FollowingStatusFailure}},
+			"statusMessage":    schema.String{MaxLength: 1024},
+			"lastPolled":       schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"pollDuration":     schema.Integer{Minimum: null.NewInt64(1)},
+			"purgeDuration":    schema.Integer{Minimum: null.NewInt64(0)},
+			"nextPoll":         schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"errorCount":       schema.Integer{Minimum: null.NewInt64(0)},
+			"importProperties": schema.Array{Items: schema.String{Enum: []string{FollowingImportName, FollowingImportSummary, FollowingImportContent, FollowingImportPhoto}}},
 		},
 	}
 }
@@ -96,6 +97,9 @@ func (following *Following) GetPointer(name string) (any, bool) {
 
 	case "errorCount":
 		return &following.ErrorCount, true
+
+	case "importProperties":
+		return &following.ImportProperties, true
 	}
 
 	return nil, false
@@ -11,6 +11,8 @@ const FollowMethodPoll = "POLL"
 // https://websub.rocks
 const FollowMethodWebSub = "WEBSUB"
 
+// FollowMethodRssCloud represents an rssCloud subscription
+// https://www.rssboard.org/rsscloud-interface
 const FollowMethodRssCloud = "RSSCLOUD"
 
 // FollowingStatusNew represents a new following that has not yet been polled
@@ -42,3 +44,15 @@ const FollowingBehaviorPostsAndReplies = "POSTS+REPLIES"
 
 // FollowingBehaviorPosts declares that only Posts (not Replies) should be imported from a followed account
 const FollowingBehaviorPosts = "POSTS"
+
+// FollowingImportName declares that the name (title) of each h-entry should be imported from a followed web page
+const FollowingImportName = "name"
+
+// FollowingImportSummary declares that the summary of each h-entry should be imported from a followed web page
+const FollowingImportSummary = "summary"
+
+// FollowingImportContent declares that the full content of each h-entry should be imported from a followed web page
+const FollowingImportContent = "content"
+
+// FollowingImportPhoto declares that the photo of each h-entry should be imported from a followed web page
+const FollowingImportPhoto = "photo"
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFollowingSchema(t *testing.T) {
@@ -35,3 +36,17 @@ func TestFollowingSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &following, table)
 }
+
+func TestFollowing_ImportsProperty(t *testing.T) {
+
+	following := NewFollowing()
+
+	// Everything is imported by default
+	require.True(t, following.ImportsProperty(FollowingImportName))
+	require.True(t, following.ImportsProperty(FollowingImportPhoto))
+
+	// Otherwise, only the selected properties are imported
+	following.ImportProperties = []string{FollowingImportSummary}
+	require.True(t, following.ImportsProperty(FollowingImportSummary))
+	require.False(t, following.ImportsProperty(FollowingImportContent))
+}
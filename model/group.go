@@ -7,8 +7,9 @@ import (
 )
 
 type Group struct {
-	GroupID primitive.ObjectID `json:"groupId" bson:"_id"`
-	Label   string             `json:"label"   bson:"label"`
+	GroupID          primitive.ObjectID `json:"groupId"          bson:"_id"`
+	Label            string             `json:"label"            bson:"label"`
+	RequireTwoFactor bool               `json:"requireTwoFactor" bson:"requireTwoFactor"` // If TRUE, then members must use two-factor authentication to sign in
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -20,7 +21,7 @@ func NewGroup() Group {
 }
 
 func GroupFields() []string {
-	return []string{"_id", "label"}
+	return []string{"_id", "label", "requireTwoFactor"}
 }
 
 func (userSummary Group) Fields() []string {
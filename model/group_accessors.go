@@ -8,8 +8,9 @@ import (
 func GroupSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"groupId": schema.String{Format: "objectId"},
-			"label":   schema.String{MaxLength: 64, Required: true},
+			"groupId":          schema.String{Format: "objectId"},
+			"label":            schema.String{MaxLength: 64, Required: true},
+			"requireTwoFactor": schema.Boolean{},
 		},
 	}
 }
@@ -18,6 +19,17 @@ func GroupSchema() schema.Element {
  * Getter Interfaces
  ******************************************/
 
+func (group *Group) GetBoolOK(name string) (bool, bool) {
+
+	switch name {
+
+	case "requireTwoFactor":
+		return group.RequireTwoFactor, true
+	}
+
+	return false, false
+}
+
 func (group *Group) GetStringOK(name string) (string, bool) {
 
 	switch name {
@@ -36,6 +48,18 @@ func (group *Group) GetStringOK(name string) (string, bool) {
  * Setter Interfaces
  ******************************************/
 
+func (group *Group) SetBool(name string, value bool) bool {
+
+	switch name {
+
+	case "requireTwoFactor":
+		group.RequireTwoFactor = value
+		return true
+	}
+
+	return false
+}
+
 func (group *Group) SetString(name string, value string) bool {
 
 	switch name {
@@ -14,6 +14,7 @@ func TestGroupSchema(t *testing.T) {
 	table := []tableTestItem{
 		{"groupId", "5e5e5e5e5e5e5e5e5e5e5e5e", nil},
 		{"label", "LABEL", nil},
+		{"requireTwoFactor", true, nil},
 	}
 
 	tableTest_Schema(t, &s, &group, table)
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invitation is a link that lets new people sign up on this domain, even when the
+// signup form is invite-only.  Invitations can be limited to a number of uses, and
+// can expire after a period of time.
+type Invitation struct {
+	InvitationID primitive.ObjectID `json:"invitationId" bson:"_id"`        // Unique identifier for this Invitation
+	UserID       primitive.ObjectID `json:"userId"       bson:"userId"`     // User who created this Invitation
+	Code         string             `json:"code"         bson:"code"`       // Secret code included in the invitation link
+	Note         string             `json:"note"         bson:"note"`       // Private note to remember who this Invitation was for
+	MaxUses      int                `json:"maxUses"      bson:"maxUses"`    // Maximum number of accounts that can be created with this Invitation.  Zero means unlimited.
+	UseCount     int                `json:"useCount"     bson:"useCount"`   // Number of accounts that have been created with this Invitation
+	ExpireDate   int64              `json:"expireDate"   bson:"expireDate"` // Unix epoch seconds when this Invitation expires.  Zero means never.
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewInvitation returns a fully initialized Invitation object
+func NewInvitation() Invitation {
+	return Invitation{
+		InvitationID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (invitation *Invitation) ID() string {
+	return invitation.InvitationID.Hex()
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// IsExpired returns TRUE if this Invitation has passed its expiration date
+func (invitation Invitation) IsExpired() bool {
+	return (invitation.ExpireDate > 0) && (invitation.ExpireDate < time.Now().Unix())
+}
+
+// IsUsedUp returns TRUE if this Invitation has already been used as many times as it allows
+func (invitation Invitation) IsUsedUp() bool {
+	return (invitation.MaxUses > 0) && (invitation.UseCount >= invitation.MaxUses)
+}
+
+// IsUsable returns TRUE if new people can still sign up with this Invitation
+func (invitation Invitation) IsUsable() bool {
+	return !invitation.IsExpired() && !invitation.IsUsedUp()
+}
+
+// RemainingUses returns the number of times that this Invitation can still be used,
+// or -1 if it can be used an unlimited number of times.
+func (invitation Invitation) RemainingUses() int {
+
+	if invitation.MaxUses == 0 {
+		return -1
+	}
+
+	if invitation.IsUsedUp() {
+		return 0
+	}
+
+	return invitation.MaxUses - invitation.UseCount
+}
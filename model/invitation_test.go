@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitation_Unlimited(t *testing.T) {
+
+	invitation := NewInvitation()
+	invitation.UseCount = 100
+
+	require.False(t, invitation.IsExpired())
+	require.False(t, invitation.IsUsedUp())
+	require.True(t, invitation.IsUsable())
+	require.Equal(t, -1, invitation.RemainingUses())
+}
+
+func TestInvitation_MaxUses(t *testing.T) {
+
+	invitation := NewInvitation()
+	invitation.MaxUses = 2
+
+	require.True(t, invitation.IsUsable())
+	require.Equal(t, 2, invitation.RemainingUses())
+
+	invitation.UseCount = 1
+	require.True(t, invitation.IsUsable())
+	require.Equal(t, 1, invitation.RemainingUses())
+
+	invitation.UseCount = 2
+	require.True(t, invitation.IsUsedUp())
+	require.False(t, invitation.IsUsable())
+	require.Equal(t, 0, invitation.RemainingUses())
+}
+
+func TestInvitation_Expired(t *testing.T) {
+
+	invitation := NewInvitation()
+	invitation.ExpireDate = time.Now().Add(time.Hour).Unix()
+	require.True(t, invitation.IsUsable())
+
+	invitation.ExpireDate = time.Now().Add(-time.Hour).Unix()
+	require.True(t, invitation.IsExpired())
+	require.False(t, invitation.IsUsable())
+}
@@ -7,7 +7,7 @@ import (
 // OriginLink represents the original source of a stream that has been imported into Emissary.
 // This could be an external ActivityPub server, RSS Feed, or Tweet.
 type OriginLink struct {
-	Type        string             `json:"type"        bson:"type,omitempty"`        // The type of message that this document (DIRECT, LIKE, DISLIKE, REPLY, ANNOUNCE)
+	Type        string             `json:"type"        bson:"type,omitempty"`        // The type of message that this document (DIRECT, LIKE, DISLIKE, REPLY, ANNOUNCE, BOOKMARK)
 	FollowingID primitive.ObjectID `json:"followingId" bson:"followingId,omitempty"` // Unique ID of a document in this database
 	Label       string             `json:"label"       bson:"label,omitempty"`       // Human-friendly label of the origin
 	URL         string             `json:"url"         bson:"url,omitempty"`         // Public URL of the origin
@@ -52,6 +52,9 @@ func (origin OriginLink) Icon() string {
 
 	case OriginTypeAnnounce:
 		return "star"
+
+	case OriginTypeBookmark:
+		return "bookmark"
 	}
 
 	return "question-square"
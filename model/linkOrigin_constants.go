@@ -14,3 +14,6 @@ const OriginTypeLike = "LIKE"
 
 // OriginTypeBoost identifies a link that was retrieved because of a "Dislike" of an existing post
 const OriginTypeDislike = "DISLIKE"
+
+// OriginTypeBookmark identifies a link that was retrieved because of a "Bookmark" of an existing post
+const OriginTypeBookmark = "BOOKMARK"
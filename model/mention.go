@@ -14,6 +14,7 @@ type Mention struct {
 	StateID   string             `json:"stateId"   bson:"stateId"`  // State of this mention (Validated, Pending, Invalid)
 	Origin    OriginLink         `json:"origin"    bson:"origin"`   // Origin information of the site that mentions this object
 	Author    PersonLink         `json:"author"    bson:"author"`   // Author information of the person who mentioned this object
+	Content   string             `json:"content"   bson:"content"`  // Text content of a reply (if this mention is a reply)
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -27,6 +28,20 @@ func NewMention() Mention {
 	}
 }
 
+// Fields returns the list of fields that are loaded when querying Mentions
+func (mention Mention) Fields() []string {
+	return []string{
+		"_id",
+		"objectId",
+		"type",
+		"stateId",
+		"origin",
+		"author",
+		"content",
+		"createDate",
+	}
+}
+
 /******************************************
  * data.Object Interface
  ******************************************/
@@ -36,3 +51,22 @@ func NewMention() Mention {
 func (mention Mention) ID() string {
 	return mention.MentionID.Hex()
 }
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsValidated returns TRUE if this Mention has been approved for display
+func (mention Mention) IsValidated() bool {
+	return mention.StateID == MentionStatusValidated
+}
+
+// IsPending returns TRUE if this Mention is waiting for the owner's approval
+func (mention Mention) IsPending() bool {
+	return mention.StateID == MentionStatusPending
+}
+
+// IsReply returns TRUE if this Mention is a reply to the mentioned object
+func (mention Mention) IsReply() bool {
+	return mention.Origin.Type == OriginTypeReply
+}
@@ -14,6 +14,7 @@ func MentionSchema() schema.Element {
 			"stateId":   schema.String{Enum: []string{MentionStatusValidated, MentionStatusPending, MentionStatusInvalid}},
 			"origin":    OriginLinkSchema(),
 			"author":    PersonLinkSchema(),
+			"content":   schema.String{},
 		},
 	}
 }
@@ -37,6 +38,9 @@ func (mention *Mention) GetPointer(name string) (any, bool) {
 
 	case "stateId":
 		return &mention.StateID, true
+
+	case "content":
+		return &mention.Content, true
 	}
 
 	return nil, false
@@ -16,7 +16,10 @@ func TestMention(t *testing.T) {
 		{"mentionId", "123412341234123412341234", nil},
 		{"objectId", "123456781234567812345678", nil},
 		{"type", "Stream", nil},
+		{"stateId", "PENDING", nil},
+		{"content", "This is a reply", nil},
 		{"origin.type", "LIKE", nil},
+		{"origin.type", "BOOKMARK", nil},
 		{"origin.label", "LABEL", nil},
 		{"origin.url", "https://source.url", nil},
 		{"origin.imageUrl", "http://entry.photo.url/", nil},
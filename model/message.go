@@ -25,6 +25,7 @@ type Message struct {
 	StateID     string                     `json:"stateId"      bson:"stateId"`               // StateID of this message (UNREAD,READ,MUTED,NEW-REPLIES)
 	ReadDate    int64                      `json:"readDate"     bson:"readDate"`              // Unix timestamp of the date/time when this Message was read.  If unread, this is MaxInt64.
 	PublishDate int64                      `json:"publishDate"  bson:"publishDate,omitempty"` // Unix timestamp of the date/time when this Message was published
+	EditDate    int64                      `json:"editDate"     bson:"editDate,omitempty"`    // Unix timestamp of the date/time when the author last edited this Message (if known)
 	Rank        int64                      `json:"rank"         bson:"rank"`                  // Sort rank for this message (publishDate * 1000 + sequence number)
 
 	journal.Journal `json:"-" bson:",inline"`
@@ -268,6 +269,22 @@ func (message *Message) AddReference(reference OriginLink) bool {
 	return true
 }
 
+// ApplyEdits copies values from a newer version of this Message, but only if the
+// author has edited it since the last time it was received.
+// It returns TRUE if the message has been updated.
+func (message *Message) ApplyEdits(edited Message) bool {
+
+	// RULE: Only apply edits that are newer than the values we already have
+	if edited.EditDate <= message.EditDate {
+		return false
+	}
+
+	message.SocialRole = edited.SocialRole
+	message.InReplyTo = edited.InReplyTo
+	message.EditDate = edited.EditDate
+	return true
+}
+
 // SetMyResponse
 func (message *Message) SetMyResponse(responseType string) {
 	message.MyResponse = responseType
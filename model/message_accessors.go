@@ -25,6 +25,7 @@ func MessageSchema() schema.Element {
 			"myResponse":  schema.String{Enum: []string{vocab.ActivityTypeAnnounce, vocab.ActivityTypeLike, vocab.ActivityTypeDislike}},
 			"stateId":     schema.String{Enum: []string{MessageStateUnread, MessageStateRead, MessageStateMuted, MessageStateNewReplies}},
 			"publishDate": schema.Integer{BitSize: 64},
+			"editDate":    schema.Integer{BitSize: 64},
 			"readDate":    schema.Integer{BitSize: 64},
 			"rank":        schema.Integer{BitSize: 64},
 		},
@@ -62,6 +63,9 @@ func (message *Message) GetPointer(name string) (any, bool) {
 	case "publishDate":
 		return &message.PublishDate, true
 
+	case "editDate":
+		return &message.EditDate, true
+
 	case "readDate":
 		return &message.ReadDate, true
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMessageSchema(t *testing.T) {
@@ -21,6 +22,7 @@ func TestMessageSchema(t *testing.T) {
 		{"references.1.url", "https://another.reference.url", nil},
 		{"folderId", "123456123456123456123456", nil},
 		{"publishDate", "123", int64(123)},
+		{"editDate", "456", int64(456)},
 		{"readDate", 456, int64(456)},
 		{"rank", "123", int64(123)},
 		{"myResponse", vocab.ActivityTypeLike, nil},
@@ -28,3 +30,27 @@ func TestMessageSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &activity, table)
 }
+
+func TestMessage_ApplyEdits(t *testing.T) {
+
+	message := NewMessage()
+	message.SocialRole = vocab.ObjectTypeNote
+	message.EditDate = 100
+
+	// Older versions are ignored
+	older := NewMessage()
+	older.SocialRole = vocab.ObjectTypeArticle
+	older.EditDate = 50
+	require.False(t, message.ApplyEdits(older))
+	require.Equal(t, vocab.ObjectTypeNote, message.SocialRole)
+
+	// Newer versions are applied
+	newer := NewMessage()
+	newer.SocialRole = vocab.ObjectTypeArticle
+	newer.InReplyTo = "https://example.com/original"
+	newer.EditDate = 200
+	require.True(t, message.ApplyEdits(newer))
+	require.Equal(t, vocab.ObjectTypeArticle, message.SocialRole)
+	require.Equal(t, "https://example.com/original", message.InReplyTo)
+	require.Equal(t, int64(200), message.EditDate)
+}
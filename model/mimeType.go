@@ -20,6 +20,8 @@ const MimeTypeHTML = "text/html"
 
 const MimeTypeImage = "image/*"
 
+const MimeTypeOPML = "text/x-opml"
+
 const MimeTypeRSS = "application/rss+xml"
 
 const MimeTypeXML = "application/xml"
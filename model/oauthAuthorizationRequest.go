@@ -3,6 +3,7 @@ package model
 import (
 	"strings"
 
+	"github.com/EmissarySocial/emissary/tools/indieauth"
 	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/slice"
 )
@@ -16,13 +17,20 @@ import (
 // https://docs.joinmastodon.org/methods/oauth/#authorize
 // GET /oauth/authorize
 // Returns: Authorization code
+//
+// IndieAuth clients also send state, PKCE, and "me" parameters
+// https://indieauth.spec.indieweb.org/#authorization-request
 type OAuthAuthorizationRequest struct {
-	ResponseType string `query:"response_type" form:"response_type"`
-	ClientID     string `query:"client_id"     form:"client_id"`
-	RedirectURI  string `query:"redirect_uri"  form:"redirect_uri"`
-	Scope        string `query:"scope"         form:"scope"`
-	ForceLogin   bool   `query:"force_login"   form:"force_login"`
-	Language     string `query:"language"      form:"language"`
+	ResponseType        string `query:"response_type"         form:"response_type"`
+	ClientID            string `query:"client_id"             form:"client_id"`
+	RedirectURI         string `query:"redirect_uri"          form:"redirect_uri"`
+	Scope               string `query:"scope"                 form:"scope"`
+	ForceLogin          bool   `query:"force_login"           form:"force_login"`
+	Language            string `query:"language"              form:"language"`
+	State               string `query:"state"                 form:"state"`
+	CodeChallenge       string `query:"code_challenge"        form:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method" form:"code_challenge_method"`
+	Me                  string `query:"me"                    form:"me"`
 }
 
 func NewOAuthAuthorizationRequest() OAuthAuthorizationRequest {
@@ -30,6 +38,11 @@ func NewOAuthAuthorizationRequest() OAuthAuthorizationRequest {
 }
 
 func (req OAuthAuthorizationRequest) Scopes() []string {
+
+	if req.Scope == "" {
+		return []string{}
+	}
+
 	return strings.Split(req.Scope, " ")
 }
 
@@ -39,6 +52,10 @@ func (req *OAuthAuthorizationRequest) Validate(app OAuthClient) error {
 
 	const location = "model.OAuthAuthorizationRequest.Validate"
 
+	if app.IsIndieAuth() {
+		return req.validateIndieAuth(app)
+	}
+
 	if len(app.RedirectURIs) == 0 {
 		return derp.NewInternalError(location, "Application must have at least one redirect_uri")
 	}
@@ -77,3 +94,61 @@ func (req *OAuthAuthorizationRequest) Validate(app OAuthClient) error {
 	// Success
 	return nil
 }
+
+// validateIndieAuth confirms that a request from an IndieAuth client is valid.  IndieAuth clients
+// are not pre-registered, so redirects must match the client_id or its published metadata,
+// and requests must use the "code" flow with PKCE.
+func (req *OAuthAuthorizationRequest) validateIndieAuth(app OAuthClient) error {
+
+	const location = "model.OAuthAuthorizationRequest.validateIndieAuth"
+
+	// RULE: RedirectURI is required
+	if req.RedirectURI == "" {
+		return derp.NewBadRequestError(location, "redirect_uri is required")
+	}
+
+	// RULE: RedirectURI must be on the same origin as the client, or published by the client
+	if !indieauth.RedirectAllowed(app.ClientURL, req.RedirectURI, app.RedirectURIs) {
+		return derp.NewBadRequestError(location, "Invalid redirect_uri", req.RedirectURI)
+	}
+
+	// RULE: Verify that scope is valid.  Empty scopes are allowed, and only authenticate the user.
+	for _, scope := range req.Scopes() {
+		if !slice.Contains(app.Scopes, scope) {
+			return derp.NewBadRequestError(location, "Invalid scope", scope)
+		}
+	}
+
+	// RULE: IndieAuth only supports the "code" flow
+	if req.ResponseType == "" || req.ResponseType == "id" {
+		req.ResponseType = "code"
+	}
+
+	if req.ResponseType != "code" {
+		return derp.NewBadRequestError(location, "Invalid response_type", req.ResponseType)
+	}
+
+	// RULE: PKCE is required
+	if req.CodeChallenge == "" {
+		return derp.NewBadRequestError(location, "code_challenge is required")
+	}
+
+	// RULE: Only S256 challenges are supported (matching the server metadata)
+	if req.CodeChallengeMethod == "" {
+		req.CodeChallengeMethod = indieauth.CodeChallengeMethodS256
+	}
+
+	if req.CodeChallengeMethod != indieauth.CodeChallengeMethodS256 {
+		return derp.NewBadRequestError(location, "Invalid code_challenge_method", req.CodeChallengeMethod)
+	}
+
+	// The "me" parameter is only a hint, but should be canonicalized if present
+	if req.Me != "" {
+		if me, err := indieauth.CanonicalURL(req.Me); err == nil {
+			req.Me = me
+		}
+	}
+
+	// Success
+	return nil
+}
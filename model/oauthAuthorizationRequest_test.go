@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthAuthorizationRequest_IndieAuth(t *testing.T) {
+
+	client := NewIndieAuthClient("https://app.example.com/")
+
+	request := NewOAuthAuthorizationRequest()
+	request.ClientID = "https://app.example.com/"
+	request.RedirectURI = "https://app.example.com/callback"
+	request.Scope = "profile create"
+	request.CodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	request.Me = "Example.com"
+
+	require.Nil(t, request.Validate(client))
+	require.Equal(t, "code", request.ResponseType)
+	require.Equal(t, "S256", request.CodeChallengeMethod)
+	require.Equal(t, "https://example.com/", request.Me)
+}
+
+func TestOAuthAuthorizationRequest_IndieAuth_Invalid(t *testing.T) {
+
+	client := NewIndieAuthClient("https://app.example.com/")
+
+	valid := func() OAuthAuthorizationRequest {
+		request := NewOAuthAuthorizationRequest()
+		request.ClientID = "https://app.example.com/"
+		request.RedirectURI = "https://app.example.com/callback"
+		request.CodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+		return request
+	}
+
+	// Authentication-only requests have no scopes
+	request := valid()
+	require.Nil(t, request.Validate(client))
+	require.Empty(t, request.Scopes())
+
+	// Redirects to other origins must be published by the client
+	request = valid()
+	request.RedirectURI = "https://evil.example.com/callback"
+	require.NotNil(t, request.Validate(client))
+
+	// PKCE is required
+	request = valid()
+	request.CodeChallenge = ""
+	require.NotNil(t, request.Validate(client))
+
+	// Plaintext PKCE is not supported
+	request = valid()
+	request.CodeChallengeMethod = "plain"
+	require.NotNil(t, request.Validate(client))
+
+	// Unknown scopes are rejected
+	request = valid()
+	request.Scope = "admin"
+	require.NotNil(t, request.Validate(client))
+
+	// Implicit flow is not supported
+	request = valid()
+	request.ResponseType = "token"
+	require.NotNil(t, request.Validate(client))
+}
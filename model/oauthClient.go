@@ -14,6 +14,8 @@ type OAuthClient struct {
 	Website      string             `json:"website"       bson:"website"`
 	RedirectURIs []string           `json:"redirectUris"  bson:"redirectUris"`
 	Scopes       sliceof.String     `json:"scopes"        bson:"scopes"`
+	ClientURL    string             `json:"clientUrl"     bson:"clientUrl,omitempty"`
+	LogoURL      string             `json:"logoUrl"       bson:"logoUrl,omitempty"`
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -26,10 +28,42 @@ func NewOAuthClient() OAuthClient {
 	}
 }
 
+// NewIndieAuthClient returns a fully initialized OAuthClient for an IndieAuth client_id URL
+func NewIndieAuthClient(clientURL string) OAuthClient {
+	result := NewOAuthClient()
+	result.ClientURL = clientURL
+	result.Website = clientURL
+	result.Name = clientURL
+	result.Scopes = IndieAuthScopes()
+	return result
+}
+
+// IndieAuthScopes returns all of the scopes that IndieAuth clients can request
+// https://indieweb.org/scope
+func IndieAuthScopes() []string {
+	return []string{"profile", "email", "create", "update", "delete", "undelete", "media", "read", "write"}
+}
+
 func (app OAuthClient) ID() string {
 	return app.ClientID.Hex()
 }
 
+// IsIndieAuth returns TRUE if this client was discovered via IndieAuth,
+// rather than pre-registered through the Mastodon API
+func (app OAuthClient) IsIndieAuth() bool {
+	return app.ClientURL != ""
+}
+
+// PublicClientID returns the client_id that this client uses in OAuth requests
+func (app OAuthClient) PublicClientID() string {
+
+	if app.IsIndieAuth() {
+		return app.ClientURL
+	}
+
+	return app.ClientID.Hex()
+}
+
 // ToToot converts this object into a Mastodon-compatible Application object
 func (app OAuthClient) Toot() object.Application {
 	return object.Application{
@@ -13,6 +13,8 @@ func OAuthClientSchema() schema.Element {
 			"name":          schema.String{Required: true},
 			"website":       schema.String{Format: "url"},
 			"scopes":        schema.Array{Items: schema.String{}},
+			"clientUrl":     schema.String{Format: "url"},
+			"logoUrl":       schema.String{Format: "url"},
 		},
 	}
 }
@@ -28,6 +30,12 @@ func (app *OAuthClient) GetPointer(name string) (any, bool) {
 
 	case "scopes":
 		return &app.Scopes, true
+
+	case "clientUrl":
+		return &app.ClientURL, true
+
+	case "logoUrl":
+		return &app.LogoURL, true
 	}
 
 	return nil, false
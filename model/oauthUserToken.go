@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/slice"
 	"github.com/benpate/rosetta/sliceof"
 	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,6 +21,12 @@ type OAuthUserToken struct {
 	APIUser          bool               `json:"A" bson:"apiUser"`
 	Scopes           sliceof.String     `json:"S" bson:"scopes"`
 
+	// Values for the most recent authorization code, which is redeemed for this token
+	RedirectURI         string `json:"-" bson:"redirectUri,omitempty"`
+	CodeChallenge       string `json:"-" bson:"codeChallenge,omitempty"`
+	CodeChallengeMethod string `json:"-" bson:"codeChallengeMethod,omitempty"`
+	CodeExpires         int64  `json:"-" bson:"codeExpires,omitempty"`
+
 	journal.Journal `json:"-" bson:",inline"`
 }
 
@@ -40,6 +47,17 @@ func (token OAuthUserToken) Code() string {
 	return token.OAuthUserTokenID.Hex()
 }
 
+// IsCodeExpired returns TRUE if the authorization code for this token
+// has already been redeemed, or is too old to be used.
+func (token OAuthUserToken) IsCodeExpired() bool {
+	return token.CodeExpires < time.Now().Unix()
+}
+
+// HasScope returns TRUE if this token includes the provided scope
+func (token OAuthUserToken) HasScope(scope string) bool {
+	return slice.Contains(token.Scopes, scope)
+}
+
 func (token OAuthUserToken) JSONResponse() map[string]any {
 
 	return map[string]any{
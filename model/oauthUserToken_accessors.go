@@ -8,13 +8,17 @@ import (
 func OAuthUserTokenSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"oauthUserTokenId": schema.String{Format: "objectId", Required: true},
-			"userId":           schema.String{Format: "objectId", Required: true},
-			"clientId":         schema.String{Format: "objectId", Required: true},
-			"token":            schema.String{},
-			"clientSecret":     schema.String{},
-			"scopes":           schema.Array{Items: schema.String{}},
-			"apiUser":          schema.Boolean{},
+			"oauthUserTokenId":    schema.String{Format: "objectId", Required: true},
+			"userId":              schema.String{Format: "objectId", Required: true},
+			"clientId":            schema.String{Format: "objectId", Required: true},
+			"token":               schema.String{},
+			"clientSecret":        schema.String{},
+			"scopes":              schema.Array{Items: schema.String{}},
+			"apiUser":             schema.Boolean{},
+			"redirectUri":         schema.String{},
+			"codeChallenge":       schema.String{},
+			"codeChallengeMethod": schema.String{Enum: []string{"", "S256", "plain"}},
+			"codeExpires":         schema.Integer{BitSize: 64},
 		},
 	}
 }
@@ -30,6 +34,18 @@ func (userToken *OAuthUserToken) GetPointer(name string) (any, bool) {
 
 	case "apiUser":
 		return &userToken.APIUser, true
+
+	case "redirectUri":
+		return &userToken.RedirectURI, true
+
+	case "codeChallenge":
+		return &userToken.CodeChallenge, true
+
+	case "codeChallengeMethod":
+		return &userToken.CodeChallengeMethod, true
+
+	case "codeExpires":
+		return &userToken.CodeExpires, true
 	}
 
 	return nil, false
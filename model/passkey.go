@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Passkey is a WebAuthn credential that a User can sign in with instead of (or in addition to) their password.
+type Passkey struct {
+	PasskeyID      primitive.ObjectID `bson:"passkeyId"`                // Unique identifier for this Passkey
+	CredentialID   string             `bson:"credentialId"`             // base64url-encoded WebAuthn credential ID
+	PublicKey      []byte             `bson:"publicKey"`                // CBOR-encoded COSE public key
+	SignCount      int64              `bson:"signCount"`                // Signature counter reported by the authenticator, used to detect cloned passkeys
+	Transports     sliceof.String     `bson:"transports,omitempty"`     // Transports (like "internal" or "usb") reported by the browser
+	Label          string             `bson:"label"`                    // Human-friendly name for this Passkey (like "My Phone")
+	BackupEligible bool               `bson:"backupEligible,omitempty"` // TRUE if the passkey can be synced to other devices
+	CreateDate     int64              `bson:"createDate"`               // Unix epoch seconds when this Passkey was registered
+	LastUsedDate   int64              `bson:"lastUsedDate,omitempty"`   // Unix epoch seconds when this Passkey was last used to sign in
+}
+
+// NewPasskey returns a fully initialized Passkey object
+func NewPasskey() Passkey {
+	return Passkey{
+		PasskeyID:  primitive.NewObjectID(),
+		Transports: sliceof.NewString(),
+	}
+}
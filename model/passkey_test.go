@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUser_Passkeys(t *testing.T) {
+
+	user := NewUser()
+	require.False(t, user.HasPasskeys())
+	require.Equal(t, -1, user.PasskeyIndex("abc"))
+
+	first := NewPasskey()
+	first.CredentialID = "abc"
+
+	second := NewPasskey()
+	second.CredentialID = "def"
+
+	user.Passkeys = append(user.Passkeys, first, second)
+	require.True(t, user.HasPasskeys())
+	require.Equal(t, 0, user.PasskeyIndex("abc"))
+	require.Equal(t, 1, user.PasskeyIndex("def"))
+
+	// Remove the first passkey
+	require.True(t, user.RemovePasskey(first.PasskeyID))
+	require.Equal(t, -1, user.PasskeyIndex("abc"))
+	require.Equal(t, 0, user.PasskeyIndex("def"))
+
+	// Removing a missing passkey does nothing
+	require.False(t, user.RemovePasskey(primitive.NewObjectID()))
+	require.Equal(t, 1, len(user.Passkeys))
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicyMinLength is the shortest password that any PasswordPolicy allows
+const PasswordPolicyMinLength = 8
+
+// PasswordPolicyDefaultLength is the minimum password length used when a domain has not set its own
+const PasswordPolicyDefaultLength = 12
+
+// PasswordPolicy defines the rules that new passwords must follow on a domain.
+// It implements the steranko.PasswordRule interface.
+type PasswordPolicy struct {
+	MinLength        int  `json:"minLength"        bson:"minLength"`        // Minimum number of characters in a password
+	RequireMixedCase bool `json:"requireMixedCase" bson:"requireMixedCase"` // If TRUE, then passwords must include upper and lower case letters
+	RequireDigit     bool `json:"requireDigit"     bson:"requireDigit"`     // If TRUE, then passwords must include a number
+	RequireSymbol    bool `json:"requireSymbol"    bson:"requireSymbol"`    // If TRUE, then passwords must include a symbol or punctuation mark
+}
+
+// NewPasswordPolicy returns a fully initialized PasswordPolicy object
+func NewPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength: PasswordPolicyDefaultLength,
+	}
+}
+
+// MinimumLength returns the minimum number of characters allowed by this policy
+func (policy PasswordPolicy) MinimumLength() int {
+
+	if policy.MinLength == 0 {
+		return PasswordPolicyDefaultLength
+	}
+
+	if policy.MinLength < PasswordPolicyMinLength {
+		return PasswordPolicyMinLength
+	}
+
+	return policy.MinLength
+}
+
+// ID returns a string that uniquely identifies this rule.  A part of the "steranko.PasswordRule" interface.
+func (policy PasswordPolicy) ID() string {
+	return "PasswordPolicy"
+}
+
+// PasswordRuleDescription returns a human-friendly description of this policy.  A part of the "steranko.PasswordRule" interface.
+func (policy PasswordPolicy) PasswordRuleDescription(language string) string {
+
+	result := strconv.Itoa(policy.MinimumLength()) + " or more characters"
+	requirements := make([]string, 0, 3)
+
+	if policy.RequireMixedCase {
+		requirements = append(requirements, "upper and lower case letters")
+	}
+
+	if policy.RequireDigit {
+		requirements = append(requirements, "a number")
+	}
+
+	if policy.RequireSymbol {
+		requirements = append(requirements, "a symbol")
+	}
+
+	switch len(requirements) {
+	case 0:
+	case 1:
+		result += ", with " + requirements[0]
+	default:
+		result += ", with " + strings.Join(requirements[:len(requirements)-1], ", ") + " and " + requirements[len(requirements)-1]
+	}
+
+	return result + "."
+}
+
+// ValidatePassword returns TRUE if the password follows this policy.  If not, it returns FALSE
+// and a message explaining why.  A part of the "steranko.PasswordRule" interface.
+func (policy PasswordPolicy) ValidatePassword(password string) (bool, string) {
+
+	if len([]rune(password)) < policy.MinimumLength() {
+		return false, "Password must be at least " + strconv.Itoa(policy.MinimumLength()) + " characters long."
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireMixedCase && !(hasUpper && hasLower) {
+		return false, "Password must include upper and lower case letters."
+	}
+
+	if policy.RequireDigit && !hasDigit {
+		return false, "Password must include a number."
+	}
+
+	if policy.RequireSymbol && !hasSymbol {
+		return false, "Password must include a symbol."
+	}
+
+	return true, ""
+}
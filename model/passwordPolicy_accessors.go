@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/null"
+	"github.com/benpate/rosetta/schema"
+)
+
+func PasswordPolicySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"minLength":        schema.Integer{Minimum: null.NewInt64(PasswordPolicyMinLength), Maximum: null.NewInt64(128), Default: null.NewInt64(PasswordPolicyDefaultLength)},
+			"requireMixedCase": schema.Boolean{},
+			"requireDigit":     schema.Boolean{},
+			"requireSymbol":    schema.Boolean{},
+		},
+	}
+}
+
+/*********************************
+ * Getter/Setter Interfaces
+ *********************************/
+
+func (policy *PasswordPolicy) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "minLength":
+		return &policy.MinLength, true
+
+	case "requireMixedCase":
+		return &policy.RequireMixedCase, true
+
+	case "requireDigit":
+		return &policy.RequireDigit, true
+
+	case "requireSymbol":
+		return &policy.RequireSymbol, true
+	}
+
+	return nil, false
+}
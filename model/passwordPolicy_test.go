@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicySchema(t *testing.T) {
+
+	policy := NewPasswordPolicy()
+	s := schema.New(PasswordPolicySchema())
+
+	table := []tableTestItem{
+		{"minLength", 16, nil},
+		{"requireMixedCase", "true", true},
+		{"requireDigit", true, nil},
+		{"requireSymbol", true, nil},
+	}
+
+	tableTest_Schema(t, &s, &policy, table)
+}
+
+func TestPasswordPolicy_MinimumLength(t *testing.T) {
+
+	require.Equal(t, PasswordPolicyDefaultLength, PasswordPolicy{}.MinimumLength())
+	require.Equal(t, PasswordPolicyMinLength, PasswordPolicy{MinLength: 4}.MinimumLength())
+	require.Equal(t, 20, PasswordPolicy{MinLength: 20}.MinimumLength())
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+
+	policy := NewPasswordPolicy()
+
+	ok, message := policy.ValidatePassword("short")
+	require.False(t, ok)
+	require.Equal(t, "Password must be at least 12 characters long.", message)
+
+	ok, _ = policy.ValidatePassword("correct horse battery")
+	require.True(t, ok)
+
+	policy.RequireMixedCase = true
+	policy.RequireDigit = true
+	policy.RequireSymbol = true
+
+	ok, message = policy.ValidatePassword("correct horse battery")
+	require.False(t, ok)
+	require.Equal(t, "Password must include upper and lower case letters.", message)
+
+	ok, message = policy.ValidatePassword("Correct horse battery")
+	require.False(t, ok)
+	require.Equal(t, "Password must include a number.", message)
+
+	ok, message = policy.ValidatePassword("Correct horse battery 9")
+	require.False(t, ok)
+	require.Equal(t, "Password must include a symbol.", message)
+
+	ok, _ = policy.ValidatePassword("Correct horse battery 9!")
+	require.True(t, ok)
+}
+
+func TestPasswordPolicy_Description(t *testing.T) {
+
+	policy := NewPasswordPolicy()
+	require.Equal(t, "12 or more characters.", policy.PasswordRuleDescription("en"))
+
+	policy.RequireDigit = true
+	require.Equal(t, "12 or more characters, with a number.", policy.PasswordRuleDescription("en"))
+
+	policy.RequireMixedCase = true
+	policy.RequireSymbol = true
+	require.Equal(t, "12 or more characters, with upper and lower case letters, a number and a symbol.", policy.PasswordRuleDescription("en"))
+}
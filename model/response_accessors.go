@@ -14,7 +14,7 @@ func ResponseSchema() schema.Element {
 			"userId":     schema.String{Format: "objectId"},
 			"actor":      schema.String{Format: "url"},
 			"object":     schema.String{Format: "url"},
-			"type":       schema.String{MaxLength: 128, Enum: []string{vocab.ActivityTypeAnnounce, vocab.ActivityTypeLike, vocab.ActivityTypeDislike}},
+			"type":       schema.String{MaxLength: 128, Enum: []string{vocab.ActivityTypeAnnounce, vocab.ActivityTypeLike, vocab.ActivityTypeDislike, ResponseTypeBookmark}},
 			"content":    schema.String{MaxLength: 256},
 		},
 	}
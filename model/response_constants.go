@@ -0,0 +1,5 @@
+package model
+
+// ResponseTypeBookmark identifies a private bookmark.  ActivityStreams does not
+// define a "Bookmark" activity, so these Responses are never published.
+const ResponseTypeBookmark = "Bookmark"
@@ -4,13 +4,38 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// SignupModeOpen lets anyone create a new account
+const SignupModeOpen = "OPEN"
+
+// SignupModeInvite lets people create a new account only if they have a valid Invitation
+const SignupModeInvite = "INVITE"
+
+// SignupModeApproval lets anyone create a new account, but a domain owner must
+// approve it before the new User can sign in.
+const SignupModeApproval = "APPROVAL"
+
 type SignupForm struct {
-	Title   string             `json:"title"   bson:"title"`   // Title displayed on the signup page
-	Message string             `json:"message" bson:"message"` // Message displayed on the signup page
-	GroupID primitive.ObjectID `json:"groupId" bson:"groupId"` // Group to add new users to when completed
-	Active  bool               `json:"active"  bson:"active"`  // If true, then allow this signup page
+	Title            string             `json:"title"            bson:"title"`            // Title displayed on the signup page
+	Message          string             `json:"message"          bson:"message"`          // Message displayed on the signup page
+	GroupID          primitive.ObjectID `json:"groupId"          bson:"groupId"`          // Group to add new users to when completed
+	Active           bool               `json:"active"           bson:"active"`           // If true, then allow this signup page
+	Mode             string             `json:"mode"             bson:"mode"`             // Registration mode (OPEN, INVITE, or APPROVAL)
+	RequireEmail     bool               `json:"requireEmail"     bson:"requireEmail"`     // If true, then new users must confirm their email address before signing in
+	AllowUserInvites bool               `json:"allowUserInvites" bson:"allowUserInvites"` // If true, then all users (not just domain owners) can create invitations
 }
 
 func NewSignupForm() SignupForm {
-	return SignupForm{}
+	return SignupForm{
+		Mode: SignupModeOpen,
+	}
+}
+
+// IsInviteOnly returns TRUE if new users must have a valid Invitation to sign up
+func (form SignupForm) IsInviteOnly() bool {
+	return form.Mode == SignupModeInvite
+}
+
+// RequiresApproval returns TRUE if new users must be approved by a domain owner before they can sign in
+func (form SignupForm) RequiresApproval() bool {
+	return form.Mode == SignupModeApproval
 }
@@ -8,10 +8,13 @@ import (
 func SignupFormSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"title":   schema.String{Required: false, MaxLength: 128},
-			"message": schema.String{Required: false, MaxLength: 1024},
-			"groupId": schema.String{Format: "objectId"},
-			"active":  schema.Boolean{},
+			"title":            schema.String{Required: false, MaxLength: 128},
+			"message":          schema.String{Required: false, MaxLength: 1024},
+			"groupId":          schema.String{Format: "objectId"},
+			"active":           schema.Boolean{},
+			"mode":             schema.String{Enum: []string{SignupModeOpen, SignupModeInvite, SignupModeApproval}, Default: SignupModeOpen},
+			"requireEmail":     schema.Boolean{},
+			"allowUserInvites": schema.Boolean{},
 		},
 	}
 }
@@ -23,6 +26,12 @@ func (form SignupForm) GetBoolOK(name string) (bool, bool) {
 	case "active":
 		return form.Active, true
 
+	case "requireEmail":
+		return form.RequireEmail, true
+
+	case "allowUserInvites":
+		return form.AllowUserInvites, true
+
 	}
 
 	return false, false
@@ -41,6 +50,9 @@ func (form SignupForm) GetStringOK(name string) (string, bool) {
 	case "groupId":
 		return form.GroupID.Hex(), true
 
+	case "mode":
+		return form.Mode, true
+
 	}
 
 	return "", false
@@ -54,6 +66,14 @@ func (form *SignupForm) SetBool(name string, value bool) bool {
 		form.Active = value
 		return true
 
+	case "requireEmail":
+		form.RequireEmail = value
+		return true
+
+	case "allowUserInvites":
+		form.AllowUserInvites = value
+		return true
+
 	}
 
 	return false
@@ -71,6 +91,10 @@ func (form *SignupForm) SetString(name string, value string) bool {
 		form.Message = value
 		return true
 
+	case "mode":
+		form.Mode = value
+		return true
+
 	case "groupId":
 		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
 			form.GroupID = objectID
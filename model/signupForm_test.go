@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSignupForm(t *testing.T) {
@@ -17,7 +18,25 @@ func TestSignupForm(t *testing.T) {
 		{"message", "123456781234567812345678", nil},
 		{"groupId", "123456787182635481726354", nil},
 		{"active", "true", true},
+		{"mode", SignupModeInvite, nil},
+		{"requireEmail", "true", true},
+		{"allowUserInvites", true, nil},
 	}
 
 	tableTest_Schema(t, &s, &signupForm, table)
 }
+
+func TestSignupForm_Modes(t *testing.T) {
+
+	signupForm := NewSignupForm()
+	require.False(t, signupForm.IsInviteOnly())
+	require.False(t, signupForm.RequiresApproval())
+
+	signupForm.Mode = SignupModeInvite
+	require.True(t, signupForm.IsInviteOnly())
+	require.False(t, signupForm.RequiresApproval())
+
+	signupForm.Mode = SignupModeApproval
+	require.False(t, signupForm.IsInviteOnly())
+	require.True(t, signupForm.RequiresApproval())
+}
@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ApproveUser represents an action-step that approves a User who is waiting for a domain owner's approval
+type ApproveUser struct{}
+
+// NewApproveUser returns a fully initialized ApproveUser step
+func NewApproveUser(stepInfo mapof.Any) (ApproveUser, error) {
+	return ApproveUser{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ApproveUser) AmStep() {}
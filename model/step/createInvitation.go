@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// CreateInvitation represents an action-step that creates a new Invitation for the signed-in User
+type CreateInvitation struct{}
+
+// NewCreateInvitation returns a fully initialized CreateInvitation step
+func NewCreateInvitation(stepInfo mapof.Any) (CreateInvitation, error) {
+	return CreateInvitation{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step CreateInvitation) AmStep() {}
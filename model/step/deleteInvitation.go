@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// DeleteInvitation represents an action-step that removes an Invitation
+type DeleteInvitation struct{}
+
+// NewDeleteInvitation returns a fully initialized DeleteInvitation step
+func NewDeleteInvitation(stepInfo mapof.Any) (DeleteInvitation, error) {
+	return DeleteInvitation{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step DeleteInvitation) AmStep() {}
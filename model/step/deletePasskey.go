@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// DeletePasskey represents an action-step that removes one of the signed-in User's passkeys
+type DeletePasskey struct{}
+
+// NewDeletePasskey returns a fully initialized DeletePasskey step
+func NewDeletePasskey(stepInfo mapof.Any) (DeletePasskey, error) {
+	return DeletePasskey{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step DeletePasskey) AmStep() {}
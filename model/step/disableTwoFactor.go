@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// DisableTwoFactor represents an action-step that turns off two-factor authentication for the signed-in User
+type DisableTwoFactor struct{}
+
+// NewDisableTwoFactor returns a fully initialized DisableTwoFactor step
+func NewDisableTwoFactor(stepInfo mapof.Any) (DisableTwoFactor, error) {
+	return DisableTwoFactor{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step DisableTwoFactor) AmStep() {}
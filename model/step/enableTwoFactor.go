@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// EnableTwoFactor represents an action-step that sets up two-factor authentication for the signed-in User
+type EnableTwoFactor struct{}
+
+// NewEnableTwoFactor returns a fully initialized EnableTwoFactor step
+func NewEnableTwoFactor(stepInfo mapof.Any) (EnableTwoFactor, error) {
+	return EnableTwoFactor{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step EnableTwoFactor) AmStep() {}
@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ExportData represents an action-step that builds an archive of a User's personal data
+type ExportData struct{}
+
+// NewExportData returns a fully initialized ExportData step
+func NewExportData(stepInfo mapof.Any) (ExportData, error) {
+	return ExportData{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ExportData) AmStep() {}
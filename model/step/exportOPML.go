@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ExportOPML represents an action-step that downloads the User's Followings as an OPML document
+type ExportOPML struct{}
+
+// NewExportOPML returns a fully initialized ExportOPML step
+func NewExportOPML(stepInfo mapof.Any) (ExportOPML, error) {
+	return ExportOPML{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ExportOPML) AmStep() {}
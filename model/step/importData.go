@@ -0,0 +1,15 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ImportData represents an action-step that imports followings, blocks, mutes, or bookmarks
+// from a CSV file that was exported from Mastodon
+type ImportData struct{}
+
+// NewImportData returns a fully initialized ImportData step
+func NewImportData(stepInfo mapof.Any) (ImportData, error) {
+	return ImportData{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ImportData) AmStep() {}
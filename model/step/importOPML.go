@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ImportOPML represents an action-step that creates Followings from an uploaded OPML document
+type ImportOPML struct{}
+
+// NewImportOPML returns a fully initialized ImportOPML step
+func NewImportOPML(stepInfo mapof.Any) (ImportOPML, error) {
+	return ImportOPML{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ImportOPML) AmStep() {}
@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// NewRecoveryCodes represents an action-step that replaces the signed-in User's two-factor recovery codes
+type NewRecoveryCodes struct{}
+
+// NewNewRecoveryCodes returns a fully initialized NewRecoveryCodes step
+func NewNewRecoveryCodes(stepInfo mapof.Any) (NewRecoveryCodes, error) {
+	return NewRecoveryCodes{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step NewRecoveryCodes) AmStep() {}
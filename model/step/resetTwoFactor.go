@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// ResetTwoFactor represents an action-step that removes two-factor authentication from a User (for domain owners)
+type ResetTwoFactor struct{}
+
+// NewResetTwoFactor returns a fully initialized ResetTwoFactor step
+func NewResetTwoFactor(stepInfo mapof.Any) (ResetTwoFactor, error) {
+	return ResetTwoFactor{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ResetTwoFactor) AmStep() {}
@@ -0,0 +1,15 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RestoreRevision represents an action-step that copies a previous revision back into a Stream.
+// The revision is identified by the "revisionId" query parameter.
+type RestoreRevision struct{}
+
+// NewRestoreRevision returns a fully initialized RestoreRevision object
+func NewRestoreRevision(stepInfo mapof.Any) (RestoreRevision, error) {
+	return RestoreRevision{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RestoreRevision) AmStep() {}
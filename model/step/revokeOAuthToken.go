@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RevokeOAuthToken represents an action-step that removes an application's access to a User's account
+type RevokeOAuthToken struct{}
+
+// NewRevokeOAuthToken returns a fully initialized RevokeOAuthToken step
+func NewRevokeOAuthToken(stepInfo mapof.Any) (RevokeOAuthToken, error) {
+	return RevokeOAuthToken{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RevokeOAuthToken) AmStep() {}
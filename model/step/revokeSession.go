@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RevokeSession represents an action-step that signs a User out of a single browser
+type RevokeSession struct{}
+
+// NewRevokeSession returns a fully initialized RevokeSession step
+func NewRevokeSession(stepInfo mapof.Any) (RevokeSession, error) {
+	return RevokeSession{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RevokeSession) AmStep() {}
@@ -0,0 +1,15 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RevokeSessions represents an action-step that signs a User out of every browser
+// (except for the one making the request)
+type RevokeSessions struct{}
+
+// NewRevokeSessions returns a fully initialized RevokeSessions step
+func NewRevokeSessions(stepInfo mapof.Any) (RevokeSessions, error) {
+	return RevokeSessions{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RevokeSessions) AmStep() {}
@@ -0,0 +1,15 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RotateKeys represents an action-step that replaces an Actor's signing keys
+// and announces the new public keys to its followers
+type RotateKeys struct{}
+
+// NewRotateKeys returns a fully initialized RotateKeys step
+func NewRotateKeys(stepInfo mapof.Any) (RotateKeys, error) {
+	return RotateKeys{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RotateKeys) AmStep() {}
@@ -0,0 +1,15 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// RSSCloud represents an action-step that accepts rssCloud subscription requests
+type RSSCloud struct {
+}
+
+// NewRSSCloud generates a fully initialized RSSCloud step.
+func NewRSSCloud(stepInfo mapof.Any) (RSSCloud, error) {
+	return RSSCloud{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RSSCloud) AmStep() {}
@@ -0,0 +1,19 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// SetEd25519Key represents an action-step that publishes (or removes) an
+// Actor's optional Ed25519 key (FEP-521a)
+type SetEd25519Key struct {
+	Enabled bool
+}
+
+// NewSetEd25519Key returns a fully initialized SetEd25519Key step
+func NewSetEd25519Key(stepInfo mapof.Any) (SetEd25519Key, error) {
+	return SetEd25519Key{
+		Enabled: stepInfo.GetBool("enabled"),
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step SetEd25519Key) AmStep() {}
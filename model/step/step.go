@@ -29,6 +29,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "add-stream":
 		return NewAddStream(stepInfo)
 
+	case "approve-user":
+		return NewApproveUser(stepInfo)
+
 	case "as-confirmation":
 		return NewAsConfirmation(stepInfo)
 
@@ -38,12 +41,24 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "as-tooltip":
 		return NewAsTooltip(stepInfo)
 
+	case "create-invitation":
+		return NewCreateInvitation(stepInfo)
+
 	case "delete":
 		return NewDelete(stepInfo)
 
 	case "delete-attachments":
 		return NewDeleteAttachments(stepInfo)
 
+	case "delete-invitation":
+		return NewDeleteInvitation(stepInfo)
+
+	case "delete-passkey":
+		return NewDeletePasskey(stepInfo)
+
+	case "disable-two-factor":
+		return NewDisableTwoFactor(stepInfo)
+
 	case "edit":
 		return NewEditModelObject(stepInfo)
 
@@ -62,6 +77,15 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "edit-widget":
 		return NewEditWidget(stepInfo)
 
+	case "enable-two-factor":
+		return NewEnableTwoFactor(stepInfo)
+
+	case "export-data":
+		return NewExportData(stepInfo)
+
+	case "export-opml":
+		return NewExportOPML(stepInfo)
+
 	case "forward-to":
 		return NewForwardTo(stepInfo)
 
@@ -71,6 +95,12 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "if":
 		return NewIfCondition(stepInfo)
 
+	case "import-data":
+		return NewImportData(stepInfo)
+
+	case "import-opml":
+		return NewImportOPML(stepInfo)
+
 	case "include":
 		return NewDo(stepInfo)
 
@@ -83,6 +113,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "inline-success":
 		return NewInlineSuccess(stepInfo)
 
+	case "new-recovery-codes":
+		return NewNewRecoveryCodes(stepInfo)
+
 	case "process-content":
 		return NewProcessContent(stepInfo)
 
@@ -101,9 +134,30 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "reload-page":
 		return NewReloadPage(stepInfo)
 
+	case "reset-two-factor":
+		return NewResetTwoFactor(stepInfo)
+
+	case "restore-revision":
+		return NewRestoreRevision(stepInfo)
+
+	case "revoke-oauth-token":
+		return NewRevokeOAuthToken(stepInfo)
+
+	case "revoke-session":
+		return NewRevokeSession(stepInfo)
+
+	case "revoke-sessions":
+		return NewRevokeSessions(stepInfo)
+
 	case "remove-event":
 		return NewRemoveEvent(stepInfo)
 
+	case "rotate-keys":
+		return NewRotateKeys(stepInfo)
+
+	case "rss-cloud":
+		return NewRSSCloud(stepInfo)
+
 	case "save":
 		return NewSave(stepInfo)
 
@@ -119,6 +173,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "set-data":
 		return NewSetData(stepInfo)
 
+	case "set-ed25519-key":
+		return NewSetEd25519Key(stepInfo)
+
 	case "set-header":
 		return NewSetHeader(stepInfo)
 
@@ -199,6 +256,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 
 	case "with-rule":
 		return NewWithRule(stepInfo)
+
+	case "with-syndication-target":
+		return NewWithSyndicationTarget(stepInfo)
 	}
 
 	// Fall through means we have an unrecognized action
@@ -0,0 +1,31 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// WithSyndicationTarget represents an action-step that runs sub-steps on a SyndicationTarget owned by the current user
+type WithSyndicationTarget struct {
+	SubSteps []Step
+}
+
+// NewWithSyndicationTarget returns a fully initialized WithSyndicationTarget object
+func NewWithSyndicationTarget(stepInfo mapof.Any) (WithSyndicationTarget, error) {
+
+	const location = "NewWithSyndicationTarget"
+
+	subSteps, err := NewPipeline(convert.SliceOfMap(stepInfo["steps"]))
+
+	if err != nil {
+		return WithSyndicationTarget{}, derp.Wrap(err, location, "Invalid 'steps'", stepInfo)
+	}
+
+	return WithSyndicationTarget{
+		SubSteps: subSteps,
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step WithSyndicationTarget) AmStep() {}
@@ -32,6 +32,7 @@ type Stream struct {
 	Token            string                       `json:"token,omitempty"        bson:"token,omitempty"`        // Unique value that identifies this element in the URL
 	Label            string                       `json:"label,omitempty"        bson:"label,omitempty"`        // Label/Title of the document
 	Summary          string                       `json:"summary,omitempty"      bson:"summary,omitempty"`      // Brief summary of the document
+	Sensitive        bool                         `json:"sensitive,omitempty"    bson:"sensitive,omitempty"`    // If TRUE, then this document is hidden behind a content warning (which is stored in the Summary)
 	ImageURL         string                       `json:"imageUrl,omitempty"     bson:"imageUrl,omitempty"`     // URL of the cover image for this document's image
 	Content          Content                      `json:"content,omitempty"      bson:"content,omitempty"`      // Body content object for this Stream.
 	Widgets          set.Slice[StreamWidget]      `json:"widgets,omitempty"      bson:"widgets,omitempty"`      // Additional widgets to include when building this Stream.
@@ -40,8 +41,12 @@ type Stream struct {
 	AttributedTo     PersonLink                   `json:"attributedTo,omitempty" bson:"attributedTo,omitempty"` // List of people who are attributed to this document
 	Context          string                       `json:"context,omitempty"      bson:"context,omitempty"`      // Context of this document (usually a URL)
 	InReplyTo        string                       `json:"inReplyTo,omitempty"    bson:"inReplyTo"`              // If this stream is a reply to another stream or web page, then this links to the original document.
+	QuoteURL         string                       `json:"quoteUrl,omitempty"     bson:"quoteUrl,omitempty"`     // If this stream quotes another stream or web page, then this links to the quoted document.
+	SyndicateTo      sliceof.String               `json:"syndicateTo,omitempty"  bson:"syndicateTo,omitempty"`  // List of SyndicationTargetIDs that this document should be syndicated to when it is published.
+	Syndication      sliceof.String               `json:"syndication,omitempty"  bson:"syndication,omitempty"`  // List of URLs where this document has been syndicated to other services (u-syndication).
 	PublishDate      int64                        `json:"publishDate"            bson:"publishDate"`            // Unix timestamp of the date/time when this document is/was/will be first available on the domain.
 	UnPublishDate    int64                        `json:"unpublishDate"          bson:"unpublishDate"`          // Unix timestemp of the date/time when this document will no longer be available on the domain.
+	EditDate         int64                        `json:"editDate,omitempty"     bson:"editDate,omitempty"`     // Unix timestamp of the date/time when this document was last edited (after it was published)
 	journal.Journal  `bson:",inline"`
 }
 
@@ -369,9 +374,11 @@ func (stream Stream) Toot() object.Status {
 		Account:     stream.AttributedTo.Toot(),
 		Content:     stream.Content.HTML,
 		Visibility:  "public",
-		SpoilerText: stream.Label,
+		SpoilerText: stream.ContentWarning(),
+		Sensitive:   stream.Sensitive,
 		URL:         stream.URL,
 		InReplyToID: stream.InReplyTo,
+		EditedAt:    stream.EditedAt(),
 	}
 }
 
@@ -391,6 +398,46 @@ func (stream *Stream) DocumentLink() DocumentLink {
 	}
 }
 
+// IsEdited returns TRUE if this Stream has been edited since it was published
+func (stream *Stream) IsEdited() bool {
+	return stream.EditDate > 0
+}
+
+// EditedAt returns the date this Stream was last edited, formatted as an
+// ISO 8601 string. Streams that have never been edited return an empty string.
+func (stream *Stream) EditedAt() string {
+	if stream.IsEdited() {
+		return time.Unix(stream.EditDate, 0).UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+// ContentWarning returns the content warning for this Stream.
+// Content warnings are stored in the Summary, but only for Sensitive streams.
+func (stream *Stream) ContentWarning() string {
+	if stream.Sensitive {
+		return stream.Summary
+	}
+	return ""
+}
+
+// SetContentWarning sets (or clears) the content warning for this Stream.
+// Mastodon treats all posts with a content warning as "sensitive", so we do too.
+func (stream *Stream) SetContentWarning(contentWarning string, sensitive bool) {
+	if contentWarning != "" {
+		stream.Summary = contentWarning
+		stream.Sensitive = true
+		return
+	}
+
+	// Remove the previous content warning (if any)
+	if stream.Sensitive {
+		stream.Summary = ""
+	}
+
+	stream.Sensitive = sensitive
+}
+
 // HasParent returns TRUE if this Stream has a valid parentID
 func (stream *Stream) HasParent() bool {
 	return !stream.ParentID.IsZero()
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StreamRevision is a snapshot of a published Stream, which is recorded every
+// time the Stream is saved.  Revisions make up the edit history of a Stream.
+type StreamRevision struct {
+	StreamRevisionID primitive.ObjectID        `json:"streamRevisionId"      bson:"_id"`                   // Unique identifier of this StreamRevision
+	StreamID         primitive.ObjectID        `json:"streamId"              bson:"streamId"`              // Unique identifier of the Stream that this revision belongs to
+	Label            string                    `json:"label,omitempty"       bson:"label,omitempty"`       // Label/Title of the Stream at this revision
+	Summary          string                    `json:"summary,omitempty"     bson:"summary,omitempty"`     // Summary (or content warning) of the Stream at this revision
+	Sensitive        bool                      `json:"sensitive,omitempty"   bson:"sensitive,omitempty"`   // If TRUE, then the Stream was marked sensitive at this revision
+	ImageURL         string                    `json:"imageUrl,omitempty"    bson:"imageUrl,omitempty"`    // URL of the cover image at this revision
+	Content          Content                   `json:"content,omitempty"     bson:"content,omitempty"`     // Body content of the Stream at this revision
+	Tags             sliceof.Object[Tag]       `json:"tags,omitempty"        bson:"tags,omitempty"`        // Tags of the Stream at this revision
+	Attachments      sliceof.Object[mapof.Any] `json:"attachments,omitempty" bson:"attachments,omitempty"` // JSON-LD summaries of the Stream's Attachments at this revision
+	AttributedTo     PersonLink                `json:"attributedTo"          bson:"attributedTo"`          // Person who published this revision
+	Note             string                    `json:"note,omitempty"        bson:"note,omitempty"`        // Note describing why this revision was made
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewStreamRevision returns a fully initialized StreamRevision object
+func NewStreamRevision() StreamRevision {
+	return StreamRevision{
+		StreamRevisionID: primitive.NewObjectID(),
+		Tags:             sliceof.NewObject[Tag](),
+		Attachments:      sliceof.NewObject[mapof.Any](),
+	}
+}
+
+// NewStreamRevisionFromStream returns a new StreamRevision that is a snapshot of the provided Stream
+func NewStreamRevisionFromStream(stream *Stream, attachments []Attachment) StreamRevision {
+
+	result := NewStreamRevision()
+	result.StreamID = stream.StreamID
+	result.Label = stream.Label
+	result.Summary = stream.Summary
+	result.Sensitive = stream.Sensitive
+	result.ImageURL = stream.ImageURL
+	result.Content = stream.Content
+	result.Tags = append(result.Tags, stream.Tags...)
+	result.AttributedTo = stream.AttributedTo
+
+	for _, attachment := range attachments {
+		result.Attachments = append(result.Attachments, mapof.Any(attachment.JSONLD()))
+	}
+
+	return result
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (revision *StreamRevision) ID() string {
+	return revision.StreamRevisionID.Hex()
+}
+
+// CreateDateSeconds returns the date this revision was recorded, as a Unix epoch in seconds
+func (revision StreamRevision) CreateDateSeconds() int64 {
+	return revision.CreateDate / 1000
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this revision as a Mastodon StatusEdit
+func (revision StreamRevision) Toot() object.StatusEdit {
+
+	result := object.StatusEdit{
+		Content:          revision.Content.HTML,
+		Sensitive:        revision.Sensitive,
+		CreatedAt:        time.UnixMilli(revision.CreateDate).UTC().Format(time.RFC3339),
+		Account:          revision.AttributedTo.Toot(),
+		MediaAttachments: make([]object.MediaAttachment, 0, len(revision.Attachments)),
+		Emojis:           make([]object.CustomEmoji, 0),
+	}
+
+	if revision.Sensitive {
+		result.SpoilerText = revision.Summary
+	}
+
+	for _, attachment := range revision.Attachments {
+		url := attachment.GetString("url")
+		result.MediaAttachments = append(result.MediaAttachments, object.MediaAttachment{
+			ID:         url,
+			Type:       "image",
+			URL:        url,
+			PreviewURL: url,
+		})
+	}
+
+	return result
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// Equal returns TRUE if the provided revision includes the same values as this one.
+// Identifiers, authors, and timestamps are not compared.
+func (revision *StreamRevision) Equal(other *StreamRevision) bool {
+
+	if revision.Label != other.Label {
+		return false
+	}
+
+	if revision.Summary != other.Summary {
+		return false
+	}
+
+	if revision.Sensitive != other.Sensitive {
+		return false
+	}
+
+	if revision.ImageURL != other.ImageURL {
+		return false
+	}
+
+	if revision.Content.HTML != other.Content.HTML {
+		return false
+	}
+
+	if !slice.Equal(revision.Tags, other.Tags) {
+		return false
+	}
+
+	if len(revision.Attachments) != len(other.Attachments) {
+		return false
+	}
+
+	for index := range revision.Attachments {
+		if revision.Attachments[index].GetString("url") != other.Attachments[index].GetString("url") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AttachmentIndex returns the position of the provided Attachment in this revision,
+// or -1 if the Attachment is not included.
+func (revision *StreamRevision) AttachmentIndex(attachment Attachment) int {
+
+	suffix := "/" + attachment.AttachmentID.Hex()
+
+	for index, item := range revision.Attachments {
+		if strings.HasSuffix(item.GetString("url"), suffix) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// HasAttachment returns TRUE if the provided Attachment is included in this revision
+func (revision *StreamRevision) HasAttachment(attachment Attachment) bool {
+	return revision.AttachmentIndex(attachment) >= 0
+}
+
+// Restore copies the values from this revision back into the provided Stream
+func (revision *StreamRevision) Restore(stream *Stream) {
+	stream.Label = revision.Label
+	stream.Summary = revision.Summary
+	stream.Sensitive = revision.Sensitive
+	stream.ImageURL = revision.ImageURL
+	stream.Content = revision.Content
+	stream.Tags = append(sliceof.NewObject[Tag](), revision.Tags...)
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func StreamRevisionSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"streamRevisionId": schema.String{Format: "objectId"},
+			"streamId":         schema.String{Format: "objectId"},
+			"label":            schema.String{MaxLength: 128},
+			"summary":          schema.String{MaxLength: 2048},
+			"sensitive":        schema.Boolean{},
+			"imageUrl":         schema.String{Format: "url"},
+			"content":          ContentSchema(),
+			"attributedTo":     PersonLinkSchema(),
+			"note":             schema.String{MaxLength: 256},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (revision *StreamRevision) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "label":
+		return &revision.Label, true
+
+	case "summary":
+		return &revision.Summary, true
+
+	case "sensitive":
+		return &revision.Sensitive, true
+
+	case "imageUrl":
+		return &revision.ImageURL, true
+
+	case "content":
+		return &revision.Content, true
+
+	case "attributedTo":
+		return &revision.AttributedTo, true
+
+	case "note":
+		return &revision.Note, true
+	}
+
+	return nil, false
+}
+
+func (revision *StreamRevision) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "streamRevisionId":
+		return revision.StreamRevisionID.Hex(), true
+
+	case "streamId":
+		return revision.StreamID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (revision *StreamRevision) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "streamRevisionId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			revision.StreamRevisionID = objectID
+			return true
+		}
+
+	case "streamId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			revision.StreamID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRevisionSchema(t *testing.T) {
+
+	revision := NewStreamRevision()
+	s := schema.New(StreamRevisionSchema())
+
+	table := []tableTestItem{
+		{"streamRevisionId", "123456781234567812345678", nil},
+		{"streamId", "876543218765432187654321", nil},
+		{"label", "LABEL", nil},
+		{"summary", "SUMMARY", nil},
+		{"sensitive", true, nil},
+		{"imageUrl", "https://example.com/image.png", nil},
+		{"content.format", "HTML", nil},
+		{"content.raw", "RAW", nil},
+		{"content.html", "HTML", nil},
+		{"attributedTo.name", "AUTHOR", nil},
+		{"note", "NOTE", nil},
+	}
+
+	tableTest_Schema(t, &s, &revision, table)
+}
+
+func TestStreamRevisionEqual(t *testing.T) {
+
+	stream := NewStream()
+	stream.Label = "Hello"
+	stream.Content = NewHTMLContent("<p>Hello World</p>")
+
+	first := NewStreamRevisionFromStream(&stream, nil)
+	second := NewStreamRevisionFromStream(&stream, nil)
+	require.True(t, first.Equal(&second))
+
+	stream.Content = NewHTMLContent("<p>Hello There</p>")
+	third := NewStreamRevisionFromStream(&stream, nil)
+	require.False(t, first.Equal(&third))
+
+	// Restoring a revision puts the original values back
+	first.Restore(&stream)
+	require.Equal(t, "<p>Hello World</p>", stream.Content.HTML)
+}
@@ -24,16 +24,21 @@ func StreamSchema() schema.Element {
 			"url":              schema.String{Format: "url"},
 			"label":            schema.String{MaxLength: 128},
 			"summary":          schema.String{MaxLength: 2048},
+			"sensitive":        schema.Boolean{},
 			"imageUrl":         schema.String{Format: "url"},
 			"attributedTo":     PersonLinkSchema(),
 			"context":          schema.String{Format: "url"},
 			"inReplyTo":        schema.String{Format: "url"},
+			"quoteUrl":         schema.String{Format: "url"},
+			"syndicateTo":      schema.Array{Items: schema.String{Format: "objectId"}},
+			"syndication":      schema.Array{Items: schema.String{Format: "url"}},
 			"content":          ContentSchema(),
 			"widgets":          WidgetSchema(),
 			"tags":             schema.Object{Wildcard: schema.String{}},
 			"data":             schema.Object{Wildcard: schema.Any{}},
 			"publishDate":      schema.Integer{BitSize: 64},
 			"unpublishDate":    schema.Integer{BitSize: 64},
+			"editDate":         schema.Integer{BitSize: 64},
 		},
 	}
 }
@@ -81,6 +86,9 @@ func (stream *Stream) GetPointer(name string) (any, bool) {
 	case "summary":
 		return &stream.Summary, true
 
+	case "sensitive":
+		return &stream.Sensitive, true
+
 	case "imageUrl":
 		return &stream.ImageURL, true
 
@@ -102,6 +110,15 @@ func (stream *Stream) GetPointer(name string) (any, bool) {
 	case "inReplyTo":
 		return &stream.InReplyTo, true
 
+	case "quoteUrl":
+		return &stream.QuoteURL, true
+
+	case "syndicateTo":
+		return &stream.SyndicateTo, true
+
+	case "syndication":
+		return &stream.Syndication, true
+
 	case "rank":
 		return &stream.Rank, true
 
@@ -111,6 +128,9 @@ func (stream *Stream) GetPointer(name string) (any, bool) {
 	case "unpublishDate":
 		return &stream.UnPublishDate, true
 
+	case "editDate":
+		return &stream.EditDate, true
+
 	case "socialRole":
 		return &stream.SocialRole, true
 
@@ -6,6 +6,7 @@ import (
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/schema"
 	"github.com/benpate/rosetta/sliceof"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStreamSchema(t *testing.T) {
@@ -37,11 +38,15 @@ func TestStreamSchema(t *testing.T) {
 		{"url", "https://example/document", nil},
 		{"label", "DOC-LABEL", nil},
 		{"summary", "DOC-SUMMARY", nil},
+		{"sensitive", "true", true},
 		{"imageUrl", "DOC-IMAGEURL", nil},
 		{"attributedTo.name", "DOC-AUTHOR-NAME", nil},
 		{"attributedTo.profileUrl", "https://example/author", nil},
 
 		{"inReplyTo", "https://in-reply-to.com", nil},
+		{"quoteUrl", "https://quote-url.com", nil},
+		{"syndicateTo.0", "00000000000000000000000d", nil},
+		{"syndication.0", "https://mastodon.example/@user/1234", nil},
 		{"content.format", "HTML", nil},
 		{"content.raw", "TEST_RAWCONTENT", nil},
 		{"content.html", "TEST_HTML", nil},
@@ -60,6 +65,7 @@ func TestStreamSchema(t *testing.T) {
 
 		{"publishDate", 12345678, int64(12345678)},
 		{"unpublishDate", 123456789, int64(123456789)},
+		{"editDate", 123456790, int64(123456790)},
 	}
 
 	tableTest_Schema(t, &s, &stream, tests)
@@ -79,3 +85,23 @@ func TestPermissionSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &m, table)
 }
+
+func TestStreamContentWarning(t *testing.T) {
+
+	stream := NewStream()
+	stream.Summary = "Just a summary"
+	require.Equal(t, "", stream.ContentWarning())
+
+	// Content warnings are always sensitive
+	stream.SetContentWarning("Spoilers", false)
+	require.True(t, stream.Sensitive)
+	require.Equal(t, "Spoilers", stream.ContentWarning())
+
+	// Removing the content warning also clears the summary
+	stream.SetContentWarning("", true)
+	require.True(t, stream.Sensitive)
+	require.Equal(t, "", stream.Summary)
+
+	stream.SetContentWarning("", false)
+	require.False(t, stream.Sensitive)
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SyndicationTarget represents an external service where a User's Streams can be
+// re-posted when they are published (POSSE: Publish on your Own Site, Syndicate Elsewhere)
+type SyndicationTarget struct {
+	SyndicationTargetID primitive.ObjectID `json:"syndicationTargetId" bson:"_id"`             // Unique identifier of this SyndicationTarget
+	UserID              primitive.ObjectID `json:"userId"              bson:"userId"`          // Unique identifier of the User who owns this SyndicationTarget
+	Type                string             `json:"type"                bson:"type"`            // Type of service to syndicate to (e.g. "MASTODON", "BLUESKY", "WEBHOOK")
+	Label               string             `json:"label"               bson:"label"`           // Human-friendly label to display in the editor
+	URL                 string             `json:"url"                 bson:"url"`             // Base URL of the remote service (Mastodon server, Bluesky PDS, or webhook endpoint)
+	Username            string             `json:"username"            bson:"username"`        // Username or handle on the remote service (Bluesky only)
+	Secret              string             `json:"-"                   bson:"-"`               // Access token, app password, or signing secret used to authenticate with the remote service (plaintext, in memory only)
+	EncryptedSecret     []byte             `json:"-"                   bson:"encryptedSecret"` // Secret, encrypted with the domain's key encrypting key
+	IsDefault           bool               `json:"isDefault"           bson:"isDefault"`       // If TRUE, then this target is pre-selected in the editor
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewSyndicationTarget returns a fully initialized SyndicationTarget object
+func NewSyndicationTarget() SyndicationTarget {
+	return SyndicationTarget{
+		SyndicationTargetID: primitive.NewObjectID(),
+		Type:                SyndicationTargetTypeMastodon,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+func (target SyndicationTarget) ID() string {
+	return target.SyndicationTargetID.Hex()
+}
+
+func (target SyndicationTarget) Fields() []string {
+	return []string{
+		"_id",
+		"userId",
+		"type",
+		"label",
+		"url",
+		"username",
+		"isDefault",
+	}
+}
+
+/******************************************
+ * RoleStateEnumerator Interface
+ ******************************************/
+
+// State returns the current state of this object.
+// For SyndicationTargets, there is no state, so it returns ""
+func (target SyndicationTarget) State() string {
+	return ""
+}
+
+// Roles returns a list of all roles that match the provided authorization.
+// SyndicationTargets contain private credentials, so they are only accessible
+// by the User who owns them.
+func (target SyndicationTarget) Roles(authorization *Authorization) []string {
+
+	if authorization.UserID == target.UserID {
+		return []string{MagicRoleMyself}
+	}
+
+	// Intentionally NOT allowing MagicRoleAnonymous, MagicRoleAuthenticated, or MagicRoleOwner
+	return []string{}
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// Icon returns the name of the icon to display for this SyndicationTarget
+func (target SyndicationTarget) Icon() string {
+
+	switch target.Type {
+
+	case SyndicationTargetTypeMastodon:
+		return "mastodon"
+
+	case SyndicationTargetTypeBluesky:
+		return "bluesky"
+
+	default:
+		return "webhook"
+	}
+}
+
+// TypeLabel returns a human-friendly name for the type of this SyndicationTarget
+func (target SyndicationTarget) TypeLabel() string {
+
+	switch target.Type {
+
+	case SyndicationTargetTypeMastodon:
+		return "Mastodon"
+
+	case SyndicationTargetTypeBluesky:
+		return "Bluesky"
+
+	default:
+		return "Webhook"
+	}
+}
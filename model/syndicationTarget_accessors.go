@@ -0,0 +1,85 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func SyndicationTargetSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"syndicationTargetId": schema.String{Required: true, Format: "objectId"},
+			"userId":              schema.String{Required: true, Format: "objectId"},
+			"type":                schema.String{Required: true, Enum: []string{SyndicationTargetTypeMastodon, SyndicationTargetTypeBluesky, SyndicationTargetTypeWebhook}},
+			"label":               schema.String{Required: true, MaxLength: 128},
+			"url":                 schema.String{Required: true, Format: "url"},
+			"username":            schema.String{MaxLength: 256},
+			"secret":              schema.String{MaxLength: 1024},
+			"isDefault":           schema.Boolean{},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (target *SyndicationTarget) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "type":
+		return &target.Type, true
+
+	case "label":
+		return &target.Label, true
+
+	case "url":
+		return &target.URL, true
+
+	case "username":
+		return &target.Username, true
+
+	case "secret":
+		return &target.Secret, true
+
+	case "isDefault":
+		return &target.IsDefault, true
+	}
+
+	return nil, false
+}
+
+func (target *SyndicationTarget) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "syndicationTargetId":
+		return target.SyndicationTargetID.Hex(), true
+
+	case "userId":
+		return target.UserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (target *SyndicationTarget) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "syndicationTargetId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			target.SyndicationTargetID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			target.UserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,10 @@
+package model
+
+// SyndicationTargetTypeMastodon syndicates Streams to a Mastodon-compatible account via its REST API
+const SyndicationTargetTypeMastodon = "MASTODON"
+
+// SyndicationTargetTypeBluesky syndicates Streams to a Bluesky PDS via XRPC
+const SyndicationTargetTypeBluesky = "BLUESKY"
+
+// SyndicationTargetTypeWebhook syndicates Streams by POSTing a JSON document to a generic webhook
+const SyndicationTargetTypeWebhook = "WEBHOOK"
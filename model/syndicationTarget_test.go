@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSyndicationTargetSchema(t *testing.T) {
+
+	target := NewSyndicationTarget()
+	s := schema.New(SyndicationTargetSchema())
+
+	table := []tableTestItem{
+		{"syndicationTargetId", "123456781234567812345678", nil},
+		{"userId", "876543218765432187654321", nil},
+		{"type", "BLUESKY", nil},
+		{"label", "My Bluesky Account", nil},
+		{"url", "https://bsky.social", nil},
+		{"username", "alice.bsky.social", nil},
+		{"secret", "app-password", nil},
+		{"isDefault", "true", true},
+	}
+
+	tableTest_Schema(t, &s, &target, table)
+}
+
+func TestSyndicationTarget_Roles(t *testing.T) {
+
+	target := NewSyndicationTarget()
+	target.UserID = primitive.NewObjectID()
+
+	owner := Authorization{UserID: target.UserID}
+	require.Equal(t, []string{MagicRoleMyself}, target.Roles(&owner))
+
+	other := Authorization{UserID: primitive.NewObjectID()}
+	require.Empty(t, target.Roles(&other))
+}
@@ -38,6 +38,7 @@ type Template struct {
 	Resources          fs.FS                `json:"-"                  bson:"-"`                  // File system containing the template resources
 	DefaultAction      string               `json:"defaultAction"      bson:"defaultAction"`      // Name of the action to be used when none is provided.  Also serves as the permissions for viewing a Stream.  If this is empty, it is assumed to be "view"
 	Actor              StreamActor          `json:"actor"             bson:"actor"`               // ActivityPub Actor operated on behalf of this Template/Stream
+	Problems           sliceof.String       `json:"-"                  bson:"-"`                  // Problems found when this Template was loaded (like incomplete microformats)
 }
 
 // NewTemplate creates a new, fully initialized Template object
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/benpate/rosetta/sliceof"
+)
+
+// TwoFactorMaxAttempts is the number of consecutive failed codes that lock two-factor sign-ins
+const TwoFactorMaxAttempts = 5
+
+// TwoFactorLockout is the amount of time that two-factor sign-ins are locked after too many failed codes
+const TwoFactorLockout = 15 * time.Minute
+
+// TwoFactor contains a User's settings for TOTP two-factor authentication.
+type TwoFactor struct {
+	Secret         string         `bson:"secret,omitempty"`         // Base32 TOTP secret shared with the User's authenticator app.  If empty, then two-factor authentication is not enabled.
+	PendingSecret  string         `bson:"pendingSecret,omitempty"`  // Base32 TOTP secret that is waiting for the User to confirm their first code.
+	RecoveryCodes  sliceof.String `bson:"recoveryCodes,omitempty"`  // SHA-256 hashes of the unused, single-use recovery codes
+	EnabledDate    int64          `bson:"enabledDate,omitempty"`    // Unix epoch seconds when two-factor authentication was enabled
+	LastCounter    int64          `bson:"lastCounter,omitempty"`    // TOTP time counter of the last accepted code, so that codes cannot be replayed
+	FailedAttempts int            `bson:"failedAttempts,omitempty"` // Number of consecutive failed codes
+	LockedUntil    int64          `bson:"lockedUntil,omitempty"`    // Unix epoch seconds until which two-factor sign-ins are locked
+}
+
+// NewTwoFactor returns a fully initialized (and disabled) TwoFactor object
+func NewTwoFactor() TwoFactor {
+	return TwoFactor{
+		RecoveryCodes: sliceof.NewString(),
+	}
+}
+
+// IsEnabled returns TRUE if the User has confirmed a TOTP secret
+func (twoFactor TwoFactor) IsEnabled() bool {
+	return twoFactor.Secret != ""
+}
+
+// IsLocked returns TRUE if two-factor sign-ins are temporarily locked because of too many failed codes
+func (twoFactor TwoFactor) IsLocked() bool {
+	return twoFactor.LockedUntil > time.Now().Unix()
+}
+
+// RecoveryCodeCount returns the number of unused recovery codes
+func (twoFactor TwoFactor) RecoveryCodeCount() int {
+	return len(twoFactor.RecoveryCodes)
+}
+
+// SetRecoveryCodes replaces all recovery codes with (hashes of) the provided values
+func (twoFactor *TwoFactor) SetRecoveryCodes(codes []string) {
+
+	twoFactor.RecoveryCodes = make(sliceof.String, 0, len(codes))
+
+	for _, code := range codes {
+		twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes, hashRecoveryCode(code))
+	}
+}
+
+// UseRecoveryCode returns TRUE if the code matches an unused recovery code,
+// and removes it so that it cannot be used again.
+func (twoFactor *TwoFactor) UseRecoveryCode(code string) bool {
+
+	hashed := hashRecoveryCode(code)
+
+	for index, value := range twoFactor.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(hashed)) == 1 {
+			twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes[:index], twoFactor.RecoveryCodes[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Success resets the failure count after a valid code
+func (twoFactor *TwoFactor) Success() {
+	twoFactor.FailedAttempts = 0
+	twoFactor.LockedUntil = 0
+}
+
+// Failure records an invalid code, and locks two-factor sign-ins after too many failures in a row
+func (twoFactor *TwoFactor) Failure() {
+
+	twoFactor.FailedAttempts++
+
+	if twoFactor.FailedAttempts >= TwoFactorMaxAttempts {
+		twoFactor.FailedAttempts = 0
+		twoFactor.LockedUntil = time.Now().Add(TwoFactorLockout).Unix()
+	}
+}
+
+// hashRecoveryCode returns the SHA-256 hash of a recovery code, ignoring case, spaces, and dashes
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
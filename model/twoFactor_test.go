@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor_RecoveryCodes(t *testing.T) {
+
+	twoFactor := NewTwoFactor()
+	twoFactor.SetRecoveryCodes([]string{"abcd-efgh", "ijkl-mnop"})
+
+	require.Equal(t, 2, twoFactor.RecoveryCodeCount())
+	require.NotContains(t, twoFactor.RecoveryCodes, "abcd-efgh")
+
+	// Unknown codes are rejected
+	require.False(t, twoFactor.UseRecoveryCode("qrst-uvwx"))
+
+	// Case, spaces, and dashes are ignored
+	require.True(t, twoFactor.UseRecoveryCode("ABCD EFGH"))
+	require.Equal(t, 1, twoFactor.RecoveryCodeCount())
+
+	// Each code can only be used once
+	require.False(t, twoFactor.UseRecoveryCode("abcd-efgh"))
+	require.True(t, twoFactor.UseRecoveryCode("ijklmnop"))
+	require.Equal(t, 0, twoFactor.RecoveryCodeCount())
+}
+
+func TestTwoFactor_Lockout(t *testing.T) {
+
+	twoFactor := NewTwoFactor()
+
+	for i := 1; i < TwoFactorMaxAttempts; i++ {
+		twoFactor.Failure()
+		require.False(t, twoFactor.IsLocked())
+	}
+
+	twoFactor.Failure()
+	require.True(t, twoFactor.IsLocked())
+
+	twoFactor.Success()
+	require.False(t, twoFactor.IsLocked())
+	require.Zero(t, twoFactor.FailedAttempts)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/EmissarySocial/emissary/tools/id"
 	"github.com/benpate/data/journal"
+	"github.com/benpate/hannibal"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/sliceof"
@@ -15,38 +16,48 @@ import (
 
 // User represents a person or machine account that can own pages and sections.
 type User struct {
-	UserID          primitive.ObjectID         `json:"userId"          bson:"_id"`                  // Unique identifier for this user.
-	GroupIDs        id.Slice                   `json:"groupIds"        bson:"groupIds"`             // Slice of IDs for the groups that this user belongs to.
-	ImageID         primitive.ObjectID         `json:"imageId"         bson:"imageId"`              // AttachmentID of this user's avatar image.
-	DisplayName     string                     `json:"displayName"     bson:"displayName"`          // Name to be displayed for this user
-	StatusMessage   string                     `json:"statusMessage"   bson:"statusMessage"`        // Status summary for this user
-	Location        string                     `json:"location"        bson:"location"`             // Human-friendly description of this user's physical location.
-	ProfileURL      string                     `json:"profileUrl"      bson:"profileUrl"`           // Fully Qualified profile URL for this user (including domain name)
-	EmailAddress    string                     `json:"emailAddress"    bson:"emailAddress"`         // Email address for this user
-	Username        string                     `json:"username"        bson:"username"`             // This is the primary public identifier for the user.
-	Password        string                     `json:"-"               bson:"password"`             // This password should be encrypted with BCrypt.
-	Locale          string                     `json:"locale"          bson:"locale"`               // Language code for this user's preferred language.
-	SignupNote      string                     `json:"signupNote"      bson:"signupNote,omitempty"` // Note that was included when this user signed up.
-	InboxTemplate   string                     `json:"inboxTemplate"   bson:"inboxTemplate"`        // Template for the user's inbox
-	OutboxTemplate  string                     `json:"outboxTemplate"  bson:"outboxTemplate"`       // Template for the user's outbox
-	Links           sliceof.Object[PersonLink] `json:"links"           bson:"links"`                // Slice of links to profiles on other web services.
-	FollowerCount   int                        `json:"followerCount"   bson:"followerCount"`        // Number of followers for this user
-	FollowingCount  int                        `json:"followingCount"  bson:"followingCount"`       // Number of users that this user is following
-	RuleCount       int                        `json:"ruleCount"       bson:"ruleCount"`            // Number of users that this user is following
-	IsOwner         bool                       `json:"isOwner"         bson:"isOwner"`              // If TRUE, then this user is a website owner with FULL privileges.
-	IsPublic        bool                       `json:"isPublic"        bson:"isPublic"`             // If TRUE, then this user's profile is publicly available
-	PasswordReset   PasswordReset              `json:"-"               bson:"passwordReset"`        // Most recent password reset information.
-	Data            mapof.String               `json:"data"            bson:"data"`                 // Custom profile data that can be stored with this User.
-	journal.Journal `json:"-" bson:",inline"`
+	UserID            primitive.ObjectID         `json:"userId"          bson:"_id"`                    // Unique identifier for this user.
+	GroupIDs          id.Slice                   `json:"groupIds"        bson:"groupIds"`               // Slice of IDs for the groups that this user belongs to.
+	ImageID           primitive.ObjectID         `json:"imageId"         bson:"imageId"`                // AttachmentID of this user's avatar image.
+	DisplayName       string                     `json:"displayName"     bson:"displayName"`            // Name to be displayed for this user
+	StatusMessage     string                     `json:"statusMessage"   bson:"statusMessage"`          // Status summary for this user
+	Location          string                     `json:"location"        bson:"location"`               // Human-friendly description of this user's physical location.
+	ProfileURL        string                     `json:"profileUrl"      bson:"profileUrl"`             // Fully Qualified profile URL for this user (including domain name)
+	EmailAddress      string                     `json:"emailAddress"    bson:"emailAddress"`           // Email address for this user
+	Username          string                     `json:"username"        bson:"username"`               // This is the primary public identifier for the user.
+	Password          string                     `json:"-"               bson:"password"`               // This password should be encrypted with BCrypt.
+	Locale            string                     `json:"locale"          bson:"locale"`                 // Language code for this user's preferred language.
+	SignupNote        string                     `json:"signupNote"      bson:"signupNote,omitempty"`   // Note that was included when this user signed up.
+	InboxTemplate     string                     `json:"inboxTemplate"   bson:"inboxTemplate"`          // Template for the user's inbox
+	OutboxTemplate    string                     `json:"outboxTemplate"  bson:"outboxTemplate"`         // Template for the user's outbox
+	Links             sliceof.Object[PersonLink] `json:"links"           bson:"links"`                  // Slice of links to profiles on other web services.
+	FollowerCount     int                        `json:"followerCount"   bson:"followerCount"`          // Number of followers for this user
+	FollowingCount    int                        `json:"followingCount"  bson:"followingCount"`         // Number of users that this user is following
+	RuleCount         int                        `json:"ruleCount"       bson:"ruleCount"`              // Number of users that this user is following
+	IsOwner           bool                       `json:"isOwner"         bson:"isOwner"`                // If TRUE, then this user is a website owner with FULL privileges.
+	IsPublic          bool                       `json:"isPublic"        bson:"isPublic"`               // If TRUE, then this user's profile is publicly available
+	ShowSensitive     bool                       `json:"showSensitive"   bson:"showSensitive"`          // If TRUE, then content warnings are always expanded in this user's inbox
+	PasswordReset     PasswordReset              `json:"-"               bson:"passwordReset"`          // Most recent password reset information.
+	TwoFactor         TwoFactor                  `json:"-"               bson:"twoFactor"`              // TOTP two-factor authentication settings
+	Passkeys          sliceof.Object[Passkey]    `json:"-"               bson:"passkeys"`               // WebAuthn passkeys that this user can sign in with
+	IsPending         bool                       `json:"isPending"       bson:"isPending"`              // If TRUE, then this user is waiting for a domain owner to approve their account
+	EmailConfirmation EmailConfirmation          `json:"-"               bson:"emailConfirmation"`      // Pending confirmation of this user's email address
+	InvitationID      primitive.ObjectID         `json:"invitationId"    bson:"invitationId,omitempty"` // Invitation that this user signed up with (if any)
+	DataExport        DataExport                 `json:"-"               bson:"dataExport"`             // Most recent archive of this user's personal data
+	IsPurgePending    bool                       `json:"-"               bson:"purgePending,omitempty"` // If TRUE, then this user has been deleted but their related records have not been removed yet
+	Data              mapof.String               `json:"data"            bson:"data"`                   // Custom profile data that can be stored with this User.
+	journal.Journal   `json:"-" bson:",inline"`
 }
 
 // NewUser returns a fully initialized User object.
 func NewUser() User {
 	return User{
-		UserID:   primitive.NewObjectID(),
-		GroupIDs: make([]primitive.ObjectID, 0),
-		Links:    make([]PersonLink, 0),
-		Data:     mapof.NewString(),
+		UserID:    primitive.NewObjectID(),
+		GroupIDs:  make([]primitive.ObjectID, 0),
+		Links:     make([]PersonLink, 0),
+		Data:      mapof.NewString(),
+		TwoFactor: NewTwoFactor(),
+		Passkeys:  sliceof.NewObject[Passkey](),
 	}
 }
 
@@ -216,6 +227,17 @@ func (user User) GetJSONLD() mapof.Any {
 	return result
 }
 
+// GetTombstoneJSONLD returns the ActivityPub Tombstone that replaces a deleted User's profile
+func (user User) GetTombstoneJSONLD() mapof.Any {
+	return mapof.Any{
+		vocab.AtContext:          vocab.NamespaceActivityStreams,
+		vocab.PropertyID:         user.ProfileURL,
+		vocab.PropertyType:       vocab.ObjectTypeTombstone,
+		vocab.PropertyFormerType: vocab.ActorTypePerson,
+		vocab.PropertyDeleted:    hannibal.TimeFormat(time.UnixMilli(user.DeleteDate)),
+	}
+}
+
 func (user *User) GetProfileURL() string {
 	return user.ProfileURL
 }
@@ -316,3 +338,60 @@ func (user User) Toot() object.Account {
 func (user User) GetRank() int64 {
 	return user.CreateDate
 }
+
+/******************************************
+ * Account Status Methods
+ ******************************************/
+
+// IsActive returns TRUE if this User is allowed to sign in.  Users who signed up on their
+// own may need to confirm their email address, or wait for a domain owner's approval first.
+func (user *User) IsActive() bool {
+	return !user.IsPending && !user.EmailConfirmation.IsPending()
+}
+
+// Tombstone returns a copy of this User that includes only the information required to
+// publish a Tombstone at their profile URL.  All other personal data is removed.
+func (user *User) Tombstone() User {
+
+	result := NewUser()
+	result.UserID = user.UserID
+	result.Username = user.Username
+	result.ProfileURL = user.ProfileURL
+	result.Journal = user.Journal
+
+	return result
+}
+
+/******************************************
+ * Passkey Methods
+ ******************************************/
+
+// HasPasskeys returns TRUE if this User has registered at least one Passkey
+func (user *User) HasPasskeys() bool {
+	return len(user.Passkeys) > 0
+}
+
+// PasskeyIndex returns the index of the Passkey with the provided credential ID, or -1 if it does not exist
+func (user *User) PasskeyIndex(credentialID string) int {
+
+	for index, passkey := range user.Passkeys {
+		if passkey.CredentialID == credentialID {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// RemovePasskey removes the Passkey with the provided ID, and returns TRUE if it was found
+func (user *User) RemovePasskey(passkeyID primitive.ObjectID) bool {
+
+	for index, passkey := range user.Passkeys {
+		if passkey.PasskeyID == passkeyID {
+			user.Passkeys = append(user.Passkeys[:index], user.Passkeys[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSession represents a browser that a User has signed in with.  The ID of
+// each UserSession is included in the User's JWT certificate, so that the User
+// (or a domain owner) can sign out of individual browsers remotely.
+type UserSession struct {
+	UserSessionID primitive.ObjectID `json:"userSessionId" bson:"_id"`          // Unique identifier for this UserSession
+	UserID        primitive.ObjectID `json:"userId"        bson:"userId"`       // User who signed in
+	UserAgent     string             `json:"userAgent"     bson:"userAgent"`    // User-Agent header of the browser that signed in
+	IPAddress     string             `json:"ipAddress"     bson:"ipAddress"`    // IP address that this UserSession was most recently used from
+	LastSeenDate  int64              `json:"lastSeenDate"  bson:"lastSeenDate"` // Unix epoch seconds when this UserSession was most recently used
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewUserSession returns a fully initialized UserSession object
+func NewUserSession() UserSession {
+	return UserSession{
+		UserSessionID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (session *UserSession) ID() string {
+	return session.UserSessionID.Hex()
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// IsStale returns TRUE if this UserSession has not been seen within the provided duration
+func (session UserSession) IsStale(duration time.Duration) bool {
+	return session.LastSeenDate < time.Now().Add(-duration).Unix()
+}
+
+// Device returns a human-friendly description of the browser and operating system
+// that this UserSession was created with, like "Firefox on Windows"
+func (session UserSession) Device() string {
+
+	browser := userAgentBrowser(session.UserAgent)
+	system := userAgentSystem(session.UserAgent)
+
+	switch {
+
+	case (browser != "") && (system != ""):
+		return browser + " on " + system
+
+	case browser != "":
+		return browser
+
+	case system != "":
+		return system
+	}
+
+	return "Unknown Device"
+}
+
+// userAgentBrowser returns the name of the browser in a User-Agent header.
+// Order matters here, because many browsers include the names of others.
+func userAgentBrowser(userAgent string) string {
+
+	switch {
+
+	case strings.Contains(userAgent, "Firefox/"), strings.Contains(userAgent, "FxiOS/"):
+		return "Firefox"
+
+	case strings.Contains(userAgent, "Edg/"), strings.Contains(userAgent, "EdgiOS/"), strings.Contains(userAgent, "EdgA/"):
+		return "Edge"
+
+	case strings.Contains(userAgent, "OPR/"):
+		return "Opera"
+
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		return "Chrome"
+
+	case strings.Contains(userAgent, "Safari/"):
+		return "Safari"
+	}
+
+	return ""
+}
+
+// userAgentSystem returns the name of the operating system in a User-Agent header.
+func userAgentSystem(userAgent string) string {
+
+	switch {
+
+	case strings.Contains(userAgent, "iPhone"):
+		return "iPhone"
+
+	case strings.Contains(userAgent, "iPad"):
+		return "iPad"
+
+	case strings.Contains(userAgent, "Android"):
+		return "Android"
+
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		return "macOS"
+
+	case strings.Contains(userAgent, "Windows"):
+		return "Windows"
+
+	case strings.Contains(userAgent, "CrOS"):
+		return "ChromeOS"
+
+	case strings.Contains(userAgent, "Linux"):
+		return "Linux"
+	}
+
+	return ""
+}
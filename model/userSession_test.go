@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserSession_Device(t *testing.T) {
+
+	test := func(userAgent string, expected string) {
+		session := NewUserSession()
+		session.UserAgent = userAgent
+		require.Equal(t, expected, session.Device())
+	}
+
+	test("Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0", "Firefox on Windows")
+	test("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15", "Safari on macOS")
+	test("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", "Edge on Windows")
+	test("Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36", "Chrome on Android")
+	test("Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.0.0 Mobile/15E148 Safari/604.1", "Chrome on iPhone")
+	test("Mozilla/5.0 (X11; Linux x86_64)", "Linux")
+	test("curl/8.5.0", "Unknown Device")
+	test("", "Unknown Device")
+}
+
+func TestUserSession_IsStale(t *testing.T) {
+
+	session := NewUserSession()
+	require.True(t, session.IsStale(time.Minute))
+
+	session.LastSeenDate = time.Now().Unix()
+	require.False(t, session.IsStale(time.Minute))
+
+	session.LastSeenDate = time.Now().Add(-2 * time.Minute).Unix()
+	require.True(t, session.IsStale(time.Minute))
+}
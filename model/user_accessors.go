@@ -28,7 +28,9 @@ func UserSchema() schema.Element {
 			"followingCount": schema.Integer{},
 			"ruleCount":      schema.Integer{},
 			"isPublic":       schema.Boolean{},
+			"showSensitive":  schema.Boolean{},
 			"isOwner":        schema.Boolean{},
+			"isPending":      schema.Boolean{},
 			"data":           schema.Object{Wildcard: schema.String{}},
 		},
 	}
@@ -51,9 +53,15 @@ func (user *User) GetPointer(name string) (any, bool) {
 	case "isOwner":
 		return &user.IsOwner, true
 
+	case "isPending":
+		return &user.IsPending, true
+
 	case "isPublic":
 		return &user.IsPublic, true
 
+	case "showSensitive":
+		return &user.ShowSensitive, true
+
 	case "followerCount":
 		return &user.FollowerCount, true
 
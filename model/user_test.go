@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/benpate/rosetta/schema"
 	"github.com/stretchr/testify/require"
@@ -32,7 +33,9 @@ func TestUserSchema(t *testing.T) {
 		{"followingCount", "2", 2},
 		{"ruleCount", "3", 3},
 		{"isPublic", "true", true},
+		{"showSensitive", "true", true},
 		{"isOwner", "true", true},
+		{"isPending", "true", true},
 		{"inboxTemplate", "INBOX", nil},
 		{"outboxTemplate", "OUTBOX", nil},
 	}
@@ -48,3 +51,62 @@ func TestUserJSONLD(t *testing.T) {
 	getter := any(user).(JSONLDGetter)
 	require.NotNil(t, getter.GetJSONLD())
 }
+
+func TestUser_Tombstone(t *testing.T) {
+
+	user := NewUser()
+	user.Username = "john"
+	user.DisplayName = "John Connor"
+	user.EmailAddress = "john@connor.mil"
+	user.Password = "hashed-password"
+	user.ProfileURL = "https://emissary.social/@john"
+	user.SetDeleted("Deleted")
+
+	tombstone := user.Tombstone()
+	require.Equal(t, user.UserID, tombstone.UserID)
+	require.Equal(t, "john", tombstone.Username)
+	require.Equal(t, "https://emissary.social/@john", tombstone.ProfileURL)
+	require.Equal(t, user.DeleteDate, tombstone.DeleteDate)
+	require.Empty(t, tombstone.DisplayName)
+	require.Empty(t, tombstone.EmailAddress)
+	require.Empty(t, tombstone.Password)
+
+	jsonld := tombstone.GetTombstoneJSONLD()
+	require.Equal(t, "Tombstone", jsonld.GetString("type"))
+	require.Equal(t, "Person", jsonld.GetString("formerType"))
+	require.Equal(t, "https://emissary.social/@john", jsonld.GetString("id"))
+	require.NotEmpty(t, jsonld.GetString("deleted"))
+}
+
+func TestUser_IsActive(t *testing.T) {
+
+	user := NewUser()
+	require.True(t, user.IsActive())
+
+	// Waiting for approval
+	user.IsPending = true
+	require.False(t, user.IsActive())
+	user.IsPending = false
+
+	// Waiting for email confirmation
+	user.EmailConfirmation = NewEmailConfirmation(time.Hour)
+	require.False(t, user.IsActive())
+	require.False(t, user.EmailConfirmation.IsValid(""))
+	require.False(t, user.EmailConfirmation.IsValid("wrong"))
+	require.True(t, user.EmailConfirmation.IsValid(user.EmailConfirmation.AuthCode))
+
+	user.EmailConfirmation.Confirm()
+	require.True(t, user.IsActive())
+	require.False(t, user.EmailConfirmation.IsValid(""))
+	require.NotZero(t, user.EmailConfirmation.ConfirmDate)
+}
+
+func TestUser_IsActive_Expired(t *testing.T) {
+
+	user := NewUser()
+	user.EmailConfirmation = NewEmailConfirmation(-time.Hour)
+
+	require.True(t, user.EmailConfirmation.IsExpired())
+	require.False(t, user.EmailConfirmation.IsValid(user.EmailConfirmation.AuthCode))
+	require.False(t, user.IsActive())
+}
@@ -0,0 +1,25 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSetDataExport updates ONLY the "dataExport" field of a User, so that long-running
+// data exports cannot overwrite other changes that were made to the User in the meantime.
+func UserSetDataExport(userCollection data.Collection, userID primitive.ObjectID, dataExport model.DataExport) error {
+
+	return RawUpdate(context.Background(), userCollection,
+		exp.Equal("_id", userID),
+		bson.M{
+			"$set": bson.M{
+				"dataExport": dataExport,
+			},
+		},
+	)
+}
@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvitationRedeem atomically adds one use to an Invitation, but only if it has not been
+// deleted, has not expired, and has not already been used as many times as it allows.
+// It returns FALSE if the Invitation could not be used.
+func InvitationRedeem(collection data.Collection, invitationID primitive.ObjectID) (bool, error) {
+
+	// Guarantee that we're using MongoDB
+	mongo := mongoCollection(collection)
+
+	if mongo == nil {
+		return false, derp.NewInternalError("queries.InvitationRedeem", "Database must be MongoDB")
+	}
+
+	filter := bson.M{
+		"_id":        invitationID,
+		"deleteDate": 0,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"maxUses": 0},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$useCount", "$maxUses"}}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"expireDate": 0},
+				bson.M{"expireDate": bson.M{"$gt": time.Now().Unix()}},
+			}},
+		},
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"useCount": 1},
+	}
+
+	result, err := mongo.UpdateOne(context.Background(), filter, update)
+
+	if err != nil {
+		return false, derp.Wrap(err, "queries.InvitationRedeem", "Error redeeming Invitation", invitationID)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// InvitationRelease atomically removes one use from an Invitation, returning it to
+// the pool when the account it was redeemed for could not be created.
+func InvitationRelease(collection data.Collection, invitationID primitive.ObjectID) error {
+
+	// Guarantee that we're using MongoDB
+	mongo := mongoCollection(collection)
+
+	if mongo == nil {
+		return derp.NewInternalError("queries.InvitationRelease", "Database must be MongoDB")
+	}
+
+	filter := bson.M{
+		"_id":      invitationID,
+		"useCount": bson.M{"$gt": 0},
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"useCount": -1},
+	}
+
+	if _, err := mongo.UpdateOne(context.Background(), filter, update); err != nil {
+		return derp.Wrap(err, "queries.InvitationRelease", "Error releasing Invitation", invitationID)
+	}
+
+	return nil
+}
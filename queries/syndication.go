@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/benpate/data"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StreamSetSyndication records the results of syndicating a Stream.  Finished targets are removed
+// from the "syndicateTo" field, and new URLs are added to the "syndication" field.  No other fields
+// are changed, so edits made while the Stream was being syndicated are not overwritten.
+func StreamSetSyndication(streamCollection data.Collection, streamID primitive.ObjectID, finished []string, syndication []string) error {
+
+	update := bson.M{
+		"$pull": bson.M{
+			"syndicateTo": bson.M{"$in": finished},
+		},
+	}
+
+	if len(syndication) > 0 {
+		update["$addToSet"] = bson.M{
+			"syndication": bson.M{"$each": syndication},
+		}
+	}
+
+	return RawUpdate(context.Background(), streamCollection, exp.Equal("_id", streamID), update)
+}
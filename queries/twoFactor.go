@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TwoFactorAttempt atomically counts one two-factor attempt against a User, locking two-factor
+// sign-ins once maxAttempts have been counted.  It returns FALSE (without counting) if two-factor
+// sign-ins are already locked.  Because each attempt is counted in a single conditional update
+// BEFORE the code is checked, concurrent requests cannot make more than maxAttempts guesses.
+func TwoFactorAttempt(userCollection data.Collection, userID primitive.ObjectID, maxAttempts int, lockout time.Duration) (bool, error) {
+
+	// Guarantee that we're using MongoDB
+	mongo := mongoCollection(userCollection)
+
+	if mongo == nil {
+		return false, derp.NewInternalError("queries.TwoFactorAttempt", "Database must be MongoDB")
+	}
+
+	now := time.Now()
+
+	// Only match Users whose two-factor sign-ins are not locked
+	filter := bson.M{
+		"_id":                   userID,
+		"twoFactor.lockedUntil": bson.M{"$not": bson.M{"$gt": now.Unix()}},
+	}
+
+	// Increment the failure count, then lock (and reset the count) once it reaches the limit
+	failedAttempts := bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$twoFactor.failedAttempts", 0}}, 1}}
+	isLocked := bson.M{"$gte": bson.A{"$twoFactor.failedAttempts", maxAttempts}}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"twoFactor.failedAttempts": failedAttempts,
+		}},
+		bson.M{"$set": bson.M{
+			"twoFactor.lockedUntil":    bson.M{"$cond": bson.A{isLocked, now.Add(lockout).Unix(), 0}},
+			"twoFactor.failedAttempts": bson.M{"$cond": bson.A{isLocked, 0, "$twoFactor.failedAttempts"}},
+		}},
+	}
+
+	result, err := mongo.UpdateOne(context.Background(), filter, update)
+
+	if err != nil {
+		return false, derp.Wrap(err, "queries.TwoFactorAttempt", "Error counting two-factor attempt", userID)
+	}
+
+	return result.MatchedCount > 0, nil
+}
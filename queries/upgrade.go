@@ -13,7 +13,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func UpgradeMongoDB(connectionString string, databaseName string, domain *model.Domain) error {
+// UpgradeMongoDB runs all of the database migrations that have not yet been applied to this domain.
+// The keyEncryptingKey is used by migrations that encrypt data that is stored in the database.
+func UpgradeMongoDB(connectionString string, databaseName string, keyEncryptingKey []byte, domain *model.Domain) error {
 
 	const location = "queries.UpgradeMongoDB"
 
@@ -32,6 +34,9 @@ func UpgradeMongoDB(connectionString string, databaseName string, domain *model.
 		upgrades.Version11,
 		upgrades.Version12,
 		upgrades.Version13,
+		upgrades.Version14,
+		upgrades.Version15(keyEncryptingKey),
+		upgrades.Version16,
 	}
 
 	// If we're already at the target database version or higher, then skip any other work
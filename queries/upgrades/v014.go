@@ -4,12 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// Version14...
+// Version14 marks all existing Mentions as VALIDATED, so that they remain
+// visible once WebMentions require the owner's approval.
 func Version14(ctx context.Context, session *mongo.Database) error {
 
 	fmt.Println("... Version 14")
-	return nil
+
+	collection := session.Collection("Mention")
+
+	filter := bson.M{"stateId": bson.M{"$in": bson.A{"", nil}}}
+	update := bson.M{"$set": bson.M{"stateId": "VALIDATED"}}
+
+	_, err := collection.UpdateMany(ctx, filter, update)
+	return err
 }
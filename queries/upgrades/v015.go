@@ -2,14 +2,56 @@ package upgrades
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/keywrap"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// Version15...
-func Version15(ctx context.Context, session *mongo.Database) error {
+// Version15 encrypts all plaintext private keys in the EncryptionKey collection
+// with the domain's key encrypting key.
+func Version15(keyEncryptingKey []byte) func(context.Context, *mongo.Database) error {
 
-	fmt.Println("... Version 15")
-	return nil
+	return func(ctx context.Context, session *mongo.Database) error {
+
+		fmt.Println("... Version 15")
+
+		collection := session.Collection("EncryptionKey")
+
+		criteria := bson.M{"encoding": bson.M{"$in": bson.A{model.EncryptionKeyEncodingPlaintext, "", nil}}}
+		cursor, err := collection.Find(ctx, criteria)
+
+		if err != nil {
+			return err
+		}
+
+		encryptionKeys := make([]model.EncryptionKey, 0)
+		if err := cursor.All(ctx, &encryptionKeys); err != nil {
+			return err
+		}
+
+		for _, encryptionKey := range encryptionKeys {
+
+			ciphertext, err := keywrap.Wrap(keyEncryptingKey, []byte(encryptionKey.PrivatePEM), encryptionKey.EncryptionKeyID[:])
+
+			if err != nil {
+				return err
+			}
+
+			filter := bson.M{"_id": encryptionKey.EncryptionKeyID}
+			update := bson.M{"$set": bson.M{
+				"privatePEM": base64.StdEncoding.EncodeToString(ciphertext),
+				"encoding":   model.EncryptionKeyEncodingAESGCM,
+			}}
+
+			if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
 }
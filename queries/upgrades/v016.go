@@ -0,0 +1,29 @@
+package upgrades
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EmissarySocial/emissary/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Version16 marks all existing EncryptionKeys as the current RSA "main-key" for their
+// Actors, so that they can be rotated.
+func Version16(ctx context.Context, session *mongo.Database) error {
+
+	fmt.Println("... Version 16")
+
+	collection := session.Collection("EncryptionKey")
+
+	filter := bson.M{"algorithm": bson.M{"$in": bson.A{"", nil}}}
+	update := bson.M{"$set": bson.M{
+		"algorithm":  model.EncryptionKeyAlgorithmRSA,
+		"fragment":   model.EncryptionKeyMainFragment,
+		"expireDate": 0,
+	}}
+
+	_, err := collection.UpdateMany(ctx, filter, update)
+	return err
+}
@@ -19,10 +19,13 @@ import (
 
 	"github.com/EmissarySocial/emissary/config"
 	"github.com/EmissarySocial/emissary/handler"
+	ap "github.com/EmissarySocial/emissary/handler/activitypub"
+	ap_domain "github.com/EmissarySocial/emissary/handler/activitypub_domain"
 	ap_stream "github.com/EmissarySocial/emissary/handler/activitypub_stream"
 	ap_user "github.com/EmissarySocial/emissary/handler/activitypub_user"
 	mw "github.com/EmissarySocial/emissary/middleware"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/tools/clientip"
 	"github.com/benpate/derp"
 	"github.com/benpate/domain"
 	"github.com/benpate/hannibal/vocab"
@@ -79,17 +82,28 @@ func main() {
 	// Wait for the first time the configuration is loaded
 	<-factory.Refreshed()
 
+	// Rotate key encrypting keys (if requested) then exit without starting the web server
+	if commandLineArgs.RotateKEK {
+
+		if err := factory.RotateKeyEncryptingKeys(); err != nil {
+			derp.Report(err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
 	// Start and configure the Web server
 	e := echo.New()
 	e.Logger.SetLevel(gommonlog.OFF)
 	e.HideBanner = true
 	e.HidePort = true
 	e.HTTPErrorHandler = errorHandler
+	e.IPExtractor = clientip.Extractor
 
 	// Global middleware
 	// TODO: HIGH: Implement echo.Secure - https://echo.labstack.com/docs/middleware/secure
 	// TODO: HIGH: Implement CSRF protection - https://echo.labstack.com/docs/middleware/csrf
-	// TODO: MEDIUM: Implement Rate Limiter - https://echo.labstack.com/docs/middleware/rate-limiter
 	// TODO: LOW: Implement Timeout - https://echo.labstack.com/docs/middleware/timeout
 	// TODO: LOW: Implement GZip - https://echo.labstack.com/docs/middleware/gzip
 	e.Use(middleware.Recover())
@@ -193,8 +207,10 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 
 	// Middleware for standard pages
 	e.Use(mw.Domain(factory))
+	e.Use(mw.UserSession(factory))
 	e.Use(steranko.Middleware(factory))
 	e.Use(middleware.CORS())
+	e.Use(mw.RateLimit(factory))
 
 	// TODO: MEDIUM: Add other Well-Known API calls?
 	// https://en.wikipedia.org/wiki/List_of_/.well-known/_services_offered_by_webservers
@@ -206,6 +222,7 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.GET("/.well-known/nodeinfo", handler.GetNodeInfo(factory))
 	e.GET("/nodeinfo/2.0", handler.GetNodeInfo20(factory))
 	e.GET("/nodeinfo/2.1", handler.GetNodeInfo21(factory))
+	e.GET("/.well-known/oauth-authorization-server", handler.GetOAuthMetadata(factory))
 	e.GET("/.well-known/oembed", handler.GetOEmbed(factory))
 	e.GET("/.well-known/webfinger", handler.GetWebfinger(factory))
 
@@ -217,8 +234,14 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.GET("/.widgets/:widgetId/:bundleId", handler.GetWidgetBundle(factory))
 	e.GET("/.widgets/:widgetId//resources/:filename", handler.GetWidgetResource(factory))
 	e.GET("/.giphy", handler.GetGiphyWidget(factory))
+	e.GET("/.micropub", handler.GetMicropub(factory))
+	e.POST("/.micropub", handler.PostMicropub(factory))
+	e.POST("/.micropub/media", handler.PostMicropubMedia(factory))
+	e.GET("/.micropub/media/:attachment", handler.GetMicropubMedia(factory))
 	e.POST("/.ostatus/discover", handler.PostOStatusDiscover(factory))
 	e.GET("/.ostatus/tunnel", handler.GetFollowingTunnel)
+	e.GET("/.rsscloud/:userId/:followingId", handler.GetRSSCloudClient(factory))
+	e.POST("/.rsscloud/:userId/:followingId", handler.PostRSSCloudClient(factory))
 	e.POST("/.webmention", handler.PostWebMention(factory))
 	e.GET("/.websub/:userId/:followingId", handler.GetWebSubClient(factory))
 	e.POST("/.websub/:userId/:followingId", handler.PostWebSubClient(factory))
@@ -226,9 +249,16 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	// Authentication Pages
 	e.GET("/signin", handler.GetSignIn(factory))
 	e.POST("/signin", handler.PostSignIn(factory))
+	e.GET("/signin/two-factor", handler.GetSignInTwoFactor(factory))
+	e.POST("/signin/two-factor", handler.PostSignInTwoFactor(factory))
+	e.GET("/signin/two-factor/passkey", handler.GetSignInTwoFactorPasskey(factory))
+	e.POST("/signin/two-factor/passkey", handler.PostSignInTwoFactorPasskey(factory))
+	e.GET("/signin/passkey", handler.GetSignInPasskey(factory))
+	e.POST("/signin/passkey", handler.PostSignInPasskey(factory))
 	e.POST("/signout", handler.PostSignOut(factory))
 	e.GET("/register", handler.GetRegister(factory))
 	e.POST("/register", handler.PostRegister(factory))
+	e.GET("/register/confirm", handler.GetConfirmEmail(factory))
 	e.GET("/signin/reset", handler.GetResetPassword(factory))
 	e.POST("/signin/reset", handler.PostResetPassword(factory))
 	e.GET("/signin/reset-code", handler.GetResetCode(factory))
@@ -246,6 +276,11 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.GET("/:stream/sse", handler.ServerSentEvent(factory))                   // TODO: LOW: Can SSE be moved into a custom build step?
 	e.GET("/:stream/qrcode", handler.GetQRCode(factory))                      // TODO: LOW: Can QR Codes be moved into a custom build step?
 
+	// Domain (Application) Actor
+	e.GET("/@application", ap_domain.GetJSONLD(factory))
+	e.POST("/@application/inbox", ap_domain.PostInbox(factory))
+	e.GET("/@application/outbox", ap_domain.GetOutboxCollection(factory))
+
 	// Profile Pages
 	// NOTE: these are rewritten from /@:userId by the rewrite middleware
 	e.GET("/@", handler.TBD)
@@ -260,28 +295,33 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.POST("/@me/inbox", handler.PostInbox(factory))
 	e.GET("/@me/inbox/:action", handler.GetInbox(factory))
 	e.POST("/@me/inbox/:action", handler.PostInbox(factory))
+	e.GET("/@me/export", handler.GetDataExport(factory))
+	e.GET("/@me/passkeys/register", handler.GetPasskeyRegistration(factory))
+	e.POST("/@me/passkeys/register", handler.PostPasskeyRegistration(factory))
 
 	// ActivityPub Routes for Users
+	secureUser := ap.UserSecureMode(factory)
 	e.GET("/@:userId/pub", handler.GetOutbox(factory))
 	e.POST("/@:userId/pub/inbox", ap_user.PostInbox(factory))
-	e.GET("/@:userId/pub/outbox", ap_user.GetOutboxCollection(factory))
-	e.GET("/@:userId/pub/followers", ap_user.GetFollowersCollection(factory))
-	e.GET("/@:userId/pub/following", ap_user.GetFollowingCollection(factory))
-	e.GET("/@:userId/pub/following/:followingId", ap_user.GetFollowingRecord(factory))
-	e.GET("/@:userId/pub/shared", ap_user.GetResponseCollection(factory, vocab.ActivityTypeAnnounce))
-	e.GET("/@:userId/pub/shared/:response", ap_user.GetResponse(factory, vocab.ActivityTypeAnnounce))
-	e.GET("/@:userId/pub/liked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeLike))
-	e.GET("/@:userId/pub/liked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeLike))
-	e.GET("/@:userId/pub/disliked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeDislike))
-	e.GET("/@:userId/pub/disliked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeDislike))
-	e.GET("/@:userId/pub/blocked", ap_user.GetBlockedCollection(factory))
-	e.GET("/@:userId/pub/blocked/:ruleId", ap_user.GetBlock(factory))
+	e.GET("/@:userId/pub/outbox", ap_user.GetOutboxCollection(factory), secureUser)
+	e.GET("/@:userId/pub/followers", ap_user.GetFollowersCollection(factory), secureUser)
+	e.GET("/@:userId/pub/following", ap_user.GetFollowingCollection(factory), secureUser)
+	e.GET("/@:userId/pub/following/:followingId", ap_user.GetFollowingRecord(factory), secureUser)
+	e.GET("/@:userId/pub/shared", ap_user.GetResponseCollection(factory, vocab.ActivityTypeAnnounce), secureUser)
+	e.GET("/@:userId/pub/shared/:response", ap_user.GetResponse(factory, vocab.ActivityTypeAnnounce), secureUser)
+	e.GET("/@:userId/pub/liked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeLike), secureUser)
+	e.GET("/@:userId/pub/liked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeLike), secureUser)
+	e.GET("/@:userId/pub/disliked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeDislike), secureUser)
+	e.GET("/@:userId/pub/disliked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeDislike), secureUser)
+	e.GET("/@:userId/pub/blocked", ap_user.GetBlockedCollection(factory), secureUser)
+	e.GET("/@:userId/pub/blocked/:ruleId", ap_user.GetBlock(factory), secureUser)
 
 	// ActivityPub Routes for Streams
-	e.GET("/:stream/pub", ap_stream.GetJSONLD(factory))
+	secureStream := ap.StreamSecureMode(factory)
+	e.GET("/:stream/pub", ap_stream.GetJSONLD(factory), secureStream)
 	e.POST("/:stream/pub/inbox", ap_stream.PostInbox(factory))
-	e.GET("/:stream/pub/outbox", ap_stream.GetOutboxCollection(factory))
-	e.GET("/:stream/pub/followers", ap_stream.GetFollowersCollection(factory))
+	e.GET("/:stream/pub/outbox", ap_stream.GetOutboxCollection(factory), secureStream)
+	e.GET("/:stream/pub/followers", ap_stream.GetFollowersCollection(factory), secureStream)
 
 	// Domain Admin Pages
 	e.GET("/admin", handler.GetAdmin(factory), mw.Owner)
@@ -304,8 +344,10 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 
 	// OAuth Server
 	e.GET("/oauth/authorize", handler.GetOAuthAuthorization(factory), mw.Authenticated)
-	e.POST("/oauth/authorize", handler.PostOAuthAuthorization(factory), mw.Authenticated)
+	e.POST("/oauth/authorize", handler.PostOAuthAuthorization(factory)) // Authentication is checked in the handler, because IndieAuth clients also redeem codes here
+	e.GET("/oauth/token", handler.GetOAuthToken(factory))
 	e.POST("/oauth/token", handler.PostOAuthToken(factory))
+	e.POST("/oauth/introspect", handler.PostOAuthIntrospect(factory))
 	e.POST("/oauth/revoke", handler.PostOAuthRevoke(factory))
 
 	// Mastodon API
@@ -16,6 +16,8 @@ import (
 	"github.com/EmissarySocial/emissary/tools/ascontextmaker"
 	"github.com/EmissarySocial/emissary/tools/ashash"
 	"github.com/EmissarySocial/emissary/tools/asnormalizer"
+	"github.com/EmissarySocial/emissary/tools/assigner"
+	"github.com/EmissarySocial/emissary/tools/random"
 	mongodb "github.com/benpate/data-mongo"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/queue"
@@ -329,6 +331,84 @@ func (factory *Factory) putDomain(configuration config.Domain) error {
 	return nil
 }
 
+// RotateKeyEncryptingKeys generates a new key encrypting key for every domain, and re-encrypts all of
+// the private keys, JWT keys, and syndication secrets in each domain's database.  The new key encrypting
+// key is saved into the server configuration FIRST, next to the previous one, so that every record can
+// be decrypted with one key or the other while it is being re-encrypted.  The previous key is removed
+// only after every record has been re-encrypted.  If a rotation is interrupted, then running it again
+// finishes the interrupted rotation instead of generating another new key.
+func (factory *Factory) RotateKeyEncryptingKeys() error {
+
+	const location = "server.Factory.RotateKeyEncryptingKeys"
+
+	for _, domainConfig := range factory.ListDomains() {
+
+		log.Info().Str("domain", domainConfig.Hostname).Msg("Rotating key encrypting key")
+
+		// Save the new key encrypting key into the configuration, next to the previous one
+		if domainConfig.PreviousKeyEncryptingKey == "" {
+
+			keyEncryptingKey, err := random.GenerateString(32)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error generating key encrypting key", domainConfig.Hostname)
+			}
+
+			domainConfig.PreviousKeyEncryptingKey = domainConfig.KeyEncryptingKey
+			domainConfig.KeyEncryptingKey = keyEncryptingKey
+
+			if err := factory.putDomain(domainConfig); err != nil {
+				return derp.Wrap(err, location, "Error saving new key encrypting key", domainConfig.Hostname)
+			}
+
+		} else {
+			log.Info().Str("domain", domainConfig.Hostname).Msg("Resuming interrupted key rotation")
+		}
+
+		domainFactory, err := factory.ByDomainName(domainConfig.Hostname)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error getting domain factory", domainConfig.Hostname)
+		}
+
+		// Re-encrypt all keys in the domain database
+		if err := rewrapDomain(domainFactory); err != nil {
+			return derp.Wrap(err, location, "Error re-encrypting keys.  Run this command again to finish the rotation.", domainConfig.Hostname)
+		}
+
+		// Remove the previous key encrypting key from the configuration
+		domainConfig.PreviousKeyEncryptingKey = ""
+
+		if err := factory.putDomain(domainConfig); err != nil {
+			return derp.Wrap(err, location, "Error removing previous key encrypting key", domainConfig.Hostname)
+		}
+	}
+
+	log.Info().Msg("Key encrypting keys rotated successfully")
+	return nil
+}
+
+// rewrapDomain re-encrypts every private key, JWT key, and syndication secret
+// in a domain's database with the domain's current key encrypting key.
+func rewrapDomain(domainFactory *domain.Factory) error {
+
+	const location = "server.rewrapDomain"
+
+	if err := domainFactory.EncryptionKey().Rewrap(); err != nil {
+		return derp.Wrap(err, location, "Error re-encrypting private keys")
+	}
+
+	if err := domainFactory.JWT().Rewrap(); err != nil {
+		return derp.Wrap(err, location, "Error re-encrypting JWT keys")
+	}
+
+	if err := domainFactory.SyndicationTarget().Rewrap(); err != nil {
+		return derp.Wrap(err, location, "Error re-encrypting syndication secrets")
+	}
+
+	return nil
+}
+
 // DomainByID finds a domain in the configuration by its ID
 func (factory *Factory) DomainByID(domainID string) (config.Domain, error) {
 
@@ -580,7 +660,8 @@ func (factory *Factory) RefreshActivityService(connection mapof.String) {
 		sherlock.WithUserAgent("Emissary Social: https://emissary.social"),
 	)
 
-	normalizerClient := asnormalizer.New(sherlockClient)       // enforce opinionated data formats
+	signerClient := assigner.New(sherlockClient)               // sign outbound requests (aka "authorized fetch")
+	normalizerClient := asnormalizer.New(signerClient)         // enforce opinionated data formats
 	contextMakerClient := ascontextmaker.New(normalizerClient) // compute document context (if missing)
 	cacheRulesClient := ascacherules.New(contextMakerClient)   // apply custom caching rules to documents
 
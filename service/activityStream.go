@@ -2,18 +2,22 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/queries"
 	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/EmissarySocial/emissary/tools/assigner"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/sigs"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/sherlock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // ActivityStream implements the Hannibal HTTP client interface, and provides a cache for ActivityStream documents.
@@ -21,6 +25,20 @@ type ActivityStream struct {
 	collection  data.Collection
 	innerClient streams.Client
 	cacheClient *ascache.Client
+	keyService  *EncryptionKey // (optional) used to sign outbound requests with the Domain/Application key
+	signer      *sigs.Signer   // cached signer for the Domain/Application key (replaced when the key is rotated)
+	signerCheck signerCheck    // when the cached signer was last checked against the database
+	mutex       *sync.Mutex
+}
+
+// activityStreamSignerMaxAge is how long a cached signer is used before it is checked against
+// the database again, so that keys rotated by other server processes are noticed, too.
+const activityStreamSignerMaxAge = 10 * time.Minute
+
+// signerCheck records the EncryptionKey version and time when a cached signer was last checked
+type signerCheck struct {
+	version int64
+	date    time.Time
 }
 
 /******************************************
@@ -29,7 +47,9 @@ type ActivityStream struct {
 
 // NewActivityStream creates a new ActivityStream service
 func NewActivityStream() ActivityStream {
-	return ActivityStream{}
+	return ActivityStream{
+		mutex: &sync.Mutex{},
+	}
 }
 
 // Refresh updates the ActivityStream service with new dependencies
@@ -39,6 +59,21 @@ func (service *ActivityStream) Refresh(innerClient streams.Client, cacheClient *
 	service.collection = collection
 }
 
+// RefreshFromServer copies the (server-wide) clients from another ActivityStream service,
+// and signs all outbound requests with the Domain/Application key from the provided keyService.
+func (service *ActivityStream) RefreshFromServer(server *ActivityStream, keyService *EncryptionKey) {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.innerClient = server.innerClient
+	service.cacheClient = server.cacheClient
+	service.collection = server.collection
+	service.keyService = keyService
+	service.signer = nil
+	service.signerCheck = signerCheck{}
+}
+
 /******************************************
  * Hannibal HTTP Client Interface
  ******************************************/
@@ -57,6 +92,11 @@ func (service *ActivityStream) Load(url string, options ...any) (streams.Documen
 		return streams.Document{}, derp.NewInternalError(location, "Client not initialized")
 	}
 
+	// Sign outbound requests with the Domain key, unless the caller has provided another signer.
+	if signer, ok := service.getSigner(); ok {
+		options = append([]any{assigner.WithSigner(signer)}, options...)
+	}
+
 	// Forward request to inner client
 	result, err := service.innerClient.Load(url, options...)
 
@@ -68,6 +108,58 @@ func (service *ActivityStream) Load(url string, options ...any) (streams.Documen
 	return result, nil
 }
 
+// getSigner returns the Signer for the Domain/Application Actor's current key, if available.
+// Signers are cached until the keyService saves or removes a key (or the cache is too old)
+// so that the database is only checked when the Domain key may have been rotated.
+func (service *ActivityStream) getSigner() (sigs.Signer, bool) {
+
+	const location = "service.ActivityStream.getSigner"
+
+	// Server-level services do not sign requests
+	if service.keyService == nil {
+		return sigs.Signer{}, false
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	// Use the cached signer if no keys have changed since it was last checked
+	check := signerCheck{
+		version: service.keyService.Version(),
+		date:    time.Now(),
+	}
+
+	if (service.signer != nil) && (service.signerCheck.version == check.version) && (time.Since(service.signerCheck.date) < activityStreamSignerMaxAge) {
+		return *service.signer, true
+	}
+
+	// Find the Domain's current key
+	encryptionKey := model.NewEncryptionKey()
+
+	if err := service.keyService.LoadByParentID(model.EncryptionKeyTypeDomain, primitive.NilObjectID, &encryptionKey); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading Domain key"))
+		return sigs.Signer{}, false
+	}
+
+	// Keep the cached signer if it was made from the current key
+	if (service.signer != nil) && (service.signer.PublicKeyID == service.keyService.KeyID(&encryptionKey)) {
+		service.signerCheck = check
+		return *service.signer, true
+	}
+
+	// Otherwise, make a new signer from the current key
+	signer, err := service.keyService.NewSigner(&encryptionKey)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error creating Domain signer"))
+		return sigs.Signer{}, false
+	}
+
+	service.signer = &signer
+	service.signerCheck = check
+	return signer, true
+}
+
 // Put adds a single document to the ActivityStream cache
 func (service *ActivityStream) Put(document streams.Document) {
 	service.cacheClient.Put(document)
@@ -239,10 +331,51 @@ func (service *ActivityStream) QueryLikesBeforeDate(relationHref string, maxDate
 	return service.queryByRelation(vocab.ActivityTypeLike, relationHref, "before", maxDate, done)
 }
 
+// HasReply returns TRUE if the cache includes a specific reply to the specified document
+func (service *ActivityStream) HasReply(inReplyTo string, replyID string) bool {
+
+	criteria := exp.Equal("metadata.relationType", ascache.RelationTypeReply).
+		AndEqual("metadata.relationHref", inReplyTo).
+		AndEqual("object.id", replyID)
+
+	return service.exists(criteria)
+}
+
+// HasResponse returns TRUE if the cache includes an Announce/Like/Dislike of the specified document by the specified actor
+func (service *ActivityStream) HasResponse(relationType string, relationHref string, actorID string) bool {
+
+	criteria := exp.Equal("metadata.relationType", relationType).
+		AndEqual("metadata.relationHref", relationHref).
+		And(exp.Equal("object.actor", actorID).OrEqual("object.actor.id", actorID))
+
+	return service.exists(criteria)
+}
+
 /******************************************
  * Internal Methods
  ******************************************/
 
+// exists returns TRUE if the cache includes any documents that match the criteria
+func (service *ActivityStream) exists(criteria exp.Expression) bool {
+
+	const location = "service.ActivityStream.exists"
+
+	// NPE Check
+	if service.collection == nil {
+		derp.Report(derp.NewInternalError(location, "Document Collection not initialized"))
+		return false
+	}
+
+	count, err := service.collection.Count(criteria)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error counting documents", criteria))
+		return false
+	}
+
+	return count > 0
+}
+
 // iterator reads from the database and returns a data.Iterator with the result values.
 func (service *ActivityStream) documentIterator(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
 
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestActivityStream_SignerRotation(t *testing.T) {
+
+	collection := newMemoryCollection()
+	keyService := NewEncryptionKey()
+	keyService.Refresh(&collection, []byte("0123456789ABCDEF0123456789ABCDEF"), nil, nil, nil, "http://localhost")
+
+	service := NewActivityStream()
+	service.RefreshFromServer(&ActivityStream{}, &keyService)
+
+	// The signer is cached while the Domain key is unchanged
+	signer1, ok := service.getSigner()
+	require.True(t, ok)
+
+	// ...without loading the key from the database again
+	documents := collection.documents
+	collection.documents = map[string][]byte{}
+
+	signer2, ok := service.getSigner()
+	require.True(t, ok)
+	require.Equal(t, signer1.PublicKeyID, signer2.PublicKeyID)
+
+	collection.documents = documents
+
+	// Rotating the Domain key replaces the cached signer
+	require.Nil(t, keyService.Rotate(model.EncryptionKeyTypeDomain, primitive.NilObjectID))
+
+	signer3, ok := service.getSigner()
+	require.True(t, ok)
+	require.NotEqual(t, signer1.PublicKeyID, signer3.PublicKeyID)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
@@ -16,11 +19,18 @@ type Attachment struct {
 	collection  data.Collection
 	mediaServer mediaserver.MediaServer
 	host        string
+	closed      chan bool
 }
 
+// micropubUploadLifetime is how long files uploaded to the Micropub media endpoint
+// are kept before they are removed (if they have not been claimed by a Stream)
+const micropubUploadLifetime = 24 * time.Hour
+
 // NewAttachment returns a fully populated Attachment service
 func NewAttachment() Attachment {
-	return Attachment{}
+	return Attachment{
+		closed: make(chan bool),
+	}
 }
 
 /******************************************
@@ -36,7 +46,34 @@ func (service *Attachment) Refresh(collection data.Collection, mediaServer media
 
 // Close stops any background processes controlled by this service
 func (service *Attachment) Close() {
+	close(service.closed)
+}
+
+// Start begins the background scheduler that removes unclaimed Micropub uploads
+func (service *Attachment) Start() {
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
 
+		// Poll randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
+
+		select {
+
+		// If we're done, we're done.
+		case <-service.closed:
+			return
+
+		default:
+			if err := service.DeleteUnclaimed(); err != nil {
+				derp.Report(derp.Wrap(err, "service.Attachment.Start", "Error removing unclaimed uploads"))
+			}
+		}
+	}
 }
 
 /******************************************
@@ -193,3 +230,174 @@ func (service *Attachment) DeleteAll(objectType string, objectID primitive.Objec
 
 	return nil
 }
+
+// LoadUnclaimed loads a file that was uploaded to the Micropub media endpoint, but has not yet been claimed by a Stream
+func (service *Attachment) LoadUnclaimed(attachmentID primitive.ObjectID, result *model.Attachment) error {
+
+	criteria := exp.Equal("_id", attachmentID).
+		AndEqual("objectType", model.AttachmentTypeMicropub)
+
+	if err := service.Load(criteria, result); err != nil {
+		return derp.Wrap(err, "service.Attachment.LoadUnclaimed", "Error loading attachment", attachmentID)
+	}
+
+	return nil
+}
+
+// DeleteUnclaimed removes all Micropub uploads that were not claimed by a Stream before they expired
+func (service *Attachment) DeleteUnclaimed() error {
+
+	const location = "service.Attachment.DeleteUnclaimed"
+
+	expireDate := time.Now().Add(-micropubUploadLifetime).UnixMilli()
+
+	attachments, err := service.Query(
+		exp.Equal("objectType", model.AttachmentTypeMicropub).
+			AndLessThan("createDate", expireDate))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing unclaimed attachments")
+	}
+
+	for _, attachment := range attachments {
+		if err := service.Delete(&attachment, "Unclaimed upload expired"); err != nil {
+			return derp.Wrap(err, location, "Error deleting unclaimed attachment", attachment)
+		}
+	}
+
+	return nil
+}
+
+/******************************************
+ * Archive Methods
+ ******************************************/
+
+// Archive removes an Attachment from its object, but keeps its uploaded files
+// so that it can be restored along with a previous revision of the object.
+func (service *Attachment) Archive(attachment *model.Attachment, note string) error {
+
+	if err := service.collection.Delete(attachment, note); err != nil {
+		return derp.Wrap(err, "service.Attachment.Archive", "Error archiving Attachment", attachment, note)
+	}
+
+	return nil
+}
+
+// ArchiveByID archives a single Attachment
+func (service *Attachment) ArchiveByID(objectType string, objectID primitive.ObjectID, attachmentID primitive.ObjectID, note string) error {
+
+	const location = "service.Attachment.ArchiveByID"
+
+	attachment := model.NewAttachment(objectType, objectID)
+	if err := service.LoadByID(objectType, objectID, attachmentID, &attachment); err != nil {
+		return derp.Wrap(err, location, "Error loading attachment")
+	}
+
+	if err := service.Archive(&attachment, note); err != nil {
+		return derp.Wrap(err, location, "Error archiving attachment")
+	}
+
+	return nil
+}
+
+// ArchiveAll archives all Attachments for the provided object
+func (service *Attachment) ArchiveAll(objectType string, objectID primitive.ObjectID, note string) error {
+
+	const location = "service.Attachment.ArchiveAll"
+
+	attachments, err := service.QueryByObjectID(objectType, objectID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing attachments", objectID)
+	}
+
+	for _, attachment := range attachments {
+		if err := service.Archive(&attachment, note); err != nil {
+			return derp.Wrap(err, location, "Error archiving attachment", attachment)
+		}
+	}
+
+	return nil
+}
+
+// Unarchive returns a previously archived Attachment to its object
+func (service *Attachment) Unarchive(attachment *model.Attachment, note string) error {
+
+	attachment.DeleteDate = 0
+
+	if err := service.Save(attachment, note); err != nil {
+		return derp.Wrap(err, "service.Attachment.Unarchive", "Error restoring Attachment", attachment, note)
+	}
+
+	return nil
+}
+
+// QueryArchived returns all archived Attachments for the provided object
+func (service *Attachment) QueryArchived(objectType string, objectID primitive.ObjectID) ([]model.Attachment, error) {
+
+	result := make([]model.Attachment, 0)
+
+	criteria := exp.Equal("objectType", objectType).
+		AndEqual("objectId", objectID).
+		AndGreaterThan("deleteDate", 0)
+
+	if err := service.collection.Query(&result, criteria); err != nil {
+		return nil, derp.Wrap(err, "service.Attachment.QueryArchived", "Error querying archived Attachments", objectType, objectID)
+	}
+
+	return result, nil
+}
+
+// PurgeAll permanently removes all Attachments (and their uploaded files) for the provided
+// object, including any that have already been deleted or archived (hard delete)
+func (service *Attachment) PurgeAll(objectType string, objectID primitive.ObjectID) error {
+
+	const location = "service.Attachment.PurgeAll"
+
+	criteria := exp.Equal("objectType", objectType).AndEqual("objectId", objectID)
+	attachments := make([]model.Attachment, 0)
+
+	if err := service.collection.Query(&attachments, criteria); err != nil {
+		return derp.Wrap(err, location, "Error listing attachments", objectType, objectID)
+	}
+
+	for _, attachment := range attachments {
+
+		// Files may have been removed already if the Attachment was deleted
+		if err := service.mediaServer.Delete(attachment.AttachmentID.Hex()); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error deleting attached files", attachment))
+		}
+	}
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Error purging attachments", objectType, objectID)
+	}
+
+	return nil
+}
+
+// PurgeArchived permanently removes all archived Attachments (and their uploaded files) for the provided object
+func (service *Attachment) PurgeArchived(objectType string, objectID primitive.ObjectID) error {
+
+	const location = "service.Attachment.PurgeArchived"
+
+	attachments, err := service.QueryArchived(objectType, objectID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing archived attachments", objectType, objectID)
+	}
+
+	for _, attachment := range attachments {
+
+		// Files may have been removed already if the Attachment was deleted (not archived)
+		if err := service.mediaServer.Delete(attachment.AttachmentID.Hex()); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error deleting attached files", attachment))
+		}
+
+		if err := service.collection.HardDelete(exp.Equal("_id", attachment.AttachmentID)); err != nil {
+			return derp.Wrap(err, location, "Error purging attachment", attachment)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/benpate/mediaserver"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAttachment_PurgeAll(t *testing.T) {
+
+	collection := newMemoryCollection()
+	service := NewAttachment()
+	service.Refresh(&collection, mediaserver.New(afero.NewMemMapFs(), afero.NewMemMapFs()), "https://local.test")
+
+	userID := primitive.NewObjectID()
+
+	// Current, archived, and deleted uploads are all purged
+	current := model.NewAttachment(model.AttachmentTypeMicropub, userID)
+	require.Nil(t, service.Save(&current, "Test"))
+
+	archived := model.NewAttachment(model.AttachmentTypeMicropub, userID)
+	require.Nil(t, service.Save(&archived, "Test"))
+	require.Nil(t, service.Archive(&archived, "Test"))
+
+	deleted := model.NewAttachment(model.AttachmentTypeMicropub, userID)
+	require.Nil(t, service.Save(&deleted, "Test"))
+	require.Nil(t, collection.Delete(&deleted, "Test"))
+
+	// Attachments for other objects are not touched
+	other := model.NewAttachment(model.AttachmentTypeMicropub, primitive.NewObjectID())
+	require.Nil(t, service.Save(&other, "Test"))
+
+	require.Nil(t, service.PurgeAll(model.AttachmentTypeMicropub, userID))
+
+	count, err := collection.Count(exp.All())
+	require.Nil(t, err)
+	require.Equal(t, int64(1), count)
+	require.Nil(t, collection.Load(exp.Equal("_id", other.AttachmentID), &model.Attachment{}))
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dataExportDuration is how long a finished archive can be downloaded before it is removed
+const dataExportDuration = 7 * 24 * time.Hour
+
+// DataExport builds downloadable archives of a User's personal data
+type DataExport struct {
+	filesystem        afero.Fs
+	originals         afero.Fs
+	activityService   *ActivityStream
+	attachmentService *Attachment
+	emailService      *DomainEmail
+	folderService     *Folder
+	followerService   *Follower
+	followingService  *Following
+	outboxService     *Outbox
+	responseService   *Response
+	ruleService       *Rule
+	streamService     *Stream
+	userService       *User
+	queue             queue.Queue
+	closed            chan bool
+}
+
+// NewDataExport returns a fully populated DataExport service
+func NewDataExport() DataExport {
+	return DataExport{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *DataExport) Refresh(filesystem afero.Fs, originals afero.Fs, activityService *ActivityStream, attachmentService *Attachment, emailService *DomainEmail, folderService *Folder, followerService *Follower, followingService *Following, outboxService *Outbox, responseService *Response, ruleService *Rule, streamService *Stream, userService *User, queue queue.Queue) {
+	service.filesystem = filesystem
+	service.originals = originals
+	service.activityService = activityService
+	service.attachmentService = attachmentService
+	service.emailService = emailService
+	service.folderService = folderService
+	service.followerService = followerService
+	service.followingService = followingService
+	service.outboxService = outboxService
+	service.responseService = responseService
+	service.ruleService = ruleService
+	service.streamService = streamService
+	service.userService = userService
+	service.queue = queue
+}
+
+// Close stops any background processes controlled by this service
+func (service *DataExport) Close() {
+	close(service.closed)
+}
+
+// Start begins the background scheduler that removes expired archives
+func (service *DataExport) Start() {
+
+	// Wait until the service has booted up correctly.
+	for service.filesystem == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Poll randomly between 2 and 3 hours
+		time.Sleep(time.Duration(rand.Intn(60)+120) * time.Minute)
+
+		select {
+
+		// If we're done, we're done.
+		case <-service.closed:
+			return
+
+		default:
+			if err := service.DeleteExpired(); err != nil {
+				derp.Report(derp.Wrap(err, "service.DataExport.Start", "Error removing expired archives"))
+			}
+		}
+	}
+}
+
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// Request starts building a new archive for the User in the background.  The User
+// is sent an email with a download link once the archive is ready.
+func (service *DataExport) Request(user *model.User) error {
+
+	const location = "service.DataExport.Request"
+
+	// RULE: Only build one archive at a time.  Archives that failed, or that
+	// have been pending for too long, can be requested again.
+	if user.DataExport.IsPending() {
+		return nil
+	}
+
+	// Remove the previous archive (if any)
+	if err := service.removeFile(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error removing previous archive", user.UserID)
+	}
+
+	user.DataExport = model.NewDataExport()
+
+	if err := service.userService.SetDataExport(user.UserID, user.DataExport); err != nil {
+		return derp.Wrap(err, location, "Error updating data export", user.UserID)
+	}
+
+	service.queue.Push(NewTaskExportUser(service, user.UserID))
+	return nil
+}
+
+// Build writes the archive for a User, then emails them a link to download it
+func (service *DataExport) Build(userID primitive.ObjectID) error {
+
+	const location = "service.DataExport.Build"
+
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(userID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", userID)
+	}
+
+	// RULE: Do not rebuild archives that were cancelled or have already finished
+	if !user.DataExport.IsPending() {
+		return nil
+	}
+
+	// Write the archive into a temporary file, so that a failed build never looks complete
+	filename := dataExportFilename(userID)
+
+	if err := service.writeArchive(&user, filename+".tmp"); err != nil {
+		return service.fail(&user, derp.Wrap(err, location, "Error writing archive", userID))
+	}
+
+	if err := service.filesystem.Rename(filename+".tmp", filename); err != nil {
+		return service.fail(&user, derp.Wrap(err, location, "Error renaming archive", userID))
+	}
+
+	user.DataExport.Ready(dataExportDuration)
+
+	if err := service.userService.SetDataExport(userID, user.DataExport); err != nil {
+		return derp.Wrap(err, location, "Error updating data export", userID)
+	}
+
+	if err := service.emailService.SendDataExport(&user); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error sending data export email", userID))
+	}
+
+	log.Debug().Str("userId", userID.Hex()).Msg("Data export ready")
+	return nil
+}
+
+// Open returns the User's archive, if the code matches an archive that is ready to download
+func (service *DataExport) Open(user *model.User, code string) (afero.File, error) {
+
+	const location = "service.DataExport.Open"
+
+	if !user.DataExport.IsValid(code) {
+		return nil, derp.NewNotFoundError(location, "Archive is not available", user.UserID)
+	}
+
+	file, err := service.filesystem.Open(dataExportFilename(user.UserID))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error opening archive", user.UserID)
+	}
+
+	return file, nil
+}
+
+// Delete removes the User's archive (if any)
+func (service *DataExport) Delete(user *model.User) error {
+
+	const location = "service.DataExport.Delete"
+
+	if err := service.removeFile(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error removing archive", user.UserID)
+	}
+
+	if user.DataExport.IsEmpty() {
+		return nil
+	}
+
+	user.DataExport = model.DataExport{}
+
+	if err := service.userService.SetDataExport(user.UserID, user.DataExport); err != nil {
+		return derp.Wrap(err, location, "Error updating data export", user.UserID)
+	}
+
+	return nil
+}
+
+// fail marks the User's archive as failed (so that it can be requested again) and
+// removes any partial file.  It returns the original error to the caller.
+func (service *DataExport) fail(user *model.User, err error) error {
+
+	const location = "service.DataExport.fail"
+
+	if removeErr := service.filesystem.Remove(dataExportFilename(user.UserID) + ".tmp"); removeErr != nil && !os.IsNotExist(removeErr) {
+		derp.Report(derp.Wrap(removeErr, location, "Error removing partial archive", user.UserID))
+	}
+
+	user.DataExport.Fail()
+
+	if updateErr := service.userService.SetDataExport(user.UserID, user.DataExport); updateErr != nil {
+		derp.Report(derp.Wrap(updateErr, location, "Error updating data export", user.UserID))
+	}
+
+	return err
+}
+
+// DeleteExpired removes all archives that can no longer be downloaded
+func (service *DataExport) DeleteExpired() error {
+
+	const location = "service.DataExport.DeleteExpired"
+
+	criteria := exp.GreaterThan("dataExport.expireDate", 0).AndLessThan("dataExport.expireDate", time.Now().Unix())
+	users, err := service.userService.Query(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing expired archives")
+	}
+
+	for index := range users {
+		if err := service.Delete(&users[index]); err != nil {
+			return derp.Wrap(err, location, "Error removing expired archive", users[index].UserID)
+		}
+	}
+
+	return nil
+}
+
+// removeFile removes the User's archive file, if it exists
+func (service *DataExport) removeFile(userID primitive.ObjectID) error {
+
+	if err := service.filesystem.Remove(dataExportFilename(userID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// dataExportFilename returns the name of the file where a User's archive is stored
+func dataExportFilename(userID primitive.ObjectID) string {
+	return userID.Hex() + ".zip"
+}
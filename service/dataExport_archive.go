@@ -0,0 +1,453 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+)
+
+/******************************************
+ * Archive Layout
+ *
+ * Archives use the same layout as Mastodon's "Request your archive" feature, so that
+ * they can be read by the same tools.  The CSV files match Mastodon's "Data export" page.
+ ******************************************/
+
+// writeArchive writes a zip file containing all of the User's personal data
+func (service *DataExport) writeArchive(user *model.User, filename string) error {
+
+	const location = "service.DataExport.writeArchive"
+
+	file, err := service.filesystem.Create(filename)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error creating archive", filename)
+	}
+
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	steps := []func(*zip.Writer, *model.User) error{
+		service.writeActor,
+		service.writeOutbox,
+		service.writeLikes,
+		service.writeBookmarks,
+		service.writeFollowers,
+		service.writeFollowing,
+		service.writeRules,
+		service.writeLists,
+	}
+
+	for _, step := range steps {
+		if err := step(archive, user); err != nil {
+			return derp.Wrap(err, location, "Error writing archive", user.UserID)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return derp.Wrap(err, location, "Error closing archive", filename)
+	}
+
+	return nil
+}
+
+// writeActor writes the User's ActivityPub profile to actor.json, along with their avatar image
+func (service *DataExport) writeActor(archive *zip.Writer, user *model.User) error {
+
+	const location = "service.DataExport.writeActor"
+
+	actor, err := service.userService.ActivityPubJSONLD(user)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error building Actor")
+	}
+
+	attachments, err := service.attachmentService.QueryByObjectID(model.AttachmentTypeUser, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading avatar")
+	}
+
+	for _, attachment := range attachments {
+
+		if attachment.AttachmentID != user.ImageID {
+			continue
+		}
+
+		filename := "avatar" + attachment.OriginalExtension()
+
+		copied, err := service.copyOriginal(archive, attachment, filename)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error copying avatar")
+		}
+
+		if !copied {
+			continue
+		}
+
+		actor[vocab.PropertyIcon] = mapof.Any{
+			vocab.PropertyType:      vocab.ObjectTypeImage,
+			vocab.PropertyMediaType: attachment.MimeType(),
+			vocab.PropertyURL:       filename,
+		}
+	}
+
+	return writeArchiveJSON(archive, "actor.json", actor)
+}
+
+// writeOutbox writes all of the User's published Streams to outbox.json as "Create"
+// activities, and copies their attachments into the media_attachments folder.
+func (service *DataExport) writeOutbox(archive *zip.Writer, user *model.User) error {
+
+	const location = "service.DataExport.writeOutbox"
+
+	it, err := service.outboxService.ListByParentID(model.FollowerTypeUser, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing outbox")
+	}
+
+	activities := make([]mapof.Any, 0)
+	message := model.NewOutboxMessage()
+
+	for it.Next(&message) {
+
+		stream := model.NewStream()
+
+		// Outbox messages for remote documents (like replies) do not have a local Stream
+		if err := service.streamService.LoadByURL(message.URL, &stream); err != nil {
+			message = model.NewOutboxMessage()
+			continue
+		}
+
+		object := service.streamService.JSONLD(&stream)
+
+		// Replace attachment URLs with their location inside the archive
+		attachments, err := service.attachmentService.QueryByObjectID(model.AttachmentTypeStream, stream.StreamID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading attachments", stream.StreamID)
+		}
+
+		attachmentJSON := make([]mapof.Any, 0, len(attachments))
+
+		for _, attachment := range attachments {
+
+			filename := "media_attachments/files/" + attachment.AttachmentID.Hex() + "/original/" + attachment.AttachmentID.Hex() + attachment.OriginalExtension()
+
+			copied, err := service.copyOriginal(archive, attachment, filename)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error copying attachment", attachment.AttachmentID)
+			}
+
+			if !copied {
+				continue
+			}
+
+			attachmentJSON = append(attachmentJSON, mapof.Any{
+				vocab.PropertyType:      vocab.ObjectTypeDocument,
+				vocab.PropertyMediaType: attachment.MimeType(),
+				vocab.PropertyURL:       "/" + filename,
+			})
+		}
+
+		object[vocab.PropertyAttachment] = attachmentJSON
+
+		activities = append(activities, mapof.Any{
+			vocab.PropertyType:      vocab.ActivityTypeCreate,
+			vocab.PropertyActor:     user.ActivityPubURL(),
+			vocab.PropertyPublished: object[vocab.PropertyPublished],
+			vocab.PropertyTo:        object[vocab.PropertyTo],
+			vocab.PropertyObject:    object,
+		})
+
+		message = model.NewOutboxMessage()
+	}
+
+	return writeArchiveJSON(archive, "outbox.json", archiveCollection("outbox.json", activities))
+}
+
+// writeLikes writes the URLs of everything the User has liked to likes.json
+func (service *DataExport) writeLikes(archive *zip.Writer, user *model.User) error {
+	return service.writeResponses(archive, user, vocab.ActivityTypeLike, "likes.json")
+}
+
+// writeBookmarks writes the URLs of everything the User has bookmarked to bookmarks.json
+func (service *DataExport) writeBookmarks(archive *zip.Writer, user *model.User) error {
+	return service.writeResponses(archive, user, model.ResponseTypeBookmark, "bookmarks.json")
+}
+
+// writeResponses writes the URLs of all of the User's Responses of a single type
+func (service *DataExport) writeResponses(archive *zip.Writer, user *model.User, responseType string, filename string) error {
+
+	criteria := exp.Equal("userId", user.UserID).AndEqual("type", responseType)
+	responses, err := service.responseService.Query(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DataExport.writeResponses", "Error loading responses", responseType)
+	}
+
+	objects := make([]string, 0, len(responses))
+
+	for _, response := range responses {
+		objects = append(objects, response.Object)
+	}
+
+	return writeArchiveJSON(archive, filename, archiveCollection(filename, objects))
+}
+
+// writeFollowers writes the User's ActivityPub followers to followers.csv
+func (service *DataExport) writeFollowers(archive *zip.Writer, user *model.User) error {
+
+	followers, err := service.followerService.QueryByParent(model.FollowerTypeUser, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DataExport.writeFollowers", "Error loading followers")
+	}
+
+	rows := [][]string{{"Account address"}}
+
+	for _, follower := range followers {
+		if follower.Method == model.FollowMethodActivityPub {
+			rows = append(rows, []string{service.accountAddress(follower.Actor.ProfileURL)})
+		}
+	}
+
+	return writeArchiveCSV(archive, "followers.csv", rows)
+}
+
+// writeFollowing writes the ActivityPub accounts that the User follows to following_accounts.csv
+func (service *DataExport) writeFollowing(archive *zip.Writer, user *model.User) error {
+
+	followings, err := service.activityPubFollowings(user)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DataExport.writeFollowing", "Error loading followings")
+	}
+
+	rows := [][]string{{"Account address", "Show boosts", "Notify on new posts", "Languages"}}
+
+	for _, following := range followings {
+		rows = append(rows, []string{service.accountAddress(following.ProfileURL), "true", "false", ""})
+	}
+
+	return writeArchiveCSV(archive, "following_accounts.csv", rows)
+}
+
+// writeRules writes the User's blocks and mutes to blocked_accounts.csv,
+// muted_accounts.csv, and blocked_domains.csv
+func (service *DataExport) writeRules(archive *zip.Writer, user *model.User) error {
+
+	rules, err := service.ruleService.Query(exp.Equal("userId", user.UserID))
+
+	if err != nil {
+		return derp.Wrap(err, "service.DataExport.writeRules", "Error loading rules")
+	}
+
+	blockedAccounts := make([][]string, 0)
+	mutedAccounts := [][]string{{"Account address", "Hide notifications"}}
+	blockedDomains := make([][]string, 0)
+
+	for _, rule := range rules {
+
+		switch {
+
+		case (rule.Type == model.RuleTypeActor) && (rule.Action == model.RuleActionBlock):
+			blockedAccounts = append(blockedAccounts, []string{service.accountAddress(rule.Trigger)})
+
+		case (rule.Type == model.RuleTypeActor) && (rule.Action == model.RuleActionMute):
+			mutedAccounts = append(mutedAccounts, []string{service.accountAddress(rule.Trigger), "true"})
+
+		case (rule.Type == model.RuleTypeDomain) && (rule.Action == model.RuleActionBlock):
+			blockedDomains = append(blockedDomains, []string{rule.Trigger})
+		}
+	}
+
+	if err := writeArchiveCSV(archive, "blocked_accounts.csv", blockedAccounts); err != nil {
+		return err
+	}
+
+	if err := writeArchiveCSV(archive, "muted_accounts.csv", mutedAccounts); err != nil {
+		return err
+	}
+
+	return writeArchiveCSV(archive, "blocked_domains.csv", blockedDomains)
+}
+
+// writeLists writes the User's inbox Folders to lists.csv, which is the closest thing
+// that Mastodon has.  Each row includes a Folder name and an account in that Folder.
+func (service *DataExport) writeLists(archive *zip.Writer, user *model.User) error {
+
+	const location = "service.DataExport.writeLists"
+
+	folders, err := service.folderService.QueryByUserID(user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading folders")
+	}
+
+	followings, err := service.activityPubFollowings(user)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading followings")
+	}
+
+	rows := make([][]string, 0)
+
+	for _, folder := range folders {
+		for _, following := range followings {
+			if following.FolderID == folder.FolderID {
+				rows = append(rows, []string{folder.Label, service.accountAddress(following.ProfileURL)})
+			}
+		}
+	}
+
+	return writeArchiveCSV(archive, "lists.csv", rows)
+}
+
+/******************************************
+ * Helpers
+ ******************************************/
+
+// activityPubFollowings returns all of the User's Followings that use ActivityPub.
+// Other kinds of Followings (like RSS feeds) cannot be imported into Mastodon.
+func (service *DataExport) activityPubFollowings(user *model.User) ([]model.Following, error) {
+
+	result := make([]model.Following, 0)
+	criteria := exp.Equal("userId", user.UserID).AndEqual("method", model.FollowMethodActivityPub)
+
+	if err := service.followingService.ObjectQuery(&result, criteria); err != nil {
+		return nil, derp.Wrap(err, "service.DataExport.activityPubFollowings", "Error loading followings")
+	}
+
+	return result, nil
+}
+
+// accountAddress returns the "username@domain" address that Mastodon uses to identify
+// an account.  If the account cannot be loaded, then its profile URL is returned instead.
+func (service *DataExport) accountAddress(profileURL string) string {
+
+	if !strings.HasPrefix(profileURL, "http") {
+		return strings.TrimPrefix(profileURL, "@")
+	}
+
+	document, err := service.activityService.Load(profileURL)
+
+	if err != nil {
+		return profileURL
+	}
+
+	return strings.TrimPrefix(document.UsernameOrID(), "@")
+}
+
+// copyOriginal copies the original file for an Attachment into the archive.  Originals
+// that cannot be read are skipped (returning FALSE) so that one missing file does not
+// prevent the rest of the archive from being built.  A note is left in their place.
+func (service *DataExport) copyOriginal(archive *zip.Writer, attachment model.Attachment, filename string) (bool, error) {
+
+	const location = "service.DataExport.copyOriginal"
+
+	source, err := service.originals.Open(attachment.AttachmentID.Hex())
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Skipping unreadable attachment", attachment.AttachmentID))
+		return false, writeArchiveMissing(archive, filename)
+	}
+
+	defer source.Close()
+
+	destination, err := archive.Create(filename)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Error creating file in archive", filename)
+	}
+
+	if _, err := io.Copy(destination, source); err != nil {
+		derp.Report(derp.Wrap(err, location, "Skipping unreadable attachment", attachment.AttachmentID))
+		return false, writeArchiveMissing(archive, filename)
+	}
+
+	return true, nil
+}
+
+// writeArchiveMissing writes a note into the archive explaining that a file could not be included
+func writeArchiveMissing(archive *zip.Writer, filename string) error {
+
+	const location = "service.writeArchiveMissing"
+
+	file, err := archive.Create(filename + ".missing.txt")
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error creating file in archive", filename)
+	}
+
+	if _, err := io.WriteString(file, "This file could not be read when the archive was created.\n"); err != nil {
+		return derp.Wrap(err, location, "Error writing file in archive", filename)
+	}
+
+	return nil
+}
+
+// archiveCollection returns an OrderedCollection document for a file inside the archive
+func archiveCollection[T any](filename string, items []T) mapof.Any {
+	return mapof.Any{
+		vocab.AtContext:            vocab.NamespaceActivityStreams,
+		vocab.PropertyID:           filename,
+		vocab.PropertyType:         vocab.CoreTypeOrderedCollection,
+		vocab.PropertyTotalItems:   len(items),
+		vocab.PropertyOrderedItems: sliceof.Object[T](items),
+	}
+}
+
+// writeArchiveJSON writes a JSON document into the archive
+func writeArchiveJSON(archive *zip.Writer, filename string, value any) error {
+
+	const location = "service.writeArchiveJSON"
+
+	writer, err := archive.Create(filename)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error creating file in archive", filename)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		return derp.Wrap(err, location, "Error writing JSON", filename)
+	}
+
+	return nil
+}
+
+// writeArchiveCSV writes a CSV document into the archive
+func writeArchiveCSV(archive *zip.Writer, filename string, rows [][]string) error {
+
+	const location = "service.writeArchiveCSV"
+
+	writer, err := archive.Create(filename)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error creating file in archive", filename)
+	}
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV", filename)
+	}
+
+	return nil
+}
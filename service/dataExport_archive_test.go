@@ -0,0 +1,82 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDataExport_ArchiveFiles(t *testing.T) {
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	rows := [][]string{{"Account address", "Hide notifications"}, {"alice@example.com", "true"}}
+	require.Nil(t, writeArchiveCSV(archive, "muted_accounts.csv", rows))
+	require.Nil(t, writeArchiveJSON(archive, "likes.json", archiveCollection("likes.json", []string{"https://example.com/1"})))
+	require.Nil(t, archive.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(reader.File))
+
+	// CSV files are read back exactly as they were written
+	{
+		file, err := reader.Open("muted_accounts.csv")
+		require.Nil(t, err)
+
+		result, err := csv.NewReader(file).ReadAll()
+		require.Nil(t, err)
+		require.Equal(t, rows, result)
+	}
+
+	// JSON files are OrderedCollections
+	{
+		file, err := reader.Open("likes.json")
+		require.Nil(t, err)
+
+		result := mapof.NewAny()
+		require.Nil(t, json.NewDecoder(file).Decode(&result))
+		require.Equal(t, "OrderedCollection", result["type"])
+		require.Equal(t, "likes.json", result["id"])
+		require.Equal(t, float64(1), result["totalItems"])
+		require.Equal(t, []any{"https://example.com/1"}, result["orderedItems"])
+	}
+}
+
+func TestDataExport_AccountAddress(t *testing.T) {
+
+	service := NewDataExport()
+
+	// Addresses that are not URLs do not need to be looked up
+	require.Equal(t, "alice@example.com", service.accountAddress("@alice@example.com"))
+	require.Equal(t, "alice@example.com", service.accountAddress("alice@example.com"))
+}
+
+func TestDataExport_CopyOriginal_Missing(t *testing.T) {
+
+	service := NewDataExport()
+	service.originals = afero.NewMemMapFs()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	// Attachments without an original file are skipped, and a note is left in their place
+	copied, err := service.copyOriginal(archive, model.NewAttachment(model.AttachmentTypeStream, primitive.NewObjectID()), "media/missing.jpg")
+	require.Nil(t, err)
+	require.False(t, copied)
+	require.Nil(t, archive.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(reader.File))
+	require.Equal(t, "media/missing.jpg.missing.txt", reader.File[0].Name)
+}
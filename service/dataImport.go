@@ -0,0 +1,517 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/sherlock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dataImportMaxRows is the maximum number of rows that can be imported from a single file
+const dataImportMaxRows = 5000
+
+// dataImportBatchSize is the number of rows to process between progress updates
+const dataImportBatchSize = 20
+
+// dataImportNote is the journal note added to every record created by an import
+const dataImportNote = "Imported from Mastodon"
+
+// DataImport manages all interactions with the DataImport collection, and imports
+// followings, blocks, mutes, and bookmarks from the CSV files that Mastodon exports.
+type DataImport struct {
+	collection       data.Collection
+	activityService  *ActivityStream
+	folderService    *Folder
+	followingService *Following
+	responseService  *Response
+	ruleService      *Rule
+	userService      *User
+	queue            queue.Queue
+	closed           chan bool
+}
+
+// NewDataImport returns a fully populated DataImport service
+func NewDataImport() DataImport {
+	return DataImport{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *DataImport) Refresh(collection data.Collection, activityService *ActivityStream, folderService *Folder, followingService *Following, responseService *Response, ruleService *Rule, userService *User, queue queue.Queue) {
+	service.collection = collection
+	service.activityService = activityService
+	service.folderService = folderService
+	service.followingService = followingService
+	service.responseService = responseService
+	service.ruleService = ruleService
+	service.userService = userService
+	service.queue = queue
+}
+
+// Close stops any background processes controlled by this service
+func (service *DataImport) Close() {
+	close(service.closed)
+}
+
+// Start begins the background scheduler that resumes unfinished imports.  Unfinished
+// imports are resumed once at startup, because queued tasks do not survive a restart.
+func (service *DataImport) Start() {
+
+	const location = "service.DataImport.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	if err := service.ResumeIncomplete(time.Now()); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error resuming imports"))
+	}
+
+	for {
+
+		// Poll randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
+
+		select {
+
+		// If we're done, we're done.
+		case <-service.closed:
+			return
+
+		// Skip imports that have made progress recently, which may still be running
+		default:
+			if err := service.ResumeIncomplete(time.Now().Add(-1 * time.Hour)); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error resuming imports"))
+			}
+		}
+	}
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns an slice containing all of the DataImports that match the provided criteria
+func (service *DataImport) Query(criteria exp.Expression, options ...option.Option) ([]model.DataImport, error) {
+	result := make([]model.DataImport, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a DataImport from the database
+func (service *DataImport) Load(criteria exp.Expression, dataImport *model.DataImport) error {
+
+	if err := service.collection.Load(notDeleted(criteria), dataImport); err != nil {
+		return derp.Wrap(err, "service.DataImport.Load", "Error loading DataImport", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a DataImport in the database
+func (service *DataImport) Save(dataImport *model.DataImport, note string) error {
+
+	if err := service.collection.Save(dataImport, note); err != nil {
+		return derp.Wrap(err, "service.DataImport.Save", "Error saving DataImport", dataImport.DataImportID, note)
+	}
+
+	return nil
+}
+
+// Delete removes a DataImport from the database (virtual delete)
+func (service *DataImport) Delete(dataImport *model.DataImport, note string) error {
+
+	if err := service.collection.Delete(dataImport, note); err != nil {
+		return derp.Wrap(err, "service.DataImport.Delete", "Error deleting DataImport", dataImport.DataImportID, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns the most recent DataImports uploaded by a User, newest first
+func (service *DataImport) QueryByUser(userID primitive.ObjectID) ([]model.DataImport, error) {
+	criteria := exp.Equal("userId", userID)
+	return service.Query(criteria, option.SortDesc("createDate"), option.MaxRows(10))
+}
+
+// LoadByID loads the DataImport with the provided ID
+func (service *DataImport) LoadByID(dataImportID primitive.ObjectID, dataImport *model.DataImport) error {
+	criteria := exp.Equal("_id", dataImportID)
+	return service.Load(criteria, dataImport)
+}
+
+// DeleteByUser permanently removes all of the DataImports uploaded by a User (hard delete)
+func (service *DataImport) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.DataImport.DeleteByUser", "Error deleting DataImports", userID)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// Create reads a CSV file exported from Mastodon, then imports its rows in the background.
+func (service *DataImport) Create(userID primitive.ObjectID, importType string, filename string, reader io.Reader) (model.DataImport, error) {
+
+	const location = "service.DataImport.Create"
+
+	if !isDataImportType(importType) {
+		return model.DataImport{}, derp.NewBadRequestError(location, "Invalid import type", importType)
+	}
+
+	rows, err := parseDataImport(reader)
+
+	if err != nil {
+		return model.DataImport{}, derp.Wrap(err, location, "Error reading CSV file", filename)
+	}
+
+	dataImport := model.NewDataImport()
+	dataImport.UserID = userID
+	dataImport.Type = importType
+	dataImport.Filename = filename
+	dataImport.Rows = rows
+	dataImport.TotalRows = len(rows)
+
+	if err := service.Save(&dataImport, "Uploaded"); err != nil {
+		return model.DataImport{}, derp.Wrap(err, location, "Error saving DataImport", filename)
+	}
+
+	service.queue.Push(NewTaskImportData(service, dataImport.DataImportID))
+	return dataImport, nil
+}
+
+// ResumeIncomplete queues a new TaskImportData for every unfinished DataImport
+// that has not been updated since the provided time.
+func (service *DataImport) ResumeIncomplete(updatedBefore time.Time) error {
+
+	const location = "service.DataImport.ResumeIncomplete"
+
+	criteria := exp.Equal("completeDate", nil).AndLessThan("updateDate", updatedBefore.UnixMilli())
+	dataImports, err := service.Query(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing unfinished imports")
+	}
+
+	for _, dataImport := range dataImports {
+		service.queue.Push(NewTaskImportData(service, dataImport.DataImportID))
+	}
+
+	return nil
+}
+
+// Run imports all of the remaining rows in a DataImport, saving its progress along the way.
+// Rows that cannot be imported are added to the DataImport's error list.
+func (service *DataImport) Run(dataImportID primitive.ObjectID) error {
+
+	const location = "service.DataImport.Run"
+
+	dataImport := model.NewDataImport()
+
+	if err := service.LoadByID(dataImportID, &dataImport); err != nil {
+		return derp.Wrap(err, location, "Error loading DataImport", dataImportID)
+	}
+
+	// RULE: Do not run imports twice
+	if dataImport.IsComplete() {
+		return nil
+	}
+
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(dataImport.UserID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", dataImport.UserID)
+	}
+
+	folders, err := newOPMLFolders(service.folderService, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading folders", user.UserID)
+	}
+
+	// Continue where a previous (interrupted) run left off
+	for dataImport.Processed < len(dataImport.Rows) {
+
+		row := dataImport.Rows[dataImport.Processed]
+		created, err := service.importRow(&user, folders, dataImport.Type, row)
+
+		if err != nil {
+			dataImport.AddError(row, derp.Message(err))
+		} else if created {
+			dataImport.Imported++
+		}
+
+		dataImport.Processed++
+
+		if dataImport.Processed%dataImportBatchSize == 0 {
+			if err := service.Save(&dataImport, "Progress"); err != nil {
+				return derp.Wrap(err, location, "Error saving progress", dataImportID)
+			}
+		}
+	}
+
+	dataImport.Rows = nil
+	dataImport.CompleteDate = time.Now().Unix()
+
+	if err := service.Save(&dataImport, "Complete"); err != nil {
+		return derp.Wrap(err, location, "Error saving DataImport", dataImportID)
+	}
+
+	// Recalculate counts for this User
+	if dataImport.Imported > 0 {
+		switch dataImport.Type {
+		case model.DataImportTypeFollowing:
+			service.userService.CalcFollowingCount(user.UserID)
+		case model.DataImportTypeBlock, model.DataImportTypeMute, model.DataImportTypeDomainBlock:
+			service.userService.CalcRuleCount(user.UserID)
+		}
+	}
+
+	return nil
+}
+
+// importRow imports a single row.  It returns TRUE if a new record was created,
+// or FALSE if the row was a duplicate of an existing record.
+func (service *DataImport) importRow(user *model.User, folders *opmlFolders, importType string, row model.DataImportRow) (bool, error) {
+
+	switch importType {
+
+	case model.DataImportTypeFollowing:
+		return service.importFollowing(user, folders, row.Value)
+
+	case model.DataImportTypeBlock:
+		return service.importActorRule(user, model.RuleActionBlock, row.Value)
+
+	case model.DataImportTypeMute:
+		return service.importActorRule(user, model.RuleActionMute, row.Value)
+
+	case model.DataImportTypeDomainBlock:
+		return service.importDomainBlock(user, row.Value)
+
+	case model.DataImportTypeBookmark:
+		return service.importBookmark(user, row.Value)
+	}
+
+	return false, derp.NewBadRequestError("service.DataImport.importRow", "Invalid import type", importType)
+}
+
+// importFollowing follows an account, placing it in the User's default Folder
+func (service *DataImport) importFollowing(user *model.User, folders *opmlFolders, address string) (bool, error) {
+
+	actor, err := service.loadActor(address)
+
+	if err != nil {
+		return false, err
+	}
+
+	label := actor.Name()
+
+	if label == "" {
+		label = actor.UsernameOrID()
+	}
+
+	return service.followingService.importFollowing(user.UserID, folders, "", actor.ID(), label, dataImportNote)
+}
+
+// importActorRule blocks or mutes an account
+func (service *DataImport) importActorRule(user *model.User, action string, address string) (bool, error) {
+
+	actor, err := service.loadActor(address)
+
+	if err != nil {
+		return false, err
+	}
+
+	return service.importRule(user, model.RuleTypeActor, action, actor.ID())
+}
+
+// importDomainBlock blocks an entire domain
+func (service *DataImport) importDomainBlock(user *model.User, domain string) (bool, error) {
+
+	domain = strings.ToLower(domain)
+
+	if (domain == "") || strings.ContainsAny(domain, "/@: ") {
+		return false, derp.NewBadRequestError("service.DataImport.importDomainBlock", "Invalid domain")
+	}
+
+	return service.importRule(user, model.RuleTypeDomain, model.RuleActionBlock, domain)
+}
+
+// importRule creates a new Rule, unless the User already has a Rule for the same trigger
+func (service *DataImport) importRule(user *model.User, ruleType string, action string, trigger string) (bool, error) {
+
+	const location = "service.DataImport.importRule"
+
+	existing := model.NewRule()
+	if err := service.ruleService.LoadByTrigger(user.UserID, ruleType, trigger, &existing); err == nil {
+		return false, nil
+	} else if !derp.NotFound(err) {
+		return false, derp.Wrap(err, location, "Error searching for existing Rule", trigger)
+	}
+
+	rule := model.NewRule()
+	rule.UserID = user.UserID
+	rule.Type = ruleType
+	rule.Action = action
+	rule.Trigger = trigger
+
+	if err := service.ruleService.Save(&rule, dataImportNote); err != nil {
+		return false, derp.Wrap(err, location, "Error saving Rule", trigger)
+	}
+
+	return true, nil
+}
+
+// importBookmark bookmarks a post.  Bookmarks are private, so they are not published to the outbox.
+func (service *DataImport) importBookmark(user *model.User, postURL string) (bool, error) {
+
+	const location = "service.DataImport.importBookmark"
+
+	if parsedURL, err := url.Parse(postURL); (err != nil) || ((parsedURL.Scheme != "http") && (parsedURL.Scheme != "https")) {
+		return false, derp.NewBadRequestError(location, "Invalid URL")
+	}
+
+	document, err := service.activityService.Load(postURL)
+
+	if err != nil {
+		return false, derp.NewNotFoundError(location, "Post not found")
+	}
+
+	existing := model.NewResponse()
+	if err := service.responseService.LoadByUserAndObject(user.UserID, document.ID(), model.ResponseTypeBookmark, &existing); err == nil {
+		return false, nil
+	} else if !derp.NotFound(err) {
+		return false, derp.Wrap(err, location, "Error searching for existing bookmark", postURL)
+	}
+
+	response := model.NewResponse()
+	response.UserID = user.UserID
+	response.Actor = user.ActivityPubURL()
+	response.Object = document.ID()
+	response.Type = model.ResponseTypeBookmark
+
+	if err := service.responseService.Save(&response, dataImportNote); err != nil {
+		return false, derp.Wrap(err, location, "Error saving bookmark", postURL)
+	}
+
+	return true, nil
+}
+
+// loadActor resolves an account address (like "alice@example.com") through WebFinger
+func (service *DataImport) loadActor(address string) (streams.Document, error) {
+
+	actor, err := service.activityService.Load(dataImportAddress(address), sherlock.AsActor())
+
+	if err != nil {
+		return streams.NilDocument(), derp.NewNotFoundError("service.DataImport.loadActor", "Account not found")
+	}
+
+	return actor, nil
+}
+
+/******************************************
+ * Helpers
+ ******************************************/
+
+// isDataImportType returns TRUE if the provided value is a valid DataImport type
+func isDataImportType(importType string) bool {
+
+	switch importType {
+	case model.DataImportTypeFollowing,
+		model.DataImportTypeBlock,
+		model.DataImportTypeMute,
+		model.DataImportTypeDomainBlock,
+		model.DataImportTypeBookmark:
+		return true
+	}
+
+	return false
+}
+
+// parseDataImport reads the first column of every row in a Mastodon CSV file.
+// Header rows (like "Account address" or "#domain") and blank rows are skipped.
+func parseDataImport(reader io.Reader) ([]model.DataImportRow, error) {
+
+	const location = "service.parseDataImport"
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	result := make([]model.DataImportRow, 0)
+
+	for {
+
+		record, err := csvReader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Invalid CSV file", derp.WithBadRequest())
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		value := strings.TrimSpace(record[0])
+
+		// Skip blank rows
+		if value == "" {
+			continue
+		}
+
+		// Skip header rows
+		if (line == 1) && (strings.HasPrefix(value, "#") || strings.EqualFold(value, "Account address")) {
+			continue
+		}
+
+		// RULE: Limit the size of each import
+		if len(result) >= dataImportMaxRows {
+			return nil, derp.NewBadRequestError(location, "File has too many rows", dataImportMaxRows)
+		}
+
+		result = append(result, model.DataImportRow{
+			Line:  line,
+			Value: value,
+		})
+	}
+
+	return result, nil
+}
+
+// dataImportAddress converts a Mastodon account address ("alice@example.com") into a
+// format that can be resolved through WebFinger ("@alice@example.com").  URLs are not changed.
+func dataImportAddress(address string) string {
+
+	if strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://") {
+		return address
+	}
+
+	return "@" + strings.TrimPrefix(address, "@")
+}
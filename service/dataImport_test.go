@@ -0,0 +1,87 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDataImport_Following(t *testing.T) {
+
+	file := "Account address,Show boosts,Notify on new posts,Languages\n" +
+		"alice@example.com,true,false,\n" +
+		"\n" +
+		"bob@example.social,false,false,en\n"
+
+	rows, err := parseDataImport(strings.NewReader(file))
+	require.Nil(t, err)
+	require.Equal(t, []model.DataImportRow{
+		{Line: 2, Value: "alice@example.com"},
+		{Line: 4, Value: "bob@example.social"},
+	}, rows)
+}
+
+func TestParseDataImport_NoHeader(t *testing.T) {
+
+	rows, err := parseDataImport(strings.NewReader("spam.example\nexample.net\n"))
+	require.Nil(t, err)
+	require.Equal(t, []model.DataImportRow{
+		{Line: 1, Value: "spam.example"},
+		{Line: 2, Value: "example.net"},
+	}, rows)
+}
+
+func TestParseDataImport_DomainHeader(t *testing.T) {
+
+	rows, err := parseDataImport(strings.NewReader("#domain\nspam.example\n"))
+	require.Nil(t, err)
+	require.Equal(t, []model.DataImportRow{{Line: 2, Value: "spam.example"}}, rows)
+}
+
+func TestParseDataImport_Invalid(t *testing.T) {
+
+	_, err := parseDataImport(strings.NewReader("\"unterminated\n"))
+	require.NotNil(t, err)
+}
+
+func TestDataImportAddress(t *testing.T) {
+	require.Equal(t, "@alice@example.com", dataImportAddress("alice@example.com"))
+	require.Equal(t, "@alice@example.com", dataImportAddress("@alice@example.com"))
+	require.Equal(t, "https://example.com/users/alice", dataImportAddress("https://example.com/users/alice"))
+}
+
+func TestDataImport_ResumeIncomplete(t *testing.T) {
+
+	collection := newMemoryCollection()
+	tasks := recordingQueue{}
+
+	service := NewDataImport()
+	service.collection = &collection
+	service.queue = &tasks
+
+	// An import that was interrupted
+	unfinished := model.NewDataImport()
+	unfinished.TotalRows = 2
+	unfinished.Processed = 1
+	require.Nil(t, collection.Save(&unfinished, ""))
+
+	// An import that has finished
+	finished := model.NewDataImport()
+	finished.CompleteDate = time.Now().Unix()
+	require.Nil(t, collection.Save(&finished, ""))
+
+	// Imports that made progress recently are left alone
+	require.Nil(t, service.ResumeIncomplete(time.Now().Add(-1*time.Hour)))
+	require.Empty(t, tasks)
+
+	// Unfinished imports are queued again
+	require.Nil(t, service.ResumeIncomplete(time.Now().Add(time.Second)))
+	require.Equal(t, 1, len(tasks))
+
+	task, ok := tasks[0].(TaskImportData)
+	require.True(t, ok)
+	require.Equal(t, unfinished.DataImportID, task.dataImportID)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/queries"
 	"github.com/EmissarySocial/emissary/service/providers"
+	"github.com/EmissarySocial/emissary/tools/indieauth"
 	"github.com/EmissarySocial/emissary/tools/random"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
@@ -60,7 +61,7 @@ func (service *Domain) Refresh(collection data.Collection, configuration config.
 		return
 	}
 
-	if err := queries.UpgradeMongoDB(configuration.ConnectString, configuration.DatabaseName, &service.domain); err != nil {
+	if err := queries.UpgradeMongoDB(configuration.ConnectString, configuration.DatabaseName, []byte(configuration.KeyEncryptingKey), &service.domain); err != nil {
 		derp.Report(derp.Wrap(err, "service.Domain.Refresh", "Domain Not Ready: Error upgrading domain record"))
 		return
 	}
@@ -318,14 +319,10 @@ func (service *Domain) OAuthCodeURL(providerID string) (string, error) {
 	config := provider.OAuthConfig()
 
 	config.RedirectURL = service.OAuthClientCallbackURL(providerID)
-	/* TODO: MEDIUM: add hash value for challenge_method...
-	codeChallengeBytes := sha256.Sum256([]byte(client.GetStringOK("code_challenge")))
-	codeChallenge := oauth2.SetAuthURLParam("code_challenge", random.Base64URLEncode(codeChallengeBytes[:]))
-	codeChallengeMethod := oauth2.SetAuthURLParam("code_challenge_method", "S256")
-	*/
-
-	codeChallenge := oauth2.SetAuthURLParam("code_challenge", client.Data.GetString("code_challenge"))
-	codeChallengeMethod := oauth2.SetAuthURLParam("code_challenge_method", "plain")
+
+	// The "code_challenge" value stored in the client is the PKCE verifier, so send its SHA-256 hash here.
+	codeChallenge := oauth2.SetAuthURLParam("code_challenge", indieauth.CodeChallenge(client.Data.GetString("code_challenge")))
+	codeChallengeMethod := oauth2.SetAuthURLParam("code_challenge_method", indieauth.CodeChallengeMethodS256)
 	authCodeURL := config.AuthCodeURL(client.Data.GetString("state"), codeChallenge, codeChallengeMethod)
 
 	return authCodeURL, nil
@@ -95,6 +95,65 @@ func (service *DomainEmail) SendPasswordReset(user *model.User) error {
 	return derp.Wrap(err, "service.DomainEmail.SendWelcome", "Error sending password reset email to user", user.Username)
 }
 
+// SendEmailConfirmation sends a link that new users click to confirm their email address.
+func (service *DomainEmail) SendEmailConfirmation(user *model.User) error {
+
+	err := service.serverEmail.Send(
+		service.smtp,
+		"user-confirm-email",
+		service.owner.EmailAddress,
+		[]string{user.EmailAddress},
+		"Confirm Your Email Address",
+		mapof.Any{
+			// User info available to the template
+			"UserID":      user.UserID.Hex(),
+			"Username":    user.Username,
+			"DisplayName": user.DisplayName,
+			"ConfirmCode": user.EmailConfirmation.AuthCode,
+			"ExpireDate":  user.EmailConfirmation.ExpireDate,
+
+			// Domain info available to the template
+			"Owner": service.owner,
+			"Host":  service.host(),
+			"Label": service.label,
+		},
+	)
+
+	return derp.Wrap(err, "service.DomainEmail.SendEmailConfirmation", "Error sending confirmation email to user", user.Username)
+}
+
+// SendDataExport sends a link that users click to download their personal data archive.
+func (service *DomainEmail) SendDataExport(user *model.User) error {
+
+	err := service.serverEmail.Send(
+		service.smtp,
+		"user-data-export",
+		service.owner.EmailAddress,
+		[]string{user.EmailAddress},
+		"Your Data is Ready to Download",
+		mapof.Any{
+			// User info available to the template
+			"UserID":      user.UserID.Hex(),
+			"Username":    user.Username,
+			"DisplayName": user.DisplayName,
+			"ExportCode":  user.DataExport.AuthCode,
+			"ExpireDate":  user.DataExport.ExpireDate,
+
+			// Domain info available to the template
+			"Owner": service.owner,
+			"Host":  service.host(),
+			"Label": service.label,
+		},
+	)
+
+	return derp.Wrap(err, "service.DomainEmail.SendDataExport", "Error sending data export email to user", user.Username)
+}
+
+// CanSend returns TRUE if this domain has an SMTP connection for sending email
+func (service *DomainEmail) CanSend() bool {
+	return !service.smtp.IsNil()
+}
+
 func (service *DomainEmail) host() string {
 	return domain.Protocol(service.hostname) + service.hostname
 }
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"sync/atomic"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/keywrap"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
@@ -20,13 +24,23 @@ const encryptionKeyBits = 2048
 
 // EncryptionKey defines a service that tracks the (possibly external) accounts an internal User is encryptionKey.
 type EncryptionKey struct {
-	collection data.Collection
-	host       string
+	collection               data.Collection
+	keyEncryptingKey         []byte // "Key Encrypting Key" used to encrypt/decrypt private keys that are stored in the collection
+	previousKeyEncryptingKey []byte // Previous "Key Encrypting Key", only set while the key encrypting key is being rotated
+	domainService            *Domain
+	userService              *User
+	streamService            *Stream
+	host                     string
+	version                  *atomic.Int64 // Incremented whenever a key is saved or removed, so that cached signers can be replaced
+	closed                   chan bool
 }
 
 // NewEncryptionKey returns a fully initialized EncryptionKey service
 func NewEncryptionKey() EncryptionKey {
-	return EncryptionKey{}
+	return EncryptionKey{
+		version: &atomic.Int64{},
+		closed:  make(chan bool),
+	}
 }
 
 /******************************************
@@ -34,14 +48,18 @@ func NewEncryptionKey() EncryptionKey {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *EncryptionKey) Refresh(collection data.Collection, host string) {
+func (service *EncryptionKey) Refresh(collection data.Collection, keyEncryptingKey []byte, domainService *Domain, userService *User, streamService *Stream, host string) {
 	service.collection = collection
+	service.keyEncryptingKey = keyEncryptingKey
+	service.domainService = domainService
+	service.userService = userService
+	service.streamService = streamService
 	service.host = host
 }
 
 // Close stops any background processes controlled by this service
 func (service *EncryptionKey) Close() {
-	// Nothin to do here.
+	close(service.closed)
 }
 
 /******************************************
@@ -70,6 +88,7 @@ func (service *EncryptionKey) Save(encryptionKey *model.EncryptionKey, note stri
 		return derp.Wrap(err, "service.EncryptionKey.Save", "Error saving EncryptionKey", encryptionKey, note)
 	}
 
+	service.version.Add(1)
 	return nil
 }
 
@@ -81,6 +100,7 @@ func (service *EncryptionKey) Delete(encryptionKey *model.EncryptionKey, note st
 		return derp.Wrap(err, "service.EncryptionKey.Delete", "Error deleting EncryptionKey", encryptionKey, note)
 	}
 
+	service.version.Add(1)
 	return nil
 }
 
@@ -88,12 +108,12 @@ func (service *EncryptionKey) Delete(encryptionKey *model.EncryptionKey, note st
  * Custom Queries
  ******************************************/
 
-// LoadByID tries to load the EncryptionKey from the database.  If no key
+// LoadByID tries to load the current RSA EncryptionKey from the database.  If no key
 // exists for the designated user, then a new one is generated.
 func (service *EncryptionKey) LoadByParentID(parentType string, parentID primitive.ObjectID, encryptionKey *model.EncryptionKey) error {
 
 	// Try to load the encryption key from the database
-	err := service.Load(exp.Equal("parentType", parentType).AndEqual("parentId", parentID), encryptionKey)
+	err := service.Load(service.currentKeyCriteria(parentType, parentID, model.EncryptionKeyAlgorithmRSA), encryptionKey)
 
 	// If there is no error, then return in success
 	if err == nil {
@@ -119,23 +139,36 @@ func (service *EncryptionKey) LoadByParentID(parentType string, parentID primiti
  * Custom Actions
  ******************************************/
 
+// Create generates the original RSA EncryptionKey for an Actor
 func (service *EncryptionKey) Create(parentType string, parentID primitive.ObjectID) (model.EncryptionKey, error) {
+	return service.create(parentType, parentID, model.EncryptionKeyAlgorithmRSA, model.EncryptionKeyMainFragment)
+}
+
+// create generates a new EncryptionKey and saves it to the database.  If the fragment
+// is empty, then a unique fragment is generated from the new key's ID.
+func (service *EncryptionKey) create(parentType string, parentID primitive.ObjectID, algorithm string, fragment string) (model.EncryptionKey, error) {
 
 	// Create new model object
 	encryptionKey := model.NewEncryptionKey()
 	encryptionKey.ParentType = parentType
 	encryptionKey.ParentID = parentID
-	encryptionKey.Encoding = model.EncryptionKeyEncodingPlaintext // TODO: MEDIUM: add key encryption encoding
+	encryptionKey.Algorithm = algorithm
+	encryptionKey.Fragment = fragment
+	encryptionKey.Encoding = model.EncryptionKeyEncodingPlaintext
 
-	// Create an actual encryption key
-	privateKey, err := rsa.GenerateKey(rand.Reader, encryptionKeyBits)
+	if encryptionKey.Fragment == "" {
+		encryptionKey.Fragment = "key-" + encryptionKey.EncryptionKeyID.Hex()
+	}
 
-	if err != nil {
-		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Error generating RSA key", parentType, parentID)
+	// Create an actual encryption key
+	if err := generateKeyPair(&encryptionKey); err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Error generating key pair", parentType, parentID)
 	}
 
-	encryptionKey.PrivatePEM = sigs.EncodePrivatePEM(privateKey)
-	encryptionKey.PublicPEM = sigs.EncodePublicPEM(privateKey)
+	// Encrypt the private key before it is stored
+	if err := service.encrypt(&encryptionKey); err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Error encrypting new EncryptionKey", parentType, parentID)
+	}
 
 	if err := service.Save(&encryptionKey, "Created"); err != nil {
 		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Error saving new EncryptionKey", parentType, parentID)
@@ -144,6 +177,79 @@ func (service *EncryptionKey) Create(parentType string, parentID primitive.Objec
 	return encryptionKey, nil
 }
 
+// DeleteByParentID permanently removes all of an Actor's keys, including rotated keys
+// that are still in their grace period (hard delete)
+func (service *EncryptionKey) DeleteByParentID(parentType string, parentID primitive.ObjectID) error {
+
+	criteria := exp.Equal("parentType", parentType).AndEqual("parentId", parentID)
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, "service.EncryptionKey.DeleteByParentID", "Error deleting EncryptionKeys", parentType, parentID)
+	}
+
+	service.version.Add(1)
+	return nil
+}
+
+// Version returns a number that changes whenever this service saves or removes a key.
+// Callers that cache keys (or signers made from them) use it to tell when to reload them.
+func (service *EncryptionKey) Version() int64 {
+	return service.version.Load()
+}
+
+// currentKeyCriteria returns the query criteria for an Actor's current (not rotated) key
+func (service *EncryptionKey) currentKeyCriteria(parentType string, parentID primitive.ObjectID, algorithm string) exp.Expression {
+	return exp.Equal("parentType", parentType).
+		AndEqual("parentId", parentID).
+		AndEqual("algorithm", algorithm).
+		AndEqual("expireDate", 0)
+}
+
+// generateKeyPair populates an EncryptionKey with a new (plaintext) public and private key
+func generateKeyPair(encryptionKey *model.EncryptionKey) error {
+
+	switch encryptionKey.Algorithm {
+
+	case model.EncryptionKeyAlgorithmRSA:
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, encryptionKeyBits)
+
+		if err != nil {
+			return derp.Wrap(err, "service.generateKeyPair", "Error generating RSA key")
+		}
+
+		encryptionKey.PrivatePEM = sigs.EncodePrivatePEM(privateKey)
+		encryptionKey.PublicPEM = sigs.EncodePublicPEM(privateKey)
+		return nil
+
+	case model.EncryptionKeyAlgorithmEd25519:
+
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+		if err != nil {
+			return derp.Wrap(err, "service.generateKeyPair", "Error generating Ed25519 key")
+		}
+
+		privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+
+		if err != nil {
+			return derp.Wrap(err, "service.generateKeyPair", "Error encoding Ed25519 private key")
+		}
+
+		publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+
+		if err != nil {
+			return derp.Wrap(err, "service.generateKeyPair", "Error encoding Ed25519 public key")
+		}
+
+		encryptionKey.PrivatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}))
+		encryptionKey.PublicPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))
+		return nil
+	}
+
+	return derp.NewInternalError("service.generateKeyPair", "Unsupported key algorithm", encryptionKey.Algorithm)
+}
+
 /******************************************
  * Data Accessors
  ******************************************/
@@ -161,8 +267,23 @@ func (service *EncryptionKey) GetPublicKey(encryptionKey *model.EncryptionKey) (
 
 func (service *EncryptionKey) GetPrivateKey(encryptionKey *model.EncryptionKey) (*rsa.PrivateKey, error) {
 
+	if !encryptionKey.IsRSA() {
+		return nil, derp.NewInternalError("model.EncryptionKey.PrivateKey", "EncryptionKey is not an RSA key", encryptionKey.EncryptionKeyID)
+	}
+
+	// Decrypt the private key
+	privatePEM, err := service.decrypt(encryptionKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "model.EncryptionKey.PrivateKey", "Error decrypting private key", encryptionKey.EncryptionKeyID)
+	}
+
 	// Decode PEM block
-	block, _ := pem.Decode([]byte(encryptionKey.PrivatePEM))
+	block, _ := pem.Decode([]byte(privatePEM))
+
+	if block == nil {
+		return nil, derp.NewInternalError("model.EncryptionKey.PrivateKey", "Private key is not a valid PEM", encryptionKey.EncryptionKeyID)
+	}
 
 	// Parse the key
 	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
@@ -174,6 +295,36 @@ func (service *EncryptionKey) GetPrivateKey(encryptionKey *model.EncryptionKey)
 	return privateKey, nil
 }
 
+// GetEd25519PublicKey returns the public key of an Ed25519 EncryptionKey
+func (service *EncryptionKey) GetEd25519PublicKey(encryptionKey *model.EncryptionKey) (ed25519.PublicKey, error) {
+
+	const location = "model.EncryptionKey.GetEd25519PublicKey"
+
+	if !encryptionKey.IsEd25519() {
+		return nil, derp.NewInternalError(location, "EncryptionKey is not an Ed25519 key", encryptionKey.EncryptionKeyID)
+	}
+
+	block, _ := pem.Decode([]byte(encryptionKey.PublicPEM))
+
+	if block == nil {
+		return nil, derp.NewInternalError(location, "Public key is not a valid PEM", encryptionKey.EncryptionKeyID)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error parsing public key", encryptionKey.EncryptionKeyID)
+	}
+
+	result, ok := publicKey.(ed25519.PublicKey)
+
+	if !ok {
+		return nil, derp.NewInternalError(location, "Public key is not an Ed25519 key", encryptionKey.EncryptionKeyID)
+	}
+
+	return result, nil
+}
+
 func (service *EncryptionKey) Sign(message []byte, encryptionKey *model.EncryptionKey) ([]byte, error) {
 
 	privateKey, err := service.GetPrivateKey(encryptionKey)
@@ -196,6 +347,182 @@ func (service *EncryptionKey) Verify(message []byte, signature []byte, encryptio
 	return rsa.VerifyPKCS1v15(publicKey, 0, message, signature)
 }
 
+// GetSigner returns a sigs.Signer that signs outbound GET requests (aka "authorized fetch")
+// using the EncryptionKey for the designated parent.  Use EncryptionKeyTypeDomain with a
+// NilObjectID to sign requests as the Domain/Application Actor.
+func (service *EncryptionKey) GetSigner(parentType string, parentID primitive.ObjectID) (sigs.Signer, error) {
+
+	const location = "service.EncryptionKey.GetSigner"
+
+	// Load (or create) the EncryptionKey for this parent
+	encryptionKey := model.NewEncryptionKey()
+	if err := service.LoadByParentID(parentType, parentID, &encryptionKey); err != nil {
+		return sigs.Signer{}, derp.Wrap(err, location, "Error loading EncryptionKey", parentType, parentID)
+	}
+
+	signer, err := service.NewSigner(&encryptionKey)
+
+	if err != nil {
+		return sigs.Signer{}, derp.Wrap(err, location, "Error creating signer", parentType, parentID)
+	}
+
+	return signer, nil
+}
+
+// NewSigner returns a Signer that signs outbound GET requests with the provided EncryptionKey
+func (service *EncryptionKey) NewSigner(encryptionKey *model.EncryptionKey) (sigs.Signer, error) {
+
+	// Decode the private key
+	privateKey, err := service.GetPrivateKey(encryptionKey)
+
+	if err != nil {
+		return sigs.Signer{}, derp.Wrap(err, "service.EncryptionKey.NewSigner", "Error getting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	// GET requests have no body, so there is no Digest to sign
+	signer := sigs.NewSigner(
+		service.KeyID(encryptionKey),
+		privateKey,
+		sigs.SignerFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate),
+	)
+
+	return signer, nil
+}
+
+/******************************************
+ * Key Encryption
+ ******************************************/
+
+// SetKeyEncryptingKeys updates the key encrypting keys used by this service.  New values are always
+// encrypted with the current key.  The previous key is only set while the key encrypting key is being
+// rotated, so that values which have not been re-encrypted yet can still be decrypted.
+func (service *EncryptionKey) SetKeyEncryptingKeys(keyEncryptingKey []byte, previousKeyEncryptingKey []byte) {
+	service.keyEncryptingKey = keyEncryptingKey
+	service.previousKeyEncryptingKey = previousKeyEncryptingKey
+}
+
+// Rewrap encrypts every private key with the current key encrypting key.  Plaintext keys are
+// encrypted for the first time, and keys that are already encrypted with the current key encrypting
+// key are skipped, so this can safely be run again if it is interrupted.
+func (service *EncryptionKey) Rewrap() error {
+
+	const location = "service.EncryptionKey.Rewrap"
+
+	it, err := service.collection.Iterator(exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing EncryptionKeys")
+	}
+
+	encryptionKey := model.NewEncryptionKey()
+
+	for it.Next(&encryptionKey) {
+
+		if err := service.rewrap(encryptionKey); err != nil {
+			return derp.Wrap(err, location, "Error re-encrypting EncryptionKey", encryptionKey.EncryptionKeyID)
+		}
+
+		encryptionKey = model.NewEncryptionKey()
+	}
+
+	return nil
+}
+
+// rewrap decrypts a single EncryptionKey with either key encrypting key,
+// then encrypts it with the current key encrypting key and saves it to the database
+func (service *EncryptionKey) rewrap(encryptionKey model.EncryptionKey) error {
+
+	const location = "service.EncryptionKey.rewrap"
+
+	if service.isCurrent(&encryptionKey) {
+		return nil
+	}
+
+	privatePEM, err := service.decrypt(&encryptionKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error decrypting EncryptionKey")
+	}
+
+	encryptionKey.PrivatePEM = privatePEM
+	encryptionKey.Encoding = model.EncryptionKeyEncodingPlaintext
+
+	if err := service.encrypt(&encryptionKey); err != nil {
+		return derp.Wrap(err, location, "Error encrypting EncryptionKey")
+	}
+
+	if err := service.collection.Save(&encryptionKey, "Rotated key encrypting key"); err != nil {
+		return derp.Wrap(err, location, "Error saving EncryptionKey")
+	}
+
+	return nil
+}
+
+// isCurrent returns TRUE if the EncryptionKey is already encrypted with the current key encrypting key
+func (service *EncryptionKey) isCurrent(encryptionKey *model.EncryptionKey) bool {
+
+	if !encryptionKey.IsEncrypted() {
+		return false
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptionKey.PrivatePEM)
+
+	if err != nil {
+		return false
+	}
+
+	_, err = keywrap.Unwrap(service.keyEncryptingKey, ciphertext, encryptionKey.EncryptionKeyID[:])
+	return err == nil
+}
+
+// encrypt encrypts the (plaintext) private key of an EncryptionKey with the key encrypting key
+func (service *EncryptionKey) encrypt(encryptionKey *model.EncryptionKey) error {
+	return encryptPrivateKey(service.keyEncryptingKey, encryptionKey)
+}
+
+// decrypt returns the plaintext PEM of an EncryptionKey's private key
+func (service *EncryptionKey) decrypt(encryptionKey *model.EncryptionKey) (string, error) {
+
+	// Keys that have not been encrypted yet can be used as-is
+	if !encryptionKey.IsEncrypted() {
+		return encryptionKey.PrivatePEM, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptionKey.PrivatePEM)
+
+	if err != nil {
+		return "", derp.Wrap(err, "service.EncryptionKey.decrypt", "Error decoding encrypted private key")
+	}
+
+	plaintext, err := keywrap.UnwrapAny([][]byte{service.keyEncryptingKey, service.previousKeyEncryptingKey}, ciphertext, encryptionKey.EncryptionKeyID[:])
+
+	if err != nil {
+		return "", derp.Wrap(err, "service.EncryptionKey.decrypt", "Error decrypting private key")
+	}
+
+	return string(plaintext), nil
+}
+
+// encryptPrivateKey encrypts the (plaintext) private key of an EncryptionKey with the provided key
+// encrypting key.  The EncryptionKeyID is used as additional data, so encrypted private keys cannot
+// be moved from one record to another.
+func encryptPrivateKey(keyEncryptingKey []byte, encryptionKey *model.EncryptionKey) error {
+
+	if encryptionKey.IsEncrypted() {
+		return nil
+	}
+
+	ciphertext, err := keywrap.Wrap(keyEncryptingKey, []byte(encryptionKey.PrivatePEM), encryptionKey.EncryptionKeyID[:])
+
+	if err != nil {
+		return derp.Wrap(err, "service.encryptPrivateKey", "Error encrypting private key")
+	}
+
+	encryptionKey.PrivatePEM = base64.StdEncoding.EncodeToString(ciphertext)
+	encryptionKey.Encoding = model.EncryptionKeyEncodingAESGCM
+	return nil
+}
+
 /******************************************
  * Other Key Metadata
  ******************************************/
@@ -203,7 +530,12 @@ func (service *EncryptionKey) Verify(message []byte, signature []byte, encryptio
 // OwnerID returns the publicly accessible URL of the Actor who owns this EncryptionKey
 func (service *EncryptionKey) OwnerID(encryptionKey *model.EncryptionKey) string {
 
-	if encryptionKey.ParentType == model.EncryptionKeyTypeUser {
+	switch encryptionKey.ParentType {
+
+	case model.EncryptionKeyTypeDomain:
+		return service.host + "/@application"
+
+	case model.EncryptionKeyTypeUser:
 		return service.host + "/@" + encryptionKey.ParentID.Hex()
 	}
 
@@ -212,5 +544,5 @@ func (service *EncryptionKey) OwnerID(encryptionKey *model.EncryptionKey) string
 
 // KeyID returns the publicly accessible URL of this EncryptionKey
 func (service *EncryptionKey) KeyID(encryptionKey *model.EncryptionKey) string {
-	return service.OwnerID(encryptionKey) + "#main-key"
+	return service.OwnerID(encryptionKey) + "#" + encryptionKey.KeyFragment()
 }
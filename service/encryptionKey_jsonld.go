@@ -0,0 +1,123 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JSONLD adds an Actor's public keys to its JSON-LD document.  The current RSA key is
+// always published as the only "publicKey", because most servers (including Mastodon)
+// only read the first one.  Rotated RSA keys that are still in their grace period are
+// published as "assertionMethod" values, so that their keyId still resolves to a key
+// in this document.  Ed25519 keys are also published as "assertionMethod" values (FEP-521a).
+func (service *EncryptionKey) JSONLD(parentType string, parentID primitive.ObjectID, document mapof.Any) error {
+
+	const location = "service.EncryptionKey.JSONLD"
+
+	// Make sure that the Actor has a current RSA key
+	current := model.NewEncryptionKey()
+
+	if err := service.LoadByParentID(parentType, parentID, &current); err != nil {
+		return derp.Wrap(err, location, "Error loading current key", parentType, parentID)
+	}
+
+	// Load all of the keys that remote servers should accept
+	encryptionKeys, err := service.QueryValidByParentID(parentType, parentID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading keys", parentType, parentID)
+	}
+
+	assertionMethods := make([]any, 0)
+	hasMultikey := false
+
+	for index := range encryptionKeys {
+
+		encryptionKey := &encryptionKeys[index]
+
+		if encryptionKey.IsEd25519() {
+
+			assertionMethod, err := service.assertionMethodJSONLD(encryptionKey)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error encoding Ed25519 key", encryptionKey.EncryptionKeyID)
+			}
+
+			assertionMethods = append(assertionMethods, assertionMethod)
+			hasMultikey = true
+			continue
+		}
+
+		if encryptionKey.EncryptionKeyID != current.EncryptionKeyID {
+			assertionMethods = append(assertionMethods, service.rotatedKeyJSONLD(encryptionKey))
+		}
+	}
+
+	document[vocab.PropertyPublicKey] = service.publicKeyJSONLD(&current)
+	addJSONLDContext(document, vocab.ContextTypeSecurity)
+
+	if len(assertionMethods) > 0 {
+		document["assertionMethod"] = assertionMethods
+	}
+
+	if hasMultikey {
+		addJSONLDContext(document, multikey.Context)
+	}
+
+	return nil
+}
+
+// publicKeyJSONLD returns the "publicKey" representation of an RSA key
+func (service *EncryptionKey) publicKeyJSONLD(encryptionKey *model.EncryptionKey) mapof.Any {
+	return mapof.Any{
+		vocab.PropertyID:   service.KeyID(encryptionKey),
+		vocab.PropertyType: "Key",
+		"owner":            service.OwnerID(encryptionKey),
+		"publicKeyPem":     encryptionKey.PublicPEM,
+	}
+}
+
+// rotatedKeyJSONLD returns the "assertionMethod" representation of a rotated RSA key
+func (service *EncryptionKey) rotatedKeyJSONLD(encryptionKey *model.EncryptionKey) mapof.Any {
+	result := service.publicKeyJSONLD(encryptionKey)
+	result["controller"] = service.OwnerID(encryptionKey)
+	return result
+}
+
+// assertionMethodJSONLD returns the "assertionMethod" (Multikey) representation of an Ed25519 key
+func (service *EncryptionKey) assertionMethodJSONLD(encryptionKey *model.EncryptionKey) (mapof.Any, error) {
+
+	publicKey, err := service.GetEd25519PublicKey(encryptionKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.EncryptionKey.assertionMethodJSONLD", "Error getting public key")
+	}
+
+	result := mapof.Any{
+		vocab.PropertyID:     service.KeyID(encryptionKey),
+		vocab.PropertyType:   multikey.Type,
+		"controller":         service.OwnerID(encryptionKey),
+		"publicKeyMultibase": multikey.EncodeEd25519(publicKey),
+	}
+
+	return result, nil
+}
+
+// addJSONLDContext adds a context to a JSON-LD document, if it is not already present
+func addJSONLDContext(document mapof.Any, context string) {
+
+	contexts := convert.SliceOfAny(document[vocab.AtContext])
+
+	if slices.Contains(contexts, any(context)) {
+		return
+	}
+
+	document[vocab.AtContext] = append(contexts, context)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// encryptionKeyGracePeriod is how long a rotated key remains valid, so that remote servers
+// can still verify activities that were signed (and queued for delivery) before the rotation.
+const encryptionKeyGracePeriod = 7 * 24 * time.Hour
+
+/******************************************
+ * Key Rotation Queries
+ ******************************************/
+
+// QueryValidByParentID returns all of an Actor's keys that remote servers should accept:
+// the current keys, and any rotated keys that are still within their grace period.
+// Keys are sorted newest first, so the current key is always first.
+func (service *EncryptionKey) QueryValidByParentID(parentType string, parentID primitive.ObjectID) ([]model.EncryptionKey, error) {
+
+	criteria := exp.Equal("parentType", parentType).AndEqual("parentId", parentID)
+	encryptionKeys := make([]model.EncryptionKey, 0)
+
+	if err := service.collection.Query(&encryptionKeys, notDeleted(criteria), option.SortDesc("createDate")); err != nil {
+		return nil, derp.Wrap(err, "service.EncryptionKey.QueryValidByParentID", "Error querying EncryptionKeys", parentType, parentID)
+	}
+
+	result := make([]model.EncryptionKey, 0, len(encryptionKeys))
+
+	for _, encryptionKey := range encryptionKeys {
+		if !encryptionKey.IsExpired() {
+			result = append(result, encryptionKey)
+		}
+	}
+
+	return result, nil
+}
+
+// LoadEd25519ByParentID loads an Actor's current Ed25519 key.  Ed25519 keys are optional,
+// so (unlike LoadByParentID) a new key is NOT generated if one does not already exist.
+func (service *EncryptionKey) LoadEd25519ByParentID(parentType string, parentID primitive.ObjectID, encryptionKey *model.EncryptionKey) error {
+	criteria := service.currentKeyCriteria(parentType, parentID, model.EncryptionKeyAlgorithmEd25519)
+	return service.Load(criteria, encryptionKey)
+}
+
+// HasEd25519 returns TRUE if an Actor currently publishes an Ed25519 key
+func (service *EncryptionKey) HasEd25519(parentType string, parentID primitive.ObjectID) bool {
+	encryptionKey := model.NewEncryptionKey()
+	return service.LoadEd25519ByParentID(parentType, parentID, &encryptionKey) == nil
+}
+
+/******************************************
+ * Key Rotation Actions
+ ******************************************/
+
+// Rotate replaces an Actor's current keys with new ones, then announces the new public keys
+// to the Actor's followers.  Rotated keys remain valid for a grace period, so that activities
+// that are already queued for delivery can still be verified.
+func (service *EncryptionKey) Rotate(parentType string, parentID primitive.ObjectID) error {
+
+	const location = "service.EncryptionKey.Rotate"
+
+	// Rotate the RSA key (which is generated first, if it does not already exist)
+	encryptionKey := model.NewEncryptionKey()
+
+	if err := service.LoadByParentID(parentType, parentID, &encryptionKey); err != nil {
+		return derp.Wrap(err, location, "Error loading RSA key", parentType, parentID)
+	}
+
+	if err := service.rotate(&encryptionKey); err != nil {
+		return derp.Wrap(err, location, "Error rotating RSA key", parentType, parentID)
+	}
+
+	// Rotate the Ed25519 key, if the Actor publishes one
+	ed25519Key := model.NewEncryptionKey()
+
+	if err := service.LoadEd25519ByParentID(parentType, parentID, &ed25519Key); err == nil {
+
+		if err := service.rotate(&ed25519Key); err != nil {
+			return derp.Wrap(err, location, "Error rotating Ed25519 key", parentType, parentID)
+		}
+
+	} else if !derp.NotFound(err) {
+		return derp.Wrap(err, location, "Error loading Ed25519 key", parentType, parentID)
+	}
+
+	return service.announce(parentType, parentID)
+}
+
+// EnableEd25519 generates an Ed25519 key for an Actor (if it does not already have one)
+// and announces it to the Actor's followers.
+func (service *EncryptionKey) EnableEd25519(parentType string, parentID primitive.ObjectID) error {
+
+	const location = "service.EncryptionKey.EnableEd25519"
+
+	// If the Actor already has an Ed25519 key, then there's nothing to do.
+	if service.HasEd25519(parentType, parentID) {
+		return nil
+	}
+
+	if _, err := service.create(parentType, parentID, model.EncryptionKeyAlgorithmEd25519, ""); err != nil {
+		return derp.Wrap(err, location, "Error creating Ed25519 key", parentType, parentID)
+	}
+
+	return service.announce(parentType, parentID)
+}
+
+// DisableEd25519 removes an Actor's Ed25519 key (if it has one) and announces the change
+// to the Actor's followers.
+func (service *EncryptionKey) DisableEd25519(parentType string, parentID primitive.ObjectID) error {
+
+	const location = "service.EncryptionKey.DisableEd25519"
+
+	encryptionKey := model.NewEncryptionKey()
+
+	if err := service.LoadEd25519ByParentID(parentType, parentID, &encryptionKey); err != nil {
+
+		if derp.NotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Error loading Ed25519 key", parentType, parentID)
+	}
+
+	if err := service.Delete(&encryptionKey, "Disabled"); err != nil {
+		return derp.Wrap(err, location, "Error deleting Ed25519 key", parentType, parentID)
+	}
+
+	return service.announce(parentType, parentID)
+}
+
+// DeleteExpired removes all rotated keys whose grace period has ended
+func (service *EncryptionKey) DeleteExpired() error {
+
+	const location = "service.EncryptionKey.DeleteExpired"
+
+	criteria := exp.GreaterThan("expireDate", 0).AndLessThan("expireDate", time.Now().Unix())
+	it, err := service.List(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing expired EncryptionKeys")
+	}
+
+	encryptionKey := model.NewEncryptionKey()
+
+	for it.Next(&encryptionKey) {
+
+		if err := service.Delete(&encryptionKey, "Expired"); err != nil {
+			return derp.Wrap(err, location, "Error deleting expired EncryptionKey", encryptionKey.EncryptionKeyID)
+		}
+
+		encryptionKey = model.NewEncryptionKey()
+	}
+
+	return nil
+}
+
+// rotate replaces a single key with a new one, using the same algorithm.  The new key
+// is created before the old key is expired, so that the Actor always has a current key.
+func (service *EncryptionKey) rotate(encryptionKey *model.EncryptionKey) error {
+
+	const location = "service.EncryptionKey.rotate"
+
+	if _, err := service.create(encryptionKey.ParentType, encryptionKey.ParentID, encryptionKey.Algorithm, ""); err != nil {
+		return derp.Wrap(err, location, "Error creating new key")
+	}
+
+	encryptionKey.ExpireDate = time.Now().Add(encryptionKeyGracePeriod).Unix()
+
+	if err := service.Save(encryptionKey, "Rotated"); err != nil {
+		return derp.Wrap(err, location, "Error expiring old key")
+	}
+
+	return nil
+}
+
+// announce sends the Actor's updated profile (including its new public keys) to all of its followers
+func (service *EncryptionKey) announce(parentType string, parentID primitive.ObjectID) error {
+
+	switch parentType {
+
+	case model.EncryptionKeyTypeUser:
+		return service.userService.SendActorUpdate(parentID)
+
+	case model.EncryptionKeyTypeStream:
+		return service.streamService.SendActorUpdate(parentID)
+	}
+
+	// The Domain/Application Actor does not have followers
+	return nil
+}
+
+/******************************************
+ * Scheduled Rotation
+ ******************************************/
+
+// Start begins the background scheduler that removes expired keys, and rotates
+// old keys according to the Domain's key rotation schedule.
+func (service *EncryptionKey) Start() {
+
+	const location = "service.EncryptionKey.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Poll randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
+
+		// If (for some reason) the service collection is still nil, then
+		// wait this one out.
+		if service.collection == nil {
+			continue
+		}
+
+		if err := service.DeleteExpired(); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error deleting expired keys"))
+		}
+
+		// If the Domain does not rotate keys automatically, then there's nothing else to do.
+		rotationDays := service.domainService.Get().KeyRotationDays
+
+		if rotationDays <= 0 {
+			continue
+		}
+
+		// Find all current RSA keys that are older than the rotation schedule
+		maxDate := time.Now().AddDate(0, 0, -rotationDays).UnixMilli()
+		criteria := exp.Equal("algorithm", model.EncryptionKeyAlgorithmRSA).
+			AndEqual("expireDate", 0).
+			AndLessThan("createDate", maxDate)
+
+		it, err := service.List(criteria)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error listing keys to rotate"))
+			continue
+		}
+
+		encryptionKey := model.NewEncryptionKey()
+
+		for it.Next(&encryptionKey) {
+			select {
+
+			// If we're done, we're done.
+			case <-service.closed:
+				return
+
+			default:
+				if err := service.Rotate(encryptionKey.ParentType, encryptionKey.ParentID); err != nil {
+					derp.Report(derp.Wrap(err, location, "Error rotating keys", encryptionKey.ParentType, encryptionKey.ParentID))
+				}
+			}
+
+			encryptionKey = model.NewEncryptionKey()
+		}
+	}
+}
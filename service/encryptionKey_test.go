@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	mockdb "github.com/benpate/data-mock"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEncryptionKey_Encryption(t *testing.T) {
+
+	// Set up mock server and session
+	server := mockdb.New()
+	session, err := server.Session(context.TODO())
+	require.Nil(t, err)
+
+	service := NewEncryptionKey()
+	collection := session.Collection("EncryptionKey")
+	service.Refresh(collection, []byte("0123456789ABCDEF0123456789ABCDEF"), nil, nil, nil, "localhost")
+
+	// New keys are encrypted before they are stored
+	parentID := primitive.NewObjectID()
+	encryptionKey, err := service.Create(model.EncryptionKeyTypeUser, parentID)
+	require.Nil(t, err)
+	require.True(t, encryptionKey.IsEncrypted())
+	require.NotContains(t, encryptionKey.PrivatePEM, "PRIVATE KEY")
+
+	// Private keys are decrypted transparently
+	privateKey, err := service.GetPrivateKey(&encryptionKey)
+	require.Nil(t, err)
+
+	// Rotate the key encrypting key
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), []byte("0123456789ABCDEF0123456789ABCDEF"))
+	require.Nil(t, service.Rewrap())
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), nil)
+
+	it, err := collection.Iterator(exp.All())
+	require.Nil(t, err)
+
+	rotated := model.NewEncryptionKey()
+	require.True(t, it.Next(&rotated))
+	require.True(t, rotated.IsEncrypted())
+	require.NotEqual(t, encryptionKey.PrivatePEM, rotated.PrivatePEM)
+
+	// Rotated keys still decrypt to the same private key
+	rotatedKey, err := service.GetPrivateKey(&rotated)
+	require.Nil(t, err)
+	require.True(t, privateKey.Equal(rotatedKey))
+}
+
+func TestEncryptionKey_Plaintext(t *testing.T) {
+
+	// Set up mock server and session
+	server := mockdb.New()
+	session, err := server.Session(context.TODO())
+	require.Nil(t, err)
+
+	service := NewEncryptionKey()
+	collection := session.Collection("EncryptionKey")
+	service.Refresh(collection, []byte("0123456789ABCDEF0123456789ABCDEF"), nil, nil, nil, "localhost")
+
+	// Make a plaintext key, like those created by earlier versions
+	encryptionKey, err := service.Create(model.EncryptionKeyTypeUser, primitive.NewObjectID())
+	require.Nil(t, err)
+
+	privatePEM, err := service.decrypt(&encryptionKey)
+	require.Nil(t, err)
+
+	encryptionKey.PrivatePEM = privatePEM
+	encryptionKey.Encoding = model.EncryptionKeyEncodingPlaintext
+	require.Nil(t, service.Save(&encryptionKey, "plaintext"))
+
+	// Plaintext keys can still be used
+	_, err = service.GetPrivateKey(&encryptionKey)
+	require.Nil(t, err)
+
+	// Plaintext keys are encrypted when the key encrypting key is rotated
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), []byte("0123456789ABCDEF0123456789ABCDEF"))
+	require.Nil(t, service.Rewrap())
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), nil)
+
+	it, err := collection.Iterator(exp.All())
+	require.Nil(t, err)
+
+	rotated := model.NewEncryptionKey()
+	require.True(t, it.Next(&rotated))
+	require.True(t, rotated.IsEncrypted())
+
+	_, err = service.GetPrivateKey(&rotated)
+	require.Nil(t, err)
+}
+
+func TestEncryptionKey_InterruptedRotation(t *testing.T) {
+
+	oldKey := []byte("0123456789ABCDEF0123456789ABCDEF")
+	newKey := []byte("FEDCBA9876543210FEDCBA9876543210")
+
+	collection := newMemoryCollection()
+	service := NewEncryptionKey()
+	service.Refresh(&collection, oldKey, nil, nil, nil, "localhost")
+
+	// Create several keys with the old key encrypting key
+	encryptionKeys := make([]model.EncryptionKey, 3)
+
+	for index := range encryptionKeys {
+		encryptionKey, err := service.Create(model.EncryptionKeyTypeUser, primitive.NewObjectID())
+		require.Nil(t, err)
+		encryptionKeys[index] = encryptionKey
+	}
+
+	// Start a rotation that fails after the first key has been re-encrypted
+	failing := failingCollection{Collection: &collection, saves: 1}
+	service.Refresh(&failing, newKey, nil, nil, nil, "localhost")
+	service.SetKeyEncryptingKeys(newKey, oldKey)
+	require.NotNil(t, service.Rewrap())
+
+	// Every key can still be decrypted with one key encrypting key or the other
+	for _, encryptionKey := range encryptionKeys {
+		loaded := model.NewEncryptionKey()
+		require.Nil(t, service.Load(exp.Equal("_id", encryptionKey.EncryptionKeyID), &loaded))
+		_, err := service.GetPrivateKey(&loaded)
+		require.Nil(t, err)
+	}
+
+	// Running the rotation again finishes the job
+	service.Refresh(&collection, newKey, nil, nil, nil, "localhost")
+	require.Nil(t, service.Rewrap())
+
+	// Once every key has been re-encrypted, the previous key encrypting key is no longer needed
+	service.SetKeyEncryptingKeys(newKey, nil)
+
+	for _, encryptionKey := range encryptionKeys {
+		loaded := model.NewEncryptionKey()
+		require.Nil(t, service.Load(exp.Equal("_id", encryptionKey.EncryptionKeyID), &loaded))
+		_, err := service.GetPrivateKey(&loaded)
+		require.Nil(t, err)
+	}
+}
+
+func TestEncryptionKey_Ed25519(t *testing.T) {
+
+	// Set up mock server and session
+	server := mockdb.New()
+	session, err := server.Session(context.TODO())
+	require.Nil(t, err)
+
+	collection := session.Collection("EncryptionKey")
+	service := NewEncryptionKey()
+	service.Refresh(collection, []byte("0123456789ABCDEF0123456789ABCDEF"), nil, nil, nil, "http://localhost")
+
+	// Ed25519 keys are encrypted, and get a unique fragment
+	parentID := primitive.NewObjectID()
+	encryptionKey, err := service.create(model.EncryptionKeyTypeUser, parentID, model.EncryptionKeyAlgorithmEd25519, "")
+	require.Nil(t, err)
+	require.True(t, encryptionKey.IsEncrypted())
+	require.True(t, encryptionKey.IsEd25519())
+	require.Equal(t, "http://localhost/@"+parentID.Hex()+"#key-"+encryptionKey.EncryptionKeyID.Hex(), service.KeyID(&encryptionKey))
+
+	// Ed25519 keys cannot be used as RSA keys
+	_, err = service.GetPrivateKey(&encryptionKey)
+	require.NotNil(t, err)
+
+	// Ed25519 keys are published as Multikeys
+	assertionMethod, err := service.assertionMethodJSONLD(&encryptionKey)
+	require.Nil(t, err)
+	require.Equal(t, "Multikey", assertionMethod["type"])
+	require.Equal(t, "http://localhost/@"+parentID.Hex(), assertionMethod["controller"])
+	require.True(t, strings.HasPrefix(assertionMethod.GetString("publicKeyMultibase"), "z6Mk"))
+}
+
+func TestEncryptionKey_JSONLD_Rotated(t *testing.T) {
+
+	collection := newMemoryCollection()
+	service := NewEncryptionKey()
+	service.Refresh(&collection, []byte("0123456789ABCDEF0123456789ABCDEF"), nil, nil, nil, "http://localhost")
+
+	parentID := primitive.NewObjectID()
+	original, err := service.Create(model.EncryptionKeyTypeUser, parentID)
+	require.Nil(t, err)
+	require.Nil(t, service.rotate(&original))
+
+	current := model.NewEncryptionKey()
+	require.Nil(t, service.LoadByParentID(model.EncryptionKeyTypeUser, parentID, &current))
+	require.NotEqual(t, original.EncryptionKeyID, current.EncryptionKeyID)
+
+	document := mapof.Any{}
+	require.Nil(t, service.JSONLD(model.EncryptionKeyTypeUser, parentID, document))
+
+	// The current key is the only publicKey, so that every server uses it
+	publicKey, ok := document["publicKey"].(mapof.Any)
+	require.True(t, ok)
+	require.Equal(t, service.KeyID(&current), publicKey.GetString("id"))
+
+	// The rotated key can still be found by its keyId during the grace period
+	assertionMethods, ok := document["assertionMethod"].([]any)
+	require.True(t, ok)
+	require.Equal(t, 1, len(assertionMethods))
+
+	rotated := assertionMethods[0].(mapof.Any)
+	require.Equal(t, service.KeyID(&original), rotated.GetString("id"))
+	require.Equal(t, original.PublicPEM, rotated.GetString("publicKeyPem"))
+	require.Equal(t, "http://localhost/@"+parentID.Hex(), rotated.GetString("controller"))
+}
+
+func TestAddJSONLDContext(t *testing.T) {
+
+	document := mapof.Any{"@context": "https://www.w3.org/ns/activitystreams"}
+
+	addJSONLDContext(document, "https://w3id.org/security/v1")
+	addJSONLDContext(document, "https://w3id.org/security/v1")
+	require.Equal(t, []any{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"}, document["@context"])
+}
+
+// failingCollection wraps a data.Collection, and fails every Save after the first few succeed
+type failingCollection struct {
+	data.Collection
+	saves int
+}
+
+func (collection *failingCollection) Save(object data.Object, note string) error {
+
+	if collection.saves == 0 {
+		return derp.NewInternalError("service.failingCollection.Save", "Injected failure")
+	}
+
+	collection.saves--
+	return collection.Collection.Save(object, note)
+}
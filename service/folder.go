@@ -179,6 +179,17 @@ func (service *Folder) QueryByUserID(userID primitive.ObjectID) ([]model.Folder,
 	return service.Query(exp.Equal("userId", userID), option.SortAsc("rank"))
 }
 
+// DeleteByUser permanently removes all of a User's Folders (hard delete).  The Messages
+// inside them are removed separately, by Inbox.DeleteByUser.
+func (service *Folder) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.Folder.DeleteByUser", "Error deleting Folders", userID)
+	}
+
+	return nil
+}
+
 // LoadByID loads a single stream that matches the provided ID
 func (service *Folder) LoadByID(userID primitive.ObjectID, folderID primitive.ObjectID, result *model.Folder) error {
 
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
@@ -25,11 +28,14 @@ type Follower struct {
 	activityService *ActivityStream
 	queue           queue.Queue
 	host            string
+	closed          chan bool
 }
 
 // NewFollower returns a fully initialized Follower service
 func NewFollower() Follower {
-	return Follower{}
+	return Follower{
+		closed: make(chan bool),
+	}
 }
 
 /******************************************
@@ -48,7 +54,34 @@ func (service *Follower) Refresh(collection data.Collection, userService *User,
 
 // Close stops any background processes controlled by this service
 func (service *Follower) Close() {
-	// Nothin to do here.
+	close(service.closed)
+}
+
+// Start begins the background scheduler that removes expired rssCloud subscriptions
+func (service *Follower) Start() {
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Poll randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
+
+		select {
+
+		// If we're done, we're done.
+		case <-service.closed:
+			return
+
+		default:
+			if err := service.DeleteExpiredRSSCloud(); err != nil {
+				derp.Report(derp.Wrap(err, "service.Follower.Start", "Error removing expired rssCloud followers"))
+			}
+		}
+	}
 }
 
 /******************************************
@@ -231,6 +264,18 @@ func (service *Follower) ListByParent(parentID primitive.ObjectID, options ...op
 	return service.List(criteria, options...)
 }
 
+// DeleteByParent permanently removes all of the Followers of a specific parent (hard delete)
+func (service *Follower) DeleteByParent(parentType string, parentID primitive.ObjectID) error {
+
+	criteria := exp.Equal("type", parentType).AndEqual("parentId", parentID)
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, "service.Follower.DeleteByParent", "Error deleting Followers", parentType, parentID)
+	}
+
+	return nil
+}
+
 func (service *Follower) QueryByParent(parentType string, parentID primitive.ObjectID, options ...option.Option) ([]model.Follower, error) {
 	criteria := exp.Equal("type", parentType).AndEqual("parentId", parentID)
 	return service.Query(criteria, options...)
@@ -358,6 +403,73 @@ func (service *Follower) LoadOrCreateByWebSub(objectType string, parentID primit
 	return result, derp.Wrap(err, "service.Follower.LoadByWebSub", "Error loading follower", parentID, callback)
 }
 
+/******************************************
+ * RSSCloud Queries
+ ******************************************/
+
+// RSSCloudFollowersChannel returns a channel containing all of the Followers of specific parentID
+// who use rssCloud for updates.  rssCloud subscriptions expire after 25 hours, so
+// expired subscriptions are not included.
+func (service *Follower) RSSCloudFollowersChannel(parentType string, parentID primitive.ObjectID) (<-chan model.Follower, error) {
+
+	return service.Channel(
+		exp.Equal("parentId", parentID).
+			AndEqual("type", parentType).
+			AndEqual("method", model.FollowMethodRssCloud).
+			AndGreaterThan("expireDate", time.Now().Unix()),
+	)
+}
+
+// DeleteExpiredRSSCloud removes all rssCloud subscriptions that were not renewed before they expired
+func (service *Follower) DeleteExpiredRSSCloud() error {
+
+	criteria := exp.Equal("method", model.FollowMethodRssCloud).
+		AndLessThan("expireDate", time.Now().Unix())
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, "service.Follower.DeleteExpiredRSSCloud", "Error removing expired rssCloud followers")
+	}
+
+	return nil
+}
+
+// LoadByRSSCloud retrieves a follower based on the parentID and notification callback
+func (service *Follower) LoadByRSSCloud(objectType string, parentID primitive.ObjectID, callback string, result *model.Follower) error {
+
+	criteria := exp.
+		Equal("type", objectType).
+		AndEqual("parentId", parentID).
+		AndEqual("method", model.FollowMethodRssCloud).
+		AndEqual("actor.inboxUrl", callback)
+
+	return service.Load(criteria, result)
+}
+
+// LoadOrCreateByRSSCloud finds a follower based on the parentID and notification callback.  If no follower is found, a new record is created.
+func (service *Follower) LoadOrCreateByRSSCloud(objectType string, parentID primitive.ObjectID, callback string) (model.Follower, error) {
+
+	// Try to load the Follower from the database
+	result := model.NewFollower()
+	err := service.LoadByRSSCloud(objectType, parentID, callback, &result)
+
+	// If EXISTS, then we've found it.
+	if err == nil {
+		return result, nil
+	}
+
+	// If NOT EXISTS, then create a new one
+	if derp.NotFound(err) {
+		result.ParentID = parentID
+		result.Type = objectType
+		result.Method = model.FollowMethodRssCloud
+		result.Actor.InboxURL = callback
+		return result, nil
+	}
+
+	// If REAL ERROR, then derp
+	return result, derp.Wrap(err, "service.Follower.LoadOrCreateByRSSCloud", "Error loading follower", parentID, callback)
+}
+
 /******************************************
  * ActivityPub Queries
  ******************************************/
@@ -403,6 +515,70 @@ func (service *Follower) NewActivityPubFollower(parentType string, parentID prim
 	return nil
 }
 
+// RefreshActor updates the denormalized Actor data (name, avatar, inbox) in every
+// Follower record for this parent that matches the provided Actor.
+func (service *Follower) RefreshActor(parentID primitive.ObjectID, actor streams.Document) error {
+
+	const location = "service.Follower.RefreshActor"
+
+	// Find all Followers of this parent that match the Actor
+	followers, err := service.Query(exp.Equal("parentId", parentID).AndEqual("actor.profileUrl", actor.ID()))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying followers", parentID, actor.ID())
+	}
+
+	// Update each Follower with the new Actor data
+	for _, follower := range followers {
+
+		follower.Actor.Name = actor.Name()
+		follower.Actor.ImageURL = actor.IconOrImage().URL()
+
+		if inboxURL := actor.Get("inbox").String(); inboxURL != "" {
+			follower.Actor.InboxURL = inboxURL
+		}
+
+		if err := service.collection.Save(&follower, "Actor updated via ActivityPub"); err != nil {
+			return derp.Wrap(err, location, "Error saving follower", follower)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByActor removes all Follower records for this parent that match the provided Actor.
+// This is used when a remote Actor has been deleted from its home server.
+func (service *Follower) DeleteByActor(parentID primitive.ObjectID, actorID string) error {
+
+	const location = "service.Follower.DeleteByActor"
+
+	// Find all Followers of this parent that match the Actor
+	followers, err := service.Query(exp.Equal("parentId", parentID).AndEqual("actor.profileUrl", actorID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying followers", parentID, actorID)
+	}
+
+	// Nothing to delete
+	if len(followers) == 0 {
+		return nil
+	}
+
+	// Delete each Follower
+	for _, follower := range followers {
+		if err := service.Delete(&follower, "Actor deleted via ActivityPub"); err != nil {
+			return derp.Wrap(err, location, "Error deleting follower", follower)
+		}
+	}
+
+	// Recalculate the follower count (Users only.  Streams do not count their followers)
+	if followers[0].Type == model.FollowerTypeUser {
+		go service.userService.CalcFollowerCount(parentID)
+	}
+
+	return nil
+}
+
 func (service *Follower) LoadByActivityPubFollower(parentID primitive.ObjectID, followerURL string, follower *model.Follower) error {
 
 	criteria := exp.
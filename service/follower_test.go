@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFollower_RefreshActor(t *testing.T) {
+
+	followerService, parentID := testFollowerService(t, model.FollowerTypeUser)
+
+	actor := streams.NewDocument(mapof.Any{
+		"id":    "https://remote.test/users/alice",
+		"type":  "Person",
+		"name":  "Alice (new name)",
+		"icon":  "https://remote.test/alice-new.png",
+		"inbox": "https://remote.test/users/alice/new-inbox",
+	})
+
+	require.Nil(t, followerService.RefreshActor(parentID, actor))
+
+	followers, err := followerService.Query(exp.Equal("parentId", parentID))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(followers))
+
+	for _, follower := range followers {
+
+		// Only the matching Actor is updated
+		if follower.Actor.ProfileURL != "https://remote.test/users/alice" {
+			require.Equal(t, "Bob", follower.Actor.Name)
+			continue
+		}
+
+		require.Equal(t, "Alice (new name)", follower.Actor.Name)
+		require.Equal(t, "https://remote.test/alice-new.png", follower.Actor.ImageURL)
+		require.Equal(t, "https://remote.test/users/alice/new-inbox", follower.Actor.InboxURL)
+	}
+}
+
+func TestFollower_DeleteByActor(t *testing.T) {
+
+	// Stream followers do not recalculate a User's follower count,
+	// so this service works without a User service
+	followerService, parentID := testFollowerService(t, model.FollowerTypeStream)
+
+	require.Nil(t, followerService.DeleteByActor(parentID, "https://remote.test/users/alice"))
+
+	followers, err := followerService.Query(exp.Equal("parentId", parentID))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(followers))
+	require.Equal(t, "https://remote.test/users/bob", followers[0].Actor.ProfileURL)
+
+	// Deleting an unknown Actor is not an error
+	require.Nil(t, followerService.DeleteByActor(parentID, "https://remote.test/users/missing"))
+}
+
+// testFollowerService returns a Follower service with two remote followers of the same parent
+func testFollowerService(t *testing.T, followerType string) (Follower, primitive.ObjectID) {
+
+	collection := newMemoryCollection()
+
+	followerService := NewFollower()
+	followerService.Refresh(&collection, nil, nil, nil, nil, "https://local.test")
+
+	parentID := primitive.NewObjectID()
+
+	for name, profileURL := range map[string]string{"Alice": "https://remote.test/users/alice", "Bob": "https://remote.test/users/bob"} {
+		follower := model.NewFollower()
+		follower.ParentID = parentID
+		follower.Type = followerType
+		follower.Method = model.FollowMethodActivityPub
+		follower.Actor.Name = name
+		follower.Actor.ProfileURL = profileURL
+		follower.Actor.InboxURL = follower.Actor.ProfileURL + "/inbox"
+		require.Nil(t, followerService.collection.Save(&follower, "Test"))
+	}
+
+	return followerService, parentID
+}
+
+func TestFollower_DeleteExpiredRSSCloud(t *testing.T) {
+
+	followerService, parentID := testFollowerService(t, model.FollowerTypeUser)
+
+	for _, expireDate := range []int64{time.Now().Add(-time.Hour).Unix(), time.Now().Add(time.Hour).Unix()} {
+		follower := model.NewFollower()
+		follower.ParentID = parentID
+		follower.Type = model.FollowerTypeUser
+		follower.Method = model.FollowMethodRssCloud
+		follower.Actor.InboxURL = "https://remote.test/notify"
+		follower.ExpireDate = expireDate
+		require.Nil(t, followerService.collection.Save(&follower, "Test"))
+	}
+
+	require.Nil(t, followerService.DeleteExpiredRSSCloud())
+
+	// Only the expired rssCloud subscription is removed
+	followers, err := followerService.Query(exp.Equal("parentId", parentID))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(followers))
+
+	for _, follower := range followers {
+		if follower.Method == model.FollowMethodRssCloud {
+			require.Greater(t, follower.ExpireDate, time.Now().Unix())
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/hannibal/vocab"
 
 	"github.com/benpate/rosetta/mapof"
@@ -28,6 +29,7 @@ type Following struct {
 	folderService   *Folder
 	keyService      *EncryptionKey
 	activityService *ActivityStream
+	queue           queue.Queue
 	host            string
 	closed          chan bool
 }
@@ -42,7 +44,7 @@ func NewFollowing() Following {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Following) Refresh(collection data.Collection, streamService *Stream, userService *User, inboxService *Inbox, folderService *Folder, keyService *EncryptionKey, activityService *ActivityStream, host string) {
+func (service *Following) Refresh(collection data.Collection, streamService *Stream, userService *User, inboxService *Inbox, folderService *Folder, keyService *EncryptionKey, activityService *ActivityStream, queue queue.Queue, host string) {
 	service.collection = collection
 	service.streamService = streamService
 	service.userService = userService
@@ -50,6 +52,7 @@ func (service *Following) Refresh(collection data.Collection, streamService *Str
 	service.folderService = folderService
 	service.keyService = keyService
 	service.activityService = activityService
+	service.queue = queue
 	service.host = host
 }
 
@@ -154,7 +157,29 @@ func (service *Following) Load(criteria exp.Expression, result *model.Following)
 // Save adds/updates an Following in the database
 func (service *Following) Save(following *model.Following, note string) error {
 
-	const location = "service.Following.Save"
+	if err := service.save(following, note); err != nil {
+		return derp.Wrap(err, "service.Following.Save", "Error saving Following", following, note)
+	}
+
+	// RULE: Update messages if requested by the UX
+	go service.inboxService.UpdateInboxFolders(following.UserID, following.FollowingID, following.FolderID)
+
+	// Recalculate the follower count for this user
+	go service.userService.CalcFollowingCount(following.UserID)
+
+	// Connect to external services and discover the best update method.
+	// This will also update the status again, soon.
+	go service.RefreshAndConnect(*following)
+
+	// Win!
+	return nil
+}
+
+// save validates and saves a Following to the database, without
+// connecting to the remote server or updating any related records.
+func (service *Following) save(following *model.Following, note string) error {
+
+	const location = "service.Following.save"
 
 	// TODO: LOW: Add duplicate checks to this function?
 
@@ -186,6 +211,9 @@ func (service *Following) Save(following *model.Following, note string) error {
 	case model.FollowMethodWebSub:
 		following.PollDuration = 24 * 7 // retry WebSub connections every 7 days
 
+	case model.FollowMethodRssCloud:
+		following.PollDuration = 12 // rssCloud subscriptions expire after 25 hours
+
 	default:
 		following.PollDuration = 24
 	}
@@ -206,17 +234,6 @@ func (service *Following) Save(following *model.Following, note string) error {
 		return derp.Wrap(err, location, "Error saving Following", following, note)
 	}
 
-	// RULE: Update messages if requested by the UX
-	go service.inboxService.UpdateInboxFolders(following.UserID, following.FollowingID, following.FolderID)
-
-	// Recalculate the follower count for this user
-	go service.userService.CalcFollowingCount(following.UserID)
-
-	// Connect to external services and discover the best update method.
-	// This will also update the status again, soon.
-	go service.RefreshAndConnect(*following)
-
-	// Win!
 	return nil
 }
 
@@ -225,6 +242,23 @@ func (service *Following) Delete(following *model.Following, note string) error
 
 	const location = "service.Following.Delete"
 
+	// Remove the Following record (and its messages)
+	if err := service.delete(following, note); err != nil {
+		return derp.Wrap(err, location, "Error deleting Following", following, note)
+	}
+
+	// Disconnect from external services (if necessary)
+	service.Disconnect(following)
+
+	return nil
+}
+
+// delete removes an Following (and all of its messages) from the database
+// WITHOUT disconnecting from external services.
+func (service *Following) delete(following *model.Following, note string) error {
+
+	const location = "service.Following.delete"
+
 	// Remove the Following record
 	if err := service.collection.Delete(following, note); err != nil {
 		return derp.Wrap(err, location, "Error deleting Following", following, note)
@@ -241,9 +275,6 @@ func (service *Following) Delete(following *model.Following, note string) error
 	// Recalculate the unread count for this folder
 	go derp.Report(service.folderService.ReCalculateUnreadCountFromFolder(following.UserID, following.FolderID))
 
-	// Disconnect from external services (if necessary)
-	service.Disconnect(following)
-
 	return nil
 }
 
@@ -365,6 +396,34 @@ func (service *Following) ListByUserID(userID primitive.ObjectID) (data.Iterator
 	return service.List(criteria, option.SortAsc("lastPolled"))
 }
 
+// DeleteByUser sends "Undo Follow" activities to any ActivityPub actors that a User
+// was following, then permanently removes all of the User's Followings (hard delete).
+func (service *Following) DeleteByUser(userID primitive.ObjectID, note string) error {
+
+	const location = "service.Following.DeleteByUser"
+
+	it, err := service.ListByUserID(userID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing Followings", userID)
+	}
+
+	following := model.NewFollowing()
+
+	for it.Next(&following) {
+		if err := service.Delete(&following, note); err != nil {
+			return derp.Wrap(err, location, "Error deleting Following", following.FollowingID)
+		}
+		following = model.NewFollowing()
+	}
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Error deleting Followings", userID)
+	}
+
+	return nil
+}
+
 // LoadByID retrieves an Following from the database.  UserID is required to prevent
 // people from snooping on other's following.
 func (service *Following) LoadByID(userID primitive.ObjectID, followingID primitive.ObjectID, result *model.Following) error {
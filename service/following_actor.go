@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshActor updates the denormalized Actor data (label, avatar) in every Following
+// record for this User that matches the provided Actor, along with all of the
+// inbox Messages that were received from it.
+func (service *Following) RefreshActor(userID primitive.ObjectID, actor streams.Document) error {
+
+	const location = "service.Following.RefreshActor"
+
+	// Find all Following records for this User that match the Actor
+	followings, err := service.Query(exp.Equal("userId", userID).AndEqual("profileUrl", actor.ID()))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying followings", userID, actor.ID())
+	}
+
+	for _, following := range followings {
+
+		label := actor.Name()
+		imageURL := actor.IconOrImage().URL()
+
+		// Skip records that have not changed
+		if (following.Label == label) && (following.ImageURL == imageURL) {
+			continue
+		}
+
+		following.Label = label
+		following.ImageURL = imageURL
+
+		// Save directly to the collection so that we don't reset the connection status
+		if err := service.collection.Save(&following, "Actor updated via ActivityPub"); err != nil {
+			return derp.Wrap(err, location, "Error saving following", following)
+		}
+
+		// Update messages that were received from this Following
+		if err := service.inboxService.UpdateOrigin(following.Origin("")); err != nil {
+			return derp.Wrap(err, location, "Error updating messages", following)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByActor removes all Following records for this User that match the provided Actor,
+// along with all of the inbox Messages that were received from it.  This is used when a
+// remote Actor has been deleted from its home server, so there is no need to disconnect.
+func (service *Following) DeleteByActor(userID primitive.ObjectID, actorID string) error {
+
+	const location = "service.Following.DeleteByActor"
+
+	// Find all Following records for this User that match the Actor
+	followings, err := service.Query(exp.Equal("userId", userID).AndEqual("profileUrl", actorID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying followings", userID, actorID)
+	}
+
+	for _, following := range followings {
+		if err := service.delete(&following, "Actor deleted via ActivityPub"); err != nil {
+			return derp.Wrap(err, location, "Error deleting following", following)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFollowing_RefreshActor(t *testing.T) {
+
+	followingCollection := newMemoryCollection()
+	inboxCollection := newMemoryCollection()
+
+	inboxService := NewInbox()
+	inboxService.Refresh(&inboxCollection, nil, nil, "https://local.test")
+
+	followingService := NewFollowing()
+	followingService.Refresh(&followingCollection, nil, nil, &inboxService, nil, nil, nil, nil, "https://local.test")
+
+	// Follow a remote Actor, and receive a message from them
+	userID := primitive.NewObjectID()
+	following := model.NewFollowing()
+	following.UserID = userID
+	following.ProfileURL = "https://remote.test/users/alice"
+	following.Label = "Alice"
+	following.ImageURL = "https://remote.test/alice.png"
+	following.Status = model.FollowingStatusSuccess
+	require.Nil(t, followingCollection.Save(&following, "Test"))
+
+	message := model.NewMessage()
+	message.UserID = userID
+	message.Origin = following.Origin(model.OriginTypePrimary)
+	require.Nil(t, inboxCollection.Save(&message, "Test"))
+
+	// Receive an Update for the Actor
+	actor := streams.NewDocument(mapof.Any{
+		"id":   "https://remote.test/users/alice",
+		"type": "Person",
+		"name": "Alice (new name)",
+		"icon": "https://remote.test/alice-new.png",
+	})
+
+	require.Nil(t, followingService.RefreshActor(userID, actor))
+
+	// The Following is updated, without changing its connection status
+	updated := model.NewFollowing()
+	require.Nil(t, followingCollection.Load(exp.Equal("_id", following.FollowingID), &updated))
+	require.Equal(t, "Alice (new name)", updated.Label)
+	require.Equal(t, "https://remote.test/alice-new.png", updated.ImageURL)
+	require.Equal(t, model.FollowingStatusSuccess, updated.Status)
+
+	// Messages from this Following show the new name and avatar
+	updatedMessage := model.NewMessage()
+	require.Nil(t, inboxCollection.Load(exp.Equal("_id", message.MessageID), &updatedMessage))
+	require.Equal(t, "Alice (new name)", updatedMessage.Origin.Label)
+	require.Equal(t, "https://remote.test/alice-new.png", updatedMessage.Origin.ImageURL)
+
+	// Updates for other Actors are ignored
+	other := streams.NewDocument(mapof.Any{"id": "https://remote.test/users/bob", "type": "Person", "name": "Bob"})
+	require.Nil(t, followingService.RefreshActor(userID, other))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/EmissarySocial/emissary/tools/assigner"
 	"github.com/benpate/derp"
 	"github.com/benpate/domain"
 	"github.com/benpate/hannibal/collections"
@@ -18,7 +19,7 @@ func (service *Following) RefreshAndConnect(following model.Following) {
 
 	// Try to refresh the Actor in the cache
 	// nolint:errcheck
-	service.activityService.Load(following.URL, sherlock.AsActor(), ascache.WithForceReload())
+	service.activityService.Load(following.URL, sherlock.AsActor(), ascache.WithForceReload(), service.signAs(&following))
 
 	// Try to connect the Following record
 	if err := service.Connect(following); err != nil {
@@ -39,7 +40,7 @@ func (service *Following) Connect(following model.Following) error {
 
 	// Try to load the actor from the remote server.  Errors mean that this actor cannot
 	// be resolved, so we should mark the Following as a "Failure".
-	actor, err := service.activityService.Load(following.URL, sherlock.AsActor())
+	actor, err := service.activityService.Load(following.URL, sherlock.AsActor(), service.signAs(&following))
 
 	if err != nil {
 		if innerError := service.SetStatusFailure(&following, err.Error()); innerError != nil {
@@ -58,8 +59,10 @@ func (service *Following) Connect(following model.Following) error {
 		return derp.Wrap(err, location, "Error setting status", following)
 	}
 
-	// Try to load an initial list of messages from the actor's outbox
-	service.connect_LoadMessages(&following, &actor)
+	// Try to load an initial list of messages from an h-feed, or from the actor's outbox
+	if !service.connect_Microformats(&following, &actor) {
+		service.connect_LoadMessages(&following, &actor)
+	}
 
 	// Try to connect to push services (WebSub, ActivityPub, etc)
 	service.connect_PushServices(&following, &actor)
@@ -68,6 +71,21 @@ func (service *Following) Connect(following model.Following) error {
 	return nil
 }
 
+// signAs returns a LoadOption that signs outbound requests with the key of the
+// User who owns this Following.  If the key cannot be loaded, then requests are
+// signed with the Domain key instead.
+func (service *Following) signAs(following *model.Following) any {
+
+	signer, err := service.keyService.GetSigner(model.EncryptionKeyTypeUser, following.UserID)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "service.Following.signAs", "Error loading User signer", following.UserID))
+		return nil
+	}
+
+	return assigner.WithSigner(signer)
+}
+
 func (service *Following) connect_LoadMessages(following *model.Following, actor *streams.Document) {
 
 	const location = "service.Following.connect_LoadMessages"
@@ -131,7 +149,6 @@ func (service *Following) connect_PushServices(following *model.Following, actor
 
 	// If a WebSub hub is defined, then use that.
 	if hub := actor.Endpoints().Get("websub").String(); hub != "" {
-		// TODO: LOW: Implement Fat Pings
 		if ok, err := service.connect_WebSub(following, hub); ok {
 			return
 		} else {
@@ -139,6 +156,10 @@ func (service *Following) connect_PushServices(following *model.Following, actor
 		}
 	}
 
-	// RSSCloud is TBD because WebSub seems to have won the war.
-	// TODO: LOW: RSSCloud
+	// If the RSS feed defines an rssCloud server, then use that.
+	if ok, err := service.connect_RSSCloud(following, actor); ok {
+		return
+	} else if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error connecting to rssCloud"))
+	}
 }
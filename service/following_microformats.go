@@ -0,0 +1,233 @@
+package service
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/convert"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/mapof"
+	"willnorris.com/go/microformats"
+)
+
+// microformatsMaxPages is the maximum number of h-feed pages (linked via rel=next)
+// that are loaded each time that a web page is polled.
+const microformatsMaxPages = 3
+
+// microformatsPage is a single page of an h-feed
+type microformatsPage struct {
+	Items    []mapof.Any // ActivityStreams version of each h-entry on the page
+	NextURL  string      // URL of the next (older) page of the h-feed, if any
+	HasFeeds bool        // TRUE if the page links to an alternate (ActivityPub, JSON, Atom, or RSS) feed
+}
+
+// connect_Microformats imports messages from a web page that publishes an h-feed
+// (and no other kind of feed).  It returns TRUE if the page was imported, or FALSE if
+// messages should be loaded from the actor's outbox instead.
+func (service *Following) connect_Microformats(following *model.Following, actor *streams.Document) bool {
+
+	const location = "service.Following.connect_Microformats"
+
+	// RULE: ActivityPub actors are always loaded from their outbox
+	if actor.Inbox().NotNil() {
+		return false
+	}
+
+	items, ok := loadMicroformatsFeed(following.URL)
+
+	if !ok {
+		return false
+	}
+
+	// Sort the items chronologically so that they're imported in the correct order.
+	sort.SliceStable(items, func(a int, b int) bool {
+		return items[a].GetInt64(vocab.PropertyPublished) < items[b].GetInt64(vocab.PropertyPublished)
+	})
+
+	// Save each item into the cache and the inbox
+	for _, item := range items {
+
+		applyImportProperties(following, item)
+
+		document := service.activityService.NewDocument(item)
+		service.activityService.Put(document)
+
+		if err := service.SaveMessage(following, document, model.OriginTypePrimary); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error saving document to Inbox", item))
+		}
+	}
+
+	// Recalculate Folder unread counts
+	if err := service.folderService.ReCalculateUnreadCountFromFolder(following.UserID, following.FolderID); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error recalculating unread count"))
+	}
+
+	return true
+}
+
+// loadMicroformatsFeed loads every h-entry from a web page, following rel=next links to older
+// pages of the h-feed.  It returns FALSE if the page does not publish an h-feed, or if it links
+// to another feed format that should be used instead.
+func loadMicroformatsFeed(pageURL string) ([]mapof.Any, bool) {
+
+	const location = "service.loadMicroformatsFeed"
+
+	result := make([]mapof.Any, 0)
+	visited := make(map[string]bool)
+
+	for pageNumber := 0; pageNumber < microformatsMaxPages; pageNumber++ {
+
+		// RULE: Stop at the end of the feed, and do not loop through pages we've already seen
+		if (pageURL == "") || visited[pageURL] {
+			break
+		}
+
+		visited[pageURL] = true
+
+		var body string
+		txn := remote.Get(pageURL).Accept(model.MimeTypeHTML).Result(&body)
+
+		if err := txn.Send(); err != nil {
+			if pageNumber > 0 {
+				derp.Report(derp.Wrap(err, location, "Error loading h-feed page", pageURL))
+			}
+			break
+		}
+
+		// RULE: h-feeds are only published in HTML documents
+		if !strings.HasPrefix(txn.ResponseContentType(), model.MimeTypeHTML) {
+			break
+		}
+
+		page, err := parseMicroformatsFeed(pageURL, strings.NewReader(body))
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error parsing h-feed page", pageURL))
+			break
+		}
+
+		// RULE: Pages that link to other feeds are loaded from those feeds instead
+		if (pageNumber == 0) && page.HasFeeds {
+			return nil, false
+		}
+
+		result = append(result, page.Items...)
+		pageURL = page.NextURL
+	}
+
+	return result, len(result) > 0
+}
+
+// parseMicroformatsFeed parses a single page of an h-feed
+func parseMicroformatsFeed(pageURL string, body io.Reader) (microformatsPage, error) {
+
+	baseURL, err := url.Parse(pageURL)
+
+	if err != nil {
+		return microformatsPage{}, derp.Wrap(err, "service.parseMicroformatsFeed", "Invalid page URL", pageURL)
+	}
+
+	data := microformats.Parse(body, baseURL)
+
+	result := microformatsPage{
+		Items: microformatsToActivityMaps(pageURL, data),
+	}
+
+	if next := data.Rels["next"]; len(next) > 0 {
+		result.NextURL = next[0]
+	}
+
+	for _, alternate := range data.Rels["alternate"] {
+		if relURL, ok := data.RelURLs[alternate]; ok && isFeedMimeType(relURL.Type) {
+			result.HasFeeds = true
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// microformatsToActivityMaps returns the ActivityStreams version of every h-entry in an
+// h-feed, or every top-level h-entry if the page does not include an explicit h-feed.
+func microformatsToActivityMaps(pageURL string, data *microformats.Data) []mapof.Any {
+
+	type feedEntry struct {
+		feed  *microformats.Microformat
+		entry *microformats.Microformat
+	}
+
+	entries := make([]feedEntry, 0)
+
+	for _, item := range data.Items {
+
+		switch {
+
+		case hasMicroformatType(item, "h-feed"):
+			for _, child := range item.Children {
+				if hasMicroformatType(child, "h-entry") {
+					entries = append(entries, feedEntry{feed: item, entry: child})
+				}
+			}
+
+		case hasMicroformatType(item, "h-entry"):
+			entries = append(entries, feedEntry{entry: item})
+		}
+	}
+
+	// Number entries that share a published date from the bottom of the page up,
+	// so that new entries at the top do not change the identity of older ones.
+	result := make([]mapof.Any, len(entries))
+	positions := make(map[string]int)
+
+	for index := len(entries) - 1; index >= 0; index-- {
+		published := convert.MicroformatPropertyToString(entries[index].entry, "published")
+		result[index] = convert.MicroformatToActivityMap(pageURL, entries[index].feed, entries[index].entry, positions[published])
+		positions[published]++
+	}
+
+	// Default PublishDate just in case
+	for _, item := range result {
+		if item.GetInt64(vocab.PropertyPublished) == 0 {
+			item[vocab.PropertyPublished] = time.Now().Unix()
+		}
+	}
+
+	return result
+}
+
+// applyImportProperties removes the content properties that the Following has chosen not to import
+func applyImportProperties(following *model.Following, item mapof.Any) {
+
+	properties := map[string]string{
+		model.FollowingImportName:    vocab.PropertyName,
+		model.FollowingImportSummary: vocab.PropertySummary,
+		model.FollowingImportContent: vocab.PropertyContent,
+		model.FollowingImportPhoto:   vocab.PropertyImage,
+	}
+
+	for importName, property := range properties {
+		if !following.ImportsProperty(importName) {
+			delete(item, property)
+		}
+	}
+}
+
+// isFeedMimeType returns TRUE if the mime type identifies a feed format that is preferred over h-feed
+func isFeedMimeType(mimeType string) bool {
+
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+
+	switch mimeType {
+	case model.MimeTypeActivityPub, model.MimeTypeJSONFeed, model.MimeTypeJSON, model.MimeTypeAtom, model.MimeTypeRSS:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMicroformatsFeed(t *testing.T) {
+
+	body := `<html><head><link rel="next" href="/page/2"></head><body>
+		<div class="h-feed">
+			<article class="h-entry">
+				<a class="u-url p-name" href="/posts/1">Has a Permalink</a>
+				<time class="dt-published" datetime="2024-01-01 10:00:00+0000">Jan 1</time>
+				<time class="dt-updated" datetime="2024-01-02T10:00:00Z">Jan 2</time>
+			</article>
+			<article class="h-entry">
+				<time class="dt-published" datetime="2024-01-03T10:00:00Z">Jan 3</time>
+				<div class="e-content p-name">A note without a permalink</div>
+			</article>
+		</div>
+	</body></html>`
+
+	page, err := parseMicroformatsFeed("https://example.com/", strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, "https://example.com/page/2", page.NextURL)
+	require.False(t, page.HasFeeds)
+	require.Equal(t, 2, len(page.Items))
+
+	// Entries with a u-url use it as their ID
+	first := streams.NewDocument(page.Items[0])
+	require.Equal(t, "https://example.com/posts/1", first.ID())
+	require.Equal(t, int64(1704103200), first.Published().Unix())
+	require.Equal(t, int64(1704189600), first.Updated().Unix())
+
+	// Entries without a u-url are identified by a hash
+	second := page.Items[1].GetString(vocab.PropertyID)
+	require.True(t, strings.HasPrefix(second, "https://example.com/#h-entry-"))
+
+	// The hash does not change when the page is loaded again
+	again, err := parseMicroformatsFeed("https://example.com/", strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, second, again.Items[1].GetString(vocab.PropertyID))
+}
+
+func TestParseMicroformatsFeed_Identity(t *testing.T) {
+
+	parseAll := func(content string) []string {
+		body := `<div class="h-feed">` + content + `</div>`
+		page, err := parseMicroformatsFeed("https://example.com/notes", strings.NewReader(body))
+		require.Nil(t, err)
+
+		result := make([]string, len(page.Items))
+		for index, item := range page.Items {
+			result[index] = item.GetString(vocab.PropertyID)
+		}
+		return result
+	}
+
+	parse := func(content string) string {
+		result := parseAll(content)
+		require.Equal(t, 1, len(result))
+		return result[0]
+	}
+
+	// Edits to the name or content of an entry do not change its identity
+	original := parse(`<article class="h-entry"><time class="dt-published" datetime="2024-01-01">Jan 1</time><div class="e-content">First draft</div></article>`)
+	edited := parse(`<article class="h-entry"><h1 class="p-name">Title</h1><time class="dt-published" datetime="2024-01-01">Jan 1</time><div class="e-content">Second draft</div></article>`)
+	require.Equal(t, original, edited)
+
+	// Different entries published at the same time have different identities
+	both := parseAll(`<article class="h-entry"><p class="p-name">Newer</p><time class="dt-published" datetime="2024-01-01">Jan 1</time></article>` +
+		`<article class="h-entry"><p class="p-name">Older</p><time class="dt-published" datetime="2024-01-01">Jan 1</time></article>`)
+	require.Equal(t, 2, len(both))
+	require.NotEqual(t, both[0], both[1])
+
+	// New entries at the top of the page do not change the identity of older ones
+	require.Equal(t, original, both[1])
+
+	// u-uid is used when there is no u-url
+	require.Equal(t, "tag:example.com,2024:1", parse(`<article class="h-entry"><data class="u-uid" value="tag:example.com,2024:1"></data><p class="p-name">Hello</p></article>`))
+}
+
+func TestParseMicroformatsFeed_AlternateFeeds(t *testing.T) {
+
+	test := func(link string) bool {
+		body := `<html><head>` + link + `</head><body><div class="h-entry"><p class="p-name">Hello</p></div></body></html>`
+		page, err := parseMicroformatsFeed("https://example.com/", strings.NewReader(body))
+		require.Nil(t, err)
+		return page.HasFeeds
+	}
+
+	require.True(t, test(`<link rel="alternate" type="application/rss+xml" href="/feed.xml">`))
+	require.True(t, test(`<link rel="alternate" type="application/feed+json; charset=utf-8" href="/feed.json">`))
+	require.True(t, test(`<link rel="alternate" type="application/activity+json" href="/actor">`))
+	require.False(t, test(`<link rel="alternate" hreflang="fr" href="/fr/">`))
+	require.False(t, test(``))
+}
+
+func TestLoadMicroformatsFeed_Pagination(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		switch r.URL.Path {
+
+		case "/":
+			w.Write([]byte(`<link rel="next" href="/page/2"><div class="h-feed"><article class="h-entry"><a class="u-url p-name" href="/posts/3">Three</a></article></div>`)) // nolint:errcheck
+
+		case "/page/2":
+			w.Write([]byte(`<link rel="next" href="/page/3"><div class="h-feed"><article class="h-entry"><a class="u-url p-name" href="/posts/2">Two</a></article></div>`)) // nolint:errcheck
+
+		case "/page/3":
+			// Links back to the first page, which should not be loaded again
+			w.Write([]byte(`<link rel="next" href="/"><div class="h-feed"><article class="h-entry"><a class="u-url p-name" href="/posts/1">One</a></article></div>`)) // nolint:errcheck
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	items, ok := loadMicroformatsFeed(server.URL + "/")
+	require.True(t, ok)
+	require.Equal(t, microformatsMaxPages, len(items))
+	require.Equal(t, server.URL+"/posts/3", items[0].GetString(vocab.PropertyID))
+	require.Equal(t, server.URL+"/posts/1", items[2].GetString(vocab.PropertyID))
+}
+
+func TestLoadMicroformatsFeed_NotHTML(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title></channel></rss>`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	_, ok := loadMicroformatsFeed(server.URL + "/feed.xml")
+	require.False(t, ok)
+}
+
+func TestApplyImportProperties(t *testing.T) {
+
+	item := mapof.Any{
+		vocab.PropertyID:      "https://example.com/posts/1",
+		vocab.PropertyName:    "Name",
+		vocab.PropertySummary: "Summary",
+		vocab.PropertyContent: "<p>Content</p>",
+		vocab.PropertyImage:   "https://example.com/photo.jpg",
+	}
+
+	following := model.NewFollowing()
+	following.ImportProperties = []string{model.FollowingImportName, model.FollowingImportSummary}
+
+	applyImportProperties(&following, item)
+
+	require.Equal(t, "https://example.com/posts/1", item.GetString(vocab.PropertyID))
+	require.Equal(t, "Name", item.GetString(vocab.PropertyName))
+	require.Equal(t, "Summary", item.GetString(vocab.PropertySummary))
+	require.Empty(t, item.GetString(vocab.PropertyContent))
+	require.Empty(t, item.GetString(vocab.PropertyImage))
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/opml"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// opmlMaxFeeds is the maximum number of feeds that can be imported from a single OPML document
+const opmlMaxFeeds = 1000
+
+// opmlDefaultFolder is the label of the Folder that is created for uncategorized feeds
+// when the User does not have any Folders yet.
+const opmlDefaultFolder = "Imported"
+
+// ImportOPML creates a Following for every feed in an OPML document.  Feeds are placed into the
+// Folder that matches their top-level category (creating Folders as needed) and uncategorized feeds
+// are placed into the User's first Folder.  Feeds that the User already follows are skipped, and new
+// Followings are connected in the background queue.  It returns the number of Followings created.
+func (service *Following) ImportOPML(userID primitive.ObjectID, reader io.Reader) (int, error) {
+
+	const location = "service.Following.ImportOPML"
+
+	document, err := opml.Parse(reader)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error parsing OPML document")
+	}
+
+	folders, err := newOPMLFolders(service.folderService, userID)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error loading folders", userID)
+	}
+
+	count := 0
+	processed := 0
+
+	for _, category := range document.Body.Outlines {
+
+		// Feeds at the top level are not in any category
+		categoryLabel := ""
+
+		if !category.IsFeed() {
+			categoryLabel = category.Label()
+		}
+
+		for _, feed := range category.Feeds() {
+
+			// RULE: Limit the size of each import
+			if processed >= opmlMaxFeeds {
+				break
+			}
+
+			processed++
+
+			created, err := service.importOPML_Feed(userID, folders, categoryLabel, feed)
+
+			if err != nil {
+				return count, derp.Wrap(err, location, "Error importing feed", feed.XMLURL)
+			}
+
+			if created {
+				count++
+			}
+		}
+	}
+
+	// Recalculate the follower count for this user
+	if count > 0 {
+		go service.userService.CalcFollowingCount(userID)
+	}
+
+	return count, nil
+}
+
+// importOPML_Feed creates a single Following from an OPML outline.  It returns TRUE if
+// a new Following was created, or FALSE if the feed was skipped.
+func (service *Following) importOPML_Feed(userID primitive.ObjectID, folders *opmlFolders, categoryLabel string, feed opml.Outline) (bool, error) {
+
+	feedURL := strings.TrimSpace(feed.XMLURL)
+
+	// RULE: Only import web URLs
+	if parsedURL, err := url.Parse(feedURL); (err != nil) || ((parsedURL.Scheme != "http") && (parsedURL.Scheme != "https")) {
+		return false, nil
+	}
+
+	return service.importFollowing(userID, folders, categoryLabel, feedURL, feed.Label(), "Imported from OPML")
+}
+
+// importFollowing creates a new Following for a URL in the Folder that matches categoryLabel,
+// and connects to it in the background.  It returns FALSE if the User already follows this URL.
+func (service *Following) importFollowing(userID primitive.ObjectID, folders *opmlFolders, categoryLabel string, followingURL string, label string, note string) (bool, error) {
+
+	const location = "service.Following.importFollowing"
+
+	// RULE: Do not replace feeds that the User already follows
+	existing := model.NewFollowing()
+	if err := service.LoadByURL(userID, followingURL, &existing); err == nil {
+		return false, nil
+	} else if !derp.NotFound(err) {
+		return false, derp.Wrap(err, location, "Error searching for existing Following", followingURL)
+	}
+
+	folderID, err := folders.Get(categoryLabel)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Error loading folder", categoryLabel)
+	}
+
+	following := model.NewFollowing()
+	following.UserID = userID
+	following.FolderID = folderID
+	following.URL = followingURL
+	following.Label = label
+
+	if following.Label == "" {
+		following.Label = followingURL
+	}
+
+	// RULE: Labels are limited to 128 characters
+	if label := []rune(following.Label); len(label) > 128 {
+		following.Label = string(label[:128])
+	}
+
+	if err := service.save(&following, note); err != nil {
+		return false, derp.Wrap(err, location, "Error saving Following", following)
+	}
+
+	// Connect to the remote server in the background
+	service.queue.Push(NewTaskConnectFollowing(service, following))
+
+	return true, nil
+}
+
+// ExportOPML writes an OPML document that lists all of the User's Followings,
+// grouped into outlines for each Folder.
+func (service *Following) ExportOPML(user *model.User, writer io.Writer) error {
+
+	const location = "service.Following.ExportOPML"
+
+	folders, err := service.folderService.QueryByUserID(user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading folders", user.UserID)
+	}
+
+	followings, err := service.QueryByUser(user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading followings", user.UserID)
+	}
+
+	// Group Followings by Folder
+	byFolder := make(map[primitive.ObjectID][]opml.Outline)
+
+	for _, following := range followings {
+		byFolder[following.FolderID] = append(byFolder[following.FolderID], opmlOutline(following))
+	}
+
+	document := opml.New(user.DisplayName + " Subscriptions")
+	document.Head.OwnerName = user.DisplayName
+	document.Head.DateCreated = time.Now().Format(time.RFC1123Z)
+
+	// Add an outline for each Folder (in order)
+	for _, folder := range folders {
+
+		if outlines, ok := byFolder[folder.FolderID]; ok {
+			document.Body.Outlines = append(document.Body.Outlines, opml.Outline{
+				Text:     folder.Label,
+				Title:    folder.Label,
+				Outlines: outlines,
+			})
+
+			delete(byFolder, folder.FolderID)
+		}
+	}
+
+	// Followings in missing Folders are not in any category
+	for _, following := range followings {
+		if _, ok := byFolder[following.FolderID]; ok {
+			document.Body.Outlines = append(document.Body.Outlines, opmlOutline(following))
+		}
+	}
+
+	if err := document.Write(writer); err != nil {
+		return derp.Wrap(err, location, "Error writing OPML document")
+	}
+
+	return nil
+}
+
+// opmlOutline returns the OPML outline for a single Following
+func opmlOutline(following model.FollowingSummary) opml.Outline {
+	return opml.Outline{
+		Type:   "rss",
+		Text:   following.Label,
+		Title:  following.Label,
+		XMLURL: following.URL,
+	}
+}
+
+/******************************************
+ * OPML Folders
+ ******************************************/
+
+// opmlFolders finds (or creates) the Folders used by an OPML import
+type opmlFolders struct {
+	folderService *Folder
+	userID        primitive.ObjectID
+	folders       []model.Folder
+}
+
+// newOPMLFolders loads all of a User's existing Folders
+func newOPMLFolders(folderService *Folder, userID primitive.ObjectID) (*opmlFolders, error) {
+
+	folders, err := folderService.QueryByUserID(userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.newOPMLFolders", "Error loading folders", userID)
+	}
+
+	return &opmlFolders{
+		folderService: folderService,
+		userID:        userID,
+		folders:       folders,
+	}, nil
+}
+
+// Get returns the ID of the Folder that matches the provided label, creating it if necessary.
+// An empty label returns the User's first Folder.
+func (folders *opmlFolders) Get(label string) (primitive.ObjectID, error) {
+
+	// Uncategorized feeds go into the first folder
+	if label == "" {
+
+		if len(folders.folders) > 0 {
+			return folders.folders[0].FolderID, nil
+		}
+
+		label = opmlDefaultFolder
+	}
+
+	// RULE: Folder labels are limited to 100 characters
+	if runes := []rune(label); len(runes) > 100 {
+		label = string(runes[:100])
+	}
+
+	// Find an existing folder (case-insensitive)
+	for _, folder := range folders.folders {
+		if strings.EqualFold(folder.Label, label) {
+			return folder.FolderID, nil
+		}
+	}
+
+	// Otherwise, create a new folder
+	folder := model.NewFolder()
+	folder.UserID = folders.userID
+	folder.Label = label
+	folder.Layout = model.FolderLayoutNewspaper
+	folder.Rank = len(folders.folders)
+
+	if err := folders.folderService.Save(&folder, "Imported from OPML"); err != nil {
+		return primitive.NilObjectID, derp.Wrap(err, "service.opmlFolders.Get", "Error creating folder", label)
+	}
+
+	folders.folders = append(folders.folders, folder)
+	return folder.FolderID, nil
+}
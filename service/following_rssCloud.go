@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/remote"
+	"github.com/benpate/sherlock"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// rssCloudNotifyResult is the response returned by an rssCloud server to a "pleaseNotify" request
+type rssCloudNotifyResult struct {
+	XMLName xml.Name `xml:"notifyResult"`
+	Success bool     `xml:"success,attr"`
+	Message string   `xml:"msg,attr"`
+}
+
+// connect_RSSCloud tries to subscribe to the rssCloud server advertised by an RSS feed.
+// https://www.rssboard.org/rsscloud-interface
+func (service *Following) connect_RSSCloud(following *model.Following, actor *streams.Document) (bool, error) {
+
+	const location = "service.Following.connect_RSSCloud"
+
+	// RSS feeds use the URL of the feed document itself
+	feedURL := actor.URL()
+
+	if feedURL == "" {
+		return false, nil
+	}
+
+	// Download the feed so that we can look for a <cloud> element
+	var body string
+
+	if err := remote.Get(feedURL).Header("Accept", "application/rss+xml, application/xml; q=0.9, text/xml; q=0.8").Result(&body).Send(); err != nil {
+		return false, derp.Wrap(err, location, "Error loading RSS feed", feedURL)
+	}
+
+	endpoint, ok := rssCloudEndpoint(strings.NewReader(body))
+
+	if !ok {
+		return false, nil
+	}
+
+	// Describe where notifications should be sent
+	callbackURL, err := url.Parse(service.rssCloudCallbackURL(following))
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Invalid callback URL", following)
+	}
+
+	port := callbackURL.Port()
+
+	if port == "" {
+		if callbackURL.Scheme == "https" {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+
+	// Send the request to the rssCloud server.  Servers do not reliably set a
+	// Content-Type header, so the response is parsed manually.
+	var response string
+	var result rssCloudNotifyResult
+
+	transaction := remote.Post(endpoint).
+		Form("notifyProcedure", "").
+		Form("port", port).
+		Form("path", callbackURL.Path).
+		Form("protocol", "http-post").
+		Form("domain", callbackURL.Hostname()).
+		Form("url1", feedURL).
+		Result(&response)
+
+	if err := transaction.Send(); err != nil {
+		return false, derp.Wrap(err, location, "Error sending rssCloud subscription request", endpoint)
+	}
+
+	if err := xml.Unmarshal([]byte(response), &result); err != nil {
+		return false, derp.Wrap(err, location, "Error parsing rssCloud response", endpoint, response)
+	}
+
+	if !result.Success {
+		return false, derp.NewBadRequestError(location, "rssCloud subscription request was rejected", endpoint, result.Message)
+	}
+
+	// Update values in the following object.  rssCloud subscriptions expire after 25 hours,
+	// so poll (and re-subscribe) twice per day.
+	following.Method = model.FollowMethodRssCloud
+	following.PollDuration = 12
+
+	if err := service.SetStatusSuccess(following); err != nil {
+		return false, derp.Wrap(err, location, "Error updating following status", following)
+	}
+
+	// Success!
+	return true, nil
+}
+
+// ReceiveRSSCloud handles an rssCloud notification by reloading the feed and
+// saving any new items into the User's inbox.
+func (service *Following) ReceiveRSSCloud(following model.Following) error {
+
+	const location = "service.Following.ReceiveRSSCloud"
+
+	// Reload the feed, skipping any cached values
+	actor, err := service.activityService.Load(following.URL, sherlock.AsActor(), ascache.WithForceReload(), service.signAs(&following))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading actor", following.URL)
+	}
+
+	// Import any new messages
+	service.connect_LoadMessages(&following, &actor)
+
+	// Mark this Following as up-to-date
+	following.LastPolled = time.Now().Unix()
+
+	if err := service.SetStatusSuccess(&following); err != nil {
+		return derp.Wrap(err, location, "Error updating following status", following)
+	}
+
+	return nil
+}
+
+func (service *Following) rssCloudCallbackURL(following *model.Following) string {
+	return service.host + "/.rsscloud/" + following.UserID.Hex() + "/" + following.FollowingID.Hex()
+}
+
+// rssCloudEndpoint returns the URL of the rssCloud server advertised by an RSS feed.
+// It returns FALSE if the feed does not advertise a compatible rssCloud server.
+func rssCloudEndpoint(body io.Reader) (string, bool) {
+
+	feed, err := (&rss.Parser{}).Parse(body)
+
+	if err != nil {
+		return "", false
+	}
+
+	cloud := feed.Cloud
+
+	// RULE: Only HTTP-POST notifications are supported
+	if (cloud == nil) || (cloud.Domain == "") || (cloud.Protocol != "http-post") {
+		return "", false
+	}
+
+	// Build the URL of the rssCloud server
+	scheme := "http"
+	host := cloud.Domain
+
+	switch cloud.Port {
+
+	case "", "80":
+
+	case "443":
+		scheme = "https"
+
+	default:
+		host = host + ":" + cloud.Port
+	}
+
+	path := cloud.Path
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return scheme + "://" + host + path, true
+}
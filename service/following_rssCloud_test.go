@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSCloudEndpoint(t *testing.T) {
+
+	test := func(cloud string) (string, bool) {
+		body := `<?xml version="1.0"?>
+			<rss version="2.0">
+				<channel>
+					<title>Test Feed</title>
+					<link>https://example.com</link>
+					<description>Testing rssCloud</description>
+					` + cloud + `
+				</channel>
+			</rss>`
+
+		return rssCloudEndpoint(strings.NewReader(body))
+	}
+
+	{
+		endpoint, ok := test(`<cloud domain="rpc.rsscloud.io" port="5337" path="/pleaseNotify" registerProcedure="" protocol="http-post"/>`)
+		require.True(t, ok)
+		require.Equal(t, "http://rpc.rsscloud.io:5337/pleaseNotify", endpoint)
+	}
+
+	{
+		endpoint, ok := test(`<cloud domain="example.com" port="443" path="/@123/rsscloud" registerProcedure="" protocol="http-post"/>`)
+		require.True(t, ok)
+		require.Equal(t, "https://example.com/@123/rsscloud", endpoint)
+	}
+
+	{
+		endpoint, ok := test(`<cloud domain="example.com" port="80" path="RPC2" registerProcedure="" protocol="http-post"/>`)
+		require.True(t, ok)
+		require.Equal(t, "http://example.com/RPC2", endpoint)
+	}
+
+	{
+		// XML-RPC is not supported
+		_, ok := test(`<cloud domain="example.com" port="80" path="/RPC2" registerProcedure="xmlStorageSystem.rssPleaseNotify" protocol="xml-rpc"/>`)
+		require.False(t, ok)
+	}
+
+	{
+		// No cloud at all
+		_, ok := test(``)
+		require.False(t, ok)
+	}
+
+	{
+		// Not an RSS feed
+		_, ok := rssCloudEndpoint(strings.NewReader(`<html><body>Hello</body></html>`))
+		require.False(t, ok)
+	}
+}
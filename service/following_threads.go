@@ -64,14 +64,17 @@ func (service *Following) saveUniqueMessage(message model.Message) error {
 	isReferenceUpdated := previousMessage.AddReference(message.Origin)
 	isStatusUpdated := false
 
+	// If the author has edited the message since we last received it, then rewrite our copy
+	isEdited := previousMessage.ApplyEdits(message)
+
 	// Update the message status to "NEW-REPLIES" so that previously
 	// read messages will show up again in the Inbox.
 	if message.Origin.Type == model.OriginTypeReply {
 		isStatusUpdated = previousMessage.MarkNewReplies()
 	}
 
-	// if the message was updated (from AddReference or MarkNewReplies) then save it.
-	if isReferenceUpdated || isStatusUpdated {
+	// if the message was updated (from AddReference, MarkNewReplies, or ApplyEdits) then save it.
+	if isReferenceUpdated || isStatusUpdated || isEdited {
 		if err := service.inboxService.Save(&previousMessage, "Message Imported"); err != nil {
 			return derp.Wrap(err, location, "Error updating previous message with new origin and status", previousMessage)
 		}
@@ -144,6 +147,11 @@ func getMessage(following *model.Following, document streams.Document, originTyp
 	result.URL = document.ID()
 	result.InReplyTo = document.InReplyTo().ID()
 	result.PublishDate = document.Published().Unix()
+
+	if updated := document.Updated(); !updated.IsZero() {
+		result.EditDate = updated.Unix()
+	}
+
 	result.AddReference(following.Origin(originType))
 
 	return result
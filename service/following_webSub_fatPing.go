@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/convert"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/kr/jsonfeed"
+	"github.com/mmcdole/gofeed"
+	"willnorris.com/go/microformats"
+)
+
+// ReceiveWebSub saves the items included in a WebSub notification (a "fat ping")
+// directly into the User's inbox, without re-downloading the feed.  If the body is
+// empty or cannot be parsed (or none of its items are published on the topic's host)
+// then the feed is re-polled instead.
+func (service *Following) ReceiveWebSub(following model.Following, contentType string, body []byte) error {
+
+	const location = "service.Following.ReceiveWebSub"
+
+	// Try to parse the items that were pushed to us
+	items, err := parseFatPing(&following, contentType, body)
+
+	if err != nil {
+
+		// Fall back to re-polling the entire feed
+		if err := service.Connect(following); err != nil {
+			return derp.Wrap(err, location, "Error connecting to following", following)
+		}
+
+		return nil
+	}
+
+	// Sort the items chronologically so that they're imported in the correct order.
+	sort.Slice(items, func(a int, b int) bool {
+		return items[a].GetInt64(vocab.PropertyPublished) < items[b].GetInt64(vocab.PropertyPublished)
+	})
+
+	// Save each item into the cache and the inbox
+	for _, item := range items {
+
+		document := service.activityService.NewDocument(item)
+		service.activityService.Put(document)
+
+		if err := service.SaveMessage(&following, document, model.OriginTypePrimary); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error saving document to Inbox", item))
+		}
+	}
+
+	// Recalculate Folder unread counts
+	if err := service.folderService.ReCalculateUnreadCountFromFolder(following.UserID, following.FolderID); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error recalculating unread count"))
+	}
+
+	// Mark this Following as up-to-date
+	following.LastPolled = time.Now().Unix()
+
+	if err := service.SetStatusSuccess(&following); err != nil {
+		return derp.Wrap(err, location, "Error updating following status", following)
+	}
+
+	return nil
+}
+
+// parseFatPing converts the body of a WebSub notification into ActivityStreams documents,
+// based on its Content-Type.  It returns an error if the body is empty or unrecognized.
+func parseFatPing(following *model.Following, contentType string, body []byte) ([]mapof.Any, error) {
+
+	const location = "service.parseFatPing"
+
+	topic := following.URL
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, derp.NewBadRequestError(location, "Empty WebSub notification")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var result []mapof.Any
+	var err error
+
+	switch mediaType {
+
+	case "application/feed+json", "application/json":
+		result, err = parseFatPing_JSONFeed(body)
+
+	case "text/html":
+		result, err = parseFatPing_Microformats(following, body)
+
+	case "application/rss+xml", "application/atom+xml", "application/rdf+xml", "application/xml", "text/xml":
+		result, err = parseFatPing_XML(body)
+
+	default:
+		return nil, derp.NewBadRequestError(location, "Unsupported Content-Type", contentType)
+	}
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error parsing WebSub notification", contentType)
+	}
+
+	// Normalize each item, and remove any that we can't use
+	baseURL, _ := url.Parse(topic)
+	items := make([]mapof.Any, 0, len(result))
+
+	for _, item := range result {
+
+		id := item.GetString(vocab.PropertyID)
+
+		if id == "" {
+			continue
+		}
+
+		// Resolve relative URLs
+		if baseURL == nil {
+			continue
+		}
+
+		itemURL, err := baseURL.Parse(id)
+
+		if err != nil {
+			continue
+		}
+
+		// RULE: Items must be published on the same host as the topic.  Otherwise, a feed
+		// could place documents into the shared cache under someone else's ID.
+		if !strings.EqualFold(itemURL.Hostname(), baseURL.Hostname()) {
+			continue
+		}
+
+		item[vocab.PropertyID] = itemURL.String()
+
+		if _, ok := item[vocab.PropertyType]; !ok {
+			item[vocab.PropertyType] = vocab.ObjectTypePage
+		}
+
+		if item.GetInt64(vocab.PropertyPublished) == 0 {
+			item[vocab.PropertyPublished] = time.Now().Unix()
+		}
+
+		item[vocab.PropertyActor] = topic
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return nil, derp.NewBadRequestError(location, "WebSub notification does not contain any items")
+	}
+
+	return items, nil
+}
+
+// parseFatPing_XML parses RSS and Atom notifications
+func parseFatPing_XML(body []byte) ([]mapof.Any, error) {
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.parseFatPing_XML", "Error parsing RSS/Atom feed")
+	}
+
+	result := make([]mapof.Any, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		result = append(result, convert.RSSToActivity(feed, item))
+	}
+
+	return result, nil
+}
+
+// parseFatPing_JSONFeed parses JSON Feed notifications
+func parseFatPing_JSONFeed(body []byte) ([]mapof.Any, error) {
+
+	var feed jsonfeed.Feed
+
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, derp.Wrap(err, "service.parseFatPing_JSONFeed", "Error parsing JSON Feed")
+	}
+
+	result := make([]mapof.Any, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		result = append(result, convert.JsonFeedToActivityMap(feed, item))
+	}
+
+	return result, nil
+}
+
+// parseFatPing_Microformats parses h-feed and h-entry notifications, keeping only
+// the content properties that the Following has chosen to import.
+func parseFatPing_Microformats(following *model.Following, body []byte) ([]mapof.Any, error) {
+
+	baseURL, err := url.Parse(following.URL)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.parseFatPing_Microformats", "Invalid topic URL", following.URL)
+	}
+
+	data := microformats.Parse(bytes.NewReader(body), baseURL)
+	result := microformatsToActivityMaps(following.URL, data)
+
+	for _, item := range result {
+		applyImportProperties(following, item)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFatPing_RSS(t *testing.T) {
+
+	body := `<?xml version="1.0"?>
+	<rss version="2.0"><channel>
+		<title>Example Feed</title>
+		<link>https://example.com/</link>
+		<item>
+			<title>Second Post</title>
+			<link>https://example.com/2</link>
+			<pubDate>Tue, 02 Jan 2024 10:00:00 GMT</pubDate>
+			<description>Second summary</description>
+		</item>
+		<item>
+			<title>First Post</title>
+			<link>/1</link>
+			<pubDate>Mon, 01 Jan 2024 10:00:00 GMT</pubDate>
+		</item>
+	</channel></rss>`
+
+	items, err := parseFatPing(fatPingFollowing("https://example.com/feed"), "application/rss+xml; charset=utf-8", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(items))
+	require.Equal(t, "https://example.com/2", items[0].GetString(vocab.PropertyID))
+	require.Equal(t, "Second Post", items[0].GetString(vocab.PropertyName))
+	require.Equal(t, "https://example.com/1", items[1].GetString(vocab.PropertyID))
+	require.Equal(t, "https://example.com/feed", items[1].GetString(vocab.PropertyActor))
+	require.Equal(t, vocab.ObjectTypePage, items[1].GetString(vocab.PropertyType))
+}
+
+func TestParseFatPing_Atom(t *testing.T) {
+
+	body := `<?xml version="1.0" encoding="utf-8"?>
+	<feed xmlns="http://www.w3.org/2005/Atom">
+		<title>Example Feed</title>
+		<entry>
+			<title>Atom Post</title>
+			<link href="https://example.com/atom/1"/>
+			<updated>2024-01-01T10:00:00Z</updated>
+			<content type="html">&lt;p&gt;Hello&lt;/p&gt;</content>
+		</entry>
+	</feed>`
+
+	items, err := parseFatPing(fatPingFollowing("https://example.com/feed"), "application/atom+xml", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "https://example.com/atom/1", items[0].GetString(vocab.PropertyID))
+	require.Equal(t, "<p>Hello</p>", items[0].GetString(vocab.PropertyContent))
+}
+
+func TestParseFatPing_JSONFeed(t *testing.T) {
+
+	body := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Example Feed",
+		"items": [
+			{"id": "1", "url": "https://example.com/json/1", "title": "JSON Post", "content_text": "Hello", "date_published": "2024-01-01T10:00:00Z"}
+		]
+	}`
+
+	items, err := parseFatPing(fatPingFollowing("https://example.com/feed"), "application/feed+json", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "https://example.com/json/1", items[0].GetString(vocab.PropertyID))
+	require.Equal(t, "JSON Post", items[0].GetString(vocab.PropertyName))
+	require.Equal(t, int64(1704103200), items[0].GetInt64(vocab.PropertyPublished))
+}
+
+func TestParseFatPing_HFeed(t *testing.T) {
+
+	body := `<div class="h-feed">
+		<div class="p-author h-card"><a class="p-name u-url" href="https://example.com/">Author</a></div>
+		<article class="h-entry">
+			<a class="u-url p-name" href="/notes/1">A Note</a>
+			<time class="dt-published" datetime="2024-01-01T10:00:00Z">Jan 1</time>
+			<div class="e-content"><p>Hello</p></div>
+		</article>
+	</div>`
+
+	items, err := parseFatPing(fatPingFollowing("https://example.com/"), "text/html; charset=utf-8", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "https://example.com/notes/1", items[0].GetString(vocab.PropertyID))
+	require.Equal(t, "A Note", items[0].GetString(vocab.PropertyName))
+	require.Equal(t, "<p>Hello</p>", items[0].GetString(vocab.PropertyContent))
+	require.Equal(t, "Author", items[0].GetMap(vocab.PropertyAttributedTo).GetString(vocab.PropertyName))
+}
+
+func TestParseFatPing_HFeed_ImportProperties(t *testing.T) {
+
+	body := `<div class="h-feed">
+		<article class="h-entry">
+			<a class="u-url p-name" href="/notes/1">A Note</a>
+			<time class="dt-published" datetime="2024-01-01T10:00:00Z">Jan 1</time>
+			<div class="e-content"><p>Hello</p></div>
+		</article>
+	</div>`
+
+	following := fatPingFollowing("https://example.com/")
+	following.ImportProperties = []string{model.FollowingImportName}
+
+	items, err := parseFatPing(following, "text/html", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "A Note", items[0].GetString(vocab.PropertyName))
+	require.Empty(t, items[0].GetString(vocab.PropertyContent))
+}
+
+func TestParseFatPing_Fallback(t *testing.T) {
+
+	// Empty bodies
+	_, err := parseFatPing(fatPingFollowing("https://example.com/feed"), "application/rss+xml", []byte("  "))
+	require.NotNil(t, err)
+
+	// Unknown content types
+	_, err = parseFatPing(fatPingFollowing("https://example.com/feed"), "application/octet-stream", []byte("binary"))
+	require.NotNil(t, err)
+
+	// Unparseable bodies
+	_, err = parseFatPing(fatPingFollowing("https://example.com/feed"), "application/feed+json", []byte("not json"))
+	require.NotNil(t, err)
+
+	// Feeds without items
+	_, err = parseFatPing(fatPingFollowing("https://example.com/"), "text/html", []byte("<p>Nothing here</p>"))
+	require.NotNil(t, err)
+}
+
+func TestParseFatPing_OtherHost(t *testing.T) {
+
+	body := `<?xml version="1.0"?>
+	<rss version="2.0"><channel>
+		<title>Example Feed</title>
+		<item><title>Local Post</title><link>https://example.com/1</link></item>
+		<item><title>Forged Post</title><link>https://other.example.net/1</link></item>
+	</channel></rss>`
+
+	// Items from other hosts are removed
+	items, err := parseFatPing(fatPingFollowing("https://example.com/feed"), "application/rss+xml", []byte(body))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "https://example.com/1", items[0].GetString(vocab.PropertyID))
+
+	// If no items remain, then the feed must be re-polled
+	_, err = parseFatPing(fatPingFollowing("https://another.example.org/feed"), "application/rss+xml", []byte(body))
+	require.NotNil(t, err)
+}
+
+// fatPingFollowing returns a Following that subscribes to the provided topic URL
+func fatPingFollowing(topic string) *model.Following {
+	following := model.NewFollowing()
+	following.URL = topic
+	return &following
+}
@@ -194,6 +194,23 @@ func (service *Group) ListByIDs(groupIDs ...primitive.ObjectID) ([]model.Group,
 	return result, nil
 }
 
+// RequiresTwoFactor returns TRUE if any of the provided Groups requires its members to use two-factor authentication
+func (service *Group) RequiresTwoFactor(groupIDs ...primitive.ObjectID) (bool, error) {
+
+	if len(groupIDs) == 0 {
+		return false, nil
+	}
+
+	criteria := exp.In("_id", groupIDs).AndEqual("requireTwoFactor", true)
+	count, err := service.Count(criteria)
+
+	if err != nil {
+		return false, derp.Wrap(err, "service.Group.RequiresTwoFactor", "Error counting groups", groupIDs)
+	}
+
+	return count > 0, nil
+}
+
 // LoadByGroupname loads a single Group object that matches the provided token
 func (service *Group) LoadByToken(token string, result *model.Group) error {
 
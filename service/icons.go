@@ -82,6 +82,8 @@ func (service Icons) Get(name string) string {
 		return service.get("trash")
 	case "delete-fill":
 		return service.get("trash-fill")
+	case "download":
+		return service.get("download")
 	case "drag-handle":
 		return service.get("grip-vertical")
 	case "edit":
@@ -118,6 +120,10 @@ func (service Icons) Get(name string) string {
 		return service.get("grip-horizontal")
 	case "hashtag":
 		return service.get("hash")
+	case "history":
+		return service.get("clock-history")
+	case "history-fill":
+		return service.get("clock-history")
 	case "heart":
 		return service.get("heart")
 	case "heart-fill":
@@ -176,6 +182,10 @@ func (service Icons) Get(name string) string {
 		return service.get("people")
 	case "people-fill":
 		return service.get("people-fill")
+	case "quote":
+		return service.get("chat-quote")
+	case "quote-fill":
+		return service.get("chat-quote-fill")
 	case "reply":
 		return service.get("reply")
 	case "reply-fill":
@@ -240,6 +250,10 @@ func (service Icons) Get(name string) string {
 		return service.get("eye")
 	case "visible-fill":
 		return service.get("eye-fill")
+	case "warning":
+		return service.get("exclamation-triangle")
+	case "warning-fill":
+		return service.get("exclamation-triangle-fill")
 
 		// Layouts
 	case "layout-social":
@@ -267,6 +281,10 @@ func (service Icons) Get(name string) string {
 		return service.get("globe2")
 	case "activitypub-fill":
 		return service.get("globe2")
+	case "bluesky":
+		return service.get("cloud")
+	case "bluesky-fill":
+		return service.get("cloud-fill")
 	case "facebook":
 		return service.get("facebook")
 	case "github":
@@ -279,6 +297,8 @@ func (service Icons) Get(name string) string {
 		return service.get("braces")
 	case "instagram":
 		return service.get("instagram")
+	case "mastodon":
+		return service.get("mastodon")
 	case "twitter":
 		return service.get("twitter")
 	case "rss":
@@ -297,6 +317,10 @@ func (service Icons) Get(name string) string {
 		return service.get("cloud-arrow-down")
 	case "websub-fill":
 		return service.get("cloud-arrow-down-fill")
+	case "webhook":
+		return service.get("send")
+	case "webhook-fill":
+		return service.get("send-fill")
 
 	// Content Types
 	case "article":
@@ -517,6 +517,34 @@ func (service *Inbox) DeleteByOrigin(internalID primitive.ObjectID, note string)
 	return service.DeleteMany(exp.Equal("origin.followingId", internalID), note)
 }
 
+// UpdateOrigin updates the denormalized label and image of every Message that was received from the
+// provided origin.  Messages are saved directly so that their rank and read status are not changed.
+func (service *Inbox) UpdateOrigin(origin model.OriginLink) error {
+
+	const location = "service.Inbox.UpdateOrigin"
+
+	it, err := service.List(exp.Equal("origin.followingId", origin.FollowingID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing messages", origin)
+	}
+
+	message := model.NewMessage()
+	for it.Next(&message) {
+
+		message.Origin.Label = origin.Label
+		message.Origin.ImageURL = origin.ImageURL
+
+		if err := service.collection.Save(&message, "Origin updated"); err != nil {
+			return derp.Wrap(err, location, "Error saving message", message)
+		}
+
+		message = model.NewMessage()
+	}
+
+	return nil
+}
+
 func (service *Inbox) DeleteByFolder(userID primitive.ObjectID, folderID primitive.ObjectID) error {
 
 	it, err := service.ListByFolder(userID, folderID)
@@ -536,6 +564,16 @@ func (service *Inbox) DeleteByFolder(userID primitive.ObjectID, folderID primiti
 	return nil
 }
 
+// DeleteByUser permanently removes all of the Messages in a User's Inbox (hard delete)
+func (service *Inbox) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.Inbox.DeleteByUser", "Error deleting Messages", userID)
+	}
+
+	return nil
+}
+
 // QueryPurgeable returns a list of Inboxs that are older than the purge date for this following
 // TODO: HIGH: ReadDate is gone.  Need another way to purge messages.
 func (service *Inbox) QueryPurgeable(following *model.Following) ([]model.Message, error) {
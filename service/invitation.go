@@ -0,0 +1,241 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/queries"
+	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// invitationMaxNoteLength is the maximum number of characters in an Invitation note
+const invitationMaxNoteLength = 100
+
+// Invitation manages all interactions with the Invitation collection
+type Invitation struct {
+	collection    data.Collection
+	domainService *Domain
+	host          string
+}
+
+// NewInvitation returns a fully populated Invitation service
+func NewInvitation() Invitation {
+	return Invitation{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Invitation) Refresh(collection data.Collection, domainService *Domain, host string) {
+	service.collection = collection
+	service.domainService = domainService
+	service.host = host
+}
+
+// Close stops any background processes controlled by this service
+func (service *Invitation) Close() {
+
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns an slice containing all of the Invitations that match the provided criteria
+func (service *Invitation) Query(criteria exp.Expression, options ...option.Option) ([]model.Invitation, error) {
+	result := make([]model.Invitation, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves an Invitation from the database
+func (service *Invitation) Load(criteria exp.Expression, invitation *model.Invitation) error {
+
+	if err := service.collection.Load(notDeleted(criteria), invitation); err != nil {
+		return derp.Wrap(err, "service.Invitation.Load", "Error loading Invitation", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates an Invitation in the database
+func (service *Invitation) Save(invitation *model.Invitation, note string) error {
+
+	if err := service.collection.Save(invitation, note); err != nil {
+		return derp.Wrap(err, "service.Invitation.Save", "Error saving Invitation", invitation, note)
+	}
+
+	return nil
+}
+
+// Delete removes an Invitation from the database (virtual delete)
+func (service *Invitation) Delete(invitation *model.Invitation, note string) error {
+
+	if err := service.collection.Delete(invitation, note); err != nil {
+		return derp.Wrap(err, "service.Invitation.Delete", "Error deleting Invitation", invitation, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns all of the Invitations created by a User, newest first
+func (service *Invitation) QueryByUser(userID primitive.ObjectID) ([]model.Invitation, error) {
+	criteria := exp.Equal("userId", userID)
+	return service.Query(criteria, option.SortDesc("createDate"))
+}
+
+// QueryAll returns all of the Invitations on this domain, newest first
+func (service *Invitation) QueryAll() ([]model.Invitation, error) {
+	return service.Query(exp.All(), option.SortDesc("createDate"))
+}
+
+// LoadByID loads the Invitation with the provided ID
+func (service *Invitation) LoadByID(invitationID primitive.ObjectID, invitation *model.Invitation) error {
+	criteria := exp.Equal("_id", invitationID)
+	return service.Load(criteria, invitation)
+}
+
+// LoadByCode loads an Invitation that can still be used to sign up.  It returns a
+// "not found" error if the code does not match a usable Invitation.
+func (service *Invitation) LoadByCode(code string, invitation *model.Invitation) error {
+
+	const location = "service.Invitation.LoadByCode"
+
+	if code == "" {
+		return derp.NewNotFoundError(location, "Invitation code is required")
+	}
+
+	if err := service.Load(exp.Equal("code", code), invitation); err != nil {
+		return derp.Wrap(err, location, "Error loading Invitation")
+	}
+
+	if !invitation.IsUsable() {
+		return derp.NewNotFoundError(location, "Invitation has expired", invitation.InvitationID)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// CanCreate returns TRUE if the provided User is allowed to create new Invitations.
+// Domain owners can always invite people.  Other users can only invite people if the
+// domain's signup form is active and allows it.
+func (service *Invitation) CanCreate(authorization *model.Authorization) bool {
+
+	if authorization.DomainOwner {
+		return true
+	}
+
+	if authorization.UserID.IsZero() {
+		return false
+	}
+
+	signupForm := service.domainService.Get().SignupForm
+	return signupForm.Active && signupForm.AllowUserInvites
+}
+
+// Create makes a new Invitation for the provided User.  If maxUses is zero, then the Invitation
+// can be used any number of times.  If duration is zero, then the Invitation never expires.
+func (service *Invitation) Create(userID primitive.ObjectID, note string, maxUses int, duration time.Duration) (model.Invitation, error) {
+
+	const location = "service.Invitation.Create"
+
+	if maxUses < 0 {
+		return model.Invitation{}, derp.NewBadRequestError(location, "Maximum uses cannot be negative", maxUses)
+	}
+
+	code, err := random.GenerateString(32)
+
+	if err != nil {
+		return model.Invitation{}, derp.Wrap(err, location, "Error generating invitation code")
+	}
+
+	invitation := model.NewInvitation()
+	invitation.UserID = userID
+	invitation.Code = code
+	invitation.Note = invitationNote(note)
+	invitation.MaxUses = maxUses
+
+	if duration > 0 {
+		invitation.ExpireDate = time.Now().Add(duration).Unix()
+	}
+
+	if err := service.Save(&invitation, "Created"); err != nil {
+		return model.Invitation{}, derp.Wrap(err, location, "Error saving Invitation")
+	}
+
+	return invitation, nil
+}
+
+// Redeem uses up one of the Invitation's remaining uses for a new account.  The use is counted with a
+// single conditional update, so concurrent signups cannot use an Invitation more times than it allows.
+func (service *Invitation) Redeem(invitation *model.Invitation) error {
+
+	const location = "service.Invitation.Redeem"
+
+	redeemed, err := queries.InvitationRedeem(service.collection, invitation.InvitationID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error redeeming Invitation", invitation.InvitationID)
+	}
+
+	if !redeemed {
+		return derp.NewForbiddenError(location, "This invitation has expired or has already been used", invitation.InvitationID)
+	}
+
+	invitation.UseCount++
+	return nil
+}
+
+// Release returns a use to the Invitation when the account it was redeemed for could not be created
+func (service *Invitation) Release(invitation *model.Invitation) error {
+
+	if err := queries.InvitationRelease(service.collection, invitation.InvitationID); err != nil {
+		return derp.Wrap(err, "service.Invitation.Release", "Error releasing Invitation", invitation.InvitationID)
+	}
+
+	invitation.UseCount--
+	return nil
+}
+
+// DeleteByUser permanently removes all of the Invitations that a User created (hard delete)
+func (service *Invitation) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.Invitation.DeleteByUser", "Error deleting Invitations", userID)
+	}
+
+	return nil
+}
+
+// URL returns the link that new people use to sign up with this Invitation
+func (service *Invitation) URL(invitation *model.Invitation) string {
+	return service.host + "/register?invite=" + invitation.Code
+}
+
+// invitationNote returns a trimmed note for a new Invitation
+func invitationNote(note string) string {
+
+	runes := []rune(strings.TrimSpace(note))
+
+	if len(runes) > invitationMaxNoteLength {
+		runes = runes[:invitationMaxNoteLength]
+	}
+
+	return string(runes)
+}
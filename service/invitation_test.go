@@ -0,0 +1,14 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationNote(t *testing.T) {
+	require.Equal(t, "For my cousin", invitationNote("  For my cousin "))
+	require.Equal(t, "", invitationNote("   "))
+	require.Equal(t, invitationMaxNoteLength, len([]rune(invitationNote(strings.Repeat("é", 200)))))
+}
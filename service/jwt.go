@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/keywrap"
 	"github.com/EmissarySocial/emissary/tools/random"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
@@ -18,9 +19,10 @@ import (
 
 // JWT is a service that generates and validates JWT keys.
 type JWT struct {
-	collection       data.Collection       // Database collection where JWT keys are stored
-	cache            *ccache.Cache[[]byte] // In-Memory cache for frequently used keys
-	keyEncryptingKey []byte                // "Key Encrypting Key" used to encode/decode JWT keys that are stored in the collection
+	collection               data.Collection       // Database collection where JWT keys are stored
+	cache                    *ccache.Cache[[]byte] // In-Memory cache for frequently used keys
+	keyEncryptingKey         []byte                // "Key Encrypting Key" used to encode/decode JWT keys that are stored in the collection
+	previousKeyEncryptingKey []byte                // Previous "Key Encrypting Key", only set while the key encrypting key is being rotated
 }
 
 func NewJWT() JWT {
@@ -101,9 +103,13 @@ func (service *JWT) FindJWTKey(token *jwt.Token) (any, error) {
 // Parse retrieves a JWT token from the request, and parses it into a JWT token.
 // This method is a part of the steranko.KeyService interface.
 func (service *JWT) Parse(request *http.Request) (*jwt.Token, error) {
+	return service.ParseString(service.TokenString(request))
+}
+
+// TokenString returns the (unparsed) bearer token from the request's "Authorization" header
+func (service *JWT) TokenString(request *http.Request) string {
 	authorization := request.Header.Get("Authorization")
-	authorization = strings.TrimPrefix(authorization, "Bearer ")
-	return service.ParseString(authorization)
+	return strings.TrimPrefix(authorization, "Bearer ")
 }
 
 func (service *JWT) ParseString(tokenString string) (*jwt.Token, error) {
@@ -197,35 +203,121 @@ func (service *JWT) save(jwtKey *model.JWTKey) error {
  * Encryption Methods
  ******************************************/
 
-// encrypt encrypts the plaintext field of the JWTKey
-// and stores the result in the encryptedValue field.
-func (service *JWT) encrypt(jwtKey *model.JWTKey) error {
+// SetKeyEncryptingKeys updates the key encrypting keys used by this service.  New values are always
+// encrypted with the current key.  The previous key is only set while the key encrypting key is being
+// rotated, so that values which have not been re-encrypted yet can still be decrypted.
+func (service *JWT) SetKeyEncryptingKeys(keyEncryptingKey []byte, previousKeyEncryptingKey []byte) {
+	service.keyEncryptingKey = keyEncryptingKey
+	service.previousKeyEncryptingKey = previousKeyEncryptingKey
+}
 
-	// Create an AES cipher
-	cipher, err := aes.NewCipher(service.keyEncryptingKey)
+// Rewrap encrypts every JWT key with the current key encrypting key.  Keys that are already
+// encrypted with the current key encrypting key are skipped, so this can safely be run again
+// if it is interrupted.
+func (service *JWT) Rewrap() error {
+
+	const location = "service.JWT.Rewrap"
+
+	it, err := service.collection.Iterator(exp.All())
 
 	if err != nil {
-		return derp.Wrap(err, "service.JWT.encrypt", "Error creating AES cipher")
+		return derp.Wrap(err, location, "Error listing JWT Keys")
+	}
+
+	jwtKey := model.NewJWTKey()
+
+	for it.Next(&jwtKey) {
+
+		if err := service.rewrap(jwtKey); err != nil {
+			return derp.Wrap(err, location, "Error re-encrypting JWT Key", jwtKey.KeyName)
+		}
+
+		jwtKey = model.NewJWTKey()
+	}
+
+	return nil
+}
+
+// rewrap decrypts a single JWT key with either key encrypting key,
+// then encrypts it with the current key encrypting key and saves it to the database
+func (service *JWT) rewrap(jwtKey model.JWTKey) error {
+
+	const location = "service.JWT.rewrap"
+
+	if jwtKey.Algorithm == keywrap.Algorithm {
+		if _, err := keywrap.Unwrap(service.keyEncryptingKey, jwtKey.EncryptedValue, []byte(jwtKey.KeyName)); err == nil {
+			return nil
+		}
+	}
+
+	if err := service.decrypt(&jwtKey); err != nil {
+		return derp.Wrap(err, location, "Error decrypting JWT Key")
+	}
+
+	if err := service.encrypt(&jwtKey); err != nil {
+		return derp.Wrap(err, location, "Error encrypting JWT Key")
+	}
+
+	if err := service.collection.Save(&jwtKey, "Rotated key encrypting key"); err != nil {
+		return derp.Wrap(err, location, "Error saving JWT Key")
 	}
 
-	// Encrypt the plaintext
-	cipher.Encrypt(jwtKey.EncryptedValue, jwtKey.Plaintext)
-	jwtKey.Algorithm = "AES"
 	return nil
 }
 
+// encrypt encrypts the plaintext field of the JWTKey
+// and stores the result in the encryptedValue field.
+func (service *JWT) encrypt(jwtKey *model.JWTKey) error {
+	return encryptJWTKey(service.keyEncryptingKey, jwtKey)
+}
+
 // decrypt decrypts the encryptedValue field of the JWTKey
 // and stores the result in the plaintext field.
 func (service *JWT) decrypt(jwtKey *model.JWTKey) error {
 
-	// Create an AES cipher
-	cipher, err := aes.NewCipher(service.keyEncryptingKey)
+	// Keys created by earlier versions use a single AES block.  These are re-encrypted
+	// during a rotation, so they always use the previous key encrypting key (if present).
+	if jwtKey.Algorithm == "AES" {
+
+		keyEncryptingKey := service.keyEncryptingKey
+
+		if len(service.previousKeyEncryptingKey) > 0 {
+			keyEncryptingKey = service.previousKeyEncryptingKey
+		}
+
+		// Create an AES cipher
+		cipher, err := aes.NewCipher(keyEncryptingKey)
+
+		if err != nil {
+			return derp.Wrap(err, "service.JWT.decrypt", "Error creating AES cipher")
+		}
+
+		// Decrypt the key in memory
+		cipher.Decrypt(jwtKey.Plaintext, jwtKey.EncryptedValue)
+		return nil
+	}
+
+	plaintext, err := keywrap.UnwrapAny([][]byte{service.keyEncryptingKey, service.previousKeyEncryptingKey}, jwtKey.EncryptedValue, []byte(jwtKey.KeyName))
 
 	if err != nil {
-		return derp.Wrap(err, "service.JWT.decrypt", "Error creating AES cipher")
+		return derp.Wrap(err, "service.JWT.decrypt", "Error decrypting JWT Key")
 	}
 
-	// Decrypt the key in memory
-	cipher.Decrypt(jwtKey.Plaintext, jwtKey.EncryptedValue)
+	jwtKey.Plaintext = plaintext
+	return nil
+}
+
+// encryptJWTKey encrypts the plaintext field of the JWTKey with the provided key encrypting key.
+// The KeyName is used as additional data, so encrypted values cannot be moved from one key to another.
+func encryptJWTKey(keyEncryptingKey []byte, jwtKey *model.JWTKey) error {
+
+	ciphertext, err := keywrap.Wrap(keyEncryptingKey, jwtKey.Plaintext, []byte(jwtKey.KeyName))
+
+	if err != nil {
+		return derp.Wrap(err, "service.encryptJWTKey", "Error encrypting JWT Key")
+	}
+
+	jwtKey.EncryptedValue = ciphertext
+	jwtKey.Algorithm = keywrap.Algorithm
 	return nil
 }
@@ -78,3 +78,32 @@ func TestJWTResetCache(t *testing.T) {
 	require.Equal(t, value1, value2)
 
 }
+
+func TestJWTRotateKeyEncryptingKey(t *testing.T) {
+
+	// Set up mock server and session
+	server := mockdb.New()
+	session, err := server.Session(context.TODO())
+	require.Nil(t, err)
+
+	collection := session.Collection("test")
+	service := NewJWT()
+	service.Refresh(collection, []byte("0123456789ABCDEF0123456789ABCDEF"))
+
+	// Create Key1
+	name1, value1 := service.NewJWTKey()
+
+	// Re-encrypt all keys with a new key encrypting key
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), []byte("0123456789ABCDEF0123456789ABCDEF"))
+	require.Nil(t, service.Rewrap())
+	service.SetKeyEncryptingKeys([]byte("FEDCBA9876543210FEDCBA9876543210"), nil)
+
+	// Clear everything from the cache
+	// so we have to go to the database
+	service.cache.Clear()
+
+	// The key should still decrypt to the same value
+	name2, value2 := service.NewJWTKey()
+	require.Equal(t, name1, name2)
+	require.Equal(t, value1, value2)
+}
@@ -8,18 +8,20 @@ import (
 )
 
 type LookupProvider struct {
-	themeService  *Theme
-	groupService  *Group
-	folderService *Folder
-	userID        primitive.ObjectID
+	themeService    *Theme
+	templateService *Template
+	groupService    *Group
+	folderService   *Folder
+	userID          primitive.ObjectID
 }
 
-func NewLookupProvider(themeService *Theme, groupService *Group, folderService *Folder, userID primitive.ObjectID) LookupProvider {
+func NewLookupProvider(themeService *Theme, templateService *Template, groupService *Group, folderService *Folder, userID primitive.ObjectID) LookupProvider {
 	return LookupProvider{
-		themeService:  themeService,
-		groupService:  groupService,
-		folderService: folderService,
-		userID:        userID,
+		themeService:    themeService,
+		templateService: templateService,
+		groupService:    groupService,
+		folderService:   folderService,
+		userID:          userID,
 	}
 }
 
@@ -33,6 +35,14 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Value: "POSTS", Label: "Posts Only (ignore replies)"},
 		)
 
+	case "following-import-properties":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.FollowingImportName, Label: "Title"},
+			form.LookupCode{Value: model.FollowingImportSummary, Label: "Summary"},
+			form.LookupCode{Value: model.FollowingImportContent, Label: "Full Content"},
+			form.LookupCode{Value: model.FollowingImportPhoto, Label: "Photo"},
+		)
+
 	case "following-rule-actions":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Value: "IGNORE", Label: "Do not import rules from this source (display messages normally)"},
@@ -55,6 +65,29 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Label: "Filter by Tags & Keywords", Value: model.RuleTypeContent},
 		)
 
+	case "key-rotation-schedules":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: "0", Label: "Never", Description: "Keys are only rotated when you ask"},
+			form.LookupCode{Value: "30", Label: "Every Month"},
+			form.LookupCode{Value: "90", Label: "Every 3 Months"},
+			form.LookupCode{Value: "180", Label: "Every 6 Months"},
+			form.LookupCode{Value: "365", Label: "Every Year"},
+		)
+
+	case "signup-modes":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.SignupModeOpen, Label: "Open", Description: "Anyone can make a new account"},
+			form.LookupCode{Value: model.SignupModeApproval, Label: "Approval Required", Description: "New accounts must be approved before they can sign in"},
+			form.LookupCode{Value: model.SignupModeInvite, Label: "Invitation Only", Description: "People need an invitation link to make a new account"},
+		)
+
+	case "syndication-types":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Label: "Mastodon", Value: model.SyndicationTargetTypeMastodon, Icon: "mastodon", Description: "Post to a Mastodon-compatible account using an access token"},
+			form.LookupCode{Label: "Bluesky", Value: model.SyndicationTargetTypeBluesky, Icon: "bluesky", Description: "Post to a Bluesky account using an app password"},
+			form.LookupCode{Label: "Webhook", Value: model.SyndicationTargetTypeWebhook, Icon: "webhook", Description: "POST a JSON document to any URL"},
+		)
+
 	case "folders":
 		return NewFolderLookupProvider(service.folderService, service.userID)
 
@@ -64,6 +97,9 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 	case "groups":
 		return NewGroupLookupProvider(service.groupService)
 
+	case "outbox-templates":
+		return form.NewReadOnlyLookupGroup(service.templateService.ListByContainer("outbox")...)
+
 	case "reaction-icons":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Label: "Love", Group: "Like", Value: "❤️"},
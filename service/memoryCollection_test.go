@@ -0,0 +1,217 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/compare"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryCollection is an in-memory data.Collection for service tests.  Unlike data-mock,
+// records are stored as BSON documents, so criteria match the same field names (and nested
+// paths) that MongoDB would use.  Sorting and paging options are ignored.
+type memoryCollection struct {
+	ids       []string
+	documents map[string][]byte
+	mutex     *sync.Mutex
+}
+
+func newMemoryCollection() memoryCollection {
+	return memoryCollection{
+		ids:       make([]string, 0),
+		documents: make(map[string][]byte),
+		mutex:     &sync.Mutex{},
+	}
+}
+
+func (collection *memoryCollection) Count(criteria exp.Expression, options ...option.Option) (int64, error) {
+	return int64(len(collection.match(criteria))), nil
+}
+
+func (collection *memoryCollection) Query(target any, criteria exp.Expression, options ...option.Option) error {
+
+	slice := reflect.ValueOf(target).Elem()
+	slice.SetLen(0)
+
+	for _, document := range collection.match(criteria) {
+		item := reflect.New(slice.Type().Elem())
+
+		if err := bson.Unmarshal(document, item.Interface()); err != nil {
+			return derp.Wrap(err, "service.memoryCollection.Query", "Error decoding document")
+		}
+
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+
+	return nil
+}
+
+func (collection *memoryCollection) Iterator(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return &memoryIterator{documents: collection.match(criteria)}, nil
+}
+
+func (collection *memoryCollection) Load(criteria exp.Expression, target data.Object) error {
+
+	documents := collection.match(criteria)
+
+	if len(documents) == 0 {
+		return derp.NewNotFoundError("service.memoryCollection.Load", "Document not found", criteria)
+	}
+
+	return bson.Unmarshal(documents[0], target)
+}
+
+func (collection *memoryCollection) Save(object data.Object, note string) error {
+
+	if object.IsNew() {
+		object.SetCreated(note)
+	} else {
+		object.SetUpdated(note)
+	}
+
+	document, err := bson.Marshal(object)
+
+	if err != nil {
+		return derp.Wrap(err, "service.memoryCollection.Save", "Error encoding document")
+	}
+
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	if _, exists := collection.documents[object.ID()]; !exists {
+		collection.ids = append(collection.ids, object.ID())
+	}
+
+	collection.documents[object.ID()] = document
+	return nil
+}
+
+func (collection *memoryCollection) Delete(object data.Object, note string) error {
+	object.SetDeleted(note)
+	return collection.Save(object, note)
+}
+
+func (collection *memoryCollection) HardDelete(criteria exp.Expression) error {
+
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	ids := make([]string, 0, len(collection.ids))
+
+	for _, id := range collection.ids {
+		if criteria.Match(memoryMatcher(collection.documents[id])) {
+			delete(collection.documents, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	collection.ids = ids
+	return nil
+}
+
+// match returns all documents that match the criteria, in the order they were first saved
+func (collection *memoryCollection) match(criteria exp.Expression) [][]byte {
+
+	collection.mutex.Lock()
+	defer collection.mutex.Unlock()
+
+	result := make([][]byte, 0)
+
+	for _, id := range collection.ids {
+		document := collection.documents[id]
+		if (criteria == nil) || criteria.Match(memoryMatcher(document)) {
+			result = append(result, document)
+		}
+	}
+
+	return result
+}
+
+// memoryMatcher compares predicates against a (possibly nested) BSON field.
+// Like MongoDB, a predicate matches an array if it matches any of its values.
+func memoryMatcher(document []byte) exp.MatcherFunc {
+
+	return func(predicate exp.Predicate) bool {
+
+		value, err := bson.Raw(document).LookupErr(strings.Split(predicate.Field, ".")...)
+
+		// Like MongoDB, missing fields are equal to nil
+		if err != nil {
+			return (predicate.Operator == exp.OperatorEqual) && (predicate.Value == nil)
+		}
+
+		var stored any
+		if err := value.Unmarshal(&stored); err != nil {
+			return false
+		}
+
+		if array, ok := stored.(primitive.A); ok {
+			for _, item := range array {
+				if memoryCompare(item, predicate.Operator, predicate.Value) {
+					return true
+				}
+			}
+			return false
+		}
+
+		return memoryCompare(stored, predicate.Operator, predicate.Value)
+	}
+}
+
+// memoryCompare compares two values after converting ObjectIDs and integers to comparable types
+func memoryCompare(stored any, operator string, value any) bool {
+	result, err := compare.WithOperator(memoryNormalize(stored), operator, memoryNormalize(value))
+	return (err == nil) && result
+}
+
+func memoryNormalize(value any) any {
+
+	switch typed := value.(type) {
+	case primitive.ObjectID:
+		return typed.Hex()
+	case int:
+		return int64(typed)
+	case int32:
+		return int64(typed)
+	}
+
+	return value
+}
+
+// memoryIterator iterates over a fixed set of BSON documents
+type memoryIterator struct {
+	documents [][]byte
+	index     int
+}
+
+func (iterator *memoryIterator) Next(target any) bool {
+
+	if iterator.index >= len(iterator.documents) {
+		return false
+	}
+
+	document := iterator.documents[iterator.index]
+	iterator.index++
+
+	return bson.Unmarshal(document, target) == nil
+}
+
+func (iterator *memoryIterator) Error() error {
+	return nil
+}
+
+func (iterator *memoryIterator) Count() int {
+	return len(iterator.documents)
+}
+
+func (iterator *memoryIterator) Close() error {
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/remote"
 	"github.com/benpate/rosetta/list"
 	"github.com/benpate/rosetta/schema"
@@ -36,8 +37,11 @@ import (
 // Mention defines a service that can send and receive mention data
 type Mention struct {
 	collection      data.Collection
+	domainService   *Domain
 	ruleService     *Rule
+	streamService   *Stream
 	activityService *ActivityStream
+	queue           queue.Queue
 	host            string
 }
 
@@ -51,10 +55,13 @@ func NewMention() Mention {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Mention) Refresh(collection data.Collection, ruleService *Rule, activityService *ActivityStream, host string) {
+func (service *Mention) Refresh(collection data.Collection, domainService *Domain, ruleService *Rule, streamService *Stream, activityService *ActivityStream, queue queue.Queue, host string) {
 	service.collection = collection
+	service.domainService = domainService
 	service.ruleService = ruleService
+	service.streamService = streamService
 	service.activityService = activityService
+	service.queue = queue
 	service.host = host
 }
 
@@ -102,6 +109,9 @@ func (service *Mention) Save(mention *model.Mention, note string) error {
 		return derp.Wrap(err, "service.Mention.Save", "Error saving Mention", mention, note)
 	}
 
+	// Let upstream documents know that the conversation has changed
+	service.sendSalmention(mention)
+
 	return nil
 }
 
@@ -115,6 +125,9 @@ func (service *Mention) Delete(mention *model.Mention, note string) error {
 		return derp.Wrap(err, "service.Mention.Delete", "Error deleting Mention", criteria)
 	}
 
+	// Let upstream documents know that the conversation has changed
+	service.sendSalmention(mention)
+
 	return nil
 }
 
@@ -182,6 +195,11 @@ func (service *Mention) Schema() schema.Schema {
  * Custom Queries
  ******************************************/
 
+// LoadByID loads an existing Mention by its unique ID
+func (service *Mention) LoadByID(mentionID primitive.ObjectID, result *model.Mention) error {
+	return service.Load(exp.Equal("_id", mentionID), result)
+}
+
 // LoadByOrigin loads an existing Mention by its type/objectID/origin URL
 func (service *Mention) LoadByOrigin(objectType string, objectID primitive.ObjectID, originURL string, result *model.Mention) error {
 
@@ -192,6 +210,16 @@ func (service *Mention) LoadByOrigin(objectType string, objectID primitive.Objec
 	return service.Load(criteria, result)
 }
 
+// LoadByAuthor loads an existing Mention of the specified object with the same type (LIKE, REPLY, etc) and author
+func (service *Mention) LoadByAuthor(objectID primitive.ObjectID, originType string, profileURL string, result *model.Mention) error {
+
+	criteria := exp.Equal("objectId", objectID).
+		AndEqual("origin.type", originType).
+		AndEqual("author.profileUrl", profileURL)
+
+	return service.Load(criteria, result)
+}
+
 // LoadOrCreate loads an existing Mention or creates a new one if it doesn't exist
 func (service *Mention) LoadOrCreate(objectType string, objectID primitive.ObjectID, originURL string) (model.Mention, error) {
 
@@ -219,6 +247,37 @@ func (service *Mention) QueryByObjectID(objectID primitive.ObjectID, options ...
 	return service.Query(exp.Equal("objectId", objectID), options...)
 }
 
+// QueryValidatedByObjectID returns all approved Mentions of the provided object.
+// If originTypes are provided, then only Mentions of those types (LIKE, REPLY, etc) are returned.
+func (service *Mention) QueryValidatedByObjectID(objectID primitive.ObjectID, originTypes ...string) ([]model.Mention, error) {
+
+	criteria := exp.Equal("objectId", objectID).
+		AndEqual("stateId", model.MentionStatusValidated)
+
+	if len(originTypes) > 0 {
+		criteria = criteria.AndIn("origin.type", originTypes)
+	}
+
+	return service.Query(criteria, option.SortAsc("createDate"))
+}
+
+// QueryPending returns all Mentions that are waiting for the owner's approval
+func (service *Mention) QueryPending(options ...option.Option) ([]model.Mention, error) {
+	return service.Query(exp.Equal("stateId", model.MentionStatusPending), options...)
+}
+
+// DeleteByObject permanently removes all of the Mentions of an internal object (hard delete)
+func (service *Mention) DeleteByObject(objectType string, objectID primitive.ObjectID) error {
+
+	criteria := exp.Equal("type", objectType).AndEqual("objectId", objectID)
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, "service.Mention.DeleteByObject", "Error deleting Mentions", objectType, objectID)
+	}
+
+	return nil
+}
+
 /******************************************
  * Web-Mention Helpers
  ******************************************/
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/convert"
+	"willnorris.com/go/microformats"
+)
+
+// ParseEntry inspects the h-entry in a WebMention source document and updates the
+// Mention with the kind of response it represents (reply, like, repost, bookmark),
+// the text of any reply, and the author's h-card.
+// https://indieweb.org/responses
+func (service *Mention) ParseEntry(body io.Reader, source string, target string, mention *model.Mention) {
+
+	// Default to a plain mention in case we can't find anything more specific
+	mention.Origin.Type = model.OriginTypePrimary
+
+	sourceURL, err := url.Parse(source)
+
+	if err != nil {
+		return
+	}
+
+	entry := findMentionEntry(microformats.Parse(body, sourceURL).Items, source)
+
+	if entry == nil {
+		return
+	}
+
+	// Identify the kind of response that this entry represents
+	switch {
+
+	case mentionPropertyMatches(entry, "in-reply-to", target):
+		mention.Origin.Type = model.OriginTypeReply
+
+	case mentionPropertyMatches(entry, "repost-of", target):
+		mention.Origin.Type = model.OriginTypeAnnounce
+
+	case mentionPropertyMatches(entry, "like-of", target):
+		mention.Origin.Type = model.OriginTypeLike
+
+	case mentionPropertyMatches(entry, "bookmark-of", target):
+		mention.Origin.Type = model.OriginTypeBookmark
+	}
+
+	// Replies and plain mentions carry text content
+	if (mention.Origin.Type == model.OriginTypeReply) || (mention.Origin.Type == model.OriginTypePrimary) {
+		mention.Content = mentionEntryContent(entry)
+	} else {
+		mention.Content = ""
+	}
+
+	// Fill in any blanks in the page information
+	if mention.Origin.Label == "" {
+		mention.Origin.Label = convert.MicroformatPropertyToString(entry, "name")
+	}
+
+	if mention.Author.Name == "" {
+		mention.Author = mentionEntryAuthor(entry, mention.Author)
+	}
+}
+
+// findMentionEntry returns the h-entry that best represents the source document.
+// It prefers an entry whose URL matches the source, and searches inside of h-feeds.
+func findMentionEntry(items []*microformats.Microformat, source string) *microformats.Microformat {
+
+	var result *microformats.Microformat
+
+	for _, item := range items {
+
+		if hasMicroformatType(item, "h-feed") {
+			if entry := findMentionEntry(item.Children, source); entry != nil {
+				if result == nil || sameMentionURL(convert.MicroformatPropertyToString(entry, "url"), source) {
+					result = entry
+				}
+			}
+			continue
+		}
+
+		if !hasMicroformatType(item, "h-entry") {
+			continue
+		}
+
+		if sameMentionURL(convert.MicroformatPropertyToString(item, "url"), source) {
+			return item
+		}
+
+		if result == nil {
+			result = item
+		}
+	}
+
+	return result
+}
+
+// mentionPropertyMatches returns TRUE if the named property links to the target URL.
+// Values may be plain URLs, or nested h-cite objects.
+func mentionPropertyMatches(entry *microformats.Microformat, name string, target string) bool {
+
+	for _, value := range entry.Properties[name] {
+
+		switch typed := value.(type) {
+
+		case string:
+			if sameMentionURL(typed, target) {
+				return true
+			}
+
+		case *microformats.Microformat:
+			if sameMentionURL(typed.Value, target) {
+				return true
+			}
+
+			if sameMentionURL(convert.MicroformatPropertyToString(typed, "url"), target) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mentionEntryContent returns the plain-text content of an h-entry
+func mentionEntryContent(entry *microformats.Microformat) string {
+
+	for _, value := range entry.Properties["content"] {
+
+		switch typed := value.(type) {
+
+		case string:
+			return strings.TrimSpace(typed)
+
+		case map[string]string:
+			return strings.TrimSpace(typed["value"])
+		}
+	}
+
+	return strings.TrimSpace(convert.MicroformatPropertyToString(entry, "summary"))
+}
+
+// mentionEntryAuthor returns the author of an h-entry, falling back to the provided value
+func mentionEntryAuthor(entry *microformats.Microformat, fallback model.PersonLink) model.PersonLink {
+
+	for _, value := range entry.Properties["author"] {
+
+		switch typed := value.(type) {
+
+		case string:
+			fallback.ProfileURL = typed
+			return fallback
+
+		case *microformats.Microformat:
+			author := convert.MicroformatToAuthor(typed)
+
+			if author.Name == "" {
+				author.Name = typed.Value
+			}
+
+			return author
+		}
+	}
+
+	return fallback
+}
+
+// hasMicroformatType returns TRUE if the microformat includes the provided type
+func hasMicroformatType(item *microformats.Microformat, name string) bool {
+
+	for _, itemType := range item.Type {
+		if itemType == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameMentionURL returns TRUE if two URLs are identical, ignoring any trailing slashes
+func sameMentionURL(first string, second string) bool {
+
+	if (first == "") || (second == "") {
+		return false
+	}
+
+	return strings.TrimSuffix(first, "/") == strings.TrimSuffix(second, "/")
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionParseEntry_Reply(t *testing.T) {
+
+	body := `<div class="h-entry">
+		<a class="u-url" href="https://remote.site/reply/1">permalink</a>
+		<a class="u-in-reply-to" href="https://local.site/my-post/">In reply to</a>
+		<div class="p-author h-card"><a class="u-url p-name" href="https://remote.site/">Remote Author</a></div>
+		<div class="e-content"><p>Great post!</p></div>
+	</div>`
+
+	service := NewMention()
+	mention := model.NewMention()
+	service.ParseEntry(strings.NewReader(body), "https://remote.site/reply/1", "https://local.site/my-post", &mention)
+
+	require.Equal(t, model.OriginTypeReply, mention.Origin.Type)
+	require.Equal(t, "Great post!", mention.Content)
+	require.Equal(t, "Remote Author", mention.Author.Name)
+	require.Equal(t, "https://remote.site/", mention.Author.ProfileURL)
+}
+
+func TestMentionParseEntry_Types(t *testing.T) {
+
+	test := func(property string, expected string) {
+		body := `<div class="h-entry">
+			<a class="u-` + property + `" href="https://local.site/my-post">Post</a>
+			<div class="e-content">Ignored</div>
+		</div>`
+
+		service := NewMention()
+		mention := model.NewMention()
+		service.ParseEntry(strings.NewReader(body), "https://remote.site/1", "https://local.site/my-post", &mention)
+		require.Equal(t, expected, mention.Origin.Type)
+		require.Empty(t, mention.Content)
+	}
+
+	test("like-of", model.OriginTypeLike)
+	test("repost-of", model.OriginTypeAnnounce)
+	test("bookmark-of", model.OriginTypeBookmark)
+}
+
+func TestMentionParseEntry_Cite(t *testing.T) {
+
+	body := `<div class="h-feed">
+		<div class="h-entry">
+			<a class="u-url" href="https://remote.site/other">Other</a>
+		</div>
+		<div class="h-entry">
+			<a class="u-url" href="https://remote.site/like">Like</a>
+			<div class="u-like-of h-cite"><a class="u-url" href="https://local.site/my-post">Original</a></div>
+		</div>
+	</div>`
+
+	service := NewMention()
+	mention := model.NewMention()
+	service.ParseEntry(strings.NewReader(body), "https://remote.site/like", "https://local.site/my-post", &mention)
+
+	require.Equal(t, model.OriginTypeLike, mention.Origin.Type)
+}
+
+func TestMentionParseEntry_Mention(t *testing.T) {
+
+	body := `<p>This is just a <a href="https://local.site/my-post">link</a> with no microformats.</p>`
+
+	service := NewMention()
+	mention := model.NewMention()
+	service.ParseEntry(strings.NewReader(body), "https://remote.site/page", "https://local.site/my-post", &mention)
+
+	require.Equal(t, model.OriginTypePrimary, mention.Origin.Type)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+)
+
+/******************************************
+ * Moderation Methods
+ ******************************************/
+
+// IsBlocked returns TRUE if the source URL belongs to a domain that has been blocked by this server.
+// Mentions from blocked domains are discarded without being saved.
+func (service *Mention) IsBlocked(source string) bool {
+
+	const location = "service.Mention.IsBlocked"
+
+	rules, err := service.ruleService.QueryDomainBlocks()
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading domain blocks"))
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.FilterByActor(source) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ModerationState returns the StateID that a new Mention should have.  Mentions from domains on
+// the allowlist are VALIDATED immediately.  Mentions from a source URL, or from a verified author,
+// that the owner has already approved are VALIDATED too.  All others are PENDING until the owner
+// reviews them.  Trust is never extended to a whole host, because many people share hosts like
+// brid.gy or mastodon.social.
+func (service *Mention) ModerationState(mention *model.Mention) string {
+
+	const location = "service.Mention.ModerationState"
+
+	source := mention.Origin.URL
+	sourceURL, err := url.Parse(source)
+
+	if err != nil || sourceURL.Host == "" {
+		return model.MentionStatusPending
+	}
+
+	// Domains on the allowlist are approved automatically
+	if service.domainService.Get().AllowsMentionsFrom(sourceURL.Hostname()) {
+		return model.MentionStatusValidated
+	}
+
+	// Sources and authors that the owner has already approved are trusted, too
+	var trusted exp.Expression = exp.Equal("origin.url", source)
+
+	if isVerifiedAuthor(source, mention.Author.ProfileURL) {
+		trusted = trusted.Or(exp.Equal("author.profileUrl", mention.Author.ProfileURL))
+	}
+
+	criteria := exp.Equal("stateId", model.MentionStatusValidated).And(trusted)
+	previous := model.NewMention()
+
+	if err := service.Load(criteria, &previous); err == nil {
+		return model.MentionStatusValidated
+
+	} else if !derp.NotFound(err) {
+		derp.Report(derp.Wrap(err, location, "Error searching for previous mentions", source))
+	}
+
+	// Everyone else waits for approval
+	return model.MentionStatusPending
+}
+
+// isVerifiedAuthor returns TRUE if the source URL is published underneath the author's
+// profile URL (like https://mastodon.social/@alice/123 for https://mastodon.social/@alice)
+// so that the author cannot have been claimed by another person on the same host.
+func isVerifiedAuthor(source string, profileURL string) bool {
+
+	if profileURL == "" {
+		return false
+	}
+
+	profileURL = strings.TrimSuffix(profileURL, "/")
+	return (source == profileURL) || strings.HasPrefix(source, profileURL+"/")
+}
+
+/******************************************
+ * Salmention
+ ******************************************/
+
+// sendSalmention re-sends a WebMention upstream when a reply arrives for a Stream that is,
+// itself, a reply to another document.  This lets the upstream document update its copy
+// of the conversation.
+// https://indieweb.org/Salmention
+func (service *Mention) sendSalmention(mention *model.Mention) {
+
+	const location = "service.Mention.sendSalmention"
+
+	// RULE: Only approved replies to Streams are propagated upstream
+	if !mention.IsValidated() || !mention.IsReply() || (mention.Type != model.MentionTypeStream) {
+		return
+	}
+
+	// RULE: Required services must be present
+	if (service.streamService == nil) || (service.queue == nil) {
+		return
+	}
+
+	stream := model.NewStream()
+
+	if err := service.streamService.LoadByID(mention.ObjectID, &stream); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading stream", mention.ObjectID))
+		return
+	}
+
+	// RULE: Only streams that are replies themselves have an upstream document
+	if (stream.InReplyTo == "") || (stream.URL == "") {
+		return
+	}
+
+	service.queue.Push(NewTaskSendWebMention(stream.URL, stream.InReplyTo))
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMention_ModerationState(t *testing.T) {
+
+	collection := newMemoryCollection()
+	domainService := NewDomain()
+
+	service := NewMention()
+	service.collection = &collection
+	service.domainService = &domainService
+
+	// The owner has approved one reply from Alice on a shared host
+	approved := model.NewMention()
+	approved.StateID = model.MentionStatusValidated
+	approved.Origin.URL = "https://mastodon.social/@alice/1"
+	approved.Author.ProfileURL = "https://mastodon.social/@alice"
+	require.Nil(t, collection.Save(&approved, ""))
+
+	newMention := func(source string, author string) *model.Mention {
+		result := model.NewMention()
+		result.Origin.URL = source
+		result.Author.ProfileURL = author
+		return &result
+	}
+
+	// New posts by the same (verified) author are trusted
+	require.Equal(t, model.MentionStatusValidated, service.ModerationState(newMention("https://mastodon.social/@alice/2", "https://mastodon.social/@alice")))
+
+	// Other people on the same host are not
+	require.Equal(t, model.MentionStatusPending, service.ModerationState(newMention("https://mastodon.social/@mallory/3", "https://mastodon.social/@mallory")))
+
+	// Authors cannot be claimed by pages outside of their profile
+	require.Equal(t, model.MentionStatusPending, service.ModerationState(newMention("https://mastodon.social/@mallory/4", "https://mastodon.social/@alice")))
+
+	// The same source URL is trusted
+	require.Equal(t, model.MentionStatusValidated, service.ModerationState(newMention("https://mastodon.social/@alice/1", "")))
+
+	// Domains on the allowlist are always trusted
+	domainService.GetPointer().MentionAllowlist = "example.com"
+	require.Equal(t, model.MentionStatusValidated, service.ModerationState(newMention("https://blog.example.com/post", "")))
+}
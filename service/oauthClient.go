@@ -2,7 +2,9 @@ package service
 
 import (
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/indieauth"
 	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
@@ -130,10 +132,65 @@ func (service *OAuthClient) LoadByClientID(clientID primitive.ObjectID, client *
 	return service.Load(criteria, client)
 }
 
+// LoadByClientURL loads a single IndieAuth client using its client_id URL
+func (service *OAuthClient) LoadByClientURL(clientURL string, client *model.OAuthClient) error {
+
+	criteria := exp.Equal("clientUrl", clientURL)
+	return service.Load(criteria, client)
+}
+
+// LoadByPublicID loads a single client using the client_id that it sends in OAuth requests.
+// This is either a URL (for IndieAuth clients) or a stringified ObjectID (for pre-registered clients)
+func (service *OAuthClient) LoadByPublicID(clientID string, client *model.OAuthClient) error {
+
+	if indieauth.IsClientID(clientID) {
+		return service.LoadByClientURL(clientID, client)
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(clientID)
+
+	if err != nil {
+		return derp.Wrap(err, "service.OAuthClient.LoadByPublicID", "Invalid client_id", clientID, derp.WithBadRequest())
+	}
+
+	return service.LoadByClientID(objectID, client)
+}
+
 /******************************************
  * Custom Methods
  ******************************************/
 
+// DiscoverByURL returns the IndieAuth client for a client_id URL, without saving it.
+// Client metadata is re-discovered every time, so that changes to the client's name, logo,
+// and redirect URIs are picked up automatically.  Discovery only connects to public addresses.
+func (service *OAuthClient) DiscoverByURL(clientURL string) (model.OAuthClient, error) {
+
+	const location = "service.OAuthClient.DiscoverByURL"
+
+	// Discover the client's metadata
+	metadata, err := indieauth.DiscoverClient(clientURL, ssrf.Option())
+
+	if err != nil {
+		return model.OAuthClient{}, derp.Wrap(err, location, "Error discovering client", clientURL)
+	}
+
+	// Find the existing client record (if any)
+	result := model.NewIndieAuthClient(clientURL)
+
+	if err := service.LoadByClientURL(clientURL, &result); err != nil && !derp.NotFound(err) {
+		return model.OAuthClient{}, derp.Wrap(err, location, "Error loading client", clientURL)
+	}
+
+	// Apply metadata
+	result.Name = metadata.ClientName
+	result.Website = metadata.ClientURI
+	result.LogoURL = metadata.LogoURI
+	result.RedirectURIs = metadata.RedirectURIs
+	result.Scopes = model.IndieAuthScopes()
+
+	return result, nil
+}
+
 func (service *OAuthClient) ValidateClientSecret(clientID primitive.ObjectID, clientSecret string) error {
 
 	const location = "service.OAuthClient.ValidateClientSecret"
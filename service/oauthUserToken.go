@@ -1,7 +1,11 @@
 package service
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/indieauth"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
@@ -147,6 +151,43 @@ func (service *OAuthUserToken) LoadByClientAndCode(userTokenID primitive.ObjectI
 	return service.Load(criteria, result)
 }
 
+// LoadByCode loads the token for an authorization code that was issued to a public (IndieAuth)
+// client.  These clients do not have a client secret, so they MUST use PKCE instead.
+func (service *OAuthUserToken) LoadByCode(userTokenID primitive.ObjectID, clientID primitive.ObjectID, result *model.OAuthUserToken) error {
+
+	criteria := exp.Equal("_id", userTokenID).
+		AndEqual("clientId", clientID)
+
+	return service.Load(criteria, result)
+}
+
+// LoadByToken loads the OAuthUserToken that matches an access token
+func (service *OAuthUserToken) LoadByToken(token string, result *model.OAuthUserToken) error {
+	return service.Load(exp.Equal("token", token), result)
+}
+
+// QueryByUser returns all of the OAuthUserTokens that a User has authorized, newest first
+func (service *OAuthUserToken) QueryByUser(userID primitive.ObjectID) ([]model.OAuthUserToken, error) {
+	criteria := exp.Equal("userId", userID)
+	return service.Query(criteria, option.SortDesc("createDate"))
+}
+
+// DeleteByUser permanently removes all of the OAuthUserTokens that a User has authorized (hard delete)
+func (service *OAuthUserToken) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.OAuthUserToken.DeleteByUser", "Error deleting OAuthUserTokens", userID)
+	}
+
+	return nil
+}
+
+// LoadByUserAndID loads an OAuthUserToken that a User has authorized
+func (service *OAuthUserToken) LoadByUserAndID(userID primitive.ObjectID, userTokenID primitive.ObjectID, result *model.OAuthUserToken) error {
+	criteria := exp.Equal("_id", userTokenID).AndEqual("userId", userID)
+	return service.Load(criteria, result)
+}
+
 func (service *OAuthUserToken) LoadByClientAndToken(clientID primitive.ObjectID, clientSecret string, token string, result *model.OAuthUserToken) error {
 
 	// RULE: must have a valid clientSecret to load this record
@@ -205,26 +246,29 @@ func (service *OAuthUserToken) Create(client model.OAuthClient, authorization mo
 		return model.OAuthUserToken{}, derp.Wrap(err, location, "Invalid OAuthUserTokenRequest")
 	}
 
-	// If we already have a token for this user/client, then just return that.
+	// Every authorization creates a new token (and a new authorization code)
 	result := model.NewOAuthUserToken()
-	if err := service.LoadByUserAndClient(authorization.UserID, client.ClientID, &result); err == nil {
-		return result, nil
-	}
 
-	// Fall through means we're going to create a new token
-	token, err := service.JWT(authorization.UserID, transaction.Scope)
+	token, err := service.JWT(result.OAuthUserTokenID, authorization.UserID, client, transaction.Scope)
 
 	if err != nil {
 		return model.OAuthUserToken{}, derp.Wrap(err, location, "Error generating random token")
 	}
 
+	result.Token = token
+	result.Scopes = transaction.Scopes()
+
 	// Copy data from the authorization
 	result.ClientID = client.ClientID
 	result.UserID = authorization.UserID
-	result.Scopes = transaction.Scopes()
-	result.Token = token
 	result.APIUser = true
 
+	// Record a new authorization code, which can be redeemed (once) for this token
+	result.RedirectURI = transaction.RedirectURI
+	result.CodeChallenge = transaction.CodeChallenge
+	result.CodeChallengeMethod = transaction.CodeChallengeMethod
+	result.CodeExpires = time.Now().Add(10 * time.Minute).Unix()
+
 	// Save the result to the database
 	if err := service.Save(&result, "Create"); err != nil {
 		return model.OAuthUserToken{}, derp.Wrap(err, location, "Error saving OAuthUserToken", result)
@@ -233,19 +277,86 @@ func (service *OAuthUserToken) Create(client model.OAuthClient, authorization mo
 	return result, nil
 }
 
+// RedeemCode confirms that the authorization code for this token can be exchanged, then
+// marks the code as used.  Codes expire after 10 minutes, must be sent with the same
+// redirect_uri that was used to request them, and must include the PKCE code_verifier
+// if a code_challenge was provided.
+func (service *OAuthUserToken) RedeemCode(userToken *model.OAuthUserToken, redirectURI string, codeVerifier string) error {
+
+	const location = "service.OAuthUserToken.RedeemCode"
+
+	// RULE: Codes can only be used once, within a short time of being issued
+	if userToken.IsCodeExpired() {
+		return derp.NewBadRequestError(location, "Authorization code has expired")
+	}
+
+	// RULE: redirect_uri must match the original request
+	if (redirectURI != "") && (userToken.RedirectURI != "") && (redirectURI != userToken.RedirectURI) {
+		return derp.NewBadRequestError(location, "Invalid redirect_uri", redirectURI)
+	}
+
+	// RULE: code_verifier must match the code_challenge from the original request
+	if userToken.CodeChallenge != "" {
+		if !indieauth.VerifyCodeChallenge(codeVerifier, userToken.CodeChallenge, userToken.CodeChallengeMethod) {
+			return derp.NewBadRequestError(location, "Invalid code_verifier")
+		}
+	}
+
+	// Mark the code as used
+	userToken.CodeChallenge = ""
+	userToken.CodeChallengeMethod = ""
+	userToken.CodeExpires = 0
+
+	if err := service.Save(userToken, "Redeemed authorization code"); err != nil {
+		return derp.Wrap(err, location, "Error saving OAuthUserToken")
+	}
+
+	return nil
+}
+
+// ValidateToken returns an error if an access token has been revoked.  Access tokens are
+// JWTs, so this must be checked in addition to validating the JWT signature.
+func (service *OAuthUserToken) ValidateToken(token string) error {
+
+	userToken := model.NewOAuthUserToken()
+
+	if err := service.LoadByToken(token, &userToken); err != nil {
+		return derp.Wrap(err, "service.OAuthUserToken.ValidateToken", "Access token has been revoked", derp.WithCode(http.StatusUnauthorized))
+	}
+
+	return nil
+}
+
+// Revoke removes an OAuthUserToken, so that its application can no longer access the User's account
+func (service *OAuthUserToken) Revoke(userToken *model.OAuthUserToken) error {
+
+	if err := service.Delete(userToken, "Revoked"); err != nil {
+		return derp.Wrap(err, "service.OAuthUserToken.Revoke", "Error revoking OAuthUserToken", userToken.OAuthUserTokenID)
+	}
+
+	return nil
+}
+
 func (service *OAuthUserToken) DeleteByClient(clientID primitive.ObjectID, note string) error {
 	criteria := exp.Equal("clientId", clientID)
 	return service.DeleteMany(criteria, note)
 }
 
-// JWT encodes an OAuthUserToken as a new JWT.
-func (service *OAuthUserToken) JWT(userID primitive.ObjectID, scopes string) (string, error) {
+// JWT encodes an OAuthUserToken as a new JWT.  Tokens for IndieAuth clients are
+// marked so that they cannot be used with the Mastodon API.
+func (service *OAuthUserToken) JWT(userTokenID primitive.ObjectID, userID primitive.ObjectID, client model.OAuthClient, scopes string) (string, error) {
 
 	// Collect claims
 	claims := jwt.MapClaims{
-		"A": true,   // apiUser
-		"U": userID, // UserID
-		"S": scopes, // Scopes
+		"jti": userTokenID.Hex(), // Unique token ID
+		"A":   true,              // apiUser
+		"U":   userID,            // UserID
+		"C":   client.ClientID,   // ClientID
+		"S":   scopes,            // Scopes
+	}
+
+	if client.IsIndieAuth() {
+		claims["I"] = true // IndieAuth
 	}
 
 	// Create the token
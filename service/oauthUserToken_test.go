@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOAuthUserToken_Create(t *testing.T) {
+
+	jwtCollection := newMemoryCollection()
+	tokenCollection := newMemoryCollection()
+
+	jwtService := NewJWT()
+	jwtService.Refresh(&jwtCollection, []byte("0123456789ABCDEF0123456789ABCDEF"))
+
+	tokenService := NewOAuthUserToken()
+	tokenService.Refresh(&tokenCollection, nil, &jwtService, "https://local.test")
+
+	authorization := model.NewAuthorization()
+	authorization.UserID = primitive.NewObjectID()
+
+	client := model.NewIndieAuthClient("https://app.test/")
+
+	create := func() model.OAuthUserToken {
+		transaction := model.NewOAuthAuthorizationRequest()
+		transaction.ClientID = client.ClientURL
+		transaction.RedirectURI = "https://app.test/callback"
+		transaction.Scope = "create"
+		transaction.CodeChallenge = "challenge"
+
+		result, err := tokenService.Create(client, authorization, transaction)
+		require.Nil(t, err)
+		return result
+	}
+
+	// Every authorization issues a new token and a new code
+	first := create()
+	second := create()
+
+	require.NotEqual(t, first.Token, second.Token)
+	require.NotEqual(t, first.Code(), second.Code())
+
+	// IndieAuth tokens are marked, so that the Mastodon API can reject them
+	token, err := jwtService.ParseString(second.Token)
+	require.Nil(t, err)
+
+	claims := token.Claims.(*model.Authorization)
+	require.True(t, claims.IndieAuth)
+	require.Equal(t, "create", claims.Scope)
+}
@@ -44,6 +44,7 @@ func (service *Outbox) Publish(actor *outbox.Actor, parentType string, parentID
 	// Send notifications to all Followers
 	go service.sendNotifications_ActivityPub(actor, activity)
 	go service.sendNotifications_WebSub(parentType, parentID)
+	go service.sendNotifications_RSSCloud(parentType, parentID)
 	go service.sendNotifications_WebMention(activity)
 
 	// Success!!
@@ -113,6 +114,25 @@ func (service Outbox) sendNotifications_WebSub(parentType string, parentID primi
 	}
 }
 
+// sendNotifications_RSSCloud pings all rssCloud subscribers that the feed has been updated
+func (service Outbox) sendNotifications_RSSCloud(parentType string, parentID primitive.ObjectID) {
+
+	const location = "service.Outbox.sendNotifications_RSSCloud"
+
+	// Get this User's Followers from the database
+	followers, err := service.followerService.RSSCloudFollowersChannel(parentType, parentID)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading Followers", parentType, parentID))
+		return
+	}
+
+	// Queue up all rssCloud notifications to be sent
+	for follower := range followers {
+		service.queue.Push(NewTaskSendRSSCloudPing(follower))
+	}
+}
+
 // sendNotifications_WebMention sends WebMention updates to external websites that are
 // mentioned in this stream.  This is here (and not in the outbox service)
 // because we need to build the content in order to discover outbound links.
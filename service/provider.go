@@ -44,6 +44,24 @@ func (service *Provider) GetProvider(providerID string) (providers.Provider, boo
 	return providers.Null{}, false
 }
 
+// GetSyndicator returns the adapter that re-posts Streams to the given type of SyndicationTarget
+func (service *Provider) GetSyndicator(targetType string) (providers.Syndicator, bool) {
+
+	switch targetType {
+
+	case providers.ProviderTypeMastodon:
+		return providers.NewMastodon(), true
+
+	case providers.ProviderTypeBluesky:
+		return providers.NewBluesky(), true
+
+	case providers.ProviderTypeWebhook:
+		return providers.NewWebhook(), true
+	}
+
+	return nil, false
+}
+
 func (service *Provider) GetGiphyProvider() providers.Giphy {
 	return providers.NewGiphy()
 }
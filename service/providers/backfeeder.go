@@ -0,0 +1,28 @@
+package providers
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+)
+
+// Backfeeder is implemented by Syndicators that can retrieve the responses
+// (replies, likes, and reposts) to the copies that they have syndicated.
+// https://indieweb.org/backfeed
+type Backfeeder interface {
+
+	// IsSyndicatedBy returns TRUE if the syndicated copy at this URL was (probably) created by the target.
+	IsSyndicatedBy(target model.SyndicationTarget, syndicationURL string) bool
+
+	// Backfeed returns all of the responses to the syndicated copy at this URL
+	Backfeed(target model.SyndicationTarget, syndicationURL string) ([]BackfeedResponse, error)
+}
+
+// BackfeedResponse is the provider-neutral version of a single response
+// to a syndicated copy of a Stream.
+type BackfeedResponse struct {
+	Type     string           // Type of response (model.OriginTypeReply, model.OriginTypeLike, model.OriginTypeAnnounce)
+	URL      string           // Unique URL of this response.  Likes and reposts use a fragment of the syndicated URL.
+	ObjectID string           // ActivityPub ID of a reply (if available) used to find native copies of the same reply
+	ActorID  string           // ActivityPub ID of the author (if available) used to find native copies of the same response
+	Author   model.PersonLink // Author of the response
+	Content  string           // Plain-text content of a reply
+}
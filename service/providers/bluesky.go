@@ -0,0 +1,337 @@
+package providers
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/first"
+)
+
+// ProviderTypeBluesky identifies the Bluesky syndication provider
+const ProviderTypeBluesky = model.SyndicationTargetTypeBluesky
+
+// blueskyMaxLength is the maximum length of a Bluesky post
+const blueskyMaxLength = 300
+
+// Bluesky syndicates Streams to an account on a Bluesky PDS using XRPC.
+// https://docs.bsky.app/docs/advanced-guides/posts
+type Bluesky struct {
+	options []remote.Option // Options applied to every request.  By default, only public addresses can be reached.
+}
+
+func NewBluesky() Bluesky {
+	return Bluesky{
+		options: []remote.Option{ssrf.Option()},
+	}
+}
+
+/******************************************
+ * Syndicator Methods
+ ******************************************/
+
+// Syndicate signs into the PDS with an app password, creates a new post, and returns its URL
+func (adapter Bluesky) Syndicate(target model.SyndicationTarget, post SyndicationPost) (string, error) {
+
+	const location = "providers.Bluesky.Syndicate"
+
+	// RULE: Server, handle, and app password are required
+	if (target.URL == "") || (target.Username == "") || (target.Secret == "") {
+		return "", derp.NewBadRequestError(location, "Bluesky server, handle, and app password are required", target.SyndicationTargetID)
+	}
+
+	endpoint := strings.TrimSuffix(target.URL, "/") + "/xrpc/"
+
+	// Create a new session using the app password
+	session, err := adapter.createSession(endpoint, target)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error creating Bluesky session", target.URL, target.Username)
+	}
+
+	// Create the post record
+	text := post.Status(blueskyMaxLength)
+
+	record := map[string]any{
+		"$type":     "app.bsky.feed.post",
+		"text":      text,
+		"createdAt": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if facets := blueskyLinkFacets(text, post.URL); len(facets) > 0 {
+		record["facets"] = facets
+	}
+
+	result := struct {
+		URI string `json:"uri"`
+		CID string `json:"cid"`
+	}{}
+
+	recordTransaction := remote.Post(endpoint+"com.atproto.repo.createRecord").
+		Header("Authorization", "Bearer "+session.AccessJwt).
+		JSON(map[string]any{
+			"repo":       session.DID,
+			"collection": "app.bsky.feed.post",
+			"record":     record,
+		}).
+		Result(&result).
+		Use(adapter.options...)
+
+	if err := recordTransaction.Send(); err != nil {
+		return "", derp.Wrap(err, location, "Error creating Bluesky post", target.URL, target.Username)
+	}
+
+	postURL, ok := blueskyPostURL(result.URI)
+
+	if !ok {
+		return "", derp.NewInternalError(location, "Bluesky server returned an invalid URI", target.URL, result.URI)
+	}
+
+	return postURL, nil
+}
+
+// blueskySession is the result of signing into a PDS with an app password
+type blueskySession struct {
+	AccessJwt string `json:"accessJwt"`
+	DID       string `json:"did"`
+}
+
+// createSession signs into the PDS using the target's handle and app password
+func (adapter Bluesky) createSession(endpoint string, target model.SyndicationTarget) (blueskySession, error) {
+
+	result := blueskySession{}
+
+	transaction := remote.Post(endpoint + "com.atproto.server.createSession").
+		JSON(map[string]any{
+			"identifier": target.Username,
+			"password":   target.Secret,
+		}).
+		Result(&result).
+		Use(adapter.options...)
+
+	if err := transaction.Send(); err != nil {
+		return result, derp.Wrap(err, "providers.Bluesky.createSession", "Error sending request", target.URL, target.Username)
+	}
+
+	return result, nil
+}
+
+// blueskyLinkFacets returns the rich-text facets that make the link at the end
+// of the text clickable. Bluesky facets are indexed by UTF-8 byte offsets.
+func blueskyLinkFacets(text string, link string) []map[string]any {
+
+	if link == "" {
+		return nil
+	}
+
+	start := strings.LastIndex(text, link)
+
+	if start < 0 {
+		return nil
+	}
+
+	return []map[string]any{
+		{
+			"index": map[string]any{
+				"byteStart": start,
+				"byteEnd":   start + len(link),
+			},
+			"features": []map[string]any{
+				{
+					"$type": "app.bsky.richtext.facet#link",
+					"uri":   link,
+				},
+			},
+		},
+	}
+}
+
+// blueskyPostURL converts an at:// URI (at://{did}/app.bsky.feed.post/{rkey})
+// into the public URL of the post on bsky.app
+func blueskyPostURL(uri string) (string, bool) {
+
+	path, ok := strings.CutPrefix(uri, "at://")
+
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Split(path, "/")
+
+	if (len(parts) != 3) || (parts[1] != "app.bsky.feed.post") {
+		return "", false
+	}
+
+	return "https://bsky.app/profile/" + parts[0] + "/post/" + parts[2], true
+}
+
+// blueskyPostURI converts the public URL of a post on bsky.app back into
+// its at:// URI (at://{did}/app.bsky.feed.post/{rkey})
+func blueskyPostURI(postURL string) (string, bool) {
+
+	path, ok := strings.CutPrefix(postURL, "https://bsky.app/profile/")
+
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+
+	if (len(parts) != 3) || (parts[1] != "post") || (parts[0] == "") || (parts[2] == "") {
+		return "", false
+	}
+
+	return "at://" + parts[0] + "/app.bsky.feed.post/" + parts[2], true
+}
+
+/******************************************
+ * Backfeeder Methods
+ ******************************************/
+
+// blueskyProfile is the subset of an app.bsky.actor.defs#profileView used for backfeed
+type blueskyProfile struct {
+	DID         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"displayName"`
+	Avatar      string `json:"avatar"`
+}
+
+// blueskyThread is the subset of an app.bsky.feed.defs#threadViewPost used for backfeed
+type blueskyThread struct {
+	Post struct {
+		URI    string         `json:"uri"`
+		Author blueskyProfile `json:"author"`
+		Record struct {
+			Text string `json:"text"`
+		} `json:"record"`
+	} `json:"post"`
+	Replies []blueskyThread `json:"replies"`
+}
+
+// IsSyndicatedBy returns TRUE if the syndicated URL is a post on bsky.app
+func (adapter Bluesky) IsSyndicatedBy(target model.SyndicationTarget, syndicationURL string) bool {
+	_, ok := blueskyPostURI(syndicationURL)
+	return ok
+}
+
+// Backfeed returns the replies, likes, and reposts of a syndicated post
+// https://docs.bsky.app/docs/api/app-bsky-feed-get-post-thread
+func (adapter Bluesky) Backfeed(target model.SyndicationTarget, syndicationURL string) ([]BackfeedResponse, error) {
+
+	const location = "providers.Bluesky.Backfeed"
+
+	// RULE: Server, handle, and app password are required
+	if (target.URL == "") || (target.Username == "") || (target.Secret == "") {
+		return nil, derp.NewBadRequestError(location, "Bluesky server, handle, and app password are required", target.SyndicationTargetID)
+	}
+
+	uri, ok := blueskyPostURI(syndicationURL)
+
+	if !ok {
+		return nil, derp.NewBadRequestError(location, "Invalid Bluesky post URL", syndicationURL)
+	}
+
+	// The PDS forwards app.bsky requests to the AppView on behalf of the signed-in user
+	endpoint := strings.TrimSuffix(target.URL, "/") + "/xrpc/"
+	session, err := adapter.createSession(endpoint, target)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error creating Bluesky session", target.URL, target.Username)
+	}
+
+	query := "?uri=" + url.QueryEscape(uri)
+	result := make([]BackfeedResponse, 0)
+
+	// Replies are all of the posts beneath the original in the thread
+	thread := struct {
+		Thread blueskyThread `json:"thread"`
+	}{}
+
+	if err := adapter.get(session, endpoint+"app.bsky.feed.getPostThread"+query+"&depth=10", &thread); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading Bluesky replies", syndicationURL)
+	}
+
+	result = appendBlueskyReplies(result, thread.Thread.Replies)
+
+	// Likes
+	likes := struct {
+		Likes []struct {
+			Actor blueskyProfile `json:"actor"`
+		} `json:"likes"`
+	}{}
+
+	if err := adapter.get(session, endpoint+"app.bsky.feed.getLikes"+query+"&limit=100", &likes); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading Bluesky likes", syndicationURL)
+	}
+
+	for _, like := range likes.Likes {
+		result = append(result, BackfeedResponse{
+			Type:   model.OriginTypeLike,
+			URL:    syndicationURL + "#liked-by-" + like.Actor.DID,
+			Author: like.Actor.personLink(),
+		})
+	}
+
+	// Reposts
+	reposts := struct {
+		RepostedBy []blueskyProfile `json:"repostedBy"`
+	}{}
+
+	if err := adapter.get(session, endpoint+"app.bsky.feed.getRepostedBy"+query+"&limit=100", &reposts); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading Bluesky reposts", syndicationURL)
+	}
+
+	for _, profile := range reposts.RepostedBy {
+		result = append(result, BackfeedResponse{
+			Type:   model.OriginTypeAnnounce,
+			URL:    syndicationURL + "#reposted-by-" + profile.DID,
+			Author: profile.personLink(),
+		})
+	}
+
+	return result, nil
+}
+
+// get sends an authenticated XRPC query to the PDS
+func (adapter Bluesky) get(session blueskySession, endpoint string, result any) error {
+
+	return remote.Get(endpoint).
+		Header("Authorization", "Bearer "+session.AccessJwt).
+		Result(result).
+		Use(adapter.options...).
+		Send()
+}
+
+// appendBlueskyReplies adds every reply in a thread (including nested replies) to the result
+func appendBlueskyReplies(result []BackfeedResponse, replies []blueskyThread) []BackfeedResponse {
+
+	for _, reply := range replies {
+
+		if replyURL, ok := blueskyPostURL(reply.Post.URI); ok {
+			result = append(result, BackfeedResponse{
+				Type:    model.OriginTypeReply,
+				URL:     replyURL,
+				Author:  reply.Post.Author.personLink(),
+				Content: strings.TrimSpace(reply.Post.Record.Text),
+			})
+		}
+
+		result = appendBlueskyReplies(result, reply.Replies)
+	}
+
+	return result
+}
+
+// personLink converts a Bluesky profile into a PersonLink
+func (profile blueskyProfile) personLink() model.PersonLink {
+
+	return model.PersonLink{
+		Name:       first.String(profile.DisplayName, profile.Handle),
+		ProfileURL: "https://bsky.app/profile/" + first.String(profile.Handle, profile.DID),
+		ImageURL:   profile.Avatar,
+	}
+}
@@ -0,0 +1,178 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBluesky_Syndicate(t *testing.T) {
+
+	var record map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+
+		case "/xrpc/com.atproto.server.createSession":
+			body := map[string]string{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "alice.example", body["identifier"])
+			require.Equal(t, "APP-PASSWORD", body["password"])
+			w.Write([]byte(`{"accessJwt":"JWT","did":"did:plc:alice"}`)) // nolint:errcheck
+
+		case "/xrpc/com.atproto.repo.createRecord":
+			require.Equal(t, "Bearer JWT", r.Header.Get("Authorization"))
+			body := map[string]any{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "did:plc:alice", body["repo"])
+			require.Equal(t, "app.bsky.feed.post", body["collection"])
+			record = body["record"].(map[string]any)
+			w.Write([]byte(`{"uri":"at://did:plc:alice/app.bsky.feed.post/3kabc","cid":"CID"}`)) // nolint:errcheck
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.Type = model.SyndicationTargetTypeBluesky
+	target.URL = server.URL
+	target.Username = "alice.example"
+	target.Secret = "APP-PASSWORD"
+
+	post := SyndicationPost{Text: "Héllo", URL: "https://example.com/1"}
+
+	result, err := newTestBluesky().Syndicate(target, post)
+	require.Nil(t, err)
+	require.Equal(t, "https://bsky.app/profile/did:plc:alice/post/3kabc", result)
+	require.Equal(t, "Héllo\n\nhttps://example.com/1", record["text"])
+
+	// Verify that the link facet uses byte offsets
+	facets := record["facets"].([]any)
+	require.Equal(t, 1, len(facets))
+	index := facets[0].(map[string]any)["index"].(map[string]any)
+	require.Equal(t, float64(8), index["byteStart"])
+	require.Equal(t, float64(29), index["byteEnd"])
+}
+
+func TestBluesky_Syndicate_BadLogin(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+	target.Username = "alice.example"
+	target.Secret = "WRONG"
+
+	_, err := newTestBluesky().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.NotNil(t, err)
+}
+
+func TestBlueskyPostURL(t *testing.T) {
+
+	result, ok := blueskyPostURL("at://did:plc:alice/app.bsky.feed.post/3kabc")
+	require.True(t, ok)
+	require.Equal(t, "https://bsky.app/profile/did:plc:alice/post/3kabc", result)
+
+	_, ok = blueskyPostURL("at://did:plc:alice/app.bsky.feed.like/3kabc")
+	require.False(t, ok)
+
+	_, ok = blueskyPostURL("https://bsky.app")
+	require.False(t, ok)
+}
+
+func TestBlueskyPostURI(t *testing.T) {
+
+	result, ok := blueskyPostURI("https://bsky.app/profile/did:plc:alice/post/3kabc")
+	require.True(t, ok)
+	require.Equal(t, "at://did:plc:alice/app.bsky.feed.post/3kabc", result)
+
+	_, ok = blueskyPostURI("https://bsky.app/profile/did:plc:alice")
+	require.False(t, ok)
+
+	_, ok = blueskyPostURI("https://mastodon.example/@alice/123")
+	require.False(t, ok)
+}
+
+func TestBluesky_Backfeed(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path != "/xrpc/com.atproto.server.createSession" {
+			require.Equal(t, "Bearer JWT", r.Header.Get("Authorization"))
+			require.Equal(t, "at://did:plc:alice/app.bsky.feed.post/3kabc", r.URL.Query().Get("uri"))
+		}
+
+		switch r.URL.Path {
+
+		case "/xrpc/com.atproto.server.createSession":
+			w.Write([]byte(`{"accessJwt":"JWT","did":"did:plc:alice"}`)) // nolint:errcheck
+
+		case "/xrpc/app.bsky.feed.getPostThread":
+			w.Write([]byte(`{"thread":{"post":{"uri":"at://did:plc:alice/app.bsky.feed.post/3kabc"},"replies":[
+				{"post":{"uri":"at://did:plc:bob/app.bsky.feed.post/3kdef","author":{"did":"did:plc:bob","handle":"bob.example","displayName":"Bob"},"record":{"text":"Nice!"}},
+				 "replies":[{"post":{"uri":"at://did:plc:carol/app.bsky.feed.post/3kghi","author":{"did":"did:plc:carol","handle":"carol.example"},"record":{"text":"Agreed"}}}]}
+			]}}`)) // nolint:errcheck
+
+		case "/xrpc/app.bsky.feed.getLikes":
+			w.Write([]byte(`{"likes":[{"actor":{"did":"did:plc:dave","handle":"dave.example","avatar":"https://cdn.example/dave.jpg"}}]}`)) // nolint:errcheck
+
+		case "/xrpc/app.bsky.feed.getRepostedBy":
+			w.Write([]byte(`{"repostedBy":[{"did":"did:plc:erin","handle":"erin.example","displayName":"Erin"}]}`)) // nolint:errcheck
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+	target.Username = "alice.example"
+	target.Secret = "APP-PASSWORD"
+
+	syndicationURL := "https://bsky.app/profile/did:plc:alice/post/3kabc"
+	require.True(t, NewBluesky().IsSyndicatedBy(target, syndicationURL))
+
+	result, err := newTestBluesky().Backfeed(target, syndicationURL)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(result))
+
+	require.Equal(t, model.OriginTypeReply, result[0].Type)
+	require.Equal(t, "https://bsky.app/profile/did:plc:bob/post/3kdef", result[0].URL)
+	require.Equal(t, "Bob", result[0].Author.Name)
+	require.Equal(t, "https://bsky.app/profile/bob.example", result[0].Author.ProfileURL)
+	require.Equal(t, "Nice!", result[0].Content)
+
+	require.Equal(t, model.OriginTypeReply, result[1].Type)
+	require.Equal(t, "https://bsky.app/profile/did:plc:carol/post/3kghi", result[1].URL)
+	require.Equal(t, "carol.example", result[1].Author.Name)
+
+	require.Equal(t, model.OriginTypeLike, result[2].Type)
+	require.Equal(t, syndicationURL+"#liked-by-did:plc:dave", result[2].URL)
+	require.Equal(t, "https://cdn.example/dave.jpg", result[2].Author.ImageURL)
+
+	require.Equal(t, model.OriginTypeAnnounce, result[3].Type)
+	require.Equal(t, syndicationURL+"#reposted-by-did:plc:erin", result[3].URL)
+	require.Equal(t, "Erin", result[3].Author.Name)
+}
+
+// newTestBluesky returns a Bluesky adapter that can reach the local test server
+func newTestBluesky() Bluesky {
+	adapter := NewBluesky()
+	adapter.options = nil
+	return adapter
+}
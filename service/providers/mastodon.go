@@ -0,0 +1,238 @@
+package providers
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/html"
+)
+
+// ProviderTypeMastodon identifies the Mastodon syndication provider
+const ProviderTypeMastodon = model.SyndicationTargetTypeMastodon
+
+// mastodonMaxLength is the default maximum length of a Mastodon status
+const mastodonMaxLength = 500
+
+// Mastodon syndicates Streams to a Mastodon-compatible account using its REST API.
+// https://docs.joinmastodon.org/methods/statuses/#create
+type Mastodon struct {
+	options []remote.Option // Options applied to every request.  By default, only public addresses can be reached.
+}
+
+func NewMastodon() Mastodon {
+	return Mastodon{
+		options: []remote.Option{ssrf.Option()},
+	}
+}
+
+/******************************************
+ * Syndicator Methods
+ ******************************************/
+
+// Syndicate creates a new status on the Mastodon server, and returns its URL
+func (adapter Mastodon) Syndicate(target model.SyndicationTarget, post SyndicationPost) (string, error) {
+
+	const location = "providers.Mastodon.Syndicate"
+
+	// RULE: Server and access token are required
+	if (target.URL == "") || (target.Secret == "") {
+		return "", derp.NewBadRequestError(location, "Mastodon server and access token are required", target.SyndicationTargetID)
+	}
+
+	body := map[string]any{
+		"status":     post.Status(mastodonMaxLength),
+		"visibility": "public",
+	}
+
+	if post.Sensitive && (post.Summary != "") {
+		body["spoiler_text"] = post.Summary
+		body["sensitive"] = true
+	}
+
+	result := struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+		URI string `json:"uri"`
+	}{}
+
+	// The Idempotency-Key prevents duplicate statuses if this request is retried
+	transaction := remote.Post(strings.TrimSuffix(target.URL, "/")+"/api/v1/statuses").
+		Header("Authorization", "Bearer "+target.Secret).
+		Header("Idempotency-Key", post.URL).
+		JSON(body).
+		Result(&result).
+		Use(adapter.options...)
+
+	if err := transaction.Send(); err != nil {
+		return "", derp.Wrap(err, location, "Error creating Mastodon status", target.URL)
+	}
+
+	if result.URL != "" {
+		return result.URL, nil
+	}
+
+	if result.URI != "" {
+		return result.URI, nil
+	}
+
+	return "", derp.NewInternalError(location, "Mastodon server did not return a URL", target.URL, result.ID)
+}
+
+/******************************************
+ * Backfeeder Methods
+ ******************************************/
+
+// mastodonAccount is the subset of a Mastodon Account used for backfeed
+// https://docs.joinmastodon.org/entities/Account/
+type mastodonAccount struct {
+	ID          string `json:"id"`
+	Acct        string `json:"acct"`
+	DisplayName string `json:"display_name"`
+	URL         string `json:"url"`
+	URI         string `json:"uri"`
+	Avatar      string `json:"avatar"`
+}
+
+// mastodonStatus is the subset of a Mastodon Status used for backfeed
+// https://docs.joinmastodon.org/entities/Status/
+type mastodonStatus struct {
+	ID      string          `json:"id"`
+	URL     string          `json:"url"`
+	URI     string          `json:"uri"`
+	Content string          `json:"content"`
+	Account mastodonAccount `json:"account"`
+}
+
+// IsSyndicatedBy returns TRUE if the syndicated URL is on the same server as the target
+func (adapter Mastodon) IsSyndicatedBy(target model.SyndicationTarget, syndicationURL string) bool {
+
+	targetURL, err := url.Parse(target.URL)
+
+	if err != nil {
+		return false
+	}
+
+	parsedURL, err := url.Parse(syndicationURL)
+
+	if err != nil {
+		return false
+	}
+
+	return (targetURL.Host != "") && strings.EqualFold(targetURL.Host, parsedURL.Host)
+}
+
+// Backfeed returns the replies, favourites, and boosts of a syndicated status
+// https://docs.joinmastodon.org/methods/statuses/#context
+func (adapter Mastodon) Backfeed(target model.SyndicationTarget, syndicationURL string) ([]BackfeedResponse, error) {
+
+	const location = "providers.Mastodon.Backfeed"
+
+	// RULE: Server and access token are required
+	if (target.URL == "") || (target.Secret == "") {
+		return nil, derp.NewBadRequestError(location, "Mastodon server and access token are required", target.SyndicationTargetID)
+	}
+
+	// Status URLs end with the ID of the status (https://server/@username/{id})
+	statusID := mastodonStatusID(syndicationURL)
+
+	if statusID == "" {
+		return nil, derp.NewBadRequestError(location, "Invalid Mastodon status URL", syndicationURL)
+	}
+
+	endpoint := strings.TrimSuffix(target.URL, "/") + "/api/v1/statuses/" + url.PathEscape(statusID)
+	result := make([]BackfeedResponse, 0)
+
+	// Replies are all of the descendants in the conversation
+	thread := struct {
+		Descendants []mastodonStatus `json:"descendants"`
+	}{}
+
+	if err := adapter.get(target, endpoint+"/context", &thread); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading Mastodon replies", syndicationURL)
+	}
+
+	for _, status := range thread.Descendants {
+		result = append(result, BackfeedResponse{
+			Type:     model.OriginTypeReply,
+			URL:      first.String(status.URL, status.URI),
+			ObjectID: status.URI,
+			ActorID:  status.Account.URI,
+			Author:   status.Account.personLink(),
+			Content:  strings.TrimSpace(html.ToText(status.Content)),
+		})
+	}
+
+	// Favourites and boosts only include the accounts that responded
+	accountTypes := []struct {
+		path       string
+		fragment   string
+		originType string
+	}{
+		{"/favourited_by", "#favorited-by-", model.OriginTypeLike},
+		{"/reblogged_by", "#reblogged-by-", model.OriginTypeAnnounce},
+	}
+
+	for _, accountType := range accountTypes {
+
+		accounts := make([]mastodonAccount, 0)
+
+		if err := adapter.get(target, endpoint+accountType.path+"?limit=80", &accounts); err != nil {
+			return nil, derp.Wrap(err, location, "Error loading Mastodon responses", syndicationURL, accountType.path)
+		}
+
+		for _, account := range accounts {
+			result = append(result, BackfeedResponse{
+				Type:    accountType.originType,
+				URL:     syndicationURL + accountType.fragment + account.ID,
+				ActorID: account.URI,
+				Author:  account.personLink(),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// get sends an authenticated GET request to the Mastodon API
+func (adapter Mastodon) get(target model.SyndicationTarget, endpoint string, result any) error {
+
+	return remote.Get(endpoint).
+		Header("Authorization", "Bearer "+target.Secret).
+		Result(result).
+		Use(adapter.options...).
+		Send()
+}
+
+// personLink converts a Mastodon account into a PersonLink
+func (account mastodonAccount) personLink() model.PersonLink {
+
+	return model.PersonLink{
+		Name:       first.String(account.DisplayName, account.Acct),
+		ProfileURL: first.String(account.URL, account.URI),
+		ImageURL:   account.Avatar,
+	}
+}
+
+// mastodonStatusID returns the ID of a status from the last segment of its URL
+func mastodonStatusID(statusURL string) string {
+
+	parsedURL, err := url.Parse(statusURL)
+
+	if err != nil {
+		return ""
+	}
+
+	result := path.Base(strings.TrimSuffix(parsedURL.Path, "/"))
+
+	if (result == "/") || (result == ".") {
+		return ""
+	}
+
+	return result
+}
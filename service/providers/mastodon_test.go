@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMastodon_Syndicate(t *testing.T) {
+
+	var received map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/statuses", r.URL.Path)
+		require.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"))
+		require.Equal(t, "https://example.com/1", r.Header.Get("Idempotency-Key"))
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"123","url":"https://mastodon.example/@alice/123","uri":"https://mastodon.example/users/alice/statuses/123"}`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL + "/"
+	target.Secret = "TOKEN"
+
+	post := SyndicationPost{Text: "Hello", URL: "https://example.com/1", Summary: "CW", Sensitive: true}
+
+	result, err := newTestMastodon().Syndicate(target, post)
+	require.Nil(t, err)
+	require.Equal(t, "https://mastodon.example/@alice/123", result)
+	require.Equal(t, "Hello\n\nhttps://example.com/1", received["status"])
+	require.Equal(t, "public", received["visibility"])
+	require.Equal(t, "CW", received["spoiler_text"])
+	require.Equal(t, true, received["sensitive"])
+}
+
+func TestMastodon_Syndicate_Error(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+	target.Secret = "WRONG"
+
+	_, err := newTestMastodon().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.NotNil(t, err)
+}
+
+func TestMastodon_Syndicate_MissingToken(t *testing.T) {
+
+	target := model.NewSyndicationTarget()
+	target.URL = "https://mastodon.example"
+
+	_, err := newTestMastodon().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.NotNil(t, err)
+}
+
+func TestMastodon_Backfeed(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		require.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+
+		case "/api/v1/statuses/123/context":
+			w.Write([]byte(`{"ancestors":[],"descendants":[{"id":"456","url":"https://social.example/@bob/456","uri":"https://social.example/users/bob/statuses/456","content":"<p>Nice post!</p>","account":{"id":"9","acct":"bob@social.example","display_name":"Bob","url":"https://social.example/@bob","uri":"https://social.example/users/bob"}}]}`)) // nolint:errcheck
+
+		case "/api/v1/statuses/123/favourited_by":
+			w.Write([]byte(`[{"id":"10","acct":"carol","url":"https://mastodon.example/@carol","uri":"https://mastodon.example/users/carol","avatar":"https://mastodon.example/carol.jpg"}]`)) // nolint:errcheck
+
+		case "/api/v1/statuses/123/reblogged_by":
+			w.Write([]byte(`[{"id":"11","acct":"dave","display_name":"Dave","url":"https://mastodon.example/@dave"}]`)) // nolint:errcheck
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+	target.Secret = "TOKEN"
+
+	syndicationURL := server.URL + "/@alice/123"
+	require.True(t, NewMastodon().IsSyndicatedBy(target, syndicationURL))
+	require.False(t, NewMastodon().IsSyndicatedBy(target, "https://other.example/@alice/123"))
+
+	result, err := newTestMastodon().Backfeed(target, syndicationURL)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(result))
+
+	require.Equal(t, model.OriginTypeReply, result[0].Type)
+	require.Equal(t, "https://social.example/@bob/456", result[0].URL)
+	require.Equal(t, "https://social.example/users/bob/statuses/456", result[0].ObjectID)
+	require.Equal(t, "https://social.example/users/bob", result[0].ActorID)
+	require.Equal(t, "Bob", result[0].Author.Name)
+	require.Equal(t, "Nice post!", result[0].Content)
+
+	require.Equal(t, model.OriginTypeLike, result[1].Type)
+	require.Equal(t, syndicationURL+"#favorited-by-10", result[1].URL)
+	require.Equal(t, "https://mastodon.example/users/carol", result[1].ActorID)
+	require.Equal(t, "carol", result[1].Author.Name)
+
+	require.Equal(t, model.OriginTypeAnnounce, result[2].Type)
+	require.Equal(t, syndicationURL+"#reblogged-by-11", result[2].URL)
+	require.Equal(t, "", result[2].ActorID)
+	require.Equal(t, "https://mastodon.example/@dave", result[2].Author.ProfileURL)
+}
+
+func TestMastodonStatusID(t *testing.T) {
+	require.Equal(t, "123", mastodonStatusID("https://mastodon.example/@alice/123"))
+	require.Equal(t, "123", mastodonStatusID("https://mastodon.example/@alice/123/"))
+	require.Equal(t, "", mastodonStatusID("https://mastodon.example"))
+}
+
+// newTestMastodon returns a Mastodon adapter that can reach the local test server
+func newTestMastodon() Mastodon {
+	adapter := NewMastodon()
+	adapter.options = nil
+	return adapter
+}
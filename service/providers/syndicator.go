@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/rosetta/html"
+)
+
+// Syndicator is implemented by providers that can re-post a Stream to an external service
+type Syndicator interface {
+
+	// Syndicate publishes the post to the remote service described by the target, and
+	// returns the URL of the syndicated copy.
+	Syndicate(target model.SyndicationTarget, post SyndicationPost) (string, error)
+}
+
+// SyndicationPost is the provider-neutral version of a Stream that is sent to syndication targets.
+// It is also the JSON document that is POSTed to generic webhooks.
+type SyndicationPost struct {
+	Type      string `json:"type"`              // Always "entry"
+	URL       string `json:"url"`               // Permalink of the original Stream
+	Name      string `json:"name,omitempty"`    // Title of the Stream
+	Summary   string `json:"summary,omitempty"` // Summary (or content warning) of the Stream
+	Text      string `json:"text"`              // Plain-text version of the Stream content
+	HTML      string `json:"html,omitempty"`    // HTML version of the Stream content
+	Sensitive bool   `json:"sensitive"`         // If TRUE, then the Summary is a content warning
+	Published string `json:"published"`         // RFC3339 date when the Stream was published
+}
+
+// NewSyndicationPost converts a Stream into a SyndicationPost
+func NewSyndicationPost(stream model.Stream) SyndicationPost {
+
+	text := strings.TrimSpace(html.ToText(stream.Content.HTML))
+
+	if text == "" {
+		text = stream.Label
+	}
+
+	published := time.Now()
+
+	if (stream.PublishDate > 0) && (stream.PublishDate < time.Now().Add(24*time.Hour).Unix()) {
+		published = time.Unix(stream.PublishDate, 0)
+	}
+
+	return SyndicationPost{
+		Type:      "entry",
+		URL:       stream.URL,
+		Name:      stream.Label,
+		Summary:   stream.Summary,
+		Text:      text,
+		HTML:      stream.Content.HTML,
+		Sensitive: stream.Sensitive,
+		Published: published.UTC().Format(time.RFC3339),
+	}
+}
+
+// Status returns the text of the post, followed by a link back to the original,
+// shortened to fit within the provided number of characters.
+func (post SyndicationPost) Status(maxLength int) string {
+
+	const separator = "\n\n"
+	const ellipsis = "…"
+
+	text := []rune(post.Text)
+	available := maxLength - len([]rune(post.URL)) - len([]rune(separator))
+
+	if available <= 0 {
+		return post.URL
+	}
+
+	if len(text) > available {
+		text = append([]rune(strings.TrimSpace(string(text[:available-1]))), []rune(ellipsis)...)
+	}
+
+	if len(text) == 0 {
+		return post.URL
+	}
+
+	return string(text) + separator + post.URL
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSyndicationPost(t *testing.T) {
+
+	stream := model.NewStream()
+	stream.URL = "https://example.com/@alice/1"
+	stream.Label = "Hello"
+	stream.Content = model.NewHTMLContent("<p>Hello <b>World</b></p>")
+	stream.PublishDate = 1700000000
+
+	post := NewSyndicationPost(stream)
+
+	require.Equal(t, "entry", post.Type)
+	require.Equal(t, "https://example.com/@alice/1", post.URL)
+	require.Equal(t, "Hello World", post.Text)
+	require.Equal(t, "2023-11-14T22:13:20Z", post.Published)
+}
+
+func TestNewSyndicationPost_LabelOnly(t *testing.T) {
+
+	stream := model.NewStream()
+	stream.Label = "Just a title"
+
+	post := NewSyndicationPost(stream)
+	require.Equal(t, "Just a title", post.Text)
+}
+
+func TestSyndicationPost_Status(t *testing.T) {
+
+	post := SyndicationPost{Text: "Hello World", URL: "https://example.com/1"}
+	require.Equal(t, "Hello World\n\nhttps://example.com/1", post.Status(500))
+}
+
+func TestSyndicationPost_Status_Truncated(t *testing.T) {
+
+	post := SyndicationPost{Text: strings.Repeat("é", 400), URL: "https://example.com/1"}
+	status := post.Status(300)
+
+	require.Equal(t, 300, len([]rune(status)))
+	require.True(t, strings.HasSuffix(status, "…\n\nhttps://example.com/1"))
+}
+
+func TestSyndicationPost_Status_Empty(t *testing.T) {
+
+	post := SyndicationPost{URL: "https://example.com/1"}
+	require.Equal(t, "https://example.com/1", post.Status(500))
+}
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+)
+
+// ProviderTypeWebhook identifies the generic webhook syndication provider
+const ProviderTypeWebhook = model.SyndicationTargetTypeWebhook
+
+// WebhookSignatureHeader contains the HMAC-SHA256 signature of the request body,
+// when the webhook has been configured with a secret.
+const WebhookSignatureHeader = "X-Emissary-Signature"
+
+// Webhook syndicates Streams by POSTing a JSON document to an arbitrary URL.  The
+// remote service returns the URL of the syndicated copy in a "url" property of
+// a JSON response, or in the Location header.
+type Webhook struct {
+	options []remote.Option // Options applied to every request.  By default, only public addresses can be reached.
+}
+
+func NewWebhook() Webhook {
+	return Webhook{
+		options: []remote.Option{ssrf.Option()},
+	}
+}
+
+/******************************************
+ * Syndicator Methods
+ ******************************************/
+
+// Syndicate POSTs the post to the webhook URL and returns the URL of the syndicated copy
+func (adapter Webhook) Syndicate(target model.SyndicationTarget, post SyndicationPost) (string, error) {
+
+	const location = "providers.Webhook.Syndicate"
+
+	if target.URL == "" {
+		return "", derp.NewBadRequestError(location, "Webhook URL is required", target.SyndicationTargetID)
+	}
+
+	body, err := json.Marshal(post)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error encoding webhook body", post)
+	}
+
+	var response string
+
+	transaction := remote.Post(target.URL).
+		ContentType("application/json").
+		Body(string(body)).
+		Result(&response).
+		Use(adapter.options...)
+
+	if target.Secret != "" {
+		transaction.Header(WebhookSignatureHeader, WebhookSignature(target.Secret, body))
+	}
+
+	if err := transaction.Send(); err != nil {
+		return "", derp.Wrap(err, location, "Error sending webhook", target.URL)
+	}
+
+	// Look for the syndicated URL in the response body
+	if strings.TrimSpace(response) != "" {
+
+		result := struct {
+			URL string `json:"url"`
+		}{}
+
+		if err := json.Unmarshal([]byte(response), &result); err == nil && result.URL != "" {
+			return result.URL, nil
+		}
+	}
+
+	// Fall back to the Location header
+	if syndicatedURL := transaction.ResponseHeader().Get("Location"); syndicatedURL != "" {
+		return syndicatedURL, nil
+	}
+
+	return "", derp.NewInternalError(location, "Webhook did not return a URL", target.URL)
+}
+
+// WebhookSignature returns the value of the signature header for the provided body
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint:errcheck
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
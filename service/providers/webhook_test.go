@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Syndicate_JSON(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, WebhookSignature("SECRET", body), r.Header.Get(WebhookSignatureHeader))
+		require.Contains(t, string(body), `"url":"https://example.com/1"`)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"url":"https://other.example/posts/1"}`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.Type = model.SyndicationTargetTypeWebhook
+	target.URL = server.URL
+	target.Secret = "SECRET"
+
+	result, err := newTestWebhook().Syndicate(target, SyndicationPost{Type: "entry", Text: "Hello", URL: "https://example.com/1"})
+	require.Nil(t, err)
+	require.Equal(t, "https://other.example/posts/1", result)
+}
+
+func TestWebhook_Syndicate_Location(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get(WebhookSignatureHeader))
+		w.Header().Set("Location", "https://other.example/posts/2")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+
+	result, err := newTestWebhook().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.Nil(t, err)
+	require.Equal(t, "https://other.example/posts/2", result)
+}
+
+func TestWebhook_Syndicate_NoURL(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+
+	_, err := newTestWebhook().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.NotNil(t, err)
+}
+
+func TestWebhook_Syndicate_PrivateAddress(t *testing.T) {
+
+	called := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Header().Set("Location", "https://other.example/posts/3")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	target := model.NewSyndicationTarget()
+	target.URL = server.URL
+
+	// By default, webhooks cannot reach addresses on the server's own network
+	_, err := NewWebhook().Syndicate(target, SyndicationPost{Text: "Hello"})
+	require.NotNil(t, err)
+	require.False(t, called)
+}
+
+func TestWebhookSignature(t *testing.T) {
+	require.Equal(t, "sha256=d7dc55d27cf534a93271710e6b360344565b2637b8bd4dc955f6a17c3d2987ec", WebhookSignature("SECRET", []byte("Hello")))
+}
+
+// newTestWebhook returns a Webhook adapter that can reach the local test server
+func newTestWebhook() Webhook {
+	adapter := NewWebhook()
+	adapter.options = nil
+	return adapter
+}
@@ -218,6 +218,17 @@ func (service *Response) CountByContent(objectID string) (mapof.Int, error) {
  * Custom Behaviors
  ******************************************/
 
+// DeleteByUser removes all of a User's Responses (hard delete).  Undo activities are not
+// sent here because this is only used when the User's whole Actor is being deleted.
+func (service *Response) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.Response.DeleteByUser", "Error deleting Responses", userID)
+	}
+
+	return nil
+}
+
 // SetResponse is the preferred way of creating/updating a Response.  It includes the business
 // logic to search for an existing response, and delete it if one exists already (publishing UNDO actions in the process).
 func (service *Response) SetResponse(user *model.User, url string, responseType string, content string) error {
@@ -361,6 +361,28 @@ func (service *Rule) QueryBlockedActors(userID primitive.ObjectID) ([]model.Rule
 	return service.Query(criteria, option.SortAsc("trigger"))
 }
 
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// DeleteByUser permanently removes all of a User's Rules (hard delete).  Public Rules are NOT
+// unpublished here because this is only used when the User's whole Actor is being deleted.
+func (service *Rule) DeleteByUser(userID primitive.ObjectID) error {
+
+	const location = "service.Rule.DeleteByUser"
+
+	// RULE: Never remove domain-wide rules, which have an empty UserID
+	if userID.IsZero() {
+		return derp.NewInternalError(location, "UserID must not be empty")
+	}
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Error deleting Rules", userID)
+	}
+
+	return nil
+}
+
 /******************************************
  * Rule Filters
  ******************************************/
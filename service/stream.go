@@ -29,13 +29,16 @@ type Stream struct {
 	collection          data.Collection
 	templateService     *Template
 	draftService        *StreamDraft
+	revisionService     *StreamRevision
 	outboxService       *Outbox
 	attachmentService   *Attachment
 	activityService     *ActivityStream
 	contentService      *Content
 	keyService          *EncryptionKey
 	followerService     *Follower
+	mentionService      *Mention
 	ruleService         *Rule
+	syndicationService  *SyndicationTarget
 	userService         *User
 	host                string
 	streamUpdateChannel chan<- model.Stream
@@ -51,17 +54,20 @@ func NewStream() Stream {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Stream) Refresh(collection data.Collection, templateService *Template, draftService *StreamDraft, outboxService *Outbox, attachmentService *Attachment, activityService *ActivityStream, contentService *Content, keyService *EncryptionKey, followerService *Follower, ruleService *Rule, userService *User, host string, streamUpdateChannel chan model.Stream) {
+func (service *Stream) Refresh(collection data.Collection, templateService *Template, draftService *StreamDraft, revisionService *StreamRevision, outboxService *Outbox, attachmentService *Attachment, activityService *ActivityStream, contentService *Content, keyService *EncryptionKey, followerService *Follower, mentionService *Mention, ruleService *Rule, syndicationService *SyndicationTarget, userService *User, host string, streamUpdateChannel chan model.Stream) {
 	service.collection = collection
 	service.templateService = templateService
 	service.draftService = draftService
+	service.revisionService = revisionService
 	service.outboxService = outboxService
 	service.attachmentService = attachmentService
 	service.activityService = activityService
 	service.contentService = contentService
 	service.keyService = keyService
 	service.followerService = followerService
+	service.mentionService = mentionService
 	service.ruleService = ruleService
+	service.syndicationService = syndicationService
 	service.userService = userService
 
 	service.host = host
@@ -231,11 +237,35 @@ func (service *Stream) Save(stream *model.Stream, note string) error {
 	// RULE: Calculate the stream context
 	service.CalcContext(stream)
 
+	// RULE: Record the edit history of published streams
+	var revision *model.StreamRevision
+
+	if stream.PublishDate <= time.Now().Unix() {
+		snapshot, edited, err := service.revisionService.Snapshot(stream, note)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error calculating revision", stream)
+		}
+
+		if edited {
+			stream.EditDate = time.Now().Unix()
+		}
+
+		revision = snapshot
+	}
+
 	// Try to save the Stream to the database
 	if err := service.collection.Save(stream, note); err != nil {
 		return derp.Wrap(err, location, "Error saving Stream", stream, note)
 	}
 
+	// Record the revision only after the Stream has been saved successfully
+	if revision != nil {
+		if err := service.revisionService.Save(revision, note); err != nil {
+			return derp.Wrap(err, location, "Error recording revision", stream)
+		}
+	}
+
 	// NON-BLOCKING: Notify other processes on this server that the stream has been updated
 	go func() {
 		service.streamUpdateChannel <- *stream
@@ -264,6 +294,11 @@ func (service *Stream) Delete(stream *model.Stream, note string) error {
 			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting child streams", stream, note))
 		}
 
+		// RULE: Delete all related Revisions (and their archived Attachments)
+		if err := service.revisionService.DeleteByStream(stream.StreamID, note); err != nil {
+			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting revisions", stream, note))
+		}
+
 		// RULE: Delete all related Attachments
 		if err := service.attachmentService.DeleteAll(model.AttachmentTypeStream, stream.StreamID, note); err != nil {
 			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting attachments", stream, note))
@@ -391,6 +426,15 @@ func (service *Stream) ListByTemplate(template string) (data.Iterator, error) {
 	return service.List(exp.Equal("templateId", template))
 }
 
+// ListSyndicatedSince returns all published `Streams` that have been syndicated to other services since the provided date
+func (service *Stream) ListSyndicatedSince(publishDate int64) (data.Iterator, error) {
+	criteria := exp.GreaterOrEqual("publishDate", publishDate).
+		AndLessThan("publishDate", time.Now().Unix()).
+		AndNotEqual("syndication", nil)
+
+	return service.List(criteria)
+}
+
 // QueryByParentAndDate returns a slice of Streams that are DIRECT CHILDREN of the provided StreamID
 func (service *Stream) QueryByParentAndDate(streamID primitive.ObjectID, publishedDate int64, pageSize int) ([]model.Stream, error) {
 	criteria := exp.Equal("parentId", streamID).AndLessThan("publishDate", publishedDate)
@@ -442,6 +486,38 @@ func (service *Stream) LoadByURL(streamURL string, result *model.Stream) error {
 	return service.LoadByToken(token, result)
 }
 
+// LoadDeletedByURL returns a single soft-deleted `Stream` that matches the provided URL
+func (service *Stream) LoadDeletedByURL(streamURL string, result *model.Stream) error {
+
+	const location = "service.Stream.LoadDeletedByURL"
+
+	// Verify we have a valid URL
+	uri, err := url.Parse(streamURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid URL", streamURL)
+	}
+
+	// Retrieve the Token from the request path
+	token, _, err := service.ParsePath(uri)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid URL", streamURL)
+	}
+
+	criteria := exp.Equal("token", token)
+
+	if streamID, err := primitive.ObjectIDFromHex(token); err == nil {
+		criteria = exp.Equal("_id", streamID)
+	}
+
+	if err := service.collection.Load(criteria.AndGreaterThan("deleteDate", 0), result); err != nil {
+		return derp.Wrap(err, location, "Error loading deleted Stream", streamURL)
+	}
+
+	return nil
+}
+
 // LoadParent returns the Stream that is the parent of the provided Stream
 func (service *Stream) LoadParent(stream *model.Stream, parent *model.Stream) error {
 
@@ -543,6 +619,18 @@ func (service *Stream) MaxRank(parentID primitive.ObjectID) (int, error) {
 	return queries.MaxRank(context.TODO(), service.collection, parentID)
 }
 
+// SetSyndication records the results of syndicating a Stream, removing finished targets from
+// its SyndicateTo field and adding new URLs to its Syndication field.  No other fields are
+// changed, so edits made while the Stream was being syndicated are not overwritten.
+func (service *Stream) SetSyndication(streamID primitive.ObjectID, finished []string, syndication []string) error {
+
+	if err := queries.StreamSetSyndication(service.collection, streamID, finished, syndication); err != nil {
+		return derp.Wrap(err, "service.Stream.SetSyndication", "Error updating syndication", streamID)
+	}
+
+	return nil
+}
+
 /******************************************
  * Initialization Actions
  ******************************************/
@@ -624,6 +712,76 @@ func (service *Stream) DeleteByParent(parentID primitive.ObjectID, note string)
 	return service.DeleteMany(exp.Equal("parentId", parentID), note)
 }
 
+// PurgeByParent permanently removes every Stream underneath the provided parent, including Streams
+// that were already deleted, along with all of their children and related records (hard delete)
+func (service *Stream) PurgeByParent(parentID primitive.ObjectID) error {
+
+	const location = "service.Stream.PurgeByParent"
+
+	criteria := exp.Equal("parentId", parentID)
+	it, err := service.collection.Iterator(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing streams to purge", parentID)
+	}
+
+	stream := model.NewStream()
+
+	for it.Next(&stream) {
+		if err := service.purge(&stream); err != nil {
+			return derp.Wrap(err, location, "Error purging stream", stream.StreamID)
+		}
+		stream = model.NewStream()
+	}
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Error deleting streams", parentID)
+	}
+
+	return nil
+}
+
+// purge permanently removes all of the records related to a single Stream.
+// The Stream itself is removed by the caller.
+func (service *Stream) purge(stream *model.Stream) error {
+
+	const location = "service.Stream.purge"
+
+	if err := service.PurgeByParent(stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error purging child streams", stream.StreamID)
+	}
+
+	if err := service.revisionService.DeleteByStream(stream.StreamID, ""); err != nil {
+		return derp.Wrap(err, location, "Error deleting revisions", stream.StreamID)
+	}
+
+	if err := service.attachmentService.PurgeAll(model.AttachmentTypeStream, stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error deleting attachments", stream.StreamID)
+	}
+
+	if err := service.draftService.Delete(stream, ""); err != nil {
+		return derp.Wrap(err, location, "Error deleting draft", stream.StreamID)
+	}
+
+	if err := service.outboxService.DeleteByParentID(model.FollowerTypeStream, stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error deleting outbox messages", stream.StreamID)
+	}
+
+	if err := service.followerService.DeleteByParent(model.FollowerTypeStream, stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error deleting followers", stream.StreamID)
+	}
+
+	if err := service.mentionService.DeleteByObject(model.MentionTypeStream, stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error deleting mentions", stream.StreamID)
+	}
+
+	if err := service.keyService.DeleteByParentID(model.EncryptionKeyTypeStream, stream.StreamID); err != nil {
+		return derp.Wrap(err, location, "Error deleting encryption keys", stream.StreamID)
+	}
+
+	return nil
+}
+
 // Delete RelatedDuplicate hard deletes any inbox/outbox streams that point to the same original.
 func (service *Stream) DeleteRelatedDuplicate(parentID primitive.ObjectID, originalStreamID primitive.ObjectID) error {
 
@@ -665,6 +823,18 @@ func (service *Stream) RestoreDeleted(ancestorID primitive.ObjectID) error {
 	return nil
 }
 
+// UnDelete restores a single soft-deleted Stream
+func (service *Stream) UnDelete(stream *model.Stream, note string) error {
+
+	stream.Journal.DeleteDate = 0
+
+	if err := service.Save(stream, note); err != nil {
+		return derp.Wrap(err, "service.Stream.UnDelete", "Error restoring deleted stream", stream)
+	}
+
+	return nil
+}
+
 // PurgeDeleted hard deletes all items with the given ancestor that have already been soft-deleted
 func (service *Stream) PurgeDeleted(ancestorID primitive.ObjectID) error {
 
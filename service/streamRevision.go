@@ -0,0 +1,307 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StreamRevision manages the edit history of published Streams
+type StreamRevision struct {
+	collection        data.Collection
+	attachmentService *Attachment
+}
+
+// NewStreamRevision returns a fully populated StreamRevision service.
+func NewStreamRevision() StreamRevision {
+	return StreamRevision{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *StreamRevision) Refresh(collection data.Collection, attachmentService *Attachment) {
+	service.collection = collection
+	service.attachmentService = attachmentService
+}
+
+// Close stops any background processes controlled by this service
+func (service *StreamRevision) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// New returns a fully initialized StreamRevision
+func (service *StreamRevision) New() model.StreamRevision {
+	return model.NewStreamRevision()
+}
+
+// Query returns a slice containing all of the StreamRevisions that match the provided criteria
+func (service *StreamRevision) Query(criteria exp.Expression, options ...option.Option) ([]model.StreamRevision, error) {
+	result := make([]model.StreamRevision, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the StreamRevisions that match the provided criteria
+func (service *StreamRevision) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a StreamRevision from the database
+func (service *StreamRevision) Load(criteria exp.Expression, revision *model.StreamRevision) error {
+
+	if err := service.collection.Load(notDeleted(criteria), revision); err != nil {
+		return derp.Wrap(err, "service.StreamRevision.Load", "Error loading StreamRevision", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a StreamRevision in the database
+func (service *StreamRevision) Save(revision *model.StreamRevision, note string) error {
+
+	const location = "service.StreamRevision.Save"
+
+	// Clean the value before saving
+	if err := service.Schema().Clean(revision); err != nil {
+		return derp.Wrap(err, location, "Error cleaning StreamRevision", revision)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(revision, note); err != nil {
+		return derp.Wrap(err, location, "Error saving StreamRevision", revision, note)
+	}
+
+	return nil
+}
+
+// Delete removes a StreamRevision from the database (hard delete)
+func (service *StreamRevision) Delete(revision *model.StreamRevision, note string) error {
+
+	criteria := exp.Equal("_id", revision.StreamRevisionID)
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, "service.StreamRevision.Delete", "Error deleting StreamRevision", criteria)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *StreamRevision) ObjectType() string {
+	return "StreamRevision"
+}
+
+// New returns a fully initialized model.StreamRevision as a data.Object.
+func (service *StreamRevision) ObjectNew() data.Object {
+	result := model.NewStreamRevision()
+	return &result
+}
+
+func (service *StreamRevision) ObjectID(object data.Object) primitive.ObjectID {
+
+	if revision, ok := object.(*model.StreamRevision); ok {
+		return revision.StreamRevisionID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *StreamRevision) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *StreamRevision) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *StreamRevision) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewStreamRevision()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *StreamRevision) ObjectSave(object data.Object, comment string) error {
+	if revision, ok := object.(*model.StreamRevision); ok {
+		return service.Save(revision, comment)
+	}
+	return derp.NewInternalError("service.StreamRevision.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *StreamRevision) ObjectDelete(object data.Object, comment string) error {
+	if revision, ok := object.(*model.StreamRevision); ok {
+		return service.Delete(revision, comment)
+	}
+	return derp.NewInternalError("service.StreamRevision.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *StreamRevision) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.StreamRevision", "Not Authorized")
+}
+
+func (service *StreamRevision) Schema() schema.Schema {
+	return schema.New(model.StreamRevisionSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByStream returns all revisions of a Stream, newest first
+func (service *StreamRevision) QueryByStream(streamID primitive.ObjectID, options ...option.Option) ([]model.StreamRevision, error) {
+	criteria := exp.Equal("streamId", streamID)
+	options = append(options, option.SortDesc("createDate"))
+	return service.Query(criteria, options...)
+}
+
+// LoadByID loads a single revision of a Stream
+func (service *StreamRevision) LoadByID(streamID primitive.ObjectID, revisionID primitive.ObjectID, result *model.StreamRevision) error {
+	criteria := exp.Equal("_id", revisionID).AndEqual("streamId", streamID)
+	return service.Load(criteria, result)
+}
+
+// LoadLatest loads the most recent revision of a Stream
+func (service *StreamRevision) LoadLatest(streamID primitive.ObjectID, result *model.StreamRevision) error {
+
+	revisions, err := service.QueryByStream(streamID, option.FirstRow())
+
+	if err != nil {
+		return derp.Wrap(err, "service.StreamRevision.LoadLatest", "Error querying revisions", streamID)
+	}
+
+	if len(revisions) == 0 {
+		return derp.NewNotFoundError("service.StreamRevision.LoadLatest", "No revisions found", streamID)
+	}
+
+	*result = revisions[0]
+	return nil
+}
+
+// DeleteByStream removes all revisions of a Stream, along with any
+// archived attachments that were only kept to restore them
+func (service *StreamRevision) DeleteByStream(streamID primitive.ObjectID, note string) error {
+
+	const location = "service.StreamRevision.DeleteByStream"
+
+	if err := service.collection.HardDelete(exp.Equal("streamId", streamID)); err != nil {
+		return derp.Wrap(err, location, "Error deleting revisions", streamID)
+	}
+
+	if err := service.attachmentService.PurgeArchived(model.AttachmentTypeStream, streamID); err != nil {
+		return derp.Wrap(err, location, "Error purging archived attachments", streamID)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Revision Methods
+ ******************************************/
+
+// Snapshot returns a new revision of the provided Stream, or nil if the Stream is
+// identical to the most recent revision that was already recorded.  It also returns
+// TRUE if the Stream has been edited since its first revision was recorded.  The
+// revision is not saved, so callers should save it once the Stream itself is saved.
+func (service *StreamRevision) Snapshot(stream *model.Stream, note string) (*model.StreamRevision, bool, error) {
+
+	const location = "service.StreamRevision.Snapshot"
+
+	// Collect the Stream's current attachments
+	attachments, err := service.attachmentService.QueryByObjectID(model.AttachmentTypeStream, stream.StreamID)
+
+	if err != nil {
+		return nil, false, derp.Wrap(err, location, "Error loading attachments", stream.StreamID)
+	}
+
+	revision := model.NewStreamRevisionFromStream(stream, attachments)
+	revision.Note = note
+
+	// Skip duplicate revisions (streams are saved several times by a single action)
+	latest := model.NewStreamRevision()
+
+	if err := service.LoadLatest(stream.StreamID, &latest); err == nil {
+
+		if latest.Equal(&revision) {
+			return nil, false, nil
+		}
+
+		return &revision, true, nil
+
+	} else if !derp.NotFound(err) {
+		return nil, false, derp.Wrap(err, location, "Error loading latest revision", stream.StreamID)
+	}
+
+	return &revision, false, nil
+}
+
+// Restore copies a previous revision back into the provided Stream, including its attachments.
+// Current attachments that are not part of the revision are archived, and archived attachments
+// that are part of the revision are returned to the Stream.  The Stream itself is not saved.
+func (service *StreamRevision) Restore(revision *model.StreamRevision, stream *model.Stream, note string) error {
+
+	const location = "service.StreamRevision.Restore"
+
+	current, err := service.attachmentService.QueryByObjectID(model.AttachmentTypeStream, stream.StreamID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading attachments", stream.StreamID)
+	}
+
+	archived, err := service.attachmentService.QueryArchived(model.AttachmentTypeStream, stream.StreamID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading archived attachments", stream.StreamID)
+	}
+
+	// Archive current attachments that are not in the revision
+	for _, attachment := range current {
+		if !revision.HasAttachment(attachment) {
+			if err := service.attachmentService.Archive(&attachment, note); err != nil {
+				return derp.Wrap(err, location, "Error archiving attachment", attachment)
+			}
+		}
+	}
+
+	// Restore the revision's attachments, in their original order.
+	// Attachments that have since been purged cannot be restored.
+	for _, attachment := range append(current, archived...) {
+
+		index := revision.AttachmentIndex(attachment)
+
+		if index < 0 {
+			continue
+		}
+
+		if attachment.IsDeleted() {
+			attachment.Rank = index
+			if err := service.attachmentService.Unarchive(&attachment, note); err != nil {
+				return derp.Wrap(err, location, "Error restoring attachment", attachment)
+			}
+			continue
+		}
+
+		if attachment.Rank != index {
+			attachment.Rank = index
+			if err := service.attachmentService.Save(&attachment, note); err != nil {
+				return derp.Wrap(err, location, "Error sorting attachment", attachment)
+			}
+		}
+	}
+
+	revision.Restore(stream)
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/mediaserver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRevision_Restore(t *testing.T) {
+
+	attachmentCollection := newMemoryCollection()
+	revisionCollection := newMemoryCollection()
+
+	attachmentService := NewAttachment()
+	attachmentService.Refresh(&attachmentCollection, mediaserver.MediaServer{}, "https://local.test")
+
+	revisionService := NewStreamRevision()
+	revisionService.Refresh(&revisionCollection, &attachmentService)
+
+	stream := model.NewStream()
+	stream.Label = "First Draft"
+
+	// Record a revision with two attachments
+	first := model.NewAttachment(model.AttachmentTypeStream, stream.StreamID)
+	first.Rank = 0
+	require.Nil(t, attachmentService.Save(&first, "Test"))
+
+	second := model.NewAttachment(model.AttachmentTypeStream, stream.StreamID)
+	second.Rank = 1
+	require.Nil(t, attachmentService.Save(&second, "Test"))
+
+	revision, edited, err := revisionService.Snapshot(&stream, "Test")
+	require.Nil(t, err)
+	require.False(t, edited)
+	require.NotNil(t, revision)
+	require.Nil(t, revisionService.Save(revision, "Test"))
+
+	// Unchanged streams do not create new revisions
+	duplicate, _, err := revisionService.Snapshot(&stream, "Test")
+	require.Nil(t, err)
+	require.Nil(t, duplicate)
+
+	// Edit the stream: remove the first attachment and add a third
+	stream.Label = "Second Draft"
+	require.Nil(t, attachmentService.ArchiveByID(model.AttachmentTypeStream, stream.StreamID, first.AttachmentID, "Test"))
+
+	third := model.NewAttachment(model.AttachmentTypeStream, stream.StreamID)
+	third.Rank = 2
+	require.Nil(t, attachmentService.Save(&third, "Test"))
+
+	_, edited, err = revisionService.Snapshot(&stream, "Test")
+	require.Nil(t, err)
+	require.True(t, edited)
+
+	// Restoring the first revision brings back its values and attachments
+	require.Nil(t, revisionService.Restore(revision, &stream, "Test"))
+	require.Equal(t, "First Draft", stream.Label)
+
+	attachments, err := attachmentService.QueryByObjectID(model.AttachmentTypeStream, stream.StreamID)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(attachments))
+
+	ranks := map[string]int{}
+	for _, attachment := range attachments {
+		ranks[attachment.AttachmentID.Hex()] = attachment.Rank
+	}
+
+	require.Equal(t, map[string]int{first.AttachmentID.Hex(): 0, second.AttachmentID.Hex(): 1}, ranks)
+
+	// The removed attachment is archived, so it can be restored again later
+	archived, err := attachmentService.QueryArchived(model.AttachmentTypeStream, stream.StreamID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(archived))
+	require.Equal(t, third.AttachmentID, archived[0].AttachmentID)
+}
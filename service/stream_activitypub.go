@@ -34,6 +34,10 @@ func (service *Stream) JSONLD(stream *model.Stream) mapof.Any {
 		// "shares":    stream.ActivityPubSharesURL(),
 	}
 
+	if stream.IsEdited() {
+		result[vocab.PropertyUpdated] = stream.EditedAt()
+	}
+
 	if stream.Label != "" {
 		result[vocab.PropertyName] = stream.Label
 	}
@@ -42,6 +46,10 @@ func (service *Stream) JSONLD(stream *model.Stream) mapof.Any {
 		result[vocab.PropertySummary] = stream.Summary
 	}
 
+	if stream.Sensitive {
+		result["sensitive"] = true
+	}
+
 	if stream.Content.HTML != "" {
 		result[vocab.PropertyContent] = stream.Content.HTML
 	}
@@ -67,6 +75,10 @@ func (service *Stream) JSONLD(stream *model.Stream) mapof.Any {
 		result[vocab.PropertyTag] = slice.Map(stream.Tags, model.TagAsJSONLD)
 	}
 
+	if stream.QuoteURL != "" {
+		service.quoteJSONLD(stream, result)
+	}
+
 	// NOTE: According to Mastodon ActivityPub guide (https://docs.joinmastodon.org/spec/activitypub/)
 	// putting as:public in the To field means that this mesage is public, and "listed"
 	// putting as:public in the Cc field means that this message is public, but "unlisted"
@@ -74,6 +86,7 @@ func (service *Stream) JSONLD(stream *model.Stream) mapof.Any {
 
 	if stream.DefaultAllowAnonymous() {
 		result[vocab.PropertyTo] = []string{vocab.NamespaceActivityStreamsPublic}
+		result["interactionPolicy"] = quoteInteractionPolicy()
 	}
 
 	// Attachments
@@ -114,7 +127,7 @@ func (service *Stream) ActivityPubActor(streamID primitive.ObjectID, withFollowe
 	}
 
 	// Return the ActivityPub Actor
-	actor := outbox.NewActor(service.ActivityPubURL(streamID), privateKey)
+	actor := outbox.NewActor(service.ActivityPubURL(streamID), privateKey, outbox.WithPublicKey(service.keyService.KeyID(&encryptionKey)))
 
 	// Populate the Actor's ActivityPub Followers, if requested
 	if withFollowers {
@@ -136,3 +149,42 @@ func (service *Stream) ActivityPubActor(streamID primitive.ObjectID, withFollowe
 
 	return actor, nil
 }
+
+// SendActorUpdate sends an "Update" activity to all of the Stream's Followers, so that
+// they receive the Stream's current Actor profile (including any new public keys)
+func (service *Stream) SendActorUpdate(streamID primitive.ObjectID) error {
+
+	const location = "service.Stream.SendActorUpdate"
+
+	stream := model.NewStream()
+
+	if err := service.LoadByID(streamID, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading Stream", streamID)
+	}
+
+	template, err := service.templateService.Load(stream.TemplateID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Template", stream.TemplateID)
+	}
+
+	// Streams that are not Actors do not have Followers to notify
+	if template.Actor.IsNil() {
+		return nil
+	}
+
+	document := template.Actor.JSONLD(&stream)
+
+	if err := service.keyService.JSONLD(model.EncryptionKeyTypeStream, streamID, document); err != nil {
+		return derp.Wrap(err, location, "Error adding public keys", streamID)
+	}
+
+	actor, err := service.ActivityPubActor(streamID, true)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Actor", streamID)
+	}
+
+	go actor.Send(actorUpdateActivity(stream.ActivityPubURL(), document))
+	return nil
+}
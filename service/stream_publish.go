@@ -81,6 +81,9 @@ func (service *Stream) Publish(user *model.User, stream *model.Stream) error {
 		return derp.Wrap(err, location, "Error publishing to parent Stream's outbox")
 	}
 
+	// Re-post to any external services selected by the User (POSSE)
+	service.syndicationService.Syndicate(user, stream)
+
 	return nil
 }
 
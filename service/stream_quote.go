@@ -0,0 +1,94 @@
+package service
+
+import (
+	"html"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/asnormalizer"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+)
+
+/******************************************
+ * Quote Posts
+ ******************************************/
+
+// quoteJSONLD adds the properties for a quote post into a JSON-LD document.
+// There is no single standard for quotes (yet) so this includes FEP-e232 Object Links,
+// along with the custom properties used by Misskey, Akkoma, and Mastodon.
+func (service *Stream) quoteJSONLD(stream *model.Stream, result mapof.Any) {
+
+	// Custom properties used by other servers
+	result["quote"] = stream.QuoteURL
+	result["quoteUrl"] = stream.QuoteURL
+	result["quoteUri"] = stream.QuoteURL
+	result["_misskey_quote"] = stream.QuoteURL
+
+	// FEP-e232 Object Link
+	// https://codeberg.org/fediverse/fep/src/branch/main/fep/e232/fep-e232.md
+	link := mapof.Any{
+		vocab.PropertyType:      vocab.CoreTypeLink,
+		vocab.PropertyMediaType: vocab.ContentTypeJSONLDWithProfile,
+		vocab.PropertyRel:       asnormalizer.QuoteRel,
+		vocab.PropertyHref:      stream.QuoteURL,
+		vocab.PropertyName:      "RE: " + stream.QuoteURL,
+	}
+
+	tags, _ := result[vocab.PropertyTag].([]mapof.Any)
+	result[vocab.PropertyTag] = append(tags, link)
+
+	// Fallback content for servers that do not display quotes
+	quoteURL := html.EscapeString(stream.QuoteURL)
+	content, _ := result[vocab.PropertyContent].(string)
+	result[vocab.PropertyContent] = content + `<p class="quote-inline">RE: <a href="` + quoteURL + `">` + quoteURL + `</a></p>`
+}
+
+// quoteInteractionPolicy returns the "interactionPolicy" for public streams,
+// which allows anyone to quote them.
+func quoteInteractionPolicy() mapof.Any {
+	return mapof.Any{
+		"canQuote": mapof.Any{
+			"automaticApproval": []string{vocab.NamespaceActivityStreamsPublic},
+		},
+	}
+}
+
+// ValidateQuote confirms that the author of a quoted document allows it to be quoted.
+// Documents that do not publish an "interactionPolicy" are assumed to be quotable.
+// Quotes that require manual approval are not allowed, because there is no way
+// to ask for (or wait for) that approval yet.
+func (service *Stream) ValidateQuote(stream *model.Stream) error {
+
+	const location = "service.Stream.ValidateQuote"
+
+	// RULE: Only validate quotes
+	if stream.QuoteURL == "" {
+		return nil
+	}
+
+	// Load the quoted document
+	document, err := service.activityService.Load(stream.QuoteURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading quoted document", stream.QuoteURL)
+	}
+
+	// If there is no policy, then quotes are allowed
+	canQuote := document.Get("interactionPolicy").Get("canQuote")
+
+	if canQuote.IsNil() {
+		return nil
+	}
+
+	// Search automatic approvals for the Public namespace or the current author
+	for approval := canQuote.Get("automaticApproval"); approval.NotNil(); approval = approval.Tail() {
+		switch approval.ID() {
+		case vocab.NamespaceActivityStreamsPublic, "as:Public", "Public", stream.AttributedTo.ProfileURL:
+			return nil
+		}
+	}
+
+	// Otherwise, nope.
+	return derp.NewForbiddenError(location, "Quoted document does not allow quotes", stream.QuoteURL)
+}
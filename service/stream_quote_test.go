@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_ValidateQuote(t *testing.T) {
+
+	client := quoteTestClient{
+		"https://remote.test/notes/none": {"id": "https://remote.test/notes/none", "type": "Note"},
+		"https://remote.test/notes/public": {"id": "https://remote.test/notes/public", "type": "Note", "interactionPolicy": mapof.Any{
+			"canQuote": mapof.Any{"automaticApproval": []any{vocab.NamespaceActivityStreamsPublic}},
+		}},
+		"https://remote.test/notes/me": {"id": "https://remote.test/notes/me", "type": "Note", "interactionPolicy": mapof.Any{
+			"canQuote": mapof.Any{"automaticApproval": "https://local.test/@me"},
+		}},
+		"https://remote.test/notes/manual": {"id": "https://remote.test/notes/manual", "type": "Note", "interactionPolicy": mapof.Any{
+			"canQuote": mapof.Any{"manualApproval": []any{vocab.NamespaceActivityStreamsPublic}},
+		}},
+		"https://remote.test/notes/other": {"id": "https://remote.test/notes/other", "type": "Note", "interactionPolicy": mapof.Any{
+			"canQuote": mapof.Any{"automaticApproval": "https://remote.test/users/alice"},
+		}},
+	}
+
+	activityService := NewActivityStream()
+	activityService.Refresh(client, nil, nil)
+
+	streamService := NewStream()
+	streamService.activityService = &activityService
+
+	validate := func(quoteURL string) error {
+		stream := model.NewStream()
+		stream.QuoteURL = quoteURL
+		stream.AttributedTo.ProfileURL = "https://local.test/@me"
+		return streamService.ValidateQuote(&stream)
+	}
+
+	// Streams that are not quotes are always valid
+	require.Nil(t, validate(""))
+
+	// Automatic approvals (or no policy at all) allow quotes
+	require.Nil(t, validate("https://remote.test/notes/none"))
+	require.Nil(t, validate("https://remote.test/notes/public"))
+	require.Nil(t, validate("https://remote.test/notes/me"))
+
+	// Manual approvals, and approvals for other people, do not
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(validate("https://remote.test/notes/manual")))
+	require.Equal(t, http.StatusForbidden, derp.ErrorCode(validate("https://remote.test/notes/other")))
+}
+
+// quoteTestClient is an in-memory streams.Client that serves a fixed set of documents
+type quoteTestClient map[string]mapof.Any
+
+func (client quoteTestClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	if value, ok := client[uri]; ok {
+		return streams.NewDocument(value, streams.WithClient(client)), nil
+	}
+
+	return streams.NilDocument(), derp.NewNotFoundError("quoteTestClient.Load", "Document not found", uri)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/unicode/norm"
+)
+
+/******************************************
+ * Stream Tokens
+ ******************************************/
+
+// streamTokenMaxLength leaves room for a numeric suffix within the 128 characters allowed by the Stream schema
+const streamTokenMaxLength = 100
+
+// reservedStreamTokens are paths at the root of every domain that are handled by
+// the server itself, so they cannot be used as Stream tokens.
+var reservedStreamTokens = map[string]bool{
+	"admin":    true,
+	"api":      true,
+	"domains":  true,
+	"nodeinfo": true,
+	"oauth":    true,
+	"register": true,
+	"server":   true,
+	"signin":   true,
+	"signout":  true,
+	"startup":  true,
+}
+
+// UniqueToken converts a requested value (like a Micropub "mp-slug") into a URL-safe token
+// that is not reserved, does not look like a StreamID, and is not used by any other Stream.
+// A numeric suffix is added when the token is already taken.  It returns an empty string
+// if no usable token is found, in which case the StreamID is used instead.
+func (service *Stream) UniqueToken(value string, streamID primitive.ObjectID) (string, error) {
+
+	const location = "service.Stream.UniqueToken"
+
+	token := slugify(value)
+
+	if token == "" {
+		return "", nil
+	}
+
+	for suffix := 1; suffix <= 100; suffix++ {
+
+		candidate := token
+
+		if suffix > 1 {
+			candidate = token + "-" + strconv.Itoa(suffix)
+		}
+
+		// RULE: Tokens cannot hide server routes or other Streams' IDs
+		if reservedStreamTokens[candidate] {
+			continue
+		}
+
+		if _, err := primitive.ObjectIDFromHex(candidate); err == nil {
+			continue
+		}
+
+		// RULE: Tokens must be unique
+		count, err := service.Count(exp.Equal("token", candidate).AndNotEqual("_id", streamID))
+
+		if err != nil {
+			return "", derp.Wrap(err, location, "Error counting Streams", candidate)
+		}
+
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+
+	return "", nil
+}
+
+// slugify returns a lowercase version of the value that contains only letters,
+// numbers, and single dashes, so that it can be used as a Stream token.
+// Accents are removed from letters, and all other characters become dashes.
+func slugify(value string) string {
+
+	var result strings.Builder
+	dash := false
+
+	for _, r := range norm.NFD.String(strings.ToLower(value)) {
+
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		if (r < unicode.MaxASCII) && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			result.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && (result.Len() > 0) {
+			result.WriteRune('-')
+			dash = true
+		}
+	}
+
+	token := strings.TrimSuffix(result.String(), "-")
+
+	if len(token) > streamTokenMaxLength {
+		token = strings.TrimSuffix(token[:streamTokenMaxLength], "-")
+	}
+
+	return token
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	require.Equal(t, "hello-world", slugify("Hello, World!"))
+	require.Equal(t, "a-b", slugify("--a///b--"))
+	require.Equal(t, "cafe", slugify("café"))
+	require.Equal(t, "", slugify("../.."))
+}
+
+func TestStream_UniqueToken(t *testing.T) {
+
+	collection := newMemoryCollection()
+
+	service := NewStream()
+	service.collection = &collection
+
+	existing := model.NewStream()
+	existing.Token = "hello-world"
+	require.Nil(t, collection.Save(&existing, ""))
+
+	streamID := model.NewStream().StreamID
+
+	// Tokens that are already taken get a suffix
+	token, err := service.UniqueToken("Hello World", streamID)
+	require.Nil(t, err)
+	require.Equal(t, "hello-world-2", token)
+
+	// A Stream can keep its own token
+	token, err = service.UniqueToken("hello-world", existing.StreamID)
+	require.Nil(t, err)
+	require.Equal(t, "hello-world", token)
+
+	// Reserved routes and StreamIDs cannot be used
+	token, err = service.UniqueToken("signin", streamID)
+	require.Nil(t, err)
+	require.Equal(t, "signin-2", token)
+
+	token, err = service.UniqueToken(existing.StreamID.Hex(), streamID)
+	require.Nil(t, err)
+	require.Equal(t, existing.StreamID.Hex()+"-2", token)
+
+	// Values without any usable characters fall back to the StreamID
+	token, err = service.UniqueToken("!!!", streamID)
+	require.Nil(t, err)
+	require.Equal(t, "", token)
+}
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service/providers"
+	"github.com/EmissarySocial/emissary/tools/keywrap"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
+	"github.com/benpate/rosetta/iterator"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SyndicationTarget defines a service that manages the external services where
+// Users re-post their Streams (POSSE)
+type SyndicationTarget struct {
+	collection               data.Collection
+	keyEncryptingKey         []byte
+	previousKeyEncryptingKey []byte
+	providerService          *Provider
+	streamService            *Stream
+	mentionService           *Mention
+	activityService          *ActivityStream
+	queue                    queue.Queue
+	closed                   chan bool
+}
+
+// NewSyndicationTarget returns a fully initialized SyndicationTarget service
+func NewSyndicationTarget() SyndicationTarget {
+	return SyndicationTarget{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *SyndicationTarget) Refresh(collection data.Collection, keyEncryptingKey []byte, providerService *Provider, streamService *Stream, mentionService *Mention, activityService *ActivityStream, queue queue.Queue) {
+	service.collection = collection
+	service.keyEncryptingKey = keyEncryptingKey
+	service.providerService = providerService
+	service.streamService = streamService
+	service.mentionService = mentionService
+	service.activityService = activityService
+	service.queue = queue
+}
+
+// Close stops the backfeed watcher
+func (service *SyndicationTarget) Close() {
+	close(service.closed)
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of all the SyndicationTargets that match the provided criteria
+func (service *SyndicationTarget) Query(criteria exp.Expression, options ...option.Option) ([]model.SyndicationTarget, error) {
+	result := make([]model.SyndicationTarget, 0)
+
+	if err := service.collection.Query(&result, notDeleted(criteria), options...); err != nil {
+		return nil, derp.Wrap(err, "service.SyndicationTarget.Query", "Error querying SyndicationTargets", criteria)
+	}
+
+	// Decrypt secrets in memory
+	for index := range result {
+		if err := service.decrypt(&result[index]); err != nil {
+			return nil, derp.Wrap(err, "service.SyndicationTarget.Query", "Error decrypting SyndicationTarget", result[index].SyndicationTargetID)
+		}
+	}
+
+	return result, nil
+}
+
+// List returns an iterator containing all of the SyndicationTargets that match the provided criteria
+func (service *SyndicationTarget) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Channel returns a channel that will stream all of the SyndicationTargets that match the provided criteria
+func (service *SyndicationTarget) Channel(criteria exp.Expression, options ...option.Option) (<-chan model.SyndicationTarget, error) {
+	it, err := service.List(criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.SyndicationTarget.Channel", "Error creating iterator", criteria, options)
+	}
+
+	return iterator.Channel[model.SyndicationTarget](it, model.NewSyndicationTarget), nil
+}
+
+// Load retrieves a SyndicationTarget from the database
+func (service *SyndicationTarget) Load(criteria exp.Expression, target *model.SyndicationTarget) error {
+
+	if err := service.collection.Load(notDeleted(criteria), target); err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.Load", "Error loading SyndicationTarget", criteria)
+	}
+
+	// Decrypt the secret in memory
+	if err := service.decrypt(target); err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.Load", "Error decrypting SyndicationTarget", target.SyndicationTargetID)
+	}
+
+	return nil
+}
+
+// Save adds/updates a SyndicationTarget in the database
+func (service *SyndicationTarget) Save(target *model.SyndicationTarget, note string) error {
+
+	const location = "service.SyndicationTarget.Save"
+
+	// Clean the value before saving
+	if err := service.Schema().Clean(target); err != nil {
+		return derp.Wrap(err, location, "Error cleaning SyndicationTarget", target)
+	}
+
+	// RULE: The target type must have a matching provider
+	if _, ok := service.providerService.GetSyndicator(target.Type); !ok {
+		return derp.NewBadRequestError(location, "Unknown syndication type", target.Type)
+	}
+
+	// RULE: Targets must not point to private addresses on the server's network
+	if target.URL != "" {
+		if err := ssrf.ValidateURL(target.URL); err != nil {
+			return derp.Wrap(err, location, "Invalid target URL", target.URL)
+		}
+	}
+
+	// Encrypt the secret before it is stored
+	if err := service.encrypt(target); err != nil {
+		return derp.Wrap(err, location, "Error encrypting SyndicationTarget", target.SyndicationTargetID)
+	}
+
+	// Save the target to the database
+	if err := service.collection.Save(target, note); err != nil {
+		return derp.Wrap(err, location, "Error saving SyndicationTarget", target, note)
+	}
+
+	return nil
+}
+
+// Delete removes a SyndicationTarget from the database (virtual delete)
+func (service *SyndicationTarget) Delete(target *model.SyndicationTarget, note string) error {
+
+	if err := service.collection.Delete(target, note); err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.Delete", "Error deleting SyndicationTarget", target, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *SyndicationTarget) ObjectType() string {
+	return "SyndicationTarget"
+}
+
+// New returns a fully initialized model.SyndicationTarget as a data.Object.
+func (service *SyndicationTarget) ObjectNew() data.Object {
+	result := model.NewSyndicationTarget()
+	return &result
+}
+
+func (service *SyndicationTarget) ObjectID(object data.Object) primitive.ObjectID {
+
+	if target, ok := object.(*model.SyndicationTarget); ok {
+		return target.SyndicationTargetID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *SyndicationTarget) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *SyndicationTarget) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *SyndicationTarget) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewSyndicationTarget()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *SyndicationTarget) ObjectSave(object data.Object, comment string) error {
+	if target, ok := object.(*model.SyndicationTarget); ok {
+		return service.Save(target, comment)
+	}
+	return derp.NewInternalError("service.SyndicationTarget.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *SyndicationTarget) ObjectDelete(object data.Object, comment string) error {
+	if target, ok := object.(*model.SyndicationTarget); ok {
+		return service.Delete(target, comment)
+	}
+	return derp.NewInternalError("service.SyndicationTarget.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *SyndicationTarget) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.SyndicationTarget", "Not Authorized")
+}
+
+func (service *SyndicationTarget) Schema() schema.Schema {
+	return schema.New(model.SyndicationTargetSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns all of the SyndicationTargets owned by the provided User
+func (service *SyndicationTarget) QueryByUser(userID primitive.ObjectID) ([]model.SyndicationTarget, error) {
+	return service.Query(exp.Equal("userId", userID), option.SortAsc("label"))
+}
+
+// LoadByID retrieves a single SyndicationTarget owned by the provided User
+func (service *SyndicationTarget) LoadByID(userID primitive.ObjectID, syndicationTargetID primitive.ObjectID, target *model.SyndicationTarget) error {
+
+	criteria := exp.Equal("_id", syndicationTargetID).AndEqual("userId", userID)
+
+	return service.Load(criteria, target)
+}
+
+// LoadByToken retrieves a single SyndicationTarget owned by the provided User, using a string token
+func (service *SyndicationTarget) LoadByToken(userID primitive.ObjectID, token string, target *model.SyndicationTarget) error {
+
+	syndicationTargetID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.LoadByToken", "Error converting token to ObjectID", token)
+	}
+
+	return service.LoadByID(userID, syndicationTargetID, target)
+}
+
+// DeleteByUser permanently removes all of a User's SyndicationTargets, along with their secrets (hard delete)
+func (service *SyndicationTarget) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.DeleteByUser", "Error deleting SyndicationTargets", userID)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Syndication Methods
+ ******************************************/
+
+// Syndicate queues a background task that re-posts the Stream to each of
+// the SyndicationTargets listed in its SyndicateTo field.
+func (service *SyndicationTarget) Syndicate(user *model.User, stream *model.Stream) {
+
+	if len(stream.SyndicateTo) == 0 {
+		return
+	}
+
+	service.queue.Push(NewTaskSyndicateStream(service, user.UserID, stream.StreamID))
+}
+
+// SyndicateStream re-posts the Stream to each of the SyndicationTargets listed in
+// its SyndicateTo field, and records the resulting URLs in its Syndication field.
+// Targets that fail are left in SyndicateTo so that they can be retried later.
+func (service *SyndicationTarget) SyndicateStream(userID primitive.ObjectID, streamID primitive.ObjectID) error {
+
+	const location = "service.SyndicationTarget.SyndicateStream"
+
+	// Load the most recent copy of the Stream
+	stream := model.NewStream()
+
+	if err := service.streamService.LoadByID(streamID, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading Stream", streamID)
+	}
+
+	// RULE: Only published Streams can be syndicated
+	if !stream.IsPublished() {
+		return nil
+	}
+
+	post := providers.NewSyndicationPost(stream)
+	finished := make(sliceof.String, 0, len(stream.SyndicateTo))
+	remaining := make(sliceof.String, 0, len(stream.SyndicateTo))
+	syndication := make(sliceof.String, 0, len(stream.SyndicateTo))
+
+	for _, token := range stream.SyndicateTo {
+
+		if token == "" {
+			finished = append(finished, token)
+			continue
+		}
+
+		// Load the target.  Targets that have been removed are ignored.
+		target := model.NewSyndicationTarget()
+
+		if err := service.LoadByToken(userID, token, &target); err != nil {
+			if derp.NotFound(err) {
+				finished = append(finished, token)
+			} else {
+				derp.Report(derp.Wrap(err, location, "Error loading SyndicationTarget", token))
+				remaining = append(remaining, token)
+			}
+			continue
+		}
+
+		syndicator, ok := service.providerService.GetSyndicator(target.Type)
+
+		if !ok {
+			finished = append(finished, token)
+			continue
+		}
+
+		syndicatedURL, err := syndicator.Syndicate(target, post)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error syndicating Stream", target.SyndicationTargetID, stream.StreamID))
+			remaining = append(remaining, token)
+			continue
+		}
+
+		finished = append(finished, token)
+
+		if syndicatedURL != "" {
+			syndication = append(syndication, syndicatedURL)
+		}
+	}
+
+	// Record the results without overwriting changes made to the Stream in the meantime
+	if err := service.streamService.SetSyndication(stream.StreamID, finished, syndication); err != nil {
+		return derp.Wrap(err, location, "Error updating Stream", stream.StreamID)
+	}
+
+	if len(remaining) > 0 {
+		return derp.NewInternalError(location, "Unable to syndicate Stream to all targets", stream.StreamID, remaining)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Encryption Methods
+ ******************************************/
+
+// SetKeyEncryptingKeys updates the key encrypting keys used by this service.  New values are always
+// encrypted with the current key.  The previous key is only set while the key encrypting key is being
+// rotated, so that values which have not been re-encrypted yet can still be decrypted.
+func (service *SyndicationTarget) SetKeyEncryptingKeys(keyEncryptingKey []byte, previousKeyEncryptingKey []byte) {
+	service.keyEncryptingKey = keyEncryptingKey
+	service.previousKeyEncryptingKey = previousKeyEncryptingKey
+}
+
+// Rewrap encrypts every SyndicationTarget secret with the current key encrypting key.  Secrets that
+// are already encrypted with the current key encrypting key are skipped, so this can safely be run
+// again if it is interrupted.
+func (service *SyndicationTarget) Rewrap() error {
+
+	const location = "service.SyndicationTarget.Rewrap"
+
+	it, err := service.collection.Iterator(exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing SyndicationTargets")
+	}
+
+	target := model.NewSyndicationTarget()
+
+	for it.Next(&target) {
+
+		if err := service.rewrap(target); err != nil {
+			return derp.Wrap(err, location, "Error re-encrypting SyndicationTarget", target.SyndicationTargetID)
+		}
+
+		target = model.NewSyndicationTarget()
+	}
+
+	return nil
+}
+
+// rewrap decrypts a single SyndicationTarget secret with either key encrypting key,
+// then encrypts it with the current key encrypting key and saves it to the database
+func (service *SyndicationTarget) rewrap(target model.SyndicationTarget) error {
+
+	const location = "service.SyndicationTarget.rewrap"
+
+	if len(target.EncryptedSecret) == 0 {
+		return nil
+	}
+
+	if _, err := keywrap.Unwrap(service.keyEncryptingKey, target.EncryptedSecret, target.SyndicationTargetID[:]); err == nil {
+		return nil
+	}
+
+	if err := service.decrypt(&target); err != nil {
+		return derp.Wrap(err, location, "Error decrypting SyndicationTarget")
+	}
+
+	if err := service.encrypt(&target); err != nil {
+		return derp.Wrap(err, location, "Error encrypting SyndicationTarget")
+	}
+
+	if err := service.collection.Save(&target, "Rotated key encrypting key"); err != nil {
+		return derp.Wrap(err, location, "Error saving SyndicationTarget")
+	}
+
+	return nil
+}
+
+// encrypt encrypts the Secret field of the SyndicationTarget
+// and stores the result in the EncryptedSecret field.
+func (service *SyndicationTarget) encrypt(target *model.SyndicationTarget) error {
+	return encryptSyndicationSecret(service.keyEncryptingKey, target)
+}
+
+// decrypt decrypts the EncryptedSecret field of the SyndicationTarget
+// and stores the result in the Secret field.
+func (service *SyndicationTarget) decrypt(target *model.SyndicationTarget) error {
+
+	if len(target.EncryptedSecret) == 0 {
+		target.Secret = ""
+		return nil
+	}
+
+	plaintext, err := keywrap.UnwrapAny([][]byte{service.keyEncryptingKey, service.previousKeyEncryptingKey}, target.EncryptedSecret, target.SyndicationTargetID[:])
+
+	if err != nil {
+		return derp.Wrap(err, "service.SyndicationTarget.decrypt", "Error decrypting secret")
+	}
+
+	target.Secret = string(plaintext)
+	return nil
+}
+
+// encryptSyndicationSecret encrypts the Secret field of the SyndicationTarget with the provided key encrypting key.
+// The SyndicationTargetID is used as additional data, so encrypted secrets cannot be moved from one target to another.
+func encryptSyndicationSecret(keyEncryptingKey []byte, target *model.SyndicationTarget) error {
+
+	if target.Secret == "" {
+		target.EncryptedSecret = nil
+		return nil
+	}
+
+	ciphertext, err := keywrap.Wrap(keyEncryptingKey, []byte(target.Secret), target.SyndicationTargetID[:])
+
+	if err != nil {
+		return derp.Wrap(err, "service.encryptSyndicationSecret", "Error encrypting secret")
+	}
+
+	target.EncryptedSecret = ciphertext
+	return nil
+}
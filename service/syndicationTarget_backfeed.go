@@ -0,0 +1,252 @@
+package service
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service/providers"
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+)
+
+// backfeedMaxAge is the number of days after publishing that syndicated copies are checked for responses
+const backfeedMaxAge = 30
+
+// Start begins the background scheduler that checks the syndicated copies of
+// recently published Streams for new responses (backfeed).
+// https://indieweb.org/backfeed
+func (service *SyndicationTarget) Start() {
+
+	const location = "service.SyndicationTarget.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Poll randomly between 30 and 60 minutes
+		time.Sleep(time.Duration(rand.Intn(30)+30) * time.Minute)
+
+		// If (for some reason) the service collection is still nil, then
+		// wait this one out.
+		if service.collection == nil {
+			continue
+		}
+
+		// Get a list of all recent Streams that have been syndicated
+		it, err := service.streamService.ListSyndicatedSince(time.Now().AddDate(0, 0, -backfeedMaxAge).Unix())
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error listing syndicated streams"))
+			continue
+		}
+
+		stream := model.NewStream()
+
+		for it.Next(&stream) {
+			select {
+
+			// If we're done, we're done.
+			case <-service.closed:
+				return
+
+			default:
+				if err := service.Backfeed(&stream); err != nil {
+					derp.Report(derp.Wrap(err, location, "Error backfeeding stream", stream.StreamID))
+				}
+			}
+
+			stream = model.NewStream()
+		}
+	}
+}
+
+// Backfeed retrieves the replies, likes, and reposts of each syndicated copy of the Stream
+// and records them as Mentions of the original.  Responses that have already arrived
+// natively (via ActivityPub or WebMention) are skipped.
+func (service *SyndicationTarget) Backfeed(stream *model.Stream) error {
+
+	const location = "service.SyndicationTarget.Backfeed"
+
+	if len(stream.Syndication) == 0 {
+		return nil
+	}
+
+	targets, err := service.QueryByUser(stream.AttributedTo.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading SyndicationTargets", stream.AttributedTo.UserID)
+	}
+
+	for _, syndicationURL := range stream.Syndication {
+
+		target, backfeeder, ok := service.findBackfeeder(targets, syndicationURL)
+
+		if !ok {
+			continue
+		}
+
+		responses, err := backfeeder.Backfeed(target, syndicationURL)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error retrieving responses", target.SyndicationTargetID, syndicationURL))
+			continue
+		}
+
+		for _, response := range responses {
+			if err := service.saveBackfeedResponse(stream, target, response); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error saving response", stream.StreamID, response.URL))
+			}
+		}
+	}
+
+	return nil
+}
+
+// findBackfeeder returns the SyndicationTarget (and its Backfeeder) that created a syndicated copy
+func (service *SyndicationTarget) findBackfeeder(targets []model.SyndicationTarget, syndicationURL string) (model.SyndicationTarget, providers.Backfeeder, bool) {
+
+	for _, target := range targets {
+
+		syndicator, ok := service.providerService.GetSyndicator(target.Type)
+
+		if !ok {
+			continue
+		}
+
+		backfeeder, ok := syndicator.(providers.Backfeeder)
+
+		if !ok {
+			continue
+		}
+
+		if backfeeder.IsSyndicatedBy(target, syndicationURL) {
+			return target, backfeeder, true
+		}
+	}
+
+	return model.SyndicationTarget{}, nil, false
+}
+
+// saveBackfeedResponse adds/updates the Mention that records a single response to a syndicated copy
+func (service *SyndicationTarget) saveBackfeedResponse(stream *model.Stream, target model.SyndicationTarget, response providers.BackfeedResponse) error {
+
+	const location = "service.SyndicationTarget.saveBackfeedResponse"
+
+	// RULE: Ignore responses from blocked domains
+	if service.mentionService.IsBlocked(response.Author.ProfileURL) {
+		return nil
+	}
+
+	// Check the database for an existing Mention record
+	mention, err := service.mentionService.LoadOrCreate(model.MentionTypeStream, stream.StreamID, response.URL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Mention", response.URL)
+	}
+
+	// RULE: Do not update mentions that have already been rejected by the owner
+	if mention.StateID == model.MentionStatusInvalid {
+		return nil
+	}
+
+	if mention.IsNew() {
+
+		// RULE: Do not duplicate responses that we already know about
+		if service.isDuplicateResponse(stream, response) {
+			return nil
+		}
+
+	} else if (mention.Origin.Type == response.Type) && (mention.Author == response.Author) && (mention.Content == response.Content) {
+
+		// RULE: Do not re-save responses that have not changed since the last poll
+		return nil
+	}
+
+	mention.Origin.Type = response.Type
+	mention.Origin.Label = target.TypeLabel()
+	mention.Author = response.Author
+	mention.Content = response.Content
+
+	// New mentions are approved automatically, or wait for the owner's approval
+	if mention.StateID == "" {
+		mention.StateID = service.mentionService.ModerationState(&mention)
+	}
+
+	if err := service.mentionService.Save(&mention, "Backfeed"); err != nil {
+		return derp.Wrap(err, location, "Error saving Mention", response.URL)
+	}
+
+	return nil
+}
+
+// isDuplicateResponse returns TRUE if a response has already been received natively,
+// either as an ActivityPub activity or as a WebMention.
+func (service *SyndicationTarget) isDuplicateResponse(stream *model.Stream, response providers.BackfeedResponse) bool {
+
+	const location = "service.SyndicationTarget.isDuplicateResponse"
+
+	switch response.Type {
+
+	case model.OriginTypeReply:
+
+		if response.ObjectID == "" {
+			return false
+		}
+
+		// Reply was delivered via ActivityPub
+		if service.activityService.HasReply(stream.URL, response.ObjectID) {
+			return true
+		}
+
+		// Reply was delivered via WebMention
+		mention := model.NewMention()
+		err := service.mentionService.LoadByOrigin(model.MentionTypeStream, stream.StreamID, response.ObjectID, &mention)
+
+		if err == nil {
+			return true
+		}
+
+		if !derp.NotFound(err) {
+			derp.Report(derp.Wrap(err, location, "Error searching for duplicate reply", response.ObjectID))
+		}
+
+		return false
+
+	case model.OriginTypeLike, model.OriginTypeAnnounce:
+
+		// Like/Announce was delivered via ActivityPub
+		if response.ActorID != "" {
+			relationType := ascache.RelationTypeLike
+
+			if response.Type == model.OriginTypeAnnounce {
+				relationType = ascache.RelationTypeAnnounce
+			}
+
+			if service.activityService.HasResponse(relationType, stream.URL, response.ActorID) {
+				return true
+			}
+		}
+
+		// Like/Repost was delivered via WebMention by the same person
+		if response.Author.ProfileURL == "" {
+			return false
+		}
+
+		mention := model.NewMention()
+		err := service.mentionService.LoadByAuthor(stream.StreamID, response.Type, response.Author.ProfileURL, &mention)
+
+		if err == nil {
+			return true
+		}
+
+		if !derp.NotFound(err) {
+			derp.Report(derp.Wrap(err, location, "Error searching for duplicate response", response.Author.ProfileURL))
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyndicationTarget_EncryptSecret(t *testing.T) {
+
+	collection := newMemoryCollection()
+	oldKey := []byte("0123456789ABCDEF0123456789ABCDEF")
+	newKey := []byte("FEDCBA9876543210FEDCBA9876543210")
+
+	service := NewSyndicationTarget()
+	service.Refresh(&collection, oldKey, nil, nil, nil, nil, nil)
+
+	// Store a target with an encrypted secret
+	target := model.NewSyndicationTarget()
+	target.Secret = "APP-PASSWORD"
+	require.Nil(t, service.encrypt(&target))
+	require.Nil(t, collection.Save(&target, ""))
+
+	// The plaintext secret is never written to the database
+	for _, document := range collection.documents {
+		require.False(t, bytes.Contains(document, []byte("APP-PASSWORD")))
+	}
+
+	// Secrets are decrypted when loaded
+	loaded := model.NewSyndicationTarget()
+	require.Nil(t, service.Load(exp.Equal("_id", target.SyndicationTargetID), &loaded))
+	require.Equal(t, "APP-PASSWORD", loaded.Secret)
+
+	// Secrets are still readable after the key encrypting key is rotated
+	service.SetKeyEncryptingKeys(newKey, oldKey)
+	require.Nil(t, service.Rewrap())
+	service.SetKeyEncryptingKeys(newKey, nil)
+
+	rotated := model.NewSyndicationTarget()
+	require.Nil(t, service.Load(exp.Equal("_id", target.SyndicationTargetID), &rotated))
+	require.Equal(t, "APP-PASSWORD", rotated.Secret)
+	require.NotEqual(t, loaded.EncryptedSecret, rotated.EncryptedSecret)
+
+	// Encrypted secrets cannot be moved to another target
+	other := model.NewSyndicationTarget()
+	other.EncryptedSecret = rotated.EncryptedSecret
+	require.NotNil(t, service.decrypt(&other))
+}
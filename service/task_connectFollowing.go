@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+)
+
+// TaskConnectFollowing connects a new Following record to its remote server in the background
+type TaskConnectFollowing struct {
+	followingService *Following
+	following        model.Following
+}
+
+func NewTaskConnectFollowing(followingService *Following, following model.Following) TaskConnectFollowing {
+	return TaskConnectFollowing{
+		followingService: followingService,
+		following:        following,
+	}
+}
+
+// Run connects the Following.  Errors are recorded in the Following's status (and
+// retried by the regular polling schedule) so they are not returned to the queue.
+func (task TaskConnectFollowing) Run() error {
+	task.followingService.RefreshAndConnect(task.following)
+	return nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/labstack/gommon/random"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rssCloudLeaseDuration is how long an rssCloud subscription lasts before it must be renewed
+// https://www.rssboard.org/rsscloud-interface
+const rssCloudLeaseDuration = 25 * time.Hour
+
+// TaskCreateRSSCloudFollower handles an rssCloud "pleaseNotify" request, verifying
+// the subscriber's callback and creating/updating a Follower record.
+type TaskCreateRSSCloudFollower struct {
+	followerService *Follower
+	locatorService  Locator
+	objectType      string
+	objectID        primitive.ObjectID
+	format          string // RSS, Atom
+	url             string // URL of the feed being subscribed to
+	callback        string // URL that will receive update notifications
+	useChallenge    bool   // If TRUE, then verify the callback with a GET challenge.  Otherwise, verify with a POST
+}
+
+func NewTaskCreateRSSCloudFollower(followerService *Follower, locatorService Locator, objectType string, objectID primitive.ObjectID, format string, url string, callback string, useChallenge bool) TaskCreateRSSCloudFollower {
+	return TaskCreateRSSCloudFollower{
+		followerService: followerService,
+		locatorService:  locatorService,
+		objectType:      objectType,
+		objectID:        objectID,
+		format:          format,
+		url:             url,
+		callback:        callback,
+		useChallenge:    useChallenge,
+	}
+}
+
+// Run creates/updates a follower record
+func (task TaskCreateRSSCloudFollower) Run() error {
+
+	const location = "service.TaskCreateRSSCloudFollower.Run"
+
+	// Create a new Follower record
+	follower, err := task.followerService.LoadOrCreateByRSSCloud(task.objectType, task.objectID, task.callback)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading follower", task.objectID, task.callback)
+	}
+
+	// Set additional properties that are not handled by LoadOrCreateByRSSCloud
+	follower.Format = task.format
+	follower.ExpireDate = time.Now().Add(rssCloudLeaseDuration).Unix()
+	follower.Data = mapof.Any{
+		"url": task.url,
+	}
+
+	// Validate the request with the client
+	if err := task.validate(&follower); err != nil {
+		return derp.Wrap(err, location, "Error validating request", follower.ID)
+	}
+
+	// Save the new/updated follower
+	if err := task.followerService.Save(&follower, "Created via rssCloud"); err != nil {
+		return derp.Wrap(err, location, "Error saving follower", follower.ID)
+	}
+
+	return nil
+}
+
+// validate verifies that the request is for an object that we own, and that the callback server approves of the request.
+func (task TaskCreateRSSCloudFollower) validate(follower *model.Follower) error {
+
+	const location = "service.TaskCreateRSSCloudFollower.validate"
+
+	// Validate the object in our own database
+	objectType, objectID, err := task.locatorService.GetObjectFromURL(task.url)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error parsing feed URL", follower.ID)
+	}
+
+	if objectType != task.objectType {
+		return derp.NewBadRequestError(location, "Invalid object type", follower.ID)
+	}
+
+	if objectID != task.objectID {
+		return derp.NewBadRequestError(location, "Invalid object ID", follower.ID)
+	}
+
+	// If the subscriber did not provide a domain, then we test the callback with an empty notification
+	if !task.useChallenge {
+
+		transaction := remote.Post(follower.Actor.InboxURL).
+			Form("url", task.url).
+			Use(ssrf.Option())
+
+		if err := transaction.Send(); err != nil {
+			return derp.Wrap(err, location, "Error sending test notification", follower.ID)
+		}
+
+		return nil
+	}
+
+	// Otherwise, the subscriber must echo back a challenge string
+	var body string
+
+	challenge := random.String(42)
+	transaction := remote.Get(follower.Actor.InboxURL).
+		Query("url", task.url).
+		Query("challenge", challenge).
+		Use(ssrf.Option()).
+		Result(&body)
+
+	if err := transaction.Send(); err != nil {
+		return derp.Wrap(err, location, "Error sending verification request", follower.ID)
+	}
+
+	if body != challenge {
+		return derp.NewBadRequestError(location, "Invalid challenge response", follower.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// TaskDeleteUser removes all of the records related to a deleted User in the background,
+// and tells remote servers that the User's ActivityPub Actor has been deleted.
+type TaskDeleteUser struct {
+	userService *User
+	user        model.User
+}
+
+func NewTaskDeleteUser(userService *User, user model.User) TaskDeleteUser {
+	return TaskDeleteUser{
+		userService: userService,
+		user:        user,
+	}
+}
+
+func (task TaskDeleteUser) Run() error {
+
+	if err := task.userService.purge(&task.user); err != nil {
+		return derp.Wrap(err, "service.TaskDeleteUser.Run", "Error deleting User's records", task.user.UserID)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskExportUser builds an archive of a User's personal data in the background
+type TaskExportUser struct {
+	dataExportService *DataExport
+	userID            primitive.ObjectID
+}
+
+func NewTaskExportUser(dataExportService *DataExport, userID primitive.ObjectID) TaskExportUser {
+	return TaskExportUser{
+		dataExportService: dataExportService,
+		userID:            userID,
+	}
+}
+
+func (task TaskExportUser) Run() error {
+
+	if err := task.dataExportService.Build(task.userID); err != nil {
+		return derp.Wrap(err, "service.TaskExportUser.Run", "Error building data export", task.userID)
+	}
+
+	return nil
+}
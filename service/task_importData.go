@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskImportData imports the rows of an uploaded CSV file in the background
+type TaskImportData struct {
+	dataImportService *DataImport
+	dataImportID      primitive.ObjectID
+}
+
+func NewTaskImportData(dataImportService *DataImport, dataImportID primitive.ObjectID) TaskImportData {
+	return TaskImportData{
+		dataImportService: dataImportService,
+		dataImportID:      dataImportID,
+	}
+}
+
+func (task TaskImportData) Run() error {
+
+	if err := task.dataImportService.Run(task.dataImportID); err != nil {
+		return derp.Wrap(err, "service.TaskImportData.Run", "Error importing data", task.dataImportID)
+	}
+
+	return nil
+}
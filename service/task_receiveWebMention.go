@@ -2,6 +2,7 @@ package service
 
 import (
 	"bytes"
+	"net/http"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
@@ -30,11 +31,9 @@ func (task TaskReceiveWebMention) Run() error {
 
 	const location = "service.TaskReceiveWebMention.Run"
 
-	var content bytes.Buffer
-
-	// Validate that the WebMention source actually links to the targetURL
-	if err := task.mentionService.Verify(task.source, task.target, &content); err != nil {
-		return derp.Wrap(err, location, "Source does not link to target", task.source, task.target)
+	// RULE: Ignore mentions from blocked domains
+	if task.mentionService.IsBlocked(task.source) {
+		return nil
 	}
 
 	// Parse the target URL into an object type and token
@@ -74,13 +73,46 @@ func (task TaskReceiveWebMention) Run() error {
 		return derp.Wrap(err, location, "Error loading mention", objectType, token)
 	}
 
+	var content bytes.Buffer
+
+	// Validate that the WebMention source (still) links to the targetURL
+	if err := task.mentionService.Verify(task.source, task.target, &content); err != nil {
+
+		// Updates to existing mentions that have been deleted (410 Gone) or
+		// that no longer link to the target remove the mention.
+		if !mention.IsNew() && (derp.NotFound(err) || derp.ErrorCode(err) == http.StatusGone) {
+			if err := task.mentionService.Delete(&mention, "Source removed"); err != nil {
+				return derp.Wrap(err, location, "Error deleting mention", task.source)
+			}
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Source does not link to target", task.source, task.target)
+	}
+
+	// RULE: Do not update mentions that have already been rejected by the owner
+	if mention.StateID == model.MentionStatusInvalid {
+		return nil
+	}
+
 	// Parse the WebMention source into the Mention object
 	if err := task.mentionService.GetPageInfo(&content, task.source, &mention); err != nil {
 		return derp.Wrap(err, location, "Error parsing source", task.source)
 	}
 
+	// Keep the original source URL so that future updates find this same record
+	mention.Origin.URL = task.source
+
+	// Identify the type of response (reply, like, repost, bookmark)
+	task.mentionService.ParseEntry(&content, task.source, task.target, &mention)
+
+	// New mentions are approved automatically, or wait for the owner's approval
+	if mention.StateID == "" {
+		mention.StateID = task.mentionService.ModerationState(&mention)
+	}
+
 	// Try to save the mention to the database
-	if err := task.mentionService.Save(&mention, "Created"); err != nil {
+	if err := task.mentionService.Save(&mention, "Received"); err != nil {
 		return derp.Wrap(err, location, "Error saving mention")
 	}
 
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ssrf"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+)
+
+// TaskSendRSSCloudPing notifies a single rssCloud subscriber that a feed has been updated.
+// https://www.rssboard.org/rsscloud-interface
+type TaskSendRSSCloudPing struct {
+	follower model.Follower
+	options  []remote.Option // Options applied to the notification.  By default, only public addresses can be reached.
+}
+
+func NewTaskSendRSSCloudPing(follower model.Follower) TaskSendRSSCloudPing {
+	return TaskSendRSSCloudPing{
+		follower: follower,
+		options:  []remote.Option{ssrf.Option()},
+	}
+}
+
+func (task TaskSendRSSCloudPing) Run() error {
+
+	transaction := remote.Post(task.follower.Actor.InboxURL).
+		Form("url", task.follower.Data.GetString("url")).
+		Use(task.options...)
+
+	// Try to send the notification to the remote rssCloud subscriber
+	if err := transaction.Send(); err != nil {
+		return derp.Wrap(err, "service.TaskSendRSSCloudPing", "Error sending rssCloud notification", task.follower)
+	}
+
+	// Woot woot!
+	return nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSendRSSCloudPing(t *testing.T) {
+
+	var received string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		received = r.FormValue("url")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	follower := model.NewFollower()
+	follower.Method = model.FollowMethodRssCloud
+	follower.Actor.InboxURL = server.URL + "/.rsscloud/123/456"
+	follower.Data["url"] = "https://example.com/@123/feed?format=rss"
+
+	// Subscribers on private addresses are not notified
+	task := NewTaskSendRSSCloudPing(follower)
+	require.NotNil(t, task.Run())
+	require.Equal(t, "", received)
+
+	// The test server is on a loopback address, so allow it here
+	task.options = nil
+	require.Nil(t, task.Run())
+	require.Equal(t, "https://example.com/@123/feed?format=rss", received)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskSyndicateStream re-posts a published Stream to the User's selected SyndicationTargets
+type TaskSyndicateStream struct {
+	syndicationTargetService *SyndicationTarget
+	userID                   primitive.ObjectID
+	streamID                 primitive.ObjectID
+}
+
+func NewTaskSyndicateStream(syndicationTargetService *SyndicationTarget, userID primitive.ObjectID, streamID primitive.ObjectID) TaskSyndicateStream {
+	return TaskSyndicateStream{
+		syndicationTargetService: syndicationTargetService,
+		userID:                   userID,
+		streamID:                 streamID,
+	}
+}
+
+func (task TaskSyndicateStream) Run() error {
+
+	if err := task.syndicationTargetService.SyndicateStream(task.userID, task.streamID); err != nil {
+		return derp.Wrap(err, "service.TaskSyndicateStream", "Error syndicating stream", task.userID, task.streamID)
+	}
+
+	return nil
+}
@@ -203,6 +203,9 @@ func (service *Template) loadTemplates() error {
 
 	service.themeService.calculateAllInheritance()
 
+	// Let template authors know about any incomplete microformats
+	service.checkMicroformats()
+
 	// Assign the prep area to live
 	service.mutex.Lock()
 	defer service.mutex.Unlock()
@@ -322,6 +325,28 @@ func (service *Template) Load(templateID string) (model.Template, error) {
  * Custom Queries
  ******************************************/
 
+// ListProblems returns all Templates that had problems when they were loaded, sorted by TemplateID
+func (service *Template) ListProblems() []model.Template {
+
+	// READ Mutex to make multi-threaded access safe.
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	result := make([]model.Template, 0)
+
+	for _, template := range service.templates {
+		if len(template.Problems) > 0 {
+			result = append(result, template)
+		}
+	}
+
+	sort.Slice(result, func(a int, b int) bool {
+		return result[a].TemplateID < result[b].TemplateID
+	})
+
+	return result
+}
+
 // ListByTemplateRole returns all model.Templates that match the provided "TemplateRole" value
 func (service *Template) ListByTemplateRole(templateRole string) []form.LookupCode {
 
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"text/template/parse"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/rs/zerolog/log"
+)
+
+// microformatRule describes the properties that should be published along with a root microformat.
+// Each entry in Properties is a list of alternatives, and at least one of them must be present.
+type microformatRule struct {
+	Root       string
+	Properties [][]string
+}
+
+// microformatRules lists the minimum properties that consumers (like WebMention receivers and
+// feed readers) need in order to understand our h-entry and h-card output.
+// https://microformats.org/wiki/h-entry
+// https://microformats.org/wiki/h-card
+var microformatRules = []microformatRule{
+	{Root: "h-entry", Properties: [][]string{{"u-url"}, {"dt-published"}, {"p-author"}, {"e-content", "p-name"}}},
+	{Root: "h-card", Properties: [][]string{{"p-name"}, {"u-url"}}},
+}
+
+// classAttributeRegex finds the value of every class attribute in an HTML template
+var classAttributeRegex = regexp.MustCompile(`\bclass\s*=\s*"([^"]*)"`)
+
+// checkMicroformats inspects the HTML of every Template that is being prepared, and records the
+// microformats that each one is missing in its Problems, so that they can be shown to template
+// authors in the admin section.  This runs before the templates are used because html/template
+// rewrites its parse trees the first time that each template is executed.
+func (service *Template) checkMicroformats() {
+
+	for templateID, template := range service.templatePrep {
+
+		template.Problems = checkTemplateMicroformats(template)
+		service.templatePrep[templateID] = template
+
+		if len(template.Problems) > 0 {
+			log.Warn().Str("template", templateID).Strs("problems", template.Problems).Msg("Template Service: incomplete microformats")
+		}
+	}
+}
+
+// checkTemplateMicroformats returns a human-readable list of the microformats that are missing from a Template.
+// Content templates must publish an h-entry, and profile templates must publish an h-card and an h-feed.
+// Every h-entry and h-card must also include the properties listed in microformatRules.
+func checkTemplateMicroformats(template model.Template) sliceof.String {
+
+	result := make(sliceof.String, 0)
+	classes := templateClassNames(template)
+
+	// Verify that the required root microformats are present
+	required := []string{}
+
+	switch template.Model {
+
+	case "stream":
+		if template.SocialRole != "" {
+			required = append(required, "h-entry")
+		}
+
+	case "outbox":
+		required = append(required, "h-card", "h-feed")
+	}
+
+	for _, root := range required {
+		if !classes[root] {
+			result = append(result, "missing "+root)
+		}
+	}
+
+	// Verify the properties of each root microformat that is present
+	for _, rule := range microformatRules {
+
+		if !classes[rule.Root] {
+			continue
+		}
+
+		for _, alternatives := range rule.Properties {
+			if !hasAnyClassName(classes, alternatives) {
+				result = append(result, rule.Root+" is missing "+strings.Join(alternatives, " or "))
+			}
+		}
+	}
+
+	return result
+}
+
+// templateClassNames returns the set of all CSS class names used in a Template's HTML
+func templateClassNames(template model.Template) map[string]bool {
+
+	result := make(map[string]bool)
+
+	if template.HTMLTemplate == nil {
+		return result
+	}
+
+	for _, htmlTemplate := range template.HTMLTemplate.Templates() {
+
+		if htmlTemplate.Tree == nil {
+			continue
+		}
+
+		var source strings.Builder
+		writeTemplateText(&source, htmlTemplate.Tree.Root)
+
+		for _, match := range classAttributeRegex.FindAllStringSubmatch(source.String(), -1) {
+			for _, className := range strings.Fields(match[1]) {
+				result[className] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// writeTemplateText writes the static HTML of a template, replacing every
+// action with a space so that attribute values can be read in one piece.
+func writeTemplateText(builder *strings.Builder, node parse.Node) {
+
+	switch typed := node.(type) {
+
+	case *parse.ListNode:
+		if typed == nil {
+			return
+		}
+		for _, child := range typed.Nodes {
+			writeTemplateText(builder, child)
+		}
+
+	case *parse.TextNode:
+		builder.Write(typed.Text)
+
+	case *parse.IfNode:
+		writeTemplateText(builder, typed.List)
+		writeTemplateText(builder, typed.ElseList)
+
+	case *parse.RangeNode:
+		writeTemplateText(builder, typed.List)
+		writeTemplateText(builder, typed.ElseList)
+
+	case *parse.WithNode:
+		writeTemplateText(builder, typed.List)
+		writeTemplateText(builder, typed.ElseList)
+
+	default:
+		builder.WriteString(" ")
+	}
+}
+
+// hasAnyClassName returns TRUE if any of the class names are present in the set
+func hasAnyClassName(classes map[string]bool, names []string) bool {
+
+	for _, name := range names {
+		if classes[name] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/set"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTemplateMicroformats_Complete(t *testing.T) {
+
+	template := model.NewTemplate("article", nil)
+	template.Model = "stream"
+	template.SocialRole = "Article"
+
+	_, err := template.HTMLTemplate.New("view").Parse(`<article class="h-entry">
+		<h1 class="p-name">{{.Label}}</h1>
+		<data class="u-url" value="{{.Permalink}}"></data>
+		<time class="dt-published" datetime="{{.PublishDate}}"></time>
+		<span class="p-author h-card"><a class="p-name u-url" href="{{.Author.ProfileURL}}">{{.Author.Name}}</a></span>
+		{{if .Summary}}<div class="e-content">{{.Summary}}</div>{{end}}
+	</article>`)
+	require.Nil(t, err)
+
+	require.Empty(t, checkTemplateMicroformats(template))
+}
+
+func TestCheckTemplateMicroformats_MissingProperties(t *testing.T) {
+
+	template := model.NewTemplate("article", nil)
+	template.Model = "stream"
+	template.SocialRole = "Article"
+
+	_, err := template.HTMLTemplate.New("view").Parse(`<article class="h-entry">
+		<h1 class="{{.ClassName}}">{{.Label}}</h1>
+		<span class="h-card">{{.Author.Name}}</span>
+	</article>`)
+	require.Nil(t, err)
+
+	problems := checkTemplateMicroformats(template)
+	require.Equal(t, []string{
+		"h-entry is missing u-url",
+		"h-entry is missing dt-published",
+		"h-entry is missing p-author",
+		"h-entry is missing e-content or p-name",
+		"h-card is missing p-name",
+		"h-card is missing u-url",
+	}, []string(problems))
+}
+
+func TestCheckTemplateMicroformats_Required(t *testing.T) {
+
+	// Content templates must publish an h-entry
+	article := model.NewTemplate("article", nil)
+	article.Model = "stream"
+	article.SocialRole = "Article"
+
+	_, err := article.HTMLTemplate.New("view").Parse(`<article>{{.Label}}</article>`)
+	require.Nil(t, err)
+	require.Equal(t, []string{"missing h-entry"}, []string(checkTemplateMicroformats(article)))
+
+	// Profile templates must publish an h-card and an h-feed
+	profile := model.NewTemplate("profile", nil)
+	profile.Model = "outbox"
+
+	_, err = profile.HTMLTemplate.New("view").Parse(`<div>{{.DisplayName}}</div>`)
+	require.Nil(t, err)
+	require.Equal(t, []string{"missing h-card", "missing h-feed"}, []string(checkTemplateMicroformats(profile)))
+
+	// Templates without a social role (like folders) are not required to publish anything
+	folder := model.NewTemplate("folder", nil)
+	folder.Model = "stream"
+
+	_, err = folder.HTMLTemplate.New("view").Parse(`<div>{{.Label}}</div>`)
+	require.Nil(t, err)
+	require.Empty(t, checkTemplateMicroformats(folder))
+}
+
+func TestTemplate_ListProblems(t *testing.T) {
+
+	service := Template{
+		templates:    make(set.Map[model.Template]),
+		templatePrep: make(set.Map[model.Template]),
+	}
+
+	article := model.NewTemplate("article", nil)
+	article.Model = "stream"
+	article.SocialRole = "Article"
+
+	_, err := article.HTMLTemplate.New("view").Parse(`<article>{{.Label}}</article>`)
+	require.Nil(t, err)
+
+	folder := model.NewTemplate("folder", nil)
+	folder.Model = "stream"
+
+	_, err = folder.HTMLTemplate.New("view").Parse(`<div>{{.Label}}</div>`)
+	require.Nil(t, err)
+
+	service.templatePrep["article"] = article
+	service.templatePrep["folder"] = folder
+
+	// Problems are recorded on each Template, so that they can be shown to template authors
+	service.checkMicroformats()
+	service.templates = service.templatePrep
+
+	problems := service.ListProblems()
+	require.Equal(t, 1, len(problems))
+	require.Equal(t, "article", problems[0].TemplateID)
+	require.Equal(t, []string{"missing h-entry"}, []string(problems[0].Problems))
+}
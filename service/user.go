@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/benpate/digit"
 	"github.com/benpate/domain"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/rosetta/iterator"
 	"github.com/benpate/rosetta/list"
 	"github.com/benpate/rosetta/schema"
@@ -23,24 +25,40 @@ import (
 
 // User manages all interactions with the User collection
 type User struct {
-	collection        data.Collection
-	followers         data.Collection
-	following         data.Collection
-	rules             data.Collection
-	attachmentService *Attachment
-	ruleService       *Rule
-	emailService      *DomainEmail
-	keyService        *EncryptionKey
-	domainService     *Domain
-	folderService     *Folder
-	followerService   *Follower
-	streamService     *Stream
-	host              string
+	collection            data.Collection
+	followers             data.Collection
+	following             data.Collection
+	rules                 data.Collection
+	attachmentService     *Attachment
+	dataExportService     *DataExport
+	dataImportService     *DataImport
+	ruleService           *Rule
+	emailService          *DomainEmail
+	keyService            *EncryptionKey
+	mentionService        *Mention
+	domainService         *Domain
+	folderService         *Folder
+	followerService       *Follower
+	followingService      *Following
+	groupService          *Group
+	inboxService          *Inbox
+	invitationService     *Invitation
+	oauthUserTokenService *OAuthUserToken
+	outboxService         *Outbox
+	responseService       *Response
+	streamService         *Stream
+	syndicationService    *SyndicationTarget
+	userSessionService    *UserSession
+	queue                 queue.Queue
+	host                  string
+	closed                chan bool
 }
 
 // NewUser returns a fully populated User service
 func NewUser() User {
-	return User{}
+	return User{
+		closed: make(chan bool),
+	}
 }
 
 /******************************************
@@ -48,27 +66,75 @@ func NewUser() User {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *User) Refresh(userCollection data.Collection, followerCollection data.Collection, followingCollection data.Collection, ruleCollection data.Collection, attachmentService *Attachment, domainService *Domain, emailService *DomainEmail, folderService *Folder, followerService *Follower, keyService *EncryptionKey, ruleService *Rule, streamService *Stream, host string) {
+func (service *User) Refresh(userCollection data.Collection, followerCollection data.Collection, followingCollection data.Collection, ruleCollection data.Collection, attachmentService *Attachment, dataExportService *DataExport, dataImportService *DataImport, domainService *Domain, emailService *DomainEmail, folderService *Folder, followerService *Follower, followingService *Following, groupService *Group, inboxService *Inbox, invitationService *Invitation, keyService *EncryptionKey, mentionService *Mention, oauthUserTokenService *OAuthUserToken, outboxService *Outbox, responseService *Response, ruleService *Rule, streamService *Stream, syndicationService *SyndicationTarget, userSessionService *UserSession, queue queue.Queue, host string) {
 	service.collection = userCollection
 	service.followers = followerCollection
 	service.following = followingCollection
 	service.rules = ruleCollection
 
 	service.attachmentService = attachmentService
+	service.dataExportService = dataExportService
+	service.dataImportService = dataImportService
 	service.domainService = domainService
 	service.emailService = emailService
 	service.folderService = folderService
 	service.followerService = followerService
+	service.followingService = followingService
+	service.groupService = groupService
+	service.inboxService = inboxService
+	service.invitationService = invitationService
 	service.keyService = keyService
+	service.mentionService = mentionService
+	service.oauthUserTokenService = oauthUserTokenService
+	service.outboxService = outboxService
+	service.responseService = responseService
 	service.ruleService = ruleService
 	service.streamService = streamService
+	service.syndicationService = syndicationService
+	service.userSessionService = userSessionService
+	service.queue = queue
 
 	service.host = host
 }
 
 // Close stops any background processes controlled by this service
 func (service *User) Close() {
+	close(service.closed)
+}
+
+// Start begins the background scheduler that retries unfinished account deletions.
+// Pending deletions are retried once at startup, because queued tasks do not survive a restart.
+func (service *User) Start() {
+
+	const location = "service.User.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	if err := service.RetryPurges(time.Now()); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error retrying deleted Users"))
+	}
+
+	for {
+
+		// Poll randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
 
+		select {
+
+		// If we're done, we're done.
+		case <-service.closed:
+			return
+
+		// Skip recent deletions, which may still be waiting in the queue
+		default:
+			if err := service.RetryPurges(time.Now().Add(-1 * time.Hour)); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error retrying deleted Users"))
+			}
+		}
+	}
 }
 
 /******************************************
@@ -150,7 +216,8 @@ func (service *User) Save(user *model.User, note string) error {
 	}
 
 	// RULE: If the user has not yet been sent their password, then try to send it now.
-	if user.PasswordReset.CreateDate == 0 {
+	// Users who signed up on their own are welcomed once they are allowed to sign in.
+	if (user.PasswordReset.CreateDate == 0) && user.IsActive() {
 		service.SendWelcomeEmail(user)
 	}
 
@@ -158,18 +225,23 @@ func (service *User) Save(user *model.User, note string) error {
 	return nil
 }
 
-// Delete removes an User from the database (virtual delete)
+// Delete removes an User from the database (virtual delete).  Only a Tombstone of the
+// User remains, and all related records are removed by a background TaskDeleteUser.
 func (service *User) Delete(user *model.User, note string) error {
 
-	if err := service.collection.Delete(user, note); err != nil {
+	// RULE: Remove all personal data from the User record, and mark the
+	// related records for removal so that the purge can be retried if it fails
+	tombstone := user.Tombstone()
+	tombstone.IsPurgePending = true
+
+	if err := service.collection.Delete(&tombstone, note); err != nil {
 		return derp.Wrap(err, "service.User.Delete", "Error deleting User", user, note)
 	}
 
-	// TODO: HIGH: Clean up related records (like InboxItem and OutboxItem)
-	if err := service.streamService.DeleteByParent(user.UserID, "Deleted with owner"); err != nil {
-		return derp.Wrap(err, "service.User.Delete", "Error deleting User's streams", user, note)
-	}
+	// Clean up related records in the background
+	service.queue.Push(NewTaskDeleteUser(service, tombstone))
 
+	*user = tombstone
 	return nil
 }
 
@@ -285,6 +357,35 @@ func (service *User) LoadByUsernameOrEmail(usernameOrEmail string, result *model
 	return err
 }
 
+// LoadTombstoneByToken loads a deleted User that matches the provided token (either a UserID or username)
+func (service *User) LoadTombstoneByToken(token string, result *model.User) error {
+
+	var criteria exp.Expression = exp.Equal("username", token)
+
+	if userID, err := primitive.ObjectIDFromHex(token); err == nil {
+		criteria = criteria.Or(exp.Equal("_id", userID))
+	}
+
+	if err := service.collection.Load(exp.GreaterThan("deleteDate", 0).And(criteria), result); err != nil {
+		return derp.Wrap(err, "service.User.LoadTombstoneByToken", "Error loading deleted User", token)
+	}
+
+	return nil
+}
+
+// IsUsernameTaken returns TRUE if any User, including a deleted one, uses the provided username.
+// Deleted usernames stay reserved because their profile URL and actor ID still publish a Tombstone.
+func (service *User) IsUsernameTaken(username string) (bool, error) {
+
+	count, err := service.collection.Count(exp.Equal("username", username))
+
+	if err != nil {
+		return false, derp.Wrap(err, "service.User.IsUsernameTaken", "Error counting Users", username)
+	}
+
+	return count > 0, nil
+}
+
 // LoadByUsername loads a single model.User object that matches the provided token
 func (service *User) LoadByToken(token string, result *model.User) error {
 
@@ -364,6 +465,17 @@ func (service *User) CalcRuleCount(userID primitive.ObjectID) {
 	}
 }
 
+// SetDataExport updates ONLY the DataExport field of a User, so that other changes made
+// to the User (for instance, while an archive is being built) are not overwritten.
+func (service *User) SetDataExport(userID primitive.ObjectID, dataExport model.DataExport) error {
+
+	if err := queries.UserSetDataExport(service.collection, userID, dataExport); err != nil {
+		return derp.Wrap(err, "service.User.SetDataExport", "Error updating data export", userID)
+	}
+
+	return nil
+}
+
 func (service *User) SetOwner(owner config.Owner) error {
 
 	// If there is no owner data, then do not create/update an owner record.
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// userSessionUpdateInterval is how often a UserSession's "last seen" information is saved
+const userSessionUpdateInterval = 5 * time.Minute
+
+// UserSession manages all interactions with the UserSession collection
+type UserSession struct {
+	collection data.Collection
+}
+
+// NewUserSession returns a fully populated UserSession service
+func NewUserSession() UserSession {
+	return UserSession{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *UserSession) Refresh(collection data.Collection) {
+	service.collection = collection
+}
+
+// Close stops any background processes controlled by this service
+func (service *UserSession) Close() {
+
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns an slice containing all of the UserSessions that match the provided criteria
+func (service *UserSession) Query(criteria exp.Expression, options ...option.Option) ([]model.UserSession, error) {
+	result := make([]model.UserSession, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Iterator returns an iterator containing all of the UserSessions that match the provided criteria
+func (service *UserSession) Iterator(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a UserSession from the database
+func (service *UserSession) Load(criteria exp.Expression, session *model.UserSession) error {
+
+	if err := service.collection.Load(notDeleted(criteria), session); err != nil {
+		return derp.Wrap(err, "service.UserSession.Load", "Error loading UserSession", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a UserSession in the database
+func (service *UserSession) Save(session *model.UserSession, note string) error {
+
+	if err := service.collection.Save(session, note); err != nil {
+		return derp.Wrap(err, "service.UserSession.Save", "Error saving UserSession", session.UserSessionID, note)
+	}
+
+	return nil
+}
+
+// Delete removes a UserSession from the database (virtual delete)
+func (service *UserSession) Delete(session *model.UserSession, note string) error {
+
+	if err := service.collection.Delete(session, note); err != nil {
+		return derp.Wrap(err, "service.UserSession.Delete", "Error deleting UserSession", session.UserSessionID, note)
+	}
+
+	return nil
+}
+
+// DeleteMany removes all UserSessions that match the provided criteria (virtual delete)
+func (service *UserSession) DeleteMany(criteria exp.Expression, note string) error {
+
+	const location = "service.UserSession.DeleteMany"
+
+	it, err := service.Iterator(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing UserSessions to delete", criteria)
+	}
+
+	session := model.NewUserSession()
+
+	for it.Next(&session) {
+		if err := service.Delete(&session, note); err != nil {
+			return derp.Wrap(err, location, "Error deleting UserSession", session.UserSessionID)
+		}
+		session = model.NewUserSession()
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns all of the UserSessions for a User, most recently used first
+func (service *UserSession) QueryByUser(userID primitive.ObjectID) ([]model.UserSession, error) {
+	criteria := exp.Equal("userId", userID)
+	return service.Query(criteria, option.SortDesc("lastSeenDate"))
+}
+
+// LoadByID loads a User's UserSession
+func (service *UserSession) LoadByID(userID primitive.ObjectID, sessionID primitive.ObjectID, session *model.UserSession) error {
+	criteria := exp.Equal("_id", sessionID).AndEqual("userId", userID)
+	return service.Load(criteria, session)
+}
+
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// Create starts a new UserSession for a User who has just signed in
+func (service *UserSession) Create(userID primitive.ObjectID, userAgent string, ipAddress string) (model.UserSession, error) {
+
+	session := model.NewUserSession()
+	session.UserID = userID
+	session.UserAgent = userAgent
+	session.IPAddress = ipAddress
+	session.LastSeenDate = time.Now().Unix()
+
+	if err := service.Save(&session, "Signed in"); err != nil {
+		return model.UserSession{}, derp.Wrap(err, "service.UserSession.Create", "Error saving UserSession", userID)
+	}
+
+	return session, nil
+}
+
+// Validate returns an error if the authorization does not belong to an active UserSession.
+// Every browser certificate includes the ID of its UserSession (as the JWT "jti" claim) so
+// certificates without one, or for a UserSession that has been revoked, are not allowed.
+// Active UserSessions are also updated with the IP address they were most recently seen from.
+func (service *UserSession) Validate(authorization *model.Authorization, ipAddress string) error {
+
+	const location = "service.UserSession.Validate"
+
+	sessionID, err := primitive.ObjectIDFromHex(authorization.ID)
+
+	if err != nil {
+		return derp.NewUnauthorizedError(location, "Certificate does not include a valid session", authorization.ID)
+	}
+
+	session := model.NewUserSession()
+
+	if err := service.LoadByID(authorization.UserID, sessionID, &session); err != nil {
+		return derp.Wrap(err, location, "Session has been revoked", sessionID)
+	}
+
+	// Limit database writes by only updating UserSessions every few minutes
+	if session.IsStale(userSessionUpdateInterval) || (session.IPAddress != ipAddress) {
+
+		session.IPAddress = ipAddress
+		session.LastSeenDate = time.Now().Unix()
+
+		if err := service.Save(&session, "Seen"); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error updating UserSession", sessionID))
+		}
+	}
+
+	return nil
+}
+
+// Revoke signs a User out of a single UserSession
+func (service *UserSession) Revoke(session *model.UserSession) error {
+
+	if err := service.Delete(session, "Revoked"); err != nil {
+		return derp.Wrap(err, "service.UserSession.Revoke", "Error revoking UserSession", session.UserSessionID)
+	}
+
+	return nil
+}
+
+// DeleteByUser permanently removes all of a User's UserSessions (hard delete)
+func (service *UserSession) DeleteByUser(userID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, "service.UserSession.DeleteByUser", "Error deleting UserSessions", userID)
+	}
+
+	return nil
+}
+
+// RevokeByUser signs a User out of all of their UserSessions, except for the (optional)
+// UserSession that is making this request
+func (service *UserSession) RevokeByUser(userID primitive.ObjectID, exceptSessionID string) error {
+
+	var criteria exp.Expression = exp.Equal("userId", userID)
+
+	if exceptID, err := primitive.ObjectIDFromHex(exceptSessionID); err == nil {
+		criteria = criteria.AndNotEqual("_id", exceptID)
+	}
+
+	if err := service.DeleteMany(criteria, "Revoked"); err != nil {
+		return derp.Wrap(err, "service.UserSession.RevokeByUser", "Error revoking UserSessions", userID)
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/outbox"
 	"github.com/benpate/rosetta/list"
+	"github.com/benpate/rosetta/mapof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -87,7 +88,7 @@ func (service *User) ActivityPubActor(userID primitive.ObjectID, withFollowers b
 	}
 
 	// Return the ActivityPub Actor
-	actor := outbox.NewActor(service.ActivityPubURL(userID), privateKey)
+	actor := outbox.NewActor(service.ActivityPubURL(userID), privateKey, outbox.WithPublicKey(service.keyService.KeyID(&encryptionKey)))
 
 	// Populate the Actor's ActivityPub Followers, if requested
 	if withFollowers {
@@ -109,3 +110,43 @@ func (service *User) ActivityPubActor(userID primitive.ObjectID, withFollowers b
 
 	return actor, nil
 }
+
+// ActivityPubJSONLD returns the User's ActivityPub Actor document, including all of its public keys
+func (service *User) ActivityPubJSONLD(user *model.User) (mapof.Any, error) {
+
+	result := user.GetJSONLD()
+
+	if err := service.keyService.JSONLD(model.EncryptionKeyTypeUser, user.UserID, result); err != nil {
+		return nil, derp.Wrap(err, "service.User.ActivityPubJSONLD", "Error adding public keys", user.UserID)
+	}
+
+	return result, nil
+}
+
+// SendActorUpdate sends an "Update" activity to all of the User's Followers, so that
+// they receive the User's current profile (including any new public keys)
+func (service *User) SendActorUpdate(userID primitive.ObjectID) error {
+
+	const location = "service.User.SendActorUpdate"
+
+	user := model.NewUser()
+
+	if err := service.LoadByID(userID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", userID)
+	}
+
+	document, err := service.ActivityPubJSONLD(&user)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error building Actor document", userID)
+	}
+
+	actor, err := service.ActivityPubActor(userID, true)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Actor", userID)
+	}
+
+	go actor.Send(actorUpdateActivity(user.ActivityPubURL(), document))
+	return nil
+}
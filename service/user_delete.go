@@ -0,0 +1,160 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+)
+
+/******************************************
+ * Account Deletion
+ ******************************************/
+
+// RetryPurges queues a new TaskDeleteUser for every User that was deleted before the
+// provided time, but whose related records have not been removed yet.
+func (service *User) RetryPurges(deletedBefore time.Time) error {
+
+	const location = "service.User.RetryPurges"
+
+	criteria := exp.Equal("purgePending", true).
+		AndGreaterThan("deleteDate", 0).
+		AndLessThan("deleteDate", deletedBefore.UnixMilli())
+
+	tombstones := make([]model.User, 0)
+
+	if err := service.collection.Query(&tombstones, criteria); err != nil {
+		return derp.Wrap(err, location, "Error listing deleted Users")
+	}
+
+	for _, tombstone := range tombstones {
+		service.queue.Push(NewTaskDeleteUser(service, tombstone))
+	}
+
+	return nil
+}
+
+// purge permanently removes all of the records related to a deleted User (hard delete).  Remote
+// servers are notified first, because this requires the User's Followers and encryption keys, which
+// are removed last.  Every step is safe to repeat, so the User remains marked with IsPurgePending
+// until every step succeeds, and failed purges are retried by the scheduler in User.Start.
+func (service *User) purge(user *model.User) error {
+
+	const location = "service.User.purge"
+
+	// Send an ActivityPub "Delete" to all of the User's Followers
+	if err := service.sendActorDelete(user); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error sending Delete activity", user.UserID))
+	}
+
+	// Unfollow everyone (sending "Undo Follow" activities)
+	if err := service.followingService.DeleteByUser(user.UserID, "Deleted with owner"); err != nil {
+		return derp.Wrap(err, location, "Error deleting Followings", user.UserID)
+	}
+
+	// Remove the User's streams, along with their revisions, attachments, and mentions
+	if err := service.streamService.PurgeByParent(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Streams", user.UserID)
+	}
+
+	// Remove the User's outbox
+	if err := service.outboxService.DeleteByParentID(model.FollowerTypeUser, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Outbox Messages", user.UserID)
+	}
+
+	// Remove the User's inbox messages and folders
+	if err := service.inboxService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Inbox Messages", user.UserID)
+	}
+
+	if err := service.folderService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Folders", user.UserID)
+	}
+
+	// Remove the User's followers
+	if err := service.followerService.DeleteByParent(model.FollowerTypeUser, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Followers", user.UserID)
+	}
+
+	// Remove the User's rules
+	if err := service.ruleService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Rules", user.UserID)
+	}
+
+	// Remove the User's responses
+	if err := service.responseService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Responses", user.UserID)
+	}
+
+	// Remove mentions of the User's profile
+	if err := service.mentionService.DeleteByObject(model.MentionTypeUser, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Mentions", user.UserID)
+	}
+
+	// Remove the User's syndication targets (and their secrets)
+	if err := service.syndicationService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting SyndicationTargets", user.UserID)
+	}
+
+	// Remove the Invitations that the User created
+	if err := service.invitationService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Invitations", user.UserID)
+	}
+
+	// Remove the User's avatar and other attachments, including unclaimed Micropub uploads
+	if err := service.attachmentService.PurgeAll(model.AttachmentTypeUser, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Attachments", user.UserID)
+	}
+
+	if err := service.attachmentService.PurgeAll(model.AttachmentTypeMicropub, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting Micropub media", user.UserID)
+	}
+
+	// Remove the User's data export archive (if any)
+	if err := service.dataExportService.Delete(user); err != nil {
+		return derp.Wrap(err, location, "Error deleting data export", user.UserID)
+	}
+
+	// Remove the User's imports (if any)
+	if err := service.dataImportService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting data imports", user.UserID)
+	}
+
+	// Revoke all OAuth applications and browser sessions
+	if err := service.oauthUserTokenService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting OAuth tokens", user.UserID)
+	}
+
+	if err := service.userSessionService.DeleteByUser(user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting sessions", user.UserID)
+	}
+
+	// Remove the User's encryption keys last
+	if err := service.keyService.DeleteByParentID(model.EncryptionKeyTypeUser, user.UserID); err != nil {
+		return derp.Wrap(err, location, "Error deleting EncryptionKeys", user.UserID)
+	}
+
+	// Everything has been removed, so this purge does not need to be retried
+	user.IsPurgePending = false
+
+	if err := service.collection.Save(user, "Purged related records"); err != nil {
+		return derp.Wrap(err, location, "Error saving Tombstone", user.UserID)
+	}
+
+	return nil
+}
+
+// sendActorDelete sends an ActivityPub "Delete" activity for the User's Actor to all of their Followers
+func (service *User) sendActorDelete(user *model.User) error {
+
+	actor, err := service.ActivityPubActor(user.UserID, true)
+
+	if err != nil {
+		return derp.Wrap(err, "service.User.sendActorDelete", "Error loading Actor", user.UserID)
+	}
+
+	// Send is NOT run in a goroutine, so that all Followers are read before they are deleted
+	actor.Send(actorDeleteActivity(user.ActivityPubURL(), user.GetTombstoneJSONLD()))
+	return nil
+}
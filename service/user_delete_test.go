@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_RetryPurges(t *testing.T) {
+
+	collection := newMemoryCollection()
+	tasks := recordingQueue{}
+
+	service := NewUser()
+	service.collection = &collection
+	service.queue = &tasks
+
+	// A deleted User whose purge never finished
+	pending := model.NewUser()
+	pending.IsPurgePending = true
+	require.Nil(t, collection.Delete(&pending, "Deleted"))
+
+	// A deleted User whose purge has finished
+	purged := model.NewUser()
+	require.Nil(t, collection.Delete(&purged, "Deleted"))
+
+	// An active User
+	active := model.NewUser()
+	require.Nil(t, collection.Save(&active, "Created"))
+
+	// Recent deletions are skipped, because they may still be in the queue
+	require.Nil(t, service.RetryPurges(time.Now().Add(-1*time.Hour)))
+	require.Empty(t, tasks)
+
+	// Unfinished purges are queued again
+	require.Nil(t, service.RetryPurges(time.Now().Add(time.Second)))
+	require.Equal(t, 1, len(tasks))
+
+	task, ok := tasks[0].(TaskDeleteUser)
+	require.True(t, ok)
+	require.Equal(t, pending.UserID, task.user.UserID)
+}
+
+// recordingQueue is a queue.Queue that keeps its tasks instead of running them
+type recordingQueue []queue.Task
+
+func (tasks *recordingQueue) Push(task queue.Task) {
+	*tasks = append(*tasks, task)
+}
+
+func TestUser_IsUsernameTaken(t *testing.T) {
+
+	collection := newMemoryCollection()
+
+	service := NewUser()
+	service.collection = &collection
+
+	active := model.NewUser()
+	active.Username = "active"
+	require.Nil(t, collection.Save(&active, "Created"))
+
+	deleted := model.NewUser()
+	deleted.Username = "deleted"
+	require.Nil(t, collection.Delete(&deleted, "Deleted"))
+
+	// Deleted usernames remain reserved for their Tombstone
+	for username, expected := range map[string]bool{"active": true, "deleted": true, "available": false} {
+		taken, err := service.IsUsernameTaken(username)
+		require.Nil(t, err)
+		require.Equal(t, expected, taken, username)
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/derp"
+	"github.com/benpate/domain"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/first"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// passkeyMaxLabelLength is the maximum number of characters in a Passkey label
+const passkeyMaxLabelLength = 100
+
+// RelyingParty returns the WebAuthn relying party for this domain.  Passkeys are
+// bound to the domain's hostname, so each domain on a server has its own passkeys.
+func (service *User) RelyingParty() webauthn.RelyingParty {
+	hostname := domain.NameOnly(service.host)
+
+	return webauthn.RelyingParty{
+		ID:     hostname,
+		Name:   first.String(service.domainService.Get().Label, hostname),
+		Origin: service.host,
+	}
+}
+
+// LoadByPasskey loads the User who registered the Passkey with the provided credential ID
+func (service *User) LoadByPasskey(credentialID string, result *model.User) error {
+	criteria := exp.Equal("passkeys.credentialId", credentialID)
+	return service.Load(criteria, result)
+}
+
+// PasskeyCreationOptions returns the options that the browser uses to register a new Passkey for the User
+func (service *User) PasskeyCreationOptions(user *model.User, challenge string) webauthn.CreationOptions {
+
+	webauthnUser := webauthn.User{
+		ID:          user.UserID[:],
+		Name:        user.Username,
+		DisplayName: first.String(user.DisplayName, user.Username),
+	}
+
+	return service.RelyingParty().CreationOptions(challenge, webauthnUser, passkeyDescriptors(user)...)
+}
+
+// PasskeyRequestOptions returns the options that the browser uses to sign in with a Passkey.
+// If a User is provided, then only their Passkeys are allowed.  Otherwise, the browser
+// lets the User choose any of their Passkeys for this domain.
+func (service *User) PasskeyRequestOptions(user *model.User, challenge string) webauthn.RequestOptions {
+
+	// Passkeys that sign in without a password must also verify the User's PIN or biometrics
+	if user == nil {
+		return service.RelyingParty().RequestOptions(challenge, webauthn.UserVerificationRequired)
+	}
+
+	return service.RelyingParty().RequestOptions(challenge, webauthn.UserVerificationPreferred, passkeyDescriptors(user)...)
+}
+
+// AddPasskey verifies the browser's response to PasskeyCreationOptions and saves the new Passkey
+func (service *User) AddPasskey(user *model.User, challenge string, response webauthn.RegistrationResponse, label string) error {
+
+	const location = "service.User.AddPasskey"
+
+	credential, err := service.RelyingParty().VerifyRegistration(challenge, response, false)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid passkey", user.UserID)
+	}
+
+	// RULE: Each credential can only be registered once
+	existing := model.NewUser()
+
+	if err := service.LoadByPasskey(credential.ID, &existing); err == nil {
+		return derp.NewBadRequestError(location, "This passkey has already been registered", user.UserID)
+	} else if !derp.NotFound(err) {
+		return derp.Wrap(err, location, "Error checking for existing passkey", user.UserID)
+	}
+
+	passkey := model.NewPasskey()
+	passkey.CredentialID = credential.ID
+	passkey.PublicKey = credential.PublicKey
+	passkey.SignCount = int64(credential.SignCount)
+	passkey.Transports = append(passkey.Transports, credential.Transports...)
+	passkey.Label = passkeyLabel(label)
+	passkey.BackupEligible = credential.BackupEligible
+	passkey.CreateDate = time.Now().Unix()
+
+	user.Passkeys = append(user.Passkeys, passkey)
+
+	if err := service.Save(user, "Passkey added: "+passkey.Label); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// VerifyPasskey verifies the browser's response to PasskeyRequestOptions against one of the User's
+// Passkeys, then records the new signature counter.  Passkeys that sign in without a password
+// must require user verification.
+func (service *User) VerifyPasskey(user *model.User, challenge string, response webauthn.AssertionResponse, requireUserVerification bool) error {
+
+	const location = "service.User.VerifyPasskey"
+
+	index := user.PasskeyIndex(response.CredentialID())
+
+	if index < 0 {
+		return derp.NewForbiddenError(location, "Unknown passkey", user.UserID)
+	}
+
+	// If the browser reported a user handle, then it must match this User
+	if response.Response.UserHandle != "" {
+
+		userHandle, err := webauthn.DecodeBase64(response.Response.UserHandle)
+
+		if (err != nil) || (string(userHandle) != string(user.UserID[:])) {
+			return derp.NewForbiddenError(location, "Passkey does not belong to this User", user.UserID)
+		}
+	}
+
+	passkey := user.Passkeys[index]
+	assertion, err := service.RelyingParty().VerifyAssertion(challenge, response, passkey.PublicKey, uint32(passkey.SignCount), requireUserVerification)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid passkey", user.UserID)
+	}
+
+	passkey.SignCount = int64(assertion.SignCount)
+	passkey.LastUsedDate = time.Now().Unix()
+	user.Passkeys[index] = passkey
+
+	if err := service.Save(user, "Signed in with passkey: "+passkey.Label); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// DeletePasskey removes a Passkey from the User.  Users cannot remove their last Passkey if it is the
+// only second factor that satisfies the domain's two-factor policies.
+func (service *User) DeletePasskey(user *model.User, passkeyID primitive.ObjectID) error {
+
+	const location = "service.User.DeletePasskey"
+
+	if (len(user.Passkeys) == 1) && !user.TwoFactor.IsEnabled() {
+
+		required, err := service.IsTwoFactorRequired(user)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error checking two-factor policy", user.UserID)
+		}
+
+		if required {
+			return derp.NewForbiddenError(location, "Two-factor authentication is required by the administrator of this website.  Set up an authenticator app before removing your last passkey.", user.UserID)
+		}
+	}
+
+	if !user.RemovePasskey(passkeyID) {
+		return derp.NewNotFoundError(location, "Passkey not found", user.UserID, passkeyID)
+	}
+
+	if err := service.Save(user, "Passkey removed"); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// passkeyDescriptors returns descriptors for all of the User's Passkeys
+func passkeyDescriptors(user *model.User) []webauthn.CredentialDescriptor {
+
+	result := make([]webauthn.CredentialDescriptor, len(user.Passkeys))
+
+	for index, passkey := range user.Passkeys {
+		result[index] = webauthn.CredentialDescriptor{
+			Type:       "public-key",
+			ID:         passkey.CredentialID,
+			Transports: passkey.Transports,
+		}
+	}
+
+	return result
+}
+
+// passkeyLabel returns a trimmed label (or a default) for a new Passkey
+func passkeyLabel(label string) string {
+
+	runes := []rune(label)
+
+	if len(runes) > passkeyMaxLabelLength {
+		runes = runes[:passkeyMaxLabelLength]
+	}
+
+	return first.String(strings.TrimSpace(string(runes)), "Passkey")
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasskeyLabel(t *testing.T) {
+	require.Equal(t, "My Phone", passkeyLabel("  My Phone "))
+	require.Equal(t, "Passkey", passkeyLabel(""))
+	require.Equal(t, "Passkey", passkeyLabel("   "))
+	require.Equal(t, passkeyMaxLabelLength, len([]rune(passkeyLabel(strings.Repeat("é", 200)))))
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/benpate/derp"
+)
+
+// emailConfirmationDuration is how long new users have to confirm their email address
+const emailConfirmationDuration = 48 * time.Hour
+
+// ValidatePassword returns an error if the password does not follow this domain's password policy
+func (service *User) ValidatePassword(password string) error {
+
+	policy := service.domainService.Get().PasswordPolicy
+
+	if ok, message := policy.ValidatePassword(password); !ok {
+		return derp.NewBadRequestError("service.User.ValidatePassword", message)
+	}
+
+	return nil
+}
+
+// Register saves a new User who signed up on their own (with the signup form or the Mastodon API).
+// The domain's signup mode decides whether an Invitation is required, and whether the new User must
+// wait for a domain owner's approval.  People with an Invitation are approved automatically.  If the
+// domain requires it, then the new User must also confirm their email address before signing in.
+func (service *User) Register(user *model.User, password string, invitation *model.Invitation, note string) error {
+
+	const location = "service.User.Register"
+
+	signupForm := service.domainService.Get().SignupForm
+
+	// RULE: The signup form must be active
+	if !signupForm.Active {
+		return derp.NewForbiddenError(location, "Signup is not allowed on this domain")
+	}
+
+	// RULE: Invite-only domains require a valid Invitation
+	if signupForm.IsInviteOnly() && (invitation == nil) {
+		return derp.NewForbiddenError(location, "An invitation is required to sign up on this domain")
+	}
+
+	// RULE: Passwords must follow the domain's password policy
+	if err := service.ValidatePassword(password); err != nil {
+		return derp.Wrap(err, location, "Invalid password")
+	}
+
+	user.SetPassword(password)
+
+	if !signupForm.GroupID.IsZero() {
+		user.GroupIDs = id.Slice{signupForm.GroupID}
+	}
+
+	if invitation != nil {
+		user.InvitationID = invitation.InvitationID
+	}
+
+	// New accounts may need to be approved by a domain owner
+	user.IsPending = signupForm.RequiresApproval() && (invitation == nil)
+
+	// Email addresses can only be confirmed if this domain can send email
+	if signupForm.RequireEmail && service.emailService.CanSend() {
+		user.EmailConfirmation = model.NewEmailConfirmation(emailConfirmationDuration)
+	}
+
+	// Use up the Invitation before the account is created, so that it cannot be over-used
+	if invitation != nil {
+		if err := service.invitationService.Redeem(invitation); err != nil {
+			return derp.Wrap(err, location, "Error redeeming Invitation", user.UserID)
+		}
+	}
+
+	if err := service.Save(user, note); err != nil {
+
+		if invitation != nil {
+			derp.Report(service.invitationService.Release(invitation))
+		}
+
+		return derp.Wrap(err, location, "Error saving new User")
+	}
+
+	if user.EmailConfirmation.IsPending() {
+		if err := service.emailService.SendEmailConfirmation(user); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error sending confirmation email", user.UserID))
+		}
+	}
+
+	return nil
+}
+
+// ConfirmEmail confirms a User's email address with the code from their confirmation email
+func (service *User) ConfirmEmail(userID string, code string, user *model.User) error {
+
+	const location = "service.User.ConfirmEmail"
+
+	if err := service.LoadByToken(userID, user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", userID)
+	}
+
+	if !user.EmailConfirmation.IsValid(code) {
+		return derp.NewUnauthorizedError(location, "Invalid email confirmation code", userID)
+	}
+
+	user.EmailConfirmation.Confirm()
+
+	if err := service.Save(user, "Email address confirmed"); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// ResendEmailConfirmation creates a new confirmation code for a User who has not yet
+// confirmed their email address, and sends it to them.
+func (service *User) ResendEmailConfirmation(user *model.User) error {
+
+	const location = "service.User.ResendEmailConfirmation"
+
+	if !user.EmailConfirmation.IsPending() {
+		return nil
+	}
+
+	user.EmailConfirmation = model.NewEmailConfirmation(emailConfirmationDuration)
+
+	if err := service.Save(user, "New email confirmation code"); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	if err := service.emailService.SendEmailConfirmation(user); err != nil {
+		return derp.Wrap(err, location, "Error sending confirmation email", user.UserID)
+	}
+
+	return nil
+}
+
+// Approve lets a User who is waiting for a domain owner's approval sign in
+func (service *User) Approve(user *model.User) error {
+
+	if !user.IsPending {
+		return nil
+	}
+
+	user.IsPending = false
+
+	if err := service.Save(user, "Approved"); err != nil {
+		return derp.Wrap(err, "service.User.Approve", "Error saving User", user.UserID)
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/queries"
+	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/derp"
+	"github.com/benpate/domain"
+	"github.com/benpate/rosetta/first"
+)
+
+// twoFactorRecoveryCodes is the number of recovery codes generated for each User
+const twoFactorRecoveryCodes = 10
+
+// IsTwoFactorRequired returns TRUE if the domain's policies require this User to use
+// two-factor authentication, either because they are an owner or because of a Group they belong to.
+func (service *User) IsTwoFactorRequired(user *model.User) (bool, error) {
+
+	if user.IsOwner && service.domainService.Get().RequireOwnerTwoFactor {
+		return true, nil
+	}
+
+	result, err := service.groupService.RequiresTwoFactor(user.GroupIDs...)
+
+	if err != nil {
+		return false, derp.Wrap(err, "service.User.IsTwoFactorRequired", "Error checking groups", user.UserID)
+	}
+
+	return result, nil
+}
+
+// NeedsTwoFactor returns TRUE if the User must enter (or enroll) a two-factor code before they can sign in
+func (service *User) NeedsTwoFactor(user *model.User) (bool, error) {
+
+	if user.TwoFactor.IsEnabled() {
+		return true, nil
+	}
+
+	return service.IsTwoFactorRequired(user)
+}
+
+// BeginTwoFactor returns the secret that the User should add to their authenticator app, along
+// with the "otpauth://" URI that encodes it.  The secret is saved as "pending" until the
+// User confirms it with EnableTwoFactor.
+func (service *User) BeginTwoFactor(user *model.User) (string, string, error) {
+
+	const location = "service.User.BeginTwoFactor"
+
+	// RULE: Cannot enroll twice
+	if user.TwoFactor.IsEnabled() {
+		return "", "", derp.NewBadRequestError(location, "Two-factor authentication is already enabled", user.UserID)
+	}
+
+	// Re-use the pending secret (if any) so that reloading the page does not invalidate a scanned QR code
+	if user.TwoFactor.PendingSecret == "" {
+
+		secret, err := totp.NewSecret()
+
+		if err != nil {
+			return "", "", derp.Wrap(err, location, "Error generating secret")
+		}
+
+		user.TwoFactor.PendingSecret = secret
+
+		if err := service.Save(user, "Two-factor authentication setup started"); err != nil {
+			return "", "", derp.Wrap(err, location, "Error saving User", user.UserID)
+		}
+	}
+
+	secret := user.TwoFactor.PendingSecret
+	return secret, service.twoFactorURI(user, secret), nil
+}
+
+// EnableTwoFactor confirms the User's pending secret with a code from their authenticator app.
+// It returns a new set of recovery codes, which are only available this one time.
+func (service *User) EnableTwoFactor(user *model.User, code string) ([]string, error) {
+
+	const location = "service.User.EnableTwoFactor"
+
+	if user.TwoFactor.PendingSecret == "" {
+		return nil, derp.NewBadRequestError(location, "Two-factor authentication setup has not been started", user.UserID)
+	}
+
+	if err := service.twoFactorAttempt(user, location); err != nil {
+		return nil, err
+	}
+
+	counter, ok := totp.Validate(user.TwoFactor.PendingSecret, code, time.Now())
+
+	if !ok {
+		return nil, derp.NewForbiddenError(location, "Invalid code", user.UserID)
+	}
+
+	recoveryCodes, err := newRecoveryCodes()
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error generating recovery codes")
+	}
+
+	user.TwoFactor.Secret = user.TwoFactor.PendingSecret
+	user.TwoFactor.PendingSecret = ""
+	user.TwoFactor.EnabledDate = time.Now().Unix()
+	user.TwoFactor.LastCounter = counter
+	user.TwoFactor.SetRecoveryCodes(recoveryCodes)
+	user.TwoFactor.Success()
+
+	if err := service.Save(user, "Two-factor authentication enabled"); err != nil {
+		return nil, derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return recoveryCodes, nil
+}
+
+// VerifyTwoFactor returns an error unless the code is a valid code from the User's
+// authenticator app, or one of their unused recovery codes.
+func (service *User) VerifyTwoFactor(user *model.User, code string) error {
+
+	const location = "service.User.VerifyTwoFactor"
+
+	if !user.TwoFactor.IsEnabled() {
+		return derp.NewBadRequestError(location, "Two-factor authentication is not enabled", user.UserID)
+	}
+
+	if err := service.twoFactorAttempt(user, location); err != nil {
+		return err
+	}
+
+	note := ""
+
+	// RULE: Each code can only be used once, so codes must be newer than the last one accepted
+	if counter, ok := totp.Validate(user.TwoFactor.Secret, code, time.Now()); ok && (counter > user.TwoFactor.LastCounter) {
+		user.TwoFactor.LastCounter = counter
+		note = "Two-factor code accepted"
+
+	} else if user.TwoFactor.UseRecoveryCode(code) {
+		note = "Two-factor recovery code used"
+
+	} else {
+		return derp.NewForbiddenError(location, "Invalid code", user.UserID)
+	}
+
+	user.TwoFactor.Success()
+
+	if err := service.Save(user, note); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// DisableTwoFactor turns off two-factor authentication after verifying a current code.
+// Users cannot disable two-factor authentication when the domain's policies require it,
+// unless they have a passkey that can be used as their second factor instead.
+func (service *User) DisableTwoFactor(user *model.User, code string) error {
+
+	const location = "service.User.DisableTwoFactor"
+
+	required, err := service.IsTwoFactorRequired(user)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error checking two-factor policy", user.UserID)
+	}
+
+	if required && !user.HasPasskeys() {
+		return derp.NewForbiddenError(location, "Two-factor authentication is required by the administrator of this website", user.UserID)
+	}
+
+	if err := service.VerifyTwoFactor(user, code); err != nil {
+		return derp.Wrap(err, location, "Invalid two-factor code", user.UserID)
+	}
+
+	return service.ResetTwoFactor(user, "Two-factor authentication disabled")
+}
+
+// NewRecoveryCodes replaces all of the User's recovery codes after verifying a current code.
+func (service *User) NewRecoveryCodes(user *model.User, code string) ([]string, error) {
+
+	const location = "service.User.NewRecoveryCodes"
+
+	if err := service.VerifyTwoFactor(user, code); err != nil {
+		return nil, derp.Wrap(err, location, "Invalid two-factor code", user.UserID)
+	}
+
+	recoveryCodes, err := newRecoveryCodes()
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error generating recovery codes")
+	}
+
+	user.TwoFactor.SetRecoveryCodes(recoveryCodes)
+
+	if err := service.Save(user, "Two-factor recovery codes replaced"); err != nil {
+		return nil, derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	return recoveryCodes, nil
+}
+
+// ResetTwoFactor removes all two-factor authentication settings from the User.  Domain owners
+// use this to restore access for Users who have lost their authenticator app and recovery codes.
+func (service *User) ResetTwoFactor(user *model.User, note string) error {
+
+	user.TwoFactor = model.NewTwoFactor()
+
+	if err := service.Save(user, note); err != nil {
+		return derp.Wrap(err, "service.User.ResetTwoFactor", "Error saving User", user.UserID)
+	}
+
+	return nil
+}
+
+// twoFactorAttempt counts a two-factor attempt in the database before the code is checked, and
+// returns an error if two-factor sign-ins are locked.  The count is a single conditional update, so
+// concurrent requests cannot race past the limit.  A valid code resets the count when the User is saved.
+func (service *User) twoFactorAttempt(user *model.User, location string) error {
+
+	allowed, err := queries.TwoFactorAttempt(service.collection, user.UserID, model.TwoFactorMaxAttempts, model.TwoFactorLockout)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error counting two-factor attempt", user.UserID)
+	}
+
+	if !allowed {
+		return derp.NewForbiddenError(location, "Too many invalid codes.  Please try again later.", user.UserID)
+	}
+
+	// Mirror the count in memory so that later saves do not reset it
+	user.TwoFactor.Failure()
+	return nil
+}
+
+// twoFactorURI returns the "otpauth://" URI that authenticator apps use to add the User's secret
+func (service *User) twoFactorURI(user *model.User, secret string) string {
+	issuer := first.String(service.domainService.Get().Label, domain.NameOnly(service.host))
+	return totp.URI(issuer, user.Username, secret)
+}
+
+// newRecoveryCodes generates a new set of random, single-use recovery codes (like "abcd-2345")
+func newRecoveryCodes() ([]string, error) {
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	result := make([]string, twoFactorRecoveryCodes)
+
+	for index := range result {
+
+		value, err := random.GenerateBytes(5)
+
+		if err != nil {
+			return nil, derp.Wrap(err, "service.newRecoveryCodes", "Error generating random bytes")
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(value))
+		result[index] = code[:4] + "-" + code[4:]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecoveryCodes(t *testing.T) {
+
+	codes, err := newRecoveryCodes()
+	require.Nil(t, err)
+	require.Equal(t, twoFactorRecoveryCodes, len(codes))
+
+	format := regexp.MustCompile(`^[a-z2-7]{4}-[a-z2-7]{4}$`)
+	unique := make(map[string]bool)
+
+	for _, code := range codes {
+		require.Regexp(t, format, code)
+		unique[code] = true
+	}
+
+	require.Equal(t, len(codes), len(unique))
+}
@@ -4,12 +4,16 @@ import (
 	"html/template"
 	"io/fs"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/list"
 	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
 	"github.com/tdewolff/minify/v2"
 	"github.com/tdewolff/minify/v2/html"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -256,3 +260,32 @@ func iif(condition bool, trueValue, falseValue string) string {
 	}
 	return falseValue
 }
+
+// actorUpdateActivity returns an "Update" activity that announces changes to an Actor's own profile
+func actorUpdateActivity(actorID string, document mapof.Any) mapof.Any {
+
+	return mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     actorID + "#updates/" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+		vocab.PropertyType:   vocab.ActivityTypeUpdate,
+		vocab.PropertyActor:  actorID,
+		vocab.PropertyObject: document,
+		vocab.PropertyTo:     sliceof.String{vocab.NamespaceActivityStreamsPublic},
+	}
+}
+
+// actorDeleteActivity returns an ActivityPub "Delete" activity that tells remote servers
+// to remove an Actor.  The Tombstone is embedded so that remote servers do not need to fetch it.
+func actorDeleteActivity(actorID string, tombstone mapof.Any) mapof.Any {
+
+	delete(tombstone, vocab.AtContext)
+
+	return mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     actorID + "#delete",
+		vocab.PropertyType:   vocab.ActivityTypeDelete,
+		vocab.PropertyActor:  actorID,
+		vocab.PropertyObject: tombstone,
+		vocab.PropertyTo:     sliceof.String{vocab.NamespaceActivityStreamsPublic},
+	}
+}
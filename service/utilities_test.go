@@ -3,6 +3,7 @@ package service
 import (
 	"testing"
 
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -84,3 +85,20 @@ func TestParsePathErrors(t *testing.T) {
 		require.NotNil(t, err)
 	}
 }
+
+func TestActorDeleteActivity(t *testing.T) {
+
+	user := model.NewUser()
+	user.ProfileURL = "https://emissary.social/@john"
+	user.SetDeleted("Deleted")
+
+	activity := actorDeleteActivity(user.ActivityPubURL(), user.GetTombstoneJSONLD())
+	require.Equal(t, "Delete", activity.GetString("type"))
+	require.Equal(t, "https://emissary.social/@john", activity.GetString("actor"))
+
+	// The Tombstone is embedded without its own @context
+	object := activity.GetMap("object")
+	require.Equal(t, "Tombstone", object.GetString("type"))
+	require.Equal(t, "https://emissary.social/@john", object.GetString("id"))
+	require.NotContains(t, object, "@context")
+}
@@ -235,7 +235,7 @@ func (client *Client) save(url string, document streams.Document) {
 	}
 
 	// Create a new value
-	value.Object = document.Map()
+	value.setObject(document.Map())
 	value.HTTPHeader = document.HTTPHeader()
 	value.HTTPHeader.Set(HeaderHannibalCache, "true")
 	value.HTTPHeader.Set(HeaderHannibalCacheDate, time.Now().Format(time.RFC3339))
@@ -268,7 +268,7 @@ func (client *Client) save(url string, document streams.Document) {
 func (client *Client) asDocument(value Value) streams.Document {
 
 	return streams.NewDocument(
-		value.Object,
+		value.Document(),
 		streams.WithClient(client),
 		streams.WithStats(value.Statistics),
 		streams.WithHTTPHeader(value.HTTPHeader),
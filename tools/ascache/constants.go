@@ -26,6 +26,12 @@ const HeaderHannibalCache = "X-Hannibal-Cache"
 // Custom header used by Hannibal to indicate the date that the cached value was saved
 const HeaderHannibalCacheDate = "X-Hannibal-Cache-Date"
 
+// PropertyRevisions is added to cached documents to list previous versions of the document
+const PropertyRevisions = "x-revisions"
+
+// MaxRevisions is the maximum number of previous versions to keep for each cached document
+const MaxRevisions = 10
+
 const PropertyRelationType = "relationType"
 
 const PropertyRelationHref = "relationHref"
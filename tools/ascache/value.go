@@ -15,11 +15,12 @@ import (
 type Value struct {
 
 	// Original HTTP Response
-	URLs       sliceof.String     `bson:"urls"`                 // One or more URLs used to retrieve this document
-	Object     mapof.Any          `bson:"object"`               // Original document, parsed as a map
-	HTTPHeader http.Header        `bson:"httpHeader,omitempty"` // HTTP headers that were returned with this document
-	Statistics streams.Statistics `bson:"statistics,omitempty"` // Statistics about this document
-	Metadata   mapof.Any          `bson:"metadata,omitempty"`   // Metadata about this document
+	URLs       sliceof.String            `bson:"urls"`                 // One or more URLs used to retrieve this document
+	Object     mapof.Any                 `bson:"object"`               // Original document, parsed as a map
+	HTTPHeader http.Header               `bson:"httpHeader,omitempty"` // HTTP headers that were returned with this document
+	Statistics streams.Statistics        `bson:"statistics,omitempty"` // Statistics about this document
+	Metadata   mapof.Any                 `bson:"metadata,omitempty"`   // Metadata about this document
+	Revisions  sliceof.Object[mapof.Any] `bson:"revisions,omitempty"`  // Previous versions of this document, newest first
 
 	// Caching Rules
 	Published   int64 `bson:"published"`   // Unix epoch seconds when this document was published
@@ -35,6 +36,7 @@ func NewValue() Value {
 		HTTPHeader: make(http.Header),
 		Statistics: streams.NewStatistics(),
 		Metadata:   make(mapof.Any),
+		Revisions:  make(sliceof.Object[mapof.Any], 0),
 	}
 }
 
@@ -43,6 +45,77 @@ func (value Value) ShouldRevalidate() bool {
 	return value.Revalidates < time.Now().Unix()
 }
 
+// Document returns the cached object, including any previous revisions
+func (value Value) Document() mapof.Any {
+
+	if len(value.Revisions) == 0 {
+		return value.Object
+	}
+
+	result := make(mapof.Any, len(value.Object)+1)
+
+	for key, item := range value.Object {
+		result[key] = item
+	}
+
+	result[PropertyRevisions] = value.Revisions
+	return result
+}
+
+// setObject replaces the cached object with a new version. If the content of
+// the object has changed, then the previous version is added to the list of revisions.
+func (value *Value) setObject(object mapof.Any) {
+
+	// Revisions are calculated by the cache, so they are never saved in the object itself.
+	delete(object, PropertyRevisions)
+
+	// Record the previous version of the object if it has been edited
+	if previous := value.Object; isRevised(previous, object) {
+
+		revision := mapof.Any{
+			vocab.PropertyName:      previous.GetString(vocab.PropertyName),
+			vocab.PropertySummary:   previous.GetString(vocab.PropertySummary),
+			vocab.PropertyContent:   previous.GetString(vocab.PropertyContent),
+			"sensitive":             previous.GetBool("sensitive"),
+			vocab.PropertyPublished: first(previous[vocab.PropertyUpdated], previous[vocab.PropertyPublished]),
+		}
+
+		value.Revisions = append(sliceof.Object[mapof.Any]{revision}, value.Revisions...)
+
+		if len(value.Revisions) > MaxRevisions {
+			value.Revisions = value.Revisions[:MaxRevisions]
+		}
+	}
+
+	value.Object = object
+}
+
+// isRevised returns TRUE if the visible content of a document has changed
+func isRevised(previous mapof.Any, next mapof.Any) bool {
+
+	if len(previous) == 0 {
+		return false
+	}
+
+	for _, property := range []string{vocab.PropertyName, vocab.PropertySummary, vocab.PropertyContent} {
+		if previous.GetString(property) != next.GetString(property) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// first returns the first non-nil value
+func first(values ...any) any {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
 // calcPublished calculates the date that a document was sent/refreshed by the origin.
 // This IS NOT the original create or publish date.
 func (value *Value) calcPublished() {
@@ -0,0 +1,46 @@
+package ascache
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue_SetObject(t *testing.T) {
+
+	value := NewValue()
+
+	// First version does not create a revision
+	value.setObject(mapof.Any{"id": "https://example.com/1", "content": "First"})
+	require.Equal(t, 0, len(value.Revisions))
+
+	// Unchanged content does not create a revision
+	value.setObject(mapof.Any{"id": "https://example.com/1", "content": "First"})
+	require.Equal(t, 0, len(value.Revisions))
+
+	// Changed content records the previous version
+	value.setObject(mapof.Any{"id": "https://example.com/1", "content": "Second", PropertyRevisions: "ignored"})
+	require.Equal(t, 1, len(value.Revisions))
+	require.Equal(t, "First", value.Revisions[0].GetString("content"))
+	require.Nil(t, value.Object[PropertyRevisions])
+
+	// Revisions are exposed in the document, newest first
+	value.setObject(mapof.Any{"id": "https://example.com/1", "content": "Third"})
+	document := value.Document()
+	revisions := document[PropertyRevisions].(sliceof.Object[mapof.Any])
+	require.Equal(t, "Second", revisions[0].GetString("content"))
+	require.Equal(t, "First", revisions[1].GetString("content"))
+}
+
+func TestValue_SetObject_MaxRevisions(t *testing.T) {
+
+	value := NewValue()
+
+	for index := 0; index < MaxRevisions+5; index++ {
+		value.setObject(mapof.Any{"content": string(rune('A' + index))})
+	}
+
+	require.Equal(t, MaxRevisions, len(value.Revisions))
+}
@@ -32,7 +32,7 @@ func (client Client) Load(url string, options ...any) (streams.Document, error)
 	}
 
 	// Otherwise, try to load the baseURL and find the hash inside that document
-	result, err := client.innerClient.Load(baseURL, options)
+	result, err := client.innerClient.Load(baseURL, options...)
 
 	if err != nil {
 		return result, err
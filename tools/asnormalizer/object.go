@@ -33,12 +33,24 @@ func Object(document streams.Document) map[string]any {
 		vocab.PropertyName:         actual.Name(),
 		vocab.PropertyContext:      Context(document),
 		vocab.PropertySummary:      actual.Summary(),
+		"sensitive":                actual.Get("sensitive").Bool(),
 		vocab.PropertyContent:      actual.Content(),
 		vocab.PropertyPublished:    first(actual.Published(), time.Now()),
 		vocab.PropertyTag:          Tags(document.Tag()),
 		"x-original":               document.Value(),
 	}
 
+	// Quotes are normalized into a single "quoteUrl" property, and the
+	// fallback "RE: <url>" markup is removed because we display quotes natively.
+	if quoteURL := Quote(actual); quoteURL != "" {
+		result["quoteUrl"] = quoteURL
+		result[vocab.PropertyContent] = QuoteContent(actual.Content())
+	}
+
+	if updated := actual.Updated(); !updated.IsZero() {
+		result[vocab.PropertyUpdated] = updated
+	}
+
 	if attachments := actual.Attachment(); attachments.NotNil() {
 
 		for attachment := attachments; attachment.NotNil(); attachment = attachment.Tail() {
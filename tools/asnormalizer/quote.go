@@ -0,0 +1,79 @@
+package asnormalizer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+// quoteInline matches the "RE: <url>" fallback markup that Mastodon, Misskey, and
+// others include in the content of quote posts, for clients that cannot display quotes.
+var quoteInline = regexp.MustCompile(`(?s)<(p|span) class="quote-inline">.*?</(p|span)>`)
+
+// Quote returns the URL of the document being quoted (if any).  Quotes can
+// be published in several different ways, so this function checks all of the
+// properties that are in common use across the Fediverse.
+func Quote(document streams.Document) string {
+
+	// Check the (many) properties that other servers use for quotes
+	for _, property := range []string{"quote", "quoteUrl", "quoteUri", "_misskey_quote"} {
+		if value := document.Get(property).ID(); value != "" {
+			return value
+		}
+	}
+
+	// Fall back to FEP-e232 Object Links
+	// https://codeberg.org/fediverse/fep/src/branch/main/fep/e232/fep-e232.md
+	for tag := document.Tag(); tag.NotNil(); tag = tag.Tail() {
+		if isQuoteLink(tag) {
+			return tag.Href()
+		}
+	}
+
+	return ""
+}
+
+// QuoteContent removes the fallback markup for quote posts from an HTML string
+func QuoteContent(content string) string {
+	return strings.TrimSpace(quoteInline.ReplaceAllString(content, ""))
+}
+
+// QuoteRel is the link relation that identifies a FEP-e232 Object Link as a quote
+const QuoteRel = "https://misskey-hub.net/ns#_misskey_quote"
+
+// isQuoteLink returns TRUE if the provided tag is a FEP-e232 Object Link
+// with the quote relation (which is how quotes are communicated by several servers)
+func isQuoteLink(tag streams.Document) bool {
+
+	if tag.Type() != vocab.CoreTypeLink {
+		return false
+	}
+
+	// RULE: Object Links can point to any ActivityPub document, so only "quote" links are quotes
+	if !isQuoteRel(tag) {
+		return false
+	}
+
+	mediaType := tag.MediaType()
+
+	if mediaType == vocab.ContentTypeActivityPub {
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, vocab.ContentTypeJSONLD)
+}
+
+// isQuoteRel returns TRUE if the tag includes the quote link relation
+func isQuoteRel(tag streams.Document) bool {
+
+	for rel := tag.Rel(); rel.NotNil(); rel = rel.Next() {
+		switch rel.String() {
+		case QuoteRel, "misskey:_misskey_quote":
+			return true
+		}
+	}
+
+	return false
+}
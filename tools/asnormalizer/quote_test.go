@@ -0,0 +1,95 @@
+package asnormalizer
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote_Misskey(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		"type":           "Note",
+		"id":             "https://example.com/notes/1",
+		"_misskey_quote": "https://other.com/notes/2",
+	})
+
+	require.Equal(t, "https://other.com/notes/2", Quote(document))
+}
+
+func TestQuote_QuoteURL(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		"type":     "Note",
+		"id":       "https://example.com/notes/1",
+		"quoteUrl": "https://other.com/notes/2",
+	})
+
+	require.Equal(t, "https://other.com/notes/2", Quote(document))
+}
+
+func TestQuote_ObjectLink(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		"type": "Note",
+		"id":   "https://example.com/notes/1",
+		"tag": []any{
+			mapof.Any{
+				"type": "Hashtag",
+				"href": "https://example.com/tags/cats",
+				"name": "#cats",
+			},
+			mapof.Any{
+				"type":      "Link",
+				"mediaType": `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`,
+				"rel":       "https://misskey-hub.net/ns#_misskey_quote",
+				"href":      "https://other.com/notes/2",
+				"name":      "RE: https://other.com/notes/2",
+			},
+		},
+	})
+
+	require.Equal(t, "https://other.com/notes/2", Quote(document))
+
+	// Object Links should not be included in the normalized tags
+	tags := Tags(document.Tag())
+	require.Equal(t, 1, len(tags))
+	require.Equal(t, "#cats", tags[0]["name"])
+}
+
+func TestQuote_ObjectLinkWithoutRel(t *testing.T) {
+
+	// Object Links without the quote relation are links, not quotes
+	document := streams.NewDocument(mapof.Any{
+		"type": "Note",
+		"id":   "https://example.com/notes/1",
+		"tag": []any{
+			mapof.Any{
+				"type":      "Link",
+				"mediaType": "application/activity+json",
+				"href":      "https://other.com/notes/2",
+			},
+		},
+	})
+
+	require.Equal(t, "", Quote(document))
+	require.Equal(t, 1, len(Tags(document.Tag())))
+}
+
+func TestQuote_None(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		"type":    "Note",
+		"id":      "https://example.com/notes/1",
+		"content": "Just a regular post",
+	})
+
+	require.Equal(t, "", Quote(document))
+}
+
+func TestQuoteContent(t *testing.T) {
+	content := `<p>Look at this!</p><p class="quote-inline">RE: <a href="https://other.com/notes/2">https://other.com/notes/2</a></p>`
+	require.Equal(t, "<p>Look at this!</p>", QuoteContent(content))
+}
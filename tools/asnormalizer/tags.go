@@ -14,6 +14,12 @@ func Tags(document streams.Document) []map[string]any {
 
 	for tag := range document.Channel() {
 
+		// FEP-e232 Object Links are quotes, not tags.
+		// They are normalized separately (see Quote)
+		if isQuoteLink(tag) {
+			continue
+		}
+
 		allow := true
 
 		// Do not allow any "internal" tags to be imported from
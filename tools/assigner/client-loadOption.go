@@ -0,0 +1,43 @@
+package assigner
+
+import "github.com/benpate/hannibal/sigs"
+
+// LoadConfig contains optional settings for the Load() method
+type LoadConfig struct {
+	signer *sigs.Signer // signer is used to sign outbound requests (if present)
+}
+
+// LoadOption is a function that modifies the default behavior of the LoadConfig
+type LoadOption func(*LoadConfig)
+
+// NewLoadConfig creates a new LoadConfig object with corresponding options
+func NewLoadConfig(options ...any) LoadConfig {
+	result := LoadConfig{}
+	result.With(options...)
+	return result
+}
+
+// With applies options to the LoadConfig IF they are assigner.LoadOption
+func (config *LoadConfig) With(options ...any) {
+	for _, option := range options {
+		if typed, ok := option.(LoadOption); ok {
+			typed(config)
+		}
+	}
+}
+
+// WithSigner signs the outbound request with the provided Signer.
+// If multiple signers are provided, then the last one wins.
+func WithSigner(signer sigs.Signer) LoadOption {
+	return func(config *LoadConfig) {
+		config.signer = &signer
+	}
+}
+
+// WithoutSigner removes any previously configured Signer, so that
+// the outbound request is sent without a signature.
+func WithoutSigner() LoadOption {
+	return func(config *LoadConfig) {
+		config.signer = nil
+	}
+}
@@ -0,0 +1,77 @@
+package assigner
+
+import (
+	"net/http"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/remote"
+	"github.com/benpate/sherlock"
+)
+
+// Client is a streams.Client wrapper that signs outbound HTTP requests (aka "authorized fetch")
+// using a sigs.Signer that is passed in as a LoadOption.  Requests that do not include a signer
+// are passed through to the innerClient unchanged.
+type Client struct {
+	innerClient sherlock.Client
+}
+
+// New creates a fully initialized Client object
+func New(innerClient sherlock.Client) Client {
+	return Client{
+		innerClient: innerClient,
+	}
+}
+
+// Load implements the streams.Client interface.  If a WithSigner() option is present,
+// then the request is signed with the provided key.  Otherwise, it is sent as-is.
+func (client Client) Load(url string, options ...any) (streams.Document, error) {
+
+	config := NewLoadConfig(options...)
+
+	// If there is no signer, then pass the request through unchanged
+	if config.signer == nil {
+		return client.innerClient.Load(url, options...)
+	}
+
+	// Make a copy of the inner client (and its RemoteOptions) so that
+	// the signer does not leak into other requests
+	signedClient := client.innerClient
+	signedClient.RemoteOptions = make([]remote.Option, 0, len(client.innerClient.RemoteOptions)+1)
+	signedClient.RemoteOptions = append(signedClient.RemoteOptions, client.innerClient.RemoteOptions...)
+	signedClient.RemoteOptions = append(signedClient.RemoteOptions, withSigner(*config.signer))
+
+	// Load the document using the signed client
+	return signedClient.Load(url, options...)
+}
+
+// withSigner is a remote.Option that signs outbound GET requests.  This differs from
+// sigs.WithSigner because GET requests have no body, so there is no Digest to calculate.
+func withSigner(signer sigs.Signer) remote.Option {
+
+	return remote.Option{
+
+		ModifyRequest: func(txn *remote.Transaction, request *http.Request) *http.Response {
+
+			// The signer reads the request body to calculate a Digest, so it cannot be nil
+			if request.Body == nil {
+				request.Body = http.NoBody
+			}
+
+			// Sign the outbound request
+			if err := signer.Sign(request); err != nil {
+				derp.Report(derp.Wrap(err, "assigner.withSigner", "Error signing request"))
+			}
+
+			// Remove the (empty) body and Digest so that GET requests are sent normally
+			if request.ContentLength == 0 {
+				request.Body = http.NoBody
+				request.Header.Del(sigs.FieldDigest)
+			}
+
+			// Nil response means that we are still sending the request to the remote server
+			return nil
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package assigner
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/sherlock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Unsigned(t *testing.T) {
+
+	server, signatures := getTestServer()
+	defer server.Close()
+
+	client := New(sherlock.NewClient())
+	_, err := client.Load(server.URL + "/document")
+
+	require.Nil(t, err)
+	require.Equal(t, []string{""}, *signatures)
+}
+
+func TestClient_Signed(t *testing.T) {
+
+	server, signatures := getTestServer()
+	defer server.Close()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	signer := sigs.NewSigner("https://example.com/@application#main-key", privateKey, sigs.SignerFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate))
+	innerClient := sherlock.NewClient()
+	client := New(innerClient)
+
+	// First request is signed
+	_, err = client.Load(server.URL+"/document", WithSigner(signer))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(*signatures))
+	require.Contains(t, (*signatures)[0], `keyId="https://example.com/@application#main-key"`)
+
+	// Signer must not leak into the inner client
+	require.Equal(t, 0, len(innerClient.RemoteOptions))
+
+	// Subsequent request is unsigned
+	_, err = client.Load(server.URL+"/document", WithSigner(signer), WithoutSigner())
+	require.Nil(t, err)
+	require.Equal(t, 2, len(*signatures))
+	require.Equal(t, "", (*signatures)[1])
+}
+
+// getTestServer returns a test server that records the Signature header of every request
+func getTestServer() (*httptest.Server, *[]string) {
+
+	signatures := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get("Signature"))
+		w.Header().Set("Content-Type", "application/activity+json")
+		_, _ = w.Write([]byte(`{"id":"` + r.URL.String() + `","type":"Note","name":"Test Document"}`))
+	}))
+
+	return server, &signatures
+}
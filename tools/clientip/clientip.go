@@ -0,0 +1,19 @@
+// Package clientip identifies the IP address of the client that sent an HTTP request.
+package clientip
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Extractor returns the client IP address for a request.  X-Forwarded-For headers are
+// only trusted when they are added by a proxy on a loopback, link-local, or private
+// address.  Otherwise, the address of the direct connection is used, so that clients
+// cannot choose their own IP address by sending forged headers.
+var Extractor echo.IPExtractor = echo.ExtractIPFromXFFHeader()
+
+// FromRequest returns the IP address of the client that sent this request
+func FromRequest(request *http.Request) string {
+	return Extractor(request)
+}
@@ -0,0 +1,27 @@
+package clientip
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRequest(t *testing.T) {
+
+	request, err := http.NewRequest(http.MethodGet, "https://local.test/", nil)
+	require.Nil(t, err)
+
+	// Direct connections use the remote address
+	request.RemoteAddr = "93.184.216.34:1234"
+	require.Equal(t, "93.184.216.34", FromRequest(request))
+
+	// Forwarded headers from public addresses are ignored
+	request.Header.Set("X-Forwarded-For", "10.0.0.1")
+	require.Equal(t, "93.184.216.34", FromRequest(request))
+
+	// Forwarded headers from a local proxy are trusted
+	request.RemoteAddr = "127.0.0.1:1234"
+	request.Header.Set("X-Forwarded-For", "93.184.216.34")
+	require.Equal(t, "93.184.216.34", FromRequest(request))
+}
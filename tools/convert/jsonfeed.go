@@ -5,9 +5,11 @@ import (
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
+	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/first"
 	"github.com/benpate/rosetta/html"
 	"github.com/benpate/rosetta/iterator"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/slice"
 	"github.com/kr/jsonfeed"
 )
@@ -100,3 +102,35 @@ func JsonFeedToContentHTML(item jsonfeed.Item) string {
 
 	return SanitizeHTML(result)
 }
+
+// JsonFeedToActivityMap populates an ActivityStreams map from a JSON Feed item
+func JsonFeedToActivityMap(feed jsonfeed.Feed, item jsonfeed.Item) mapof.Any {
+
+	result := mapof.Any{
+		vocab.PropertyType: vocab.ObjectTypePage,
+		vocab.PropertyID:   first.String(item.URL, item.ID),
+		vocab.PropertyName: item.Title,
+	}
+
+	if !item.DatePublished.IsZero() {
+		result[vocab.PropertyPublished] = item.DatePublished.Unix()
+	}
+
+	if item.Summary != "" {
+		result[vocab.PropertySummary] = item.Summary
+	}
+
+	if item.Image != "" {
+		result[vocab.PropertyImage] = item.Image
+	}
+
+	if contentHTML := JsonFeedToContentHTML(item); contentHTML != "" {
+		result[vocab.PropertyContent] = contentHTML
+	}
+
+	if attributedTo := JsonFeedToAuthor(feed, item); attributedTo.NotEmpty() {
+		result[vocab.PropertyAttributedTo] = attributedTo.GetJSONLD()
+	}
+
+	return result
+}
@@ -1,9 +1,15 @@
 package convert
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
 	"willnorris.com/go/microformats"
 )
 
@@ -76,3 +82,121 @@ func MicroformatPropertyToString(entry *microformats.Microformat, names ...strin
 
 	return ""
 }
+
+// MicroformatToActivityMap populates an ActivityStreams map from an h-entry that was found on the page at pageURL.
+// The position is used to identify entries without a permalink (see MicroformatEntryID)
+func MicroformatToActivityMap(pageURL string, feed *microformats.Microformat, entry *microformats.Microformat, position int) mapof.Any {
+
+	result := mapof.Any{
+		vocab.PropertyType: vocab.ObjectTypePage,
+		vocab.PropertyID:   MicroformatEntryID(pageURL, entry, position),
+		vocab.PropertyName: MicroformatPropertyToString(entry, "name"),
+	}
+
+	// Get the publish and update dates from the entry
+	if publishDate, ok := MicroformatPropertyToTime(entry, "published"); ok {
+		result[vocab.PropertyPublished] = publishDate.Unix()
+	}
+
+	if updateDate, ok := MicroformatPropertyToTime(entry, "updated"); ok {
+		result[vocab.PropertyUpdated] = updateDate.Unix()
+	}
+
+	if summary := MicroformatPropertyToString(entry, "summary"); summary != "" {
+		result[vocab.PropertySummary] = SanitizeText(summary)
+	}
+
+	if contentHTML := MicroformatContentHTML(entry); contentHTML != "" {
+		result[vocab.PropertyContent] = SanitizeHTML(contentHTML)
+	}
+
+	if imageURL := MicroformatPropertyToString(entry, "photo", "featured"); imageURL != "" {
+		result[vocab.PropertyImage] = imageURL
+	}
+
+	// Use the entry's author, falling back to the feed's author
+	for _, item := range []*microformats.Microformat{entry, feed} {
+
+		if item == nil {
+			continue
+		}
+
+		if author := AnyToMicroformat(item.Properties["author"]); author != nil {
+			result[vocab.PropertyAttributedTo] = MicroformatToAuthor(author).GetJSONLD()
+			break
+		}
+	}
+
+	return result
+}
+
+// MicroformatContentHTML returns the HTML value of an entry's e-content property
+func MicroformatContentHTML(entry *microformats.Microformat) string {
+
+	for _, value := range entry.Properties["content"] {
+		switch typed := value.(type) {
+
+		case map[string]string:
+			if html := typed["html"]; html != "" {
+				return html
+			}
+			return typed["value"]
+
+		case string:
+			return typed
+		}
+	}
+
+	return ""
+}
+
+// MicroformatEntryID returns a stable identifier for an h-entry that was found on the page at pageURL.
+// Entries that publish a u-url or u-uid use that value.  Otherwise, the ID is a fragment of the page URL
+// that hashes the published date and the entry's position among other entries published at the same
+// time, so that edits to an entry's name or content do not change its identity.  The position should
+// be counted from the bottom of the page (where the oldest entries are) so that new entries do not
+// shift it.  Untitled entries without a published date are only as stable as their page position.
+func MicroformatEntryID(pageURL string, entry *microformats.Microformat, position int) string {
+
+	if id := MicroformatPropertyToString(entry, "url", "uid"); id != "" {
+		return id
+	}
+
+	identity := MicroformatPropertyToString(entry, "published") + "\n" + strconv.Itoa(position)
+
+	hash := sha256.Sum256([]byte(identity))
+	return strings.Split(pageURL, "#")[0] + "#h-entry-" + hex.EncodeToString(hash[:8])
+}
+
+// microformatDateLayouts lists the date formats that are commonly found in dt-* properties
+// https://microformats.org/wiki/value-class-pattern#Date_and_time_parsing
+var microformatDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// MicroformatPropertyToTime returns the first value of a dt-* property as a time.Time.
+// It returns FALSE if the property is missing or cannot be parsed.
+func MicroformatPropertyToTime(entry *microformats.Microformat, names ...string) (time.Time, bool) {
+
+	value := strings.TrimSpace(MicroformatPropertyToString(entry, names...))
+
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range microformatDateLayouts {
+		if result, err := time.Parse(layout, value); err == nil {
+			return result, true
+		}
+	}
+
+	return time.Time{}, false
+}
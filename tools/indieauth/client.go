@@ -0,0 +1,165 @@
+package indieauth
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/convert"
+	"willnorris.com/go/microformats"
+)
+
+// Client describes an IndieAuth client application, as discovered
+// by fetching its client_id URL.
+// https://indieauth.spec.indieweb.org/#client-information-discovery
+type Client struct {
+	ClientID     string   `json:"client_id"`
+	ClientName   string   `json:"client_name"`
+	ClientURI    string   `json:"client_uri"`
+	LogoURI      string   `json:"logo_uri"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// NewClient returns a fully initialized Client, with default values
+// derived from the client_id URL
+func NewClient(clientID string) Client {
+	return Client{
+		ClientID:     clientID,
+		ClientName:   clientHostname(clientID),
+		ClientURI:    clientID,
+		RedirectURIs: make([]string, 0),
+	}
+}
+
+// DiscoverClient retrieves the client_id URL and reads its metadata.  Clients may publish
+// a JSON metadata document, or an HTML page with an h-app and rel="redirect_uri" links.
+// Clients that cannot be retrieved are still returned with default values, because
+// client information discovery is optional in the IndieAuth spec.  Options are applied
+// to the outbound request (for instance, to restrict which addresses can be reached).
+func DiscoverClient(clientID string, options ...remote.Option) (Client, error) {
+
+	const location = "indieauth.DiscoverClient"
+
+	if err := ValidateClientID(clientID); err != nil {
+		return Client{}, derp.Wrap(err, location, "Invalid client_id")
+	}
+
+	result := NewClient(clientID)
+
+	var body string
+	txn := remote.Get(clientID).
+		Accept("application/json", "text/html").
+		Use(options...).
+		Result(&body)
+
+	if err := txn.Send(); err != nil {
+		return result, nil
+	}
+
+	if strings.Contains(txn.ResponseContentType(), "json") {
+		result.parseJSON(body)
+	} else {
+		result.parseHTML(body)
+	}
+
+	return result, nil
+}
+
+// parseJSON reads client metadata from a JSON document
+func (client *Client) parseJSON(body string) {
+
+	metadata := Client{}
+
+	if err := json.Unmarshal([]byte(body), &metadata); err != nil {
+		return
+	}
+
+	// RULE: client_id in the document must match the requested client_id
+	if (metadata.ClientID != "") && (metadata.ClientID != client.ClientID) {
+		return
+	}
+
+	if metadata.ClientName != "" {
+		client.ClientName = metadata.ClientName
+	}
+
+	// RULE: client_uri must be a prefix of the client_id
+	if (metadata.ClientURI != "") && strings.HasPrefix(client.ClientID, metadata.ClientURI) {
+		client.ClientURI = metadata.ClientURI
+	}
+
+	client.LogoURI = metadata.LogoURI
+	client.RedirectURIs = append(client.RedirectURIs, metadata.RedirectURIs...)
+}
+
+// parseHTML reads client metadata from an HTML page that includes an h-app microformat
+func (client *Client) parseHTML(body string) {
+
+	baseURL, err := url.Parse(client.ClientID)
+
+	if err != nil {
+		return
+	}
+
+	data := microformats.Parse(strings.NewReader(body), baseURL)
+
+	client.RedirectURIs = append(client.RedirectURIs, data.Rels["redirect_uri"]...)
+
+	for _, item := range data.Items {
+
+		if !isAppType(item.Type) {
+			continue
+		}
+
+		if name := firstProperty(item, "name"); name != "" {
+			client.ClientName = name
+		}
+
+		if logo := firstProperty(item, "logo"); logo != "" {
+			client.LogoURI = logo
+		}
+
+		return
+	}
+}
+
+// isAppType returns TRUE if the microformat types include an h-app (or legacy h-x-app)
+func isAppType(types []string) bool {
+
+	for _, value := range types {
+		if (value == "h-app") || (value == "h-x-app") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firstProperty returns the first value of a microformat property, as a string
+func firstProperty(item *microformats.Microformat, name string) string {
+
+	for _, value := range item.Properties[name] {
+
+		if mapValue, ok := value.(map[string]string); ok {
+			return mapValue["value"]
+		}
+
+		if result := convert.String(value); result != "" {
+			return result
+		}
+	}
+
+	return ""
+}
+
+// clientHostname returns the hostname of a client_id, for use as a default display name
+func clientHostname(clientID string) string {
+
+	if clientURL, err := url.Parse(clientID); err == nil {
+		return clientURL.Host
+	}
+
+	return clientID
+}
@@ -0,0 +1,63 @@
+package indieauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverClient_JSON(t *testing.T) {
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"client_id": "` + server.URL + `/",
+			"client_name": "Example App",
+			"logo_uri": "` + server.URL + `/logo.png",
+			"redirect_uris": ["https://other.example.com/callback"]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := DiscoverClient(server.URL + "/")
+	require.Nil(t, err)
+	require.Equal(t, "Example App", client.ClientName)
+	require.Equal(t, server.URL+"/logo.png", client.LogoURI)
+	require.Equal(t, []string{"https://other.example.com/callback"}, client.RedirectURIs)
+}
+
+func TestDiscoverClient_HTML(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head>
+			<link rel="redirect_uri" href="/callback">
+			</head><body>
+			<div class="h-app"><img class="u-logo" src="/logo.png"><a class="p-name u-url" href="/">HTML App</a></div>
+			</body></html>`))
+	}))
+	defer server.Close()
+
+	client, err := DiscoverClient(server.URL + "/")
+	require.Nil(t, err)
+	require.Equal(t, "HTML App", client.ClientName)
+	require.Equal(t, server.URL+"/logo.png", client.LogoURI)
+	require.Equal(t, []string{server.URL + "/callback"}, client.RedirectURIs)
+}
+
+func TestDiscoverClient_Unavailable(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := DiscoverClient(server.URL + "/")
+	require.Nil(t, err)
+	require.Equal(t, server.URL[len("http://"):], client.ClientName)
+	require.Empty(t, client.RedirectURIs)
+}
@@ -0,0 +1,130 @@
+// Package indieauth contains helper functions for running an IndieAuth authorization server,
+// including client discovery, PKCE verification, and URL canonicalization.
+//
+// https://indieauth.spec.indieweb.org/
+package indieauth
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// IsClientID returns TRUE if the provided value looks like an IndieAuth client_id
+// (which is a URL) as opposed to a pre-registered OAuth client_id.
+func IsClientID(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+// ValidateClientID confirms that a client_id is a valid IndieAuth client identifier
+// https://indieauth.spec.indieweb.org/#client-identifier
+func ValidateClientID(clientID string) error {
+
+	const location = "indieauth.ValidateClientID"
+
+	clientURL, err := url.Parse(clientID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid client_id", clientID, derp.WithBadRequest())
+	}
+
+	if (clientURL.Scheme != "https") && (clientURL.Scheme != "http") {
+		return derp.NewBadRequestError(location, "client_id must use http or https", clientID)
+	}
+
+	if clientURL.Fragment != "" {
+		return derp.NewBadRequestError(location, "client_id must not contain a fragment", clientID)
+	}
+
+	if clientURL.User != nil {
+		return derp.NewBadRequestError(location, "client_id must not contain a username or password", clientID)
+	}
+
+	if strings.Contains("/"+clientURL.Path+"/", "/./") || strings.Contains("/"+clientURL.Path+"/", "/../") {
+		return derp.NewBadRequestError(location, "client_id must not contain dot path segments", clientID)
+	}
+
+	// Client IDs must use domain names (except for loopback addresses)
+	if ip := net.ParseIP(clientURL.Hostname()); (ip != nil) && !ip.IsLoopback() {
+		return derp.NewBadRequestError(location, "client_id must not be an IP address", clientID)
+	}
+
+	return nil
+}
+
+// CanonicalURL normalizes a user-entered profile URL:  adding a missing scheme,
+// lowercasing the hostname, and adding a missing path.
+// https://indieauth.spec.indieweb.org/#url-canonicalization
+func CanonicalURL(value string) (string, error) {
+
+	const location = "indieauth.CanonicalURL"
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return "", derp.NewBadRequestError(location, "URL is required")
+	}
+
+	if !IsClientID(value) {
+		value = "https://" + value
+	}
+
+	result, err := url.Parse(value)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Invalid URL", value, derp.WithBadRequest())
+	}
+
+	if result.Host == "" {
+		return "", derp.NewBadRequestError(location, "URL must include a hostname", value)
+	}
+
+	result.Scheme = strings.ToLower(result.Scheme)
+	result.Host = strings.ToLower(result.Host)
+	result.Fragment = ""
+
+	if result.Path == "" {
+		result.Path = "/"
+	}
+
+	return result.String(), nil
+}
+
+// SameOrigin returns TRUE if two URLs share the same scheme, host, and port.
+func SameOrigin(first string, second string) bool {
+
+	firstURL, err := url.Parse(first)
+
+	if err != nil {
+		return false
+	}
+
+	secondURL, err := url.Parse(second)
+
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(firstURL.Scheme, secondURL.Scheme) && strings.EqualFold(firstURL.Host, secondURL.Host)
+}
+
+// RedirectAllowed returns TRUE if a redirect_uri is acceptable for the given client_id.
+// Redirects on the same origin as the client_id are always allowed.  Other redirects
+// must be published in the client's metadata.
+// https://indieauth.spec.indieweb.org/#redirect-url
+func RedirectAllowed(clientID string, redirectURI string, published []string) bool {
+
+	if SameOrigin(clientID, redirectURI) {
+		return true
+	}
+
+	for _, value := range published {
+		if value == redirectURI {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package indieauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// CodeChallengeMethodS256 is the SHA-256 PKCE challenge method
+const CodeChallengeMethodS256 = "S256"
+
+// CodeChallengeMethodPlain is the (discouraged) plaintext PKCE challenge method
+const CodeChallengeMethodPlain = "plain"
+
+// CodeChallenge calculates the S256 code challenge for a PKCE code verifier
+// https://www.rfc-editor.org/rfc/rfc7636#section-4.2
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// VerifyCodeChallenge returns TRUE if the code verifier matches the code challenge
+// that was provided at the beginning of the authorization request.
+func VerifyCodeChallenge(verifier string, challenge string, method string) bool {
+
+	if (verifier == "") || (challenge == "") {
+		return false
+	}
+
+	switch method {
+
+	case CodeChallengeMethodS256, "":
+		return subtle.ConstantTimeCompare([]byte(CodeChallenge(verifier)), []byte(challenge)) == 1
+
+	case CodeChallengeMethodPlain:
+		return subtle.ConstantTimeCompare([]byte(verifier), []byte(challenge)) == 1
+	}
+
+	return false
+}
@@ -0,0 +1,24 @@
+package indieauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// Example from RFC 7636, Appendix B
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	require.True(t, VerifyCodeChallenge(verifier, challenge, CodeChallengeMethodS256))
+	require.False(t, VerifyCodeChallenge("wrong", challenge, CodeChallengeMethodS256))
+	require.True(t, VerifyCodeChallenge(verifier, verifier, CodeChallengeMethodPlain))
+	require.False(t, VerifyCodeChallenge(verifier, challenge, "unknown"))
+	require.False(t, VerifyCodeChallenge("", "", CodeChallengeMethodPlain))
+}
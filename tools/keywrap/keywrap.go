@@ -0,0 +1,110 @@
+// Package keywrap encrypts secret keys with a "key encrypting key" (KEK) so that
+// they can be stored safely in the database.  Keys are encrypted with AES-GCM, using
+// a random nonce that is stored at the beginning of the encrypted value.
+package keywrap
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+
+	"github.com/benpate/derp"
+)
+
+// Algorithm is the name of the encryption algorithm used by this package
+const Algorithm = "AES-GCM"
+
+// Wrap encrypts a plaintext key with the key encrypting key.  The additionalData
+// (like the ID of the record being encrypted) is not stored, but must match when
+// the key is unwrapped.  This prevents encrypted values from being swapped between records.
+func Wrap(keyEncryptingKey []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+
+	const location = "keywrap.Wrap"
+
+	aead, err := newAEAD(keyEncryptingKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid key encrypting key")
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, derp.Wrap(err, location, "Error generating nonce")
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Unwrap decrypts a value that was encrypted by Wrap.
+func Unwrap(keyEncryptingKey []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+
+	const location = "keywrap.Unwrap"
+
+	aead, err := newAEAD(keyEncryptingKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid key encrypting key")
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, derp.NewInternalError(location, "Encrypted value is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	result, err := aead.Open(nil, nonce, ciphertext, additionalData)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error decrypting value.  The key encrypting key may be incorrect.")
+	}
+
+	return result, nil
+}
+
+// UnwrapAny decrypts a value with the first key encrypting key that works.  Empty keys are
+// skipped.  While a key encrypting key is being rotated, values may be encrypted with either
+// the new or the previous key, so both must be tried.
+func UnwrapAny(keyEncryptingKeys [][]byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+
+	var err error = derp.NewInternalError("keywrap.UnwrapAny", "No key encrypting key available")
+
+	for _, keyEncryptingKey := range keyEncryptingKeys {
+
+		if len(keyEncryptingKey) == 0 {
+			continue
+		}
+
+		var plaintext []byte
+
+		if plaintext, err = Unwrap(keyEncryptingKey, ciphertext, additionalData); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, derp.Wrap(err, "keywrap.UnwrapAny", "Error decrypting value with any key encrypting key")
+}
+
+// Rewrap decrypts a value with the old key encrypting key, then encrypts it again with the new one.
+func Rewrap(oldKeyEncryptingKey []byte, newKeyEncryptingKey []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+
+	plaintext, err := Unwrap(oldKeyEncryptingKey, ciphertext, additionalData)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "keywrap.Rewrap", "Error unwrapping value")
+	}
+
+	return Wrap(newKeyEncryptingKey, plaintext, additionalData)
+}
+
+// newAEAD returns an AES-GCM cipher for the provided key encrypting key.
+// Keys must be 16, 24, or 32 bytes long.
+func newAEAD(keyEncryptingKey []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(keyEncryptingKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,90 @@
+package keywrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+
+	kek := []byte("0123456789ABCDEF0123456789ABCDEF")
+	plaintext := []byte("this is a very secret key")
+	id := []byte("record-1")
+
+	wrapped, err := Wrap(kek, plaintext, id)
+	require.Nil(t, err)
+	require.NotContains(t, string(wrapped), string(plaintext))
+
+	// Each wrap uses a new nonce
+	wrapped2, err := Wrap(kek, plaintext, id)
+	require.Nil(t, err)
+	require.NotEqual(t, wrapped, wrapped2)
+
+	unwrapped, err := Unwrap(kek, wrapped, id)
+	require.Nil(t, err)
+	require.Equal(t, plaintext, unwrapped)
+}
+
+func TestUnwrap_Errors(t *testing.T) {
+
+	kek := []byte("0123456789ABCDEF0123456789ABCDEF")
+	wrapped, err := Wrap(kek, []byte("secret"), []byte("record-1"))
+	require.Nil(t, err)
+
+	// Wrong key encrypting key
+	_, err = Unwrap([]byte("ABCDEF0123456789ABCDEF0123456789"), wrapped, []byte("record-1"))
+	require.NotNil(t, err)
+
+	// Wrong additional data
+	_, err = Unwrap(kek, wrapped, []byte("record-2"))
+	require.NotNil(t, err)
+
+	// Truncated value
+	_, err = Unwrap(kek, wrapped[:4], []byte("record-1"))
+	require.NotNil(t, err)
+
+	// Invalid key encrypting key
+	_, err = Wrap([]byte("too short"), []byte("secret"), nil)
+	require.NotNil(t, err)
+}
+
+func TestRewrap(t *testing.T) {
+
+	oldKEK := []byte("0123456789ABCDEF0123456789ABCDEF")
+	newKEK := []byte("ABCDEF0123456789ABCDEF0123456789")
+
+	wrapped, err := Wrap(oldKEK, []byte("secret"), []byte("record-1"))
+	require.Nil(t, err)
+
+	rewrapped, err := Rewrap(oldKEK, newKEK, wrapped, []byte("record-1"))
+	require.Nil(t, err)
+
+	_, err = Unwrap(oldKEK, rewrapped, []byte("record-1"))
+	require.NotNil(t, err)
+
+	unwrapped, err := Unwrap(newKEK, rewrapped, []byte("record-1"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("secret"), unwrapped)
+}
+
+func TestUnwrapAny(t *testing.T) {
+
+	oldKEK := []byte("0123456789ABCDEF0123456789ABCDEF")
+	newKEK := []byte("ABCDEF0123456789ABCDEF0123456789")
+
+	wrapped, err := Wrap(oldKEK, []byte("secret"), []byte("record-1"))
+	require.Nil(t, err)
+
+	// Values can be decrypted with either key
+	unwrapped, err := UnwrapAny([][]byte{newKEK, oldKEK}, wrapped, []byte("record-1"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("secret"), unwrapped)
+
+	// Empty keys are skipped
+	_, err = UnwrapAny([][]byte{newKEK, nil}, wrapped, []byte("record-1"))
+	require.NotNil(t, err)
+
+	_, err = UnwrapAny(nil, wrapped, []byte("record-1"))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,160 @@
+package micropub
+
+import (
+	"strings"
+
+	"github.com/benpate/rosetta/convert"
+)
+
+// Properties is a set of microformats2 properties.  Every property is a list of values,
+// which may be strings or nested objects (such as {"html": "..."} content values).
+type Properties map[string][]any
+
+// NewProperties returns a fully initialized Properties map
+func NewProperties() Properties {
+	return make(Properties)
+}
+
+// Has returns TRUE if the named property exists and has at least one value
+func (properties Properties) Has(name string) bool {
+	return len(properties[name]) > 0
+}
+
+// String returns the first value of the named property, as a string.
+// Nested objects return their "value" or "url" (if present).
+func (properties Properties) String(name string) string {
+
+	for _, value := range properties[name] {
+		if result := stringValue(value); result != "" {
+			return result
+		}
+	}
+
+	return ""
+}
+
+// Strings returns all values of the named property, as strings
+func (properties Properties) Strings(name string) []string {
+
+	result := make([]string, 0, len(properties[name]))
+
+	for _, value := range properties[name] {
+		if stringValue := stringValue(value); stringValue != "" {
+			result = append(result, stringValue)
+		}
+	}
+
+	return result
+}
+
+// Content returns the "content" property, along with a flag that is TRUE
+// if the content was provided as HTML (as opposed to plain text)
+func (properties Properties) Content() (string, bool) {
+
+	for _, value := range properties["content"] {
+
+		switch typed := value.(type) {
+
+		case string:
+			return typed, false
+
+		case map[string]any:
+			if html := convert.String(typed["html"]); html != "" {
+				return html, true
+			}
+
+			if text := convert.String(typed["value"]); text != "" {
+				return text, false
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Set replaces all values of the named property
+func (properties Properties) Set(name string, values ...any) {
+
+	if len(values) == 0 {
+		delete(properties, name)
+		return
+	}
+
+	properties[name] = values
+}
+
+// Append adds values to the named property
+func (properties Properties) Append(name string, values ...any) {
+	properties[name] = append(properties[name], values...)
+}
+
+// Remove removes specific values from the named property.  If the property
+// is empty afterwards, then it is removed entirely.
+func (properties Properties) Remove(name string, values ...any) {
+
+	result := make([]any, 0, len(properties[name]))
+
+	for _, existing := range properties[name] {
+		if !containsValue(values, existing) {
+			result = append(result, existing)
+		}
+	}
+
+	properties.Set(name, result...)
+}
+
+// Update applies the "replace", "add", and "delete" operations
+// from a Micropub update request to this set of Properties.
+func (properties Properties) Update(request Request) {
+
+	for name, values := range request.Replace {
+		properties.Set(name, values...)
+	}
+
+	for name, values := range request.Add {
+		properties.Append(name, values...)
+	}
+
+	for name, values := range request.Delete {
+		if len(values) == 0 {
+			delete(properties, name)
+			continue
+		}
+		properties.Remove(name, values...)
+	}
+}
+
+// stringValue converts a single property value into a string
+func stringValue(value any) string {
+
+	switch typed := value.(type) {
+
+	case string:
+		return strings.TrimSpace(typed)
+
+	case map[string]any:
+		if result := convert.String(typed["value"]); result != "" {
+			return result
+		}
+
+		if result := convert.String(typed["url"]); result != "" {
+			return result
+		}
+
+		return convert.String(typed["html"])
+	}
+
+	return convert.String(value)
+}
+
+// containsValue returns TRUE if the value exists in the slice
+func containsValue(values []any, value any) bool {
+
+	for _, item := range values {
+		if stringValue(item) == stringValue(value) {
+			return true
+		}
+	}
+
+	return false
+}
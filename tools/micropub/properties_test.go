@@ -0,0 +1,38 @@
+package micropub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProperties_Update(t *testing.T) {
+
+	properties := NewProperties()
+	properties.Set("content", "Hello World")
+	properties.Set("category", "foo", "bar")
+	properties.Set("syndication", "https://example.com/1")
+
+	request := NewRequest()
+	request.Replace.Set("content", "Goodbye World")
+	request.Add.Set("category", "baz")
+	request.Delete.Set("category", "foo")
+	request.Delete["syndication"] = []any{}
+
+	properties.Update(request)
+
+	content, _ := properties.Content()
+	require.Equal(t, "Goodbye World", content)
+	require.Equal(t, []string{"bar", "baz"}, properties.Strings("category"))
+	require.False(t, properties.Has("syndication"))
+}
+
+func TestProperties_Content_HTML(t *testing.T) {
+
+	properties := NewProperties()
+	properties.Set("content", map[string]any{"html": "<b>Hello</b>", "value": "Hello"})
+
+	content, isHTML := properties.Content()
+	require.Equal(t, "<b>Hello</b>", content)
+	require.True(t, isHTML)
+}
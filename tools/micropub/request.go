@@ -0,0 +1,197 @@
+// Package micropub parses requests from Micropub clients into a common structure,
+// regardless of whether they were sent as form data, multipart forms, or JSON.
+//
+// https://www.w3.org/TR/micropub/
+package micropub
+
+import (
+	"encoding/json"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// ActionCreate creates a new post
+const ActionCreate = "create"
+
+// ActionUpdate updates an existing post
+const ActionUpdate = "update"
+
+// ActionDelete deletes an existing post
+const ActionDelete = "delete"
+
+// ActionUndelete restores a previously deleted post
+const ActionUndelete = "undelete"
+
+// maxMemory is the maximum number of bytes of a multipart form that are kept in memory
+const maxMemory = 32 << 20
+
+// Request is a parsed Micropub request
+type Request struct {
+	Action      string                             // Action to perform (create, update, delete, undelete)
+	URL         string                             // URL of the post to update/delete/undelete
+	Type        string                             // Microformats type of the post to create (e.g. "h-entry")
+	AccessToken string                             // Access token included in the request body (if any)
+	Properties  Properties                         // Properties of the post to create
+	Replace     Properties                         // Properties to replace (update only)
+	Add         Properties                         // Properties to add (update only)
+	Delete      Properties                         // Properties (or values) to remove (update only)
+	Files       map[string][]*multipart.FileHeader // Files uploaded with a multipart request (photo, video, audio)
+}
+
+// NewRequest returns a fully initialized Request
+func NewRequest() Request {
+	return Request{
+		Action:     ActionCreate,
+		Type:       "h-entry",
+		Properties: NewProperties(),
+		Replace:    NewProperties(),
+		Add:        NewProperties(),
+		Delete:     NewProperties(),
+		Files:      make(map[string][]*multipart.FileHeader),
+	}
+}
+
+// Parse reads a Micropub request from an HTTP request body
+func Parse(request *http.Request) (Request, error) {
+
+	const location = "micropub.Parse"
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+
+	switch mediaType {
+
+	case "application/json":
+		return parseJSON(request)
+
+	case "multipart/form-data":
+		if err := request.ParseMultipartForm(maxMemory); err != nil {
+			return Request{}, derp.Wrap(err, location, "Error parsing multipart form", derp.WithBadRequest())
+		}
+
+		result := parseForm(request)
+
+		for name, files := range request.MultipartForm.File {
+			result.Files[strings.TrimSuffix(name, "[]")] = files
+		}
+
+		return result, nil
+
+	default:
+		if err := request.ParseForm(); err != nil {
+			return Request{}, derp.Wrap(err, location, "Error parsing form", derp.WithBadRequest())
+		}
+
+		return parseForm(request), nil
+	}
+}
+
+// parseForm reads a form-encoded Micropub request
+func parseForm(request *http.Request) Request {
+
+	result := NewRequest()
+
+	for name, values := range request.PostForm {
+
+		switch name {
+
+		case "h":
+			result.Type = "h-" + first(values)
+
+		case "action":
+			result.Action = first(values)
+
+		case "url":
+			result.URL = first(values)
+
+		case "access_token":
+			result.AccessToken = first(values)
+
+		default:
+			name = strings.TrimSuffix(name, "[]")
+			for _, value := range values {
+				result.Properties.Append(name, value)
+			}
+		}
+	}
+
+	return result
+}
+
+// parseJSON reads a JSON-encoded Micropub request
+func parseJSON(request *http.Request) (Request, error) {
+
+	const location = "micropub.parseJSON"
+
+	body := struct {
+		Type       []string       `json:"type"`
+		Action     string         `json:"action"`
+		URL        string         `json:"url"`
+		Properties map[string]any `json:"properties"`
+		Replace    map[string]any `json:"replace"`
+		Add        map[string]any `json:"add"`
+		Delete     any            `json:"delete"`
+	}{}
+
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		return Request{}, derp.Wrap(err, location, "Error parsing JSON", derp.WithBadRequest())
+	}
+
+	result := NewRequest()
+	result.URL = body.URL
+	result.Properties = jsonProperties(body.Properties)
+	result.Replace = jsonProperties(body.Replace)
+	result.Add = jsonProperties(body.Add)
+
+	if body.Action != "" {
+		result.Action = body.Action
+	}
+
+	if len(body.Type) > 0 {
+		result.Type = body.Type[0]
+	}
+
+	// "delete" can be a list of property names, or a map of values to remove
+	switch typed := body.Delete.(type) {
+
+	case []any:
+		for _, name := range typed {
+			if name, ok := name.(string); ok {
+				result.Delete[name] = []any{}
+			}
+		}
+
+	case map[string]any:
+		result.Delete = jsonProperties(typed)
+	}
+
+	return result, nil
+}
+
+// jsonProperties converts a JSON object into a Properties map, making
+// sure that every value is a slice (as required by microformats2)
+func jsonProperties(value map[string]any) Properties {
+
+	result := NewProperties()
+
+	for name, item := range value {
+		if slice, ok := item.([]any); ok {
+			result[name] = slice
+		} else {
+			result[name] = []any{item}
+		}
+	}
+
+	return result
+}
+
+// first returns the first value in a slice of strings
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
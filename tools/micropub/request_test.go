@@ -0,0 +1,125 @@
+package micropub
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Form(t *testing.T) {
+
+	form := url.Values{}
+	form.Set("h", "entry")
+	form.Set("content", "Hello World")
+	form.Add("category[]", "foo")
+	form.Add("category[]", "bar")
+	form.Set("mp-slug", "hello")
+	form.Set("access_token", "TOKEN")
+
+	request, _ := http.NewRequest(http.MethodPost, "/.micropub", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	result, err := Parse(request)
+	require.Nil(t, err)
+	require.Equal(t, ActionCreate, result.Action)
+	require.Equal(t, "h-entry", result.Type)
+	require.Equal(t, "TOKEN", result.AccessToken)
+	require.Equal(t, "hello", result.Properties.String("mp-slug"))
+	require.Equal(t, []string{"foo", "bar"}, result.Properties.Strings("category"))
+
+	content, isHTML := result.Properties.Content()
+	require.Equal(t, "Hello World", content)
+	require.False(t, isHTML)
+}
+
+func TestParse_JSON(t *testing.T) {
+
+	body := `{
+		"type": ["h-entry"],
+		"properties": {
+			"name": ["My Article"],
+			"content": [{"html": "<p>Hello <b>World</b></p>"}],
+			"photo": [{"value": "https://photos.example.com/1.jpg", "alt": "A photo"}],
+			"post-status": "draft"
+		}
+	}`
+
+	request, _ := http.NewRequest(http.MethodPost, "/.micropub", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	result, err := Parse(request)
+	require.Nil(t, err)
+	require.Equal(t, ActionCreate, result.Action)
+	require.Equal(t, "My Article", result.Properties.String("name"))
+	require.Equal(t, "https://photos.example.com/1.jpg", result.Properties.String("photo"))
+	require.Equal(t, "draft", result.Properties.String("post-status"))
+
+	content, isHTML := result.Properties.Content()
+	require.Equal(t, "<p>Hello <b>World</b></p>", content)
+	require.True(t, isHTML)
+}
+
+func TestParse_JSON_Update(t *testing.T) {
+
+	body := `{
+		"action": "update",
+		"url": "https://example.com/post",
+		"replace": {"content": ["New Content"]},
+		"add": {"category": ["new"]},
+		"delete": {"category": ["old"]}
+	}`
+
+	request, _ := http.NewRequest(http.MethodPost, "/.micropub", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	result, err := Parse(request)
+	require.Nil(t, err)
+	require.Equal(t, ActionUpdate, result.Action)
+	require.Equal(t, "https://example.com/post", result.URL)
+
+	properties := Properties{
+		"content":  []any{"Old Content"},
+		"category": []any{"old", "keep"},
+		"name":     []any{"Name"},
+	}
+
+	properties.Update(result)
+	require.Equal(t, "New Content", properties.String("content"))
+	require.Equal(t, []string{"keep", "new"}, properties.Strings("category"))
+	require.Equal(t, "Name", properties.String("name"))
+}
+
+func TestParse_JSON_DeleteProperties(t *testing.T) {
+
+	body := `{"action": "update", "url": "https://example.com/post", "delete": ["category"]}`
+
+	request, _ := http.NewRequest(http.MethodPost, "/.micropub", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	result, err := Parse(request)
+	require.Nil(t, err)
+
+	properties := Properties{"category": []any{"foo", "bar"}, "name": []any{"Name"}}
+	properties.Update(result)
+
+	require.False(t, properties.Has("category"))
+	require.True(t, properties.Has("name"))
+}
+
+func TestParse_FormDelete(t *testing.T) {
+
+	form := url.Values{}
+	form.Set("action", "delete")
+	form.Set("url", "https://example.com/post")
+
+	request, _ := http.NewRequest(http.MethodPost, "/.micropub", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	result, err := Parse(request)
+	require.Nil(t, err)
+	require.Equal(t, ActionDelete, result.Action)
+	require.Equal(t, "https://example.com/post", result.URL)
+}
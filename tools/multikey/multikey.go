@@ -0,0 +1,112 @@
+// Package multikey encodes public keys in the "Multikey" format used by
+// FEP-521a (https://codeberg.org/fediverse/fep/src/branch/main/fep/521a/fep-521a.md).
+// Keys are prefixed with their multicodec header, then encoded as base58btc
+// multibase strings (which always begin with the letter "z").
+package multikey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// Type is the JSON-LD type of a Multikey document
+const Type = "Multikey"
+
+// Context is the JSON-LD context that defines the Multikey vocabulary
+const Context = "https://w3id.org/security/multikey/v1"
+
+// ed25519Header is the multicodec prefix for Ed25519 public keys (0xed, as an unsigned varint)
+var ed25519Header = []byte{0xed, 0x01}
+
+// base58Alphabet is the Bitcoin base58 alphabet used by multibase "z" strings
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeEd25519 returns the publicKeyMultibase value for an Ed25519 public key
+func EncodeEd25519(publicKey ed25519.PublicKey) string {
+	return "z" + encodeBase58(append(bytes.Clone(ed25519Header), publicKey...))
+}
+
+// DecodeEd25519 parses a publicKeyMultibase value into an Ed25519 public key
+func DecodeEd25519(value string) (ed25519.PublicKey, error) {
+
+	const location = "multikey.DecodeEd25519"
+
+	if !strings.HasPrefix(value, "z") {
+		return nil, derp.NewBadRequestError(location, "Multibase value must be base58btc encoded", value)
+	}
+
+	decoded, err := decodeBase58(value[1:])
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error decoding multibase value", value)
+	}
+
+	if !bytes.HasPrefix(decoded, ed25519Header) {
+		return nil, derp.NewBadRequestError(location, "Multikey is not an Ed25519 public key", value)
+	}
+
+	publicKey := decoded[len(ed25519Header):]
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, derp.NewBadRequestError(location, "Ed25519 public key has the wrong length", value)
+	}
+
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// encodeBase58 encodes a byte slice using the base58btc alphabet
+func encodeBase58(value []byte) string {
+
+	number := new(big.Int).SetBytes(value)
+	radix := big.NewInt(58)
+	remainder := new(big.Int)
+	result := make([]byte, 0, len(value)*138/100+1)
+
+	for number.Sign() > 0 {
+		number.DivMod(number, radix, remainder)
+		result = append(result, base58Alphabet[remainder.Int64()])
+	}
+
+	// Leading zero bytes are encoded as leading "1" characters
+	for _, b := range value {
+		if b != 0 {
+			break
+		}
+		result = append(result, base58Alphabet[0])
+	}
+
+	// Digits were calculated in reverse order
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
+
+// decodeBase58 decodes a base58btc string into a byte slice
+func decodeBase58(value string) ([]byte, error) {
+
+	number := new(big.Int)
+	radix := big.NewInt(58)
+
+	for _, r := range value {
+
+		digit := strings.IndexRune(base58Alphabet, r)
+
+		if digit < 0 {
+			return nil, derp.NewBadRequestError("multikey.decodeBase58", "Invalid base58 character", string(r))
+		}
+
+		number.Mul(number, radix)
+		number.Add(number, big.NewInt(int64(digit)))
+	}
+
+	// Leading "1" characters are decoded as leading zero bytes
+	leadingZeros := len(value) - len(strings.TrimLeft(value, base58Alphabet[:1]))
+
+	return append(make([]byte, leadingZeros), number.Bytes()...), nil
+}
@@ -0,0 +1,55 @@
+package multikey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase58(t *testing.T) {
+
+	test := func(value []byte, expected string) {
+		require.Equal(t, expected, encodeBase58(value))
+
+		decoded, err := decodeBase58(expected)
+		require.Nil(t, err)
+		require.Equal(t, value, decoded)
+	}
+
+	test([]byte("Hello World!"), "2NEpo7TZRRrLZSi2U")
+	test([]byte("The quick brown fox jumps over the lazy dog."), "USm3fpXnKG5EUBx2ndxBDMPVciP5hGey2Jh4NDv6gmeo1LkMeiKrLJUUBk6Z")
+	test([]byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}, "11233QC4")
+	test([]byte{}, "")
+}
+
+func TestEd25519(t *testing.T) {
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	// Ed25519 multikeys always begin with "z6Mk"
+	encoded := EncodeEd25519(publicKey)
+	require.True(t, strings.HasPrefix(encoded, "z6Mk"))
+
+	decoded, err := DecodeEd25519(encoded)
+	require.Nil(t, err)
+	require.Equal(t, publicKey, decoded)
+}
+
+func TestDecodeEd25519_Errors(t *testing.T) {
+
+	// Wrong multibase encoding
+	_, err := DecodeEd25519("m7QEAAQ")
+	require.NotNil(t, err)
+
+	// Invalid base58 characters
+	_, err = DecodeEd25519("z0OIl")
+	require.NotNil(t, err)
+
+	// Not an Ed25519 key
+	_, err = DecodeEd25519("z" + encodeBase58([]byte{0x12, 0x00, 0x01}))
+	require.NotNil(t, err)
+}
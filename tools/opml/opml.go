@@ -0,0 +1,120 @@
+// Package opml reads and writes OPML documents, which feed readers use to exchange subscription lists.
+// http://opml.org/spec2.opml
+package opml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/benpate/derp"
+	"golang.org/x/net/html/charset"
+)
+
+// OPML is the root element of an OPML document
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head contains the metadata of an OPML document
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+	OwnerName   string `xml:"ownerName,omitempty"`
+}
+
+// Body contains the top-level outlines of an OPML document
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is a single node in an OPML document.  Outlines with an XMLURL are feed
+// subscriptions, and outlines with children are categories (folders) of subscriptions.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// New returns a fully initialized OPML 2.0 document
+func New(title string) OPML {
+	return OPML{
+		Version: "2.0",
+		Head: Head{
+			Title: title,
+		},
+		Body: Body{
+			Outlines: make([]Outline, 0),
+		},
+	}
+}
+
+// Parse reads an OPML document
+func Parse(reader io.Reader) (OPML, error) {
+
+	result := OPML{}
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&result); err != nil {
+		return OPML{}, derp.NewBadRequestError("opml.Parse", "Error parsing OPML document", err.Error())
+	}
+
+	return result, nil
+}
+
+// Write writes an OPML document, including the XML header
+func (document OPML) Write(writer io.Writer) error {
+
+	const location = "opml.Write"
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return derp.Wrap(err, location, "Error writing XML header")
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "\t")
+
+	if err := encoder.Encode(document); err != nil {
+		return derp.Wrap(err, location, "Error writing OPML document")
+	}
+
+	return nil
+}
+
+// IsFeed returns TRUE if this outline is a feed subscription
+func (outline Outline) IsFeed() bool {
+	return strings.TrimSpace(outline.XMLURL) != ""
+}
+
+// Feeds returns this outline (if it is a feed) and every feed nested inside of it
+func (outline Outline) Feeds() []Outline {
+
+	result := make([]Outline, 0)
+
+	if outline.IsFeed() {
+		result = append(result, outline)
+	}
+
+	for _, child := range outline.Outlines {
+		result = append(result, child.Feeds()...)
+	}
+
+	return result
+}
+
+// Label returns the best available name for this outline
+func (outline Outline) Label() string {
+
+	if text := strings.TrimSpace(outline.Text); text != "" {
+		return text
+	}
+
+	return strings.TrimSpace(outline.Title)
+}
@@ -0,0 +1,93 @@
+package opml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+		<opml version="1.0">
+			<head><title>Subscriptions</title></head>
+			<body>
+				<outline text="News" title="News">
+					<outline type="rss" text="Example News" xmlUrl="https://example.com/feed.xml" htmlUrl="https://example.com/"/>
+				</outline>
+				<outline type="rss" title="Untitled Text" xmlUrl="https://other.com/rss"/>
+			</body>
+		</opml>`
+
+	document, err := Parse(strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, "Subscriptions", document.Head.Title)
+	require.Equal(t, 2, len(document.Body.Outlines))
+
+	category := document.Body.Outlines[0]
+	require.False(t, category.IsFeed())
+	require.Equal(t, "News", category.Label())
+	require.Equal(t, 1, len(category.Outlines))
+	require.True(t, category.Outlines[0].IsFeed())
+	require.Equal(t, "https://example.com/feed.xml", category.Outlines[0].XMLURL)
+
+	// Falls back to the title attribute
+	require.Equal(t, "Untitled Text", document.Body.Outlines[1].Label())
+}
+
+func TestFeeds(t *testing.T) {
+
+	outline := Outline{
+		Text: "Tech",
+		Outlines: []Outline{
+			{Text: "One", XMLURL: "https://one.com/feed"},
+			{Text: "Programming", Outlines: []Outline{
+				{Text: "Two", XMLURL: "https://two.com/feed"},
+			}},
+		},
+	}
+
+	feeds := outline.Feeds()
+	require.Equal(t, 2, len(feeds))
+	require.Equal(t, "One", feeds[0].Label())
+	require.Equal(t, "Two", feeds[1].Label())
+}
+
+func TestParse_Charset(t *testing.T) {
+
+	body := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><opml version=\"2.0\"><body><outline text=\"Caf\xe9\" xmlUrl=\"https://example.com/feed\"/></body></opml>"
+
+	document, err := Parse(strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, "Café", document.Body.Outlines[0].Label())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("this is not OPML"))
+	require.NotNil(t, err)
+}
+
+func TestWrite(t *testing.T) {
+
+	document := New("My Subscriptions")
+	document.Body.Outlines = append(document.Body.Outlines, Outline{
+		Text: "News & Views",
+		Outlines: []Outline{
+			{Type: "rss", Text: "Example", Title: "Example", XMLURL: "https://example.com/feed?a=1&b=2"},
+		},
+	})
+
+	var buffer bytes.Buffer
+	require.Nil(t, document.Write(&buffer))
+	require.True(t, strings.HasPrefix(buffer.String(), `<?xml version="1.0" encoding="UTF-8"?>`))
+
+	// Written documents can be read back in
+	result, err := Parse(&buffer)
+	require.Nil(t, err)
+	require.Equal(t, "2.0", result.Version)
+	require.Equal(t, "My Subscriptions", result.Head.Title)
+	require.Equal(t, "News & Views", result.Body.Outlines[0].Label())
+	require.Equal(t, "https://example.com/feed?a=1&b=2", result.Body.Outlines[0].Outlines[0].XMLURL)
+}
@@ -0,0 +1,60 @@
+// Package qrcode generates QR Code images, which are used to share stream URLs
+// and to enroll authenticator apps for two-factor authentication.
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+
+	"github.com/benpate/derp"
+	"github.com/yeqown/go-qrcode/v2"
+	"github.com/yeqown/go-qrcode/writer/standard"
+)
+
+// MimeType is the Content-Type of the images generated by this package
+const MimeType = "image/jpeg"
+
+// Write writes a QR Code image that encodes the provided content
+func Write(writer io.Writer, content string) error {
+
+	const location = "qrcode.Write"
+
+	qrc, err := qrcode.New(content)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error generating QR Code")
+	}
+
+	w := standard.NewWithWriter(writeCloser{writer})
+
+	// "save" file to the writer
+	if err := qrc.Save(w); err != nil {
+		return derp.Wrap(err, location, "Error writing image")
+	}
+
+	return nil
+}
+
+// DataURL returns a "data:" URL that embeds a QR Code image of the provided content
+// directly into an HTML page.
+func DataURL(content string) (string, error) {
+
+	var buffer bytes.Buffer
+
+	if err := Write(&buffer, content); err != nil {
+		return "", derp.Wrap(err, "qrcode.DataURL", "Error generating QR Code")
+	}
+
+	return "data:" + MimeType + ";base64," + base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// writeCloser is a hacky kludge that lets us use an io.Writer as an io.WriteCloser
+type writeCloser struct {
+	io.Writer
+}
+
+func (writeCloser writeCloser) Close() error {
+	// no op
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package ratelimit counts requests in fixed windows of time, so that clients which make
+// too many requests can be turned away until their window resets.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter counts the requests made by each client (identified by a key) during the current window.
+// It is safe for concurrent use.
+type Limiter struct {
+	window      time.Duration
+	maxBuckets  int
+	buckets     map[string]*bucket
+	nextCleanup time.Time
+	mutex       sync.Mutex
+}
+
+// bucket counts the requests made by a single client during the current window
+type bucket struct {
+	count int
+	reset time.Time
+}
+
+// Result describes the state of a client's bucket after a request is counted
+type Result struct {
+	Allowed   bool      // TRUE if the request is within the limit
+	Limit     int       // Maximum number of requests allowed in each window
+	Remaining int       // Number of requests remaining in the current window
+	Reset     time.Time // Time when the current window ends
+}
+
+// New returns a fully initialized Limiter that counts requests over the provided window.
+// No more than maxBuckets clients are counted at the same time.
+func New(window time.Duration, maxBuckets int) *Limiter {
+	return &Limiter{
+		window:     window,
+		maxBuckets: maxBuckets,
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// Allow counts a request for the provided key, and reports whether it is within the limit
+func (limiter *Limiter) Allow(key string, limit int) Result {
+	return limiter.allowAt(key, limit, time.Now())
+}
+
+func (limiter *Limiter) allowAt(key string, limit int, now time.Time) Result {
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.cleanup(now)
+
+	// Start a new window if this client has none, or if its last window has ended
+	current, ok := limiter.buckets[key]
+
+	// RULE: When every bucket is in use, new clients are turned away until the next cleanup
+	if !ok && (len(limiter.buckets) >= limiter.maxBuckets) {
+		return Result{
+			Allowed: false,
+			Limit:   limit,
+			Reset:   limiter.nextCleanup,
+		}
+	}
+
+	if !ok || !now.Before(current.reset) {
+		current = &bucket{reset: now.Add(limiter.window)}
+		limiter.buckets[key] = current
+	}
+
+	current.count++
+
+	return Result{
+		Allowed:   current.count <= limit,
+		Limit:     limit,
+		Remaining: max(limit-current.count, 0),
+		Reset:     current.reset,
+	}
+}
+
+// cleanup removes buckets whose windows have ended, so that memory does not grow forever.
+// It runs at most once per window.
+func (limiter *Limiter) cleanup(now time.Time) {
+
+	if now.Before(limiter.nextCleanup) {
+		return
+	}
+
+	for key, current := range limiter.buckets {
+		if !now.Before(current.reset) {
+			delete(limiter.buckets, key)
+		}
+	}
+
+	limiter.nextCleanup = now.Add(limiter.window)
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+
+	limiter := New(time.Minute, 100)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Requests are allowed until the limit is reached
+	for index := 1; index <= 3; index++ {
+		result := limiter.allowAt("alice", 3, now)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3-index, result.Remaining)
+		require.Equal(t, now.Add(time.Minute), result.Reset)
+	}
+
+	result := limiter.allowAt("alice", 3, now.Add(30*time.Second))
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+
+	// Other clients have their own buckets
+	require.True(t, limiter.allowAt("bob", 3, now).Allowed)
+
+	// A new window starts when the last one ends
+	result = limiter.allowAt("alice", 3, now.Add(time.Minute))
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Remaining)
+	require.Equal(t, now.Add(2*time.Minute), result.Reset)
+}
+
+func TestLimiter_Cleanup(t *testing.T) {
+
+	limiter := New(time.Minute, 100)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	limiter.allowAt("alice", 3, now)
+	limiter.allowAt("bob", 3, now.Add(30*time.Second))
+	require.Equal(t, 2, len(limiter.buckets))
+
+	// Expired buckets are removed once per window
+	limiter.allowAt("carol", 3, now.Add(time.Minute+time.Second))
+	require.Equal(t, 2, len(limiter.buckets))
+	require.NotContains(t, limiter.buckets, "alice")
+}
+
+func TestLimiter_MaxBuckets(t *testing.T) {
+
+	limiter := New(time.Minute, 2)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.True(t, limiter.allowAt("alice", 3, now).Allowed)
+	require.True(t, limiter.allowAt("bob", 3, now).Allowed)
+
+	// New clients are turned away when every bucket is in use
+	result := limiter.allowAt("carol", 3, now.Add(time.Second))
+	require.False(t, result.Allowed)
+	require.Equal(t, now.Add(time.Minute), result.Reset)
+	require.Equal(t, 2, len(limiter.buckets))
+
+	// Existing clients are still counted
+	require.True(t, limiter.allowAt("alice", 3, now.Add(time.Second)).Allowed)
+
+	// New clients are accepted again once expired buckets are removed
+	require.True(t, limiter.allowAt("carol", 3, now.Add(time.Minute)).Allowed)
+}
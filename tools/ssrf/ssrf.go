@@ -0,0 +1,137 @@
+// Package ssrf protects outbound requests to user-supplied URLs from
+// reaching loopback, private, or link-local addresses on the server's network.
+package ssrf
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is not public
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// client is an HTTP client that refuses to connect to non-public addresses,
+// including addresses that are reached by following a redirect.
+var client = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: control,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+// IsPublicIP returns TRUE if the IP address is routable on the public internet
+func IsPublicIP(ip net.IP) bool {
+
+	if ip == nil {
+		return false
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() {
+		return false
+	}
+
+	if ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	if sharedAddressSpace.Contains(ip) {
+		return false
+	}
+
+	return true
+}
+
+// ValidateURL returns an error if the URL does not use http(s), or if its
+// hostname resolves to any address that is not public.
+func ValidateURL(value string) error {
+
+	const location = "ssrf.ValidateURL"
+
+	parsed, err := url.Parse(value)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid URL", value, derp.WithBadRequest())
+	}
+
+	if (parsed.Scheme != "https") && (parsed.Scheme != "http") {
+		return derp.NewBadRequestError(location, "URL must use http or https", value)
+	}
+
+	hostname := parsed.Hostname()
+
+	if hostname == "" {
+		return derp.NewBadRequestError(location, "URL must include a hostname", value)
+	}
+
+	if strings.EqualFold(hostname, "localhost") {
+		return derp.NewForbiddenError(location, "URL must not point to a private address", value)
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(context.Background(), hostname)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to resolve hostname", hostname, derp.WithBadRequest())
+	}
+
+	for _, address := range addresses {
+		if !IsPublicIP(address.IP) {
+			return derp.NewForbiddenError(location, "URL must not point to a private address", value)
+		}
+	}
+
+	return nil
+}
+
+// Option is a remote.Option that sends requests through an HTTP client that only
+// connects to public addresses.  Requests to other addresses fail with a 403 status.
+func Option() remote.Option {
+
+	return remote.Option{
+		ModifyRequest: func(_ *remote.Transaction, request *http.Request) *http.Response {
+
+			response, err := client.Do(request)
+
+			if err != nil {
+				return &http.Response{
+					Status:     http.StatusText(http.StatusForbidden),
+					StatusCode: http.StatusForbidden,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    request,
+				}
+			}
+
+			return response
+		},
+	}
+}
+
+// control refuses to open connections to non-public addresses.  It is called after
+// DNS resolution, so it cannot be bypassed by hostnames that change their addresses.
+func control(network string, address string, _ syscall.RawConn) error {
+
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return derp.Wrap(err, "ssrf.control", "Invalid address", address)
+	}
+
+	if !IsPublicIP(net.ParseIP(host)) {
+		return derp.NewForbiddenError("ssrf.control", "Connection to private address refused", address)
+	}
+
+	return nil
+}
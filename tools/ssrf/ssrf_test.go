@@ -0,0 +1,59 @@
+package ssrf
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benpate/remote"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+
+	require.True(t, IsPublicIP(net.ParseIP("93.184.216.34")))
+	require.True(t, IsPublicIP(net.ParseIP("2606:2800:220:1:248:1893:25c8:1946")))
+
+	require.False(t, IsPublicIP(nil))
+	require.False(t, IsPublicIP(net.ParseIP("127.0.0.1")))
+	require.False(t, IsPublicIP(net.ParseIP("::1")))
+	require.False(t, IsPublicIP(net.ParseIP("10.1.2.3")))
+	require.False(t, IsPublicIP(net.ParseIP("172.16.0.1")))
+	require.False(t, IsPublicIP(net.ParseIP("192.168.1.1")))
+	require.False(t, IsPublicIP(net.ParseIP("169.254.169.254")))
+	require.False(t, IsPublicIP(net.ParseIP("100.64.0.1")))
+	require.False(t, IsPublicIP(net.ParseIP("0.0.0.0")))
+	require.False(t, IsPublicIP(net.ParseIP("fd00::1")))
+	require.False(t, IsPublicIP(net.ParseIP("fe80::1")))
+}
+
+func TestValidateURL(t *testing.T) {
+
+	require.Nil(t, ValidateURL("https://93.184.216.34/path"))
+
+	require.NotNil(t, ValidateURL("ftp://93.184.216.34/"))
+	require.NotNil(t, ValidateURL("https:///path"))
+	require.NotNil(t, ValidateURL("http://localhost:8080/"))
+	require.NotNil(t, ValidateURL("http://127.0.0.1/"))
+	require.NotNil(t, ValidateURL("http://[::1]/"))
+	require.NotNil(t, ValidateURL("http://169.254.169.254/latest/meta-data"))
+}
+
+func TestOption(t *testing.T) {
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The test server runs on a loopback address, so it must not be reached
+	err := remote.Get(server.URL).Use(Option()).Send()
+	require.NotNil(t, err)
+	require.False(t, called)
+
+	// Without the option, the same request succeeds
+	require.Nil(t, remote.Get(server.URL).Send())
+	require.True(t, called)
+}
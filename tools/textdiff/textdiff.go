@@ -0,0 +1,158 @@
+// Package textdiff calculates word-by-word differences between two strings,
+// which is used to display the changes between revisions of a document.
+package textdiff
+
+import (
+	"html"
+	"strings"
+)
+
+// Operation identifies how a word has changed between two strings
+type Operation int
+
+// OperationEqual means that the word exists in both strings
+const OperationEqual Operation = 0
+
+// OperationInsert means that the word only exists in the "after" string
+const OperationInsert Operation = 1
+
+// OperationDelete means that the word only exists in the "before" string
+const OperationDelete Operation = -1
+
+// Change represents a run of words that share the same Operation
+type Change struct {
+	Operation Operation
+	Text      string
+}
+
+// MaxCells is the largest table (words before x words after) that Words will
+// compare in detail.  Longer changes are reported as a single deletion followed
+// by a single insertion, so that large documents cannot exhaust memory or CPU.
+const MaxCells = 1 << 20
+
+// Words returns the word-by-word differences between two strings,
+// using the longest common subsequence of words in each.
+func Words(before string, after string) []Change {
+
+	a := strings.Fields(before)
+	b := strings.Fields(after)
+	result := make([]Change, 0)
+
+	// Words that are shared at the beginning and end of both strings do not need to be compared
+	prefix := 0
+	for (prefix < len(a)) && (prefix < len(b)) && (a[prefix] == b[prefix]) {
+		prefix++
+	}
+
+	suffix := 0
+	for (suffix < len(a)-prefix) && (suffix < len(b)-prefix) && (a[len(a)-1-suffix] == b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	for _, word := range a[:prefix] {
+		result = appendChange(result, OperationEqual, word)
+	}
+
+	result = compare(result, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+
+	for _, word := range a[len(a)-suffix:] {
+		result = appendChange(result, OperationEqual, word)
+	}
+
+	return result
+}
+
+// compare appends the differences between two slices of words to the list of changes.
+func compare(result []Change, a []string, b []string) []Change {
+
+	// Changes that are too large to compare in detail are replaced wholesale
+	if len(a)*len(b) > MaxCells {
+		for _, word := range a {
+			result = appendChange(result, OperationDelete, word)
+		}
+		for _, word := range b {
+			result = appendChange(result, OperationInsert, word)
+		}
+		return result
+	}
+
+	// Calculate the length of the longest common subsequence for every suffix
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk the table to collect changes
+	i, j := 0, 0
+
+	for (i < len(a)) || (j < len(b)) {
+		switch {
+
+		case (i < len(a)) && (j < len(b)) && (a[i] == b[j]):
+			result = appendChange(result, OperationEqual, a[i])
+			i++
+			j++
+
+		// Deletions come before insertions when both are possible
+		case (i < len(a)) && ((j == len(b)) || (lcs[i+1][j] >= lcs[i][j+1])):
+			result = appendChange(result, OperationDelete, a[i])
+			i++
+
+		default:
+			result = appendChange(result, OperationInsert, b[j])
+			j++
+		}
+	}
+
+	return result
+}
+
+// HTML returns the differences between two strings as an HTML fragment,
+// with deleted words wrapped in <del> tags and inserted words wrapped in <ins> tags.
+// The provided strings are treated as plain text, and are escaped in the result.
+func HTML(before string, after string) string {
+
+	var result strings.Builder
+
+	for index, change := range Words(before, after) {
+
+		if index > 0 {
+			result.WriteString(" ")
+		}
+
+		text := html.EscapeString(change.Text)
+
+		switch change.Operation {
+		case OperationInsert:
+			result.WriteString("<ins>" + text + "</ins>")
+		case OperationDelete:
+			result.WriteString("<del>" + text + "</del>")
+		default:
+			result.WriteString(text)
+		}
+	}
+
+	return result.String()
+}
+
+// appendChange adds a word to the list of changes, combining it with the
+// previous change if they share the same Operation.
+func appendChange(changes []Change, operation Operation, word string) []Change {
+
+	if last := len(changes) - 1; (last >= 0) && (changes[last].Operation == operation) {
+		changes[last].Text += " " + word
+		return changes
+	}
+
+	return append(changes, Change{Operation: operation, Text: word})
+}
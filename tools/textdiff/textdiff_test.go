@@ -0,0 +1,67 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWords_Equal(t *testing.T) {
+	result := Words("the quick brown fox", "the quick brown fox")
+	require.Equal(t, []Change{{OperationEqual, "the quick brown fox"}}, result)
+}
+
+func TestWords_Insert(t *testing.T) {
+	result := Words("the brown fox", "the quick brown fox")
+	require.Equal(t, []Change{
+		{OperationEqual, "the"},
+		{OperationInsert, "quick"},
+		{OperationEqual, "brown fox"},
+	}, result)
+}
+
+func TestWords_Delete(t *testing.T) {
+	result := Words("the quick brown fox", "the fox")
+	require.Equal(t, []Change{
+		{OperationEqual, "the"},
+		{OperationDelete, "quick brown"},
+		{OperationEqual, "fox"},
+	}, result)
+}
+
+func TestWords_Replace(t *testing.T) {
+	result := Words("the quick brown fox", "the slow brown fox")
+	require.Equal(t, []Change{
+		{OperationEqual, "the"},
+		{OperationDelete, "quick"},
+		{OperationInsert, "slow"},
+		{OperationEqual, "brown fox"},
+	}, result)
+}
+
+func TestWords_Empty(t *testing.T) {
+	require.Equal(t, []Change{}, Words("", ""))
+	require.Equal(t, []Change{{OperationInsert, "hello world"}}, Words("", "hello world"))
+	require.Equal(t, []Change{{OperationDelete, "hello world"}}, Words("hello world", ""))
+}
+
+func TestHTML(t *testing.T) {
+	result := HTML("fish & chips", "fish & <b>chips</b>")
+	require.Equal(t, "fish &amp; <del>chips</del> <ins>&lt;b&gt;chips&lt;/b&gt;</ins>", result)
+}
+
+func TestWords_Large(t *testing.T) {
+
+	before := strings.Repeat("alpha beta ", 2000)
+	after := strings.Repeat("gamma delta ", 2000)
+
+	// Changes that are too large to compare in detail are replaced wholesale,
+	// but words shared at the beginning and end are still reported as equal
+	result := Words("start "+before+"end", "start "+after+"end")
+	require.Equal(t, 4, len(result))
+	require.Equal(t, Change{OperationEqual, "start"}, result[0])
+	require.Equal(t, OperationDelete, result[1].Operation)
+	require.Equal(t, OperationInsert, result[2].Operation)
+	require.Equal(t, Change{OperationEqual, "end"}, result[3])
+}
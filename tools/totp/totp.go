@@ -0,0 +1,156 @@
+// Package totp generates and validates Time-Based One-Time Passwords, which authenticator
+// apps (like Google Authenticator, 1Password, or Aegis) use for two-factor authentication.
+// https://datatracker.ietf.org/doc/html/rfc6238
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // nolint:gosec // SHA-1 is required by RFC 6238 and is what authenticator apps support
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/benpate/derp"
+)
+
+// Period is the number of seconds that each code is valid
+const Period = 30
+
+// Digits is the number of digits in each code
+const Digits = 6
+
+// Skew is the number of periods before/after the current time that are also accepted,
+// to allow for clock drift between the server and the authenticator app
+const Skew = 1
+
+// secretSize is the number of random bytes in each secret (160 bits, per RFC 4226)
+const secretSize = 20
+
+// encoding is the base32 alphabet (without padding) that authenticator apps expect
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a new, randomly generated secret (encoded in base32)
+func NewSecret() (string, error) {
+
+	secret, err := random.GenerateBytes(secretSize)
+
+	if err != nil {
+		return "", derp.Wrap(err, "totp.NewSecret", "Error generating secret")
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// Code returns the code for the provided secret at the provided time
+func Code(secret string, now time.Time) (string, error) {
+
+	key, err := decodeSecret(secret)
+
+	if err != nil {
+		return "", derp.Wrap(err, "totp.Code", "Invalid secret")
+	}
+
+	return hotp(key, Counter(now), Digits), nil
+}
+
+// Validate returns the counter of the time period that matches the code, and TRUE
+// if the code is valid for the secret at (or near) the provided time.
+func Validate(secret string, code string, now time.Time) (int64, bool) {
+
+	key, err := decodeSecret(secret)
+
+	if err != nil {
+		return 0, false
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Counter(now)
+
+	for counter := current - Skew; counter <= current+Skew; counter++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, counter, Digits)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// Counter returns the number of time periods since the Unix epoch
+func Counter(now time.Time) int64 {
+	return now.Unix() / Period
+}
+
+// URI returns the "otpauth://" URI that authenticator apps read from a QR code.
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func URI(issuer string, account string, secret string) string {
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(Digits))
+	query.Set("period", strconv.Itoa(Period))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// FormatSecret splits a secret into groups of four characters, so that it is easier to type
+// into an authenticator app.
+func FormatSecret(secret string) string {
+
+	var result strings.Builder
+
+	for index, char := range secret {
+		if (index > 0) && (index%4 == 0) {
+			result.WriteRune(' ')
+		}
+		result.WriteRune(char)
+	}
+
+	return result.String()
+}
+
+// hotp returns an HMAC-based One-Time Password for the provided key and counter.
+// https://datatracker.ietf.org/doc/html/rfc4226#section-5.3
+func hotp(key []byte, counter int64, digits int) string {
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	result := strconv.FormatUint(uint64(value%modulo), 10)
+
+	// Left-pad with zeros
+	return strings.Repeat("0", digits-len(result)) + result
+}
+
+// decodeSecret converts a base32 secret (case-insensitive, with optional spaces and padding) into bytes
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	return encoding.DecodeString(secret)
+}
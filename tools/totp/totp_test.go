@@ -0,0 +1,107 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the shared secret used in the RFC 4226 and RFC 6238 test vectors
+var rfcSecret = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+
+	// https://datatracker.ietf.org/doc/html/rfc4226#appendix-D
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range expected {
+		require.Equal(t, code, hotp(rfcSecret, int64(counter), 6))
+	}
+}
+
+func TestTOTP(t *testing.T) {
+
+	// https://datatracker.ietf.org/doc/html/rfc6238#appendix-B
+	expected := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for seconds, code := range expected {
+		require.Equal(t, code, hotp(rfcSecret, Counter(time.Unix(seconds, 0)), 8))
+	}
+}
+
+func TestCode(t *testing.T) {
+
+	secret := base32.StdEncoding.EncodeToString(rfcSecret)
+	code, err := Code(secret, time.Unix(59, 0))
+
+	require.Nil(t, err)
+	require.Equal(t, "287082", code)
+
+	// Secrets are case-insensitive and may include spaces
+	lower, err := Code(strings.ToLower(secret[:8])+" "+secret[8:], time.Unix(59, 0))
+	require.Nil(t, err)
+	require.Equal(t, code, lower)
+
+	_, err = Code("not a valid secret!", time.Now())
+	require.NotNil(t, err)
+}
+
+func TestValidate(t *testing.T) {
+
+	secret, err := NewSecret()
+	require.Nil(t, err)
+	require.Equal(t, 32, len(secret))
+
+	now := time.Unix(1700000000, 0)
+	code, err := Code(secret, now)
+	require.Nil(t, err)
+
+	// Current code is accepted
+	counter, ok := Validate(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, Counter(now), counter)
+
+	// Codes from the adjacent periods are accepted
+	counter, ok = Validate(secret, code, now.Add(Period*time.Second))
+	require.True(t, ok)
+	require.Equal(t, Counter(now), counter)
+
+	_, ok = Validate(secret, code, now.Add(-Period*time.Second))
+	require.True(t, ok)
+
+	// Older codes are rejected
+	_, ok = Validate(secret, code, now.Add(3*Period*time.Second))
+	require.False(t, ok)
+
+	// Malformed codes are rejected
+	_, ok = Validate(secret, "", now)
+	require.False(t, ok)
+
+	_, ok = Validate(secret, code+"0", now)
+	require.False(t, ok)
+
+	// Spaces are ignored
+	_, ok = Validate(secret, code[:3]+" "+code[3:], now)
+	require.True(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("My Site", "alice", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/My%20Site:alice?algorithm=SHA1&digits=6&issuer=My+Site&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
+
+func TestFormatSecret(t *testing.T) {
+	require.Equal(t, "JBSW Y3DP EHPK 3PXP", FormatSecret("JBSWY3DPEHPK3PXP"))
+	require.Equal(t, "JBSW Y3", FormatSecret("JBSWY3"))
+	require.Equal(t, "", FormatSecret(""))
+}
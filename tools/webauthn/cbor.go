@@ -0,0 +1,246 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/benpate/derp"
+)
+
+// cborMaxDepth limits the nesting of arrays and maps, so that malicious values cannot exhaust the stack
+const cborMaxDepth = 16
+
+// decodeCBOR decodes the first CBOR item in data, and returns it along with any remaining bytes.
+// This is a minimal decoder that supports the definite-length values used by WebAuthn authenticators.
+// Integers decode as int64, byte strings as []byte, text as string, arrays as []any, and maps as map[any]any.
+// https://www.rfc-editor.org/rfc/rfc8949.html
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+
+	const location = "webauthn.decodeCBOR"
+
+	if depth > cborMaxDepth {
+		return nil, nil, derp.NewBadRequestError(location, "CBOR value is nested too deeply")
+	}
+
+	if len(data) == 0 {
+		return nil, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+	}
+
+	majorType := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// Floating point numbers and simple values
+	if majorType == 7 {
+		return decodeCBORSimple(info, data)
+	}
+
+	argument, data, err := decodeCBORArgument(info, data)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch majorType {
+
+	// Unsigned integer
+	case 0:
+		if argument > math.MaxInt64 {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR integer is out of range")
+		}
+		return int64(argument), data, nil
+
+	// Negative integer
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR integer is out of range")
+		}
+		return -1 - int64(argument), data, nil
+
+	// Byte string
+	case 2:
+		if argument > uint64(len(data)) {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR byte string is too long")
+		}
+		return data[:argument], data[argument:], nil
+
+	// Text string
+	case 3:
+		if argument > uint64(len(data)) {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR text string is too long")
+		}
+		return string(data[:argument]), data[argument:], nil
+
+	// Array
+	case 4:
+		if argument > uint64(len(data)) {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR array is too long")
+		}
+
+		result := make([]any, 0, argument)
+
+		for i := uint64(0); i < argument; i++ {
+
+			var item any
+
+			item, data, err = decodeCBORItem(data, depth+1)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			result = append(result, item)
+		}
+
+		return result, data, nil
+
+	// Map
+	case 5:
+		if argument > uint64(len(data)) {
+			return nil, nil, derp.NewBadRequestError(location, "CBOR map is too long")
+		}
+
+		result := make(map[any]any, argument)
+
+		for i := uint64(0); i < argument; i++ {
+
+			var key, value any
+
+			key, data, err = decodeCBORItem(data, depth+1)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, derp.NewBadRequestError(location, "CBOR map keys must be integers or strings")
+			}
+
+			value, data, err = decodeCBORItem(data, depth+1)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			result[key] = value
+		}
+
+		return result, data, nil
+
+	// Tagged value (the tag is ignored)
+	case 6:
+		return decodeCBORItem(data, depth+1)
+	}
+
+	return nil, nil, derp.NewBadRequestError(location, "Unsupported CBOR type", majorType)
+}
+
+// decodeCBORArgument reads the length/value argument that follows each CBOR initial byte
+func decodeCBORArgument(info byte, data []byte) (uint64, []byte, error) {
+
+	const location = "webauthn.decodeCBORArgument"
+
+	switch {
+
+	case info < 24:
+		return uint64(info), data, nil
+
+	case info == 24:
+		if len(data) < 1 {
+			return 0, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return uint64(data[0]), data[1:], nil
+
+	case info == 25:
+		if len(data) < 2 {
+			return 0, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+
+	case info == 26:
+		if len(data) < 4 {
+			return 0, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+
+	case info == 27:
+		if len(data) < 8 {
+			return 0, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+
+	return 0, nil, derp.NewBadRequestError(location, "Indefinite-length CBOR values are not supported")
+}
+
+// decodeCBORSimple decodes the booleans, null, and floating point numbers in CBOR major type 7
+func decodeCBORSimple(info byte, data []byte) (any, []byte, error) {
+
+	const location = "webauthn.decodeCBORSimple"
+
+	switch info {
+
+	case 20:
+		return false, data, nil
+
+	case 21:
+		return true, data, nil
+
+	case 22, 23:
+		return nil, data, nil
+
+	case 25:
+		if len(data) < 2 {
+			return nil, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return halfToFloat(binary.BigEndian.Uint16(data)), data[2:], nil
+
+	case 26:
+		if len(data) < 4 {
+			return nil, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+
+	case 27:
+		if len(data) < 8 {
+			return nil, nil, derp.NewBadRequestError(location, "Unexpected end of CBOR data")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	}
+
+	return nil, nil, derp.NewBadRequestError(location, "Unsupported CBOR simple value", info)
+}
+
+// halfToFloat converts an IEEE 754 half-precision number into a float64
+func halfToFloat(value uint16) float64 {
+
+	exponent := int(value>>10) & 0x1f
+	mantissa := float64(value & 0x3ff)
+
+	var result float64
+
+	switch exponent {
+	case 0:
+		result = math.Ldexp(mantissa, -24)
+	case 31:
+		if mantissa == 0 {
+			result = math.Inf(1)
+		} else {
+			result = math.NaN()
+		}
+	default:
+		result = math.Ldexp(mantissa+1024, exponent-25)
+	}
+
+	if value&0x8000 != 0 {
+		return -result
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+package webauthn
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+
+	// Examples from https://www.rfc-editor.org/rfc/rfc8949.html#appendix-A
+	tests := map[string]any{
+		"00":                 int64(0),
+		"17":                 int64(23),
+		"1818":               int64(24),
+		"1903e8":             int64(1000),
+		"1a000f4240":         int64(1000000),
+		"20":                 int64(-1),
+		"3903e7":             int64(-1000),
+		"f4":                 false,
+		"f5":                 true,
+		"f6":                 nil,
+		"f93c00":             float64(1),
+		"fb3ff199999999999a": 1.1,
+		"40":                 []byte{},
+		"4401020304":         []byte{1, 2, 3, 4},
+		"6449455446":         "IETF",
+		"83010203":           []any{int64(1), int64(2), int64(3)},
+		"a201020304":         map[any]any{int64(1): int64(2), int64(3): int64(4)},
+		"a26161016162820203": map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}},
+		"c11a514b67b0":       int64(1363896240),
+	}
+
+	for input, expected := range tests {
+		data, err := hex.DecodeString(input)
+		require.Nil(t, err)
+
+		value, rest, err := decodeCBOR(data)
+		require.Nil(t, err, input)
+		require.Empty(t, rest, input)
+		require.Equal(t, expected, value, input)
+	}
+}
+
+func TestDecodeCBOR_Rest(t *testing.T) {
+
+	value, rest, err := decodeCBOR([]byte{0x01, 0x02, 0x03})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), value)
+	require.Equal(t, []byte{0x02, 0x03}, rest)
+}
+
+func TestDecodeCBOR_Errors(t *testing.T) {
+
+	tests := []string{
+		"",                   // empty
+		"18",                 // missing argument
+		"4401",               // byte string too short
+		"82",                 // array too short
+		"a1",                 // map too short
+		"a14101f5",           // byte string map key
+		"5f",                 // indefinite length
+		"1bffffffffffffffff", // integer out of range
+	}
+
+	for _, input := range tests {
+		data, err := hex.DecodeString(input)
+		require.Nil(t, err)
+
+		_, _, err = decodeCBOR(data)
+		require.NotNil(t, err, input)
+	}
+
+	// Deeply nested arrays
+	nested := make([]byte, cborMaxDepth+2)
+	for index := range nested {
+		nested[index] = 0x81
+	}
+	_, _, err := decodeCBOR(nested)
+	require.NotNil(t, err)
+}
+
+func TestHalfToFloat(t *testing.T) {
+	require.Equal(t, 0.0, halfToFloat(0x0000))
+	require.Equal(t, 1.0, halfToFloat(0x3c00))
+	require.Equal(t, 65504.0, halfToFloat(0x7bff))
+	require.Equal(t, -4.0, halfToFloat(0xc400))
+	require.Equal(t, 5.960464477539063e-8, halfToFloat(0x0001))
+	require.True(t, math.IsInf(halfToFloat(0x7c00), 1))
+	require.True(t, math.IsNaN(halfToFloat(0x7e00)))
+}
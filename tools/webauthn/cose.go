@@ -0,0 +1,156 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/benpate/derp"
+)
+
+// COSE algorithm identifiers supported by this package.
+// https://www.iana.org/assignments/cose/cose.xhtml#algorithms
+const (
+	AlgorithmES256 = -7
+	AlgorithmEdDSA = -8
+	AlgorithmRS256 = -257
+)
+
+// COSE key parameters and values
+// https://www.rfc-editor.org/rfc/rfc9053.html#section-7
+const (
+	coseKeyType      = 1
+	coseKeyAlgorithm = 3
+	coseKeyCurve     = -1
+	coseKeyX         = -2
+	coseKeyY         = -3
+	coseKeyModulus   = -1
+	coseKeyExponent  = -2
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// Algorithms lists the COSE algorithms that this package can verify, in order of preference
+var Algorithms = []int{AlgorithmES256, AlgorithmEdDSA, AlgorithmRS256}
+
+// publicKey is a parsed COSE public key
+type publicKey struct {
+	algorithm int
+	key       crypto.PublicKey
+}
+
+// parsePublicKey parses a CBOR-encoded COSE_Key into a public key that can verify signatures
+func parsePublicKey(data []byte) (publicKey, error) {
+
+	const location = "webauthn.parsePublicKey"
+
+	value, rest, err := decodeCBOR(data)
+
+	if err != nil {
+		return publicKey{}, derp.Wrap(err, location, "Invalid public key")
+	}
+
+	if len(rest) > 0 {
+		return publicKey{}, derp.NewBadRequestError(location, "Unexpected data after public key")
+	}
+
+	coseKey, ok := value.(map[any]any)
+
+	if !ok {
+		return publicKey{}, derp.NewBadRequestError(location, "Public key must be a CBOR map")
+	}
+
+	keyType, _ := coseKey[int64(coseKeyType)].(int64)
+	algorithm, _ := coseKey[int64(coseKeyAlgorithm)].(int64)
+
+	switch {
+
+	case (keyType == coseKeyTypeEC2) && (algorithm == AlgorithmES256):
+
+		curve, _ := coseKey[int64(coseKeyCurve)].(int64)
+		x, _ := coseKey[int64(coseKeyX)].([]byte)
+		y, _ := coseKey[int64(coseKeyY)].([]byte)
+
+		if (curve != coseCurveP256) || (len(x) != 32) || (len(y) != 32) {
+			return publicKey{}, derp.NewBadRequestError(location, "Invalid P-256 public key")
+		}
+
+		key := ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return publicKey{}, derp.NewBadRequestError(location, "Public key is not on the P-256 curve")
+		}
+
+		return publicKey{algorithm: AlgorithmES256, key: &key}, nil
+
+	case (keyType == coseKeyTypeOKP) && (algorithm == AlgorithmEdDSA):
+
+		curve, _ := coseKey[int64(coseKeyCurve)].(int64)
+		x, _ := coseKey[int64(coseKeyX)].([]byte)
+
+		if (curve != coseCurveEd25519) || (len(x) != ed25519.PublicKeySize) {
+			return publicKey{}, derp.NewBadRequestError(location, "Invalid Ed25519 public key")
+		}
+
+		return publicKey{algorithm: AlgorithmEdDSA, key: ed25519.PublicKey(x)}, nil
+
+	case (keyType == coseKeyTypeRSA) && (algorithm == AlgorithmRS256):
+
+		modulus, _ := coseKey[int64(coseKeyModulus)].([]byte)
+		exponent, _ := coseKey[int64(coseKeyExponent)].([]byte)
+
+		if (len(modulus) < 256) || (len(exponent) == 0) || (len(exponent) > 4) {
+			return publicKey{}, derp.NewBadRequestError(location, "Invalid RSA public key")
+		}
+
+		key := rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+
+		return publicKey{algorithm: AlgorithmRS256, key: &key}, nil
+	}
+
+	return publicKey{}, derp.NewBadRequestError(location, "Unsupported public key algorithm", keyType, algorithm)
+}
+
+// verify returns an error unless the signature is valid for the provided data
+func (key publicKey) verify(data []byte, signature []byte) error {
+
+	const location = "webauthn.publicKey.verify"
+
+	switch key.algorithm {
+
+	case AlgorithmES256:
+		digest := sha256.Sum256(data)
+		if ecdsa.VerifyASN1(key.key.(*ecdsa.PublicKey), digest[:], signature) {
+			return nil
+		}
+
+	case AlgorithmEdDSA:
+		if ed25519.Verify(key.key.(ed25519.PublicKey), data, signature) {
+			return nil
+		}
+
+	case AlgorithmRS256:
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(key.key.(*rsa.PublicKey), crypto.SHA256, digest[:], signature); err == nil {
+			return nil
+		}
+	}
+
+	return derp.NewForbiddenError(location, "Invalid signature")
+}
@@ -0,0 +1,494 @@
+// Package webauthn implements the server side of WebAuthn (passkey) registration and sign-in.
+// It verifies the responses created by the browser's navigator.credentials API, and uses
+// "none" attestation, so it trusts any authenticator that the User chooses.
+// https://www.w3.org/TR/webauthn-2/
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/benpate/derp"
+)
+
+// Timeout is the amount of time that browsers wait for the User to complete a WebAuthn ceremony
+const Timeout = 5 * time.Minute
+
+// UserVerification values tell authenticators whether to check the User's PIN or biometrics
+const (
+	UserVerificationRequired    = "required"
+	UserVerificationPreferred   = "preferred"
+	UserVerificationDiscouraged = "discouraged"
+)
+
+// maxCredentialIDLength is the largest credential ID allowed by the specification
+const maxCredentialIDLength = 1023
+
+// Authenticator data flags
+// https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagBackupEligible         = 0x08
+	flagBackupState            = 0x10
+	flagAttestedCredentialData = 0x40
+)
+
+// RelyingParty identifies the website that Users register their passkeys with.
+// Passkeys only work on the domain (ID) that they were registered for.
+type RelyingParty struct {
+	ID     string // Domain name (without protocol or port) like "example.com"
+	Name   string // Human-friendly name displayed by the authenticator
+	Origin string // Full origin (with protocol and port) that the browser reports, like "https://example.com"
+}
+
+// User identifies the account that a new passkey belongs to
+type User struct {
+	ID          []byte // Opaque user handle (never an email address or username)
+	Name        string // Username displayed by the authenticator
+	DisplayName string // Full name displayed by the authenticator
+}
+
+// Credential is a passkey that has been verified by VerifyRegistration
+type Credential struct {
+	ID             string   // base64url-encoded credential ID
+	PublicKey      []byte   // CBOR-encoded COSE public key
+	SignCount      uint32   // Signature counter reported by the authenticator
+	Transports     []string // Transports (like "internal" or "usb") reported by the browser
+	BackupEligible bool     // TRUE if the passkey can be synced to other devices
+	BackupState    bool     // TRUE if the passkey is currently synced to other devices
+}
+
+// Assertion contains the results of a verified sign-in
+type Assertion struct {
+	SignCount    uint32 // New signature counter reported by the authenticator
+	BackupState  bool   // TRUE if the passkey is currently synced to other devices
+	UserVerified bool   // TRUE if the authenticator verified the User's PIN or biometrics
+}
+
+// CredentialDescriptor identifies an existing passkey in the options sent to the browser
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// CredentialParameter identifies an algorithm that the server accepts
+type CredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int    `json:"alg"`
+}
+
+// CreationOptions are sent to navigator.credentials.create() in the browser.
+// Binary values are base64url-encoded, and must be decoded by the browser.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RelyingParty           CreationRelyingParty   `json:"rp"`
+	User                   CreationUser           `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// CreationRelyingParty describes the website in CreationOptions
+type CreationRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreationUser describes the User in CreationOptions
+type CreationUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// AuthenticatorSelection describes the kind of authenticator that the server wants
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// RequestOptions are sent to navigator.credentials.get() in the browser.
+// Binary values are base64url-encoded, and must be decoded by the browser.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RelyingPartyID   string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RegistrationResponse is the (base64url-encoded) result of navigator.credentials.create()
+type RegistrationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the (base64url-encoded) result of navigator.credentials.get()
+type AssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// CredentialID returns the base64url-encoded (unpadded) ID of the passkey that the User chose
+func (response AssertionResponse) CredentialID() string {
+	return strings.TrimRight(response.RawID, "=")
+}
+
+// clientData is the JSON object that the browser signs for each ceremony
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// authenticatorData is the parsed binary data returned by the authenticator
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// NewChallenge returns a new, random, base64url-encoded challenge
+func NewChallenge() (string, error) {
+
+	challenge, err := random.GenerateBytes(32)
+
+	if err != nil {
+		return "", derp.Wrap(err, "webauthn.NewChallenge", "Error generating challenge")
+	}
+
+	return EncodeBase64(challenge), nil
+}
+
+// EncodeBase64 encodes binary data using base64url (without padding), which is how WebAuthn values are exchanged
+func EncodeBase64(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// DecodeBase64 decodes a base64url value, with or without padding
+func DecodeBase64(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// CreationOptions returns the options that the browser uses to register a new passkey.
+// Existing passkeys are excluded so that each authenticator is only registered once.
+func (rp RelyingParty) CreationOptions(challenge string, user User, exclude ...CredentialDescriptor) CreationOptions {
+
+	params := make([]CredentialParameter, len(Algorithms))
+
+	for index, algorithm := range Algorithms {
+		params[index] = CredentialParameter{Type: "public-key", Algorithm: algorithm}
+	}
+
+	if exclude == nil {
+		exclude = make([]CredentialDescriptor, 0)
+	}
+
+	return CreationOptions{
+		Challenge:          challenge,
+		RelyingParty:       CreationRelyingParty{ID: rp.ID, Name: rp.Name},
+		User:               CreationUser{ID: EncodeBase64(user.ID), Name: user.Name, DisplayName: user.DisplayName},
+		PubKeyCredParams:   params,
+		Timeout:            Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   UserVerificationPreferred,
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions returns the options that the browser uses to sign in with a passkey.
+// If no credentials are allowed, then the browser lets the User choose any passkey for this website.
+func (rp RelyingParty) RequestOptions(challenge string, userVerification string, allow ...CredentialDescriptor) RequestOptions {
+
+	if allow == nil {
+		allow = make([]CredentialDescriptor, 0)
+	}
+
+	return RequestOptions{
+		Challenge:        challenge,
+		Timeout:          Timeout.Milliseconds(),
+		RelyingPartyID:   rp.ID,
+		AllowCredentials: allow,
+		UserVerification: userVerification,
+	}
+}
+
+// VerifyRegistration verifies the browser's response to CreationOptions and returns the new Credential.
+// https://www.w3.org/TR/webauthn-2/#sctn-registering-a-new-credential
+func (rp RelyingParty) VerifyRegistration(challenge string, response RegistrationResponse, requireUserVerification bool) (Credential, error) {
+
+	const location = "webauthn.RelyingParty.VerifyRegistration"
+
+	if response.Type != "public-key" {
+		return Credential{}, derp.NewBadRequestError(location, "Invalid credential type", response.Type)
+	}
+
+	if _, err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid client data")
+	}
+
+	// Parse the attestation object.  Because we request "none" attestation,
+	// the attestation statement is not verified.
+	attestationObject, err := DecodeBase64(response.Response.AttestationObject)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid attestation object encoding", derp.WithCode(http.StatusBadRequest))
+	}
+
+	value, _, err := decodeCBOR(attestationObject)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid attestation object")
+	}
+
+	attestation, ok := value.(map[any]any)
+
+	if !ok {
+		return Credential{}, derp.NewBadRequestError(location, "Attestation object must be a CBOR map")
+	}
+
+	rawAuthData, ok := attestation["authData"].([]byte)
+
+	if !ok {
+		return Credential{}, derp.NewBadRequestError(location, "Attestation object is missing authenticator data")
+	}
+
+	authData, err := rp.verifyAuthenticatorData(rawAuthData, requireUserVerification)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid authenticator data")
+	}
+
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return Credential{}, derp.NewBadRequestError(location, "Authenticator data does not include a credential")
+	}
+
+	// The credential ID reported by the browser must match the authenticator data
+	rawID, err := DecodeBase64(response.RawID)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid credential ID encoding", derp.WithCode(http.StatusBadRequest))
+	}
+
+	if !bytes.Equal(rawID, authData.credentialID) {
+		return Credential{}, derp.NewBadRequestError(location, "Credential ID does not match authenticator data")
+	}
+
+	// Verify that the public key uses an algorithm that we support
+	if _, err := parsePublicKey(authData.publicKey); err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid public key")
+	}
+
+	return Credential{
+		ID:             EncodeBase64(authData.credentialID),
+		PublicKey:      authData.publicKey,
+		SignCount:      authData.signCount,
+		Transports:     response.Response.Transports,
+		BackupEligible: authData.flags&flagBackupEligible != 0,
+		BackupState:    authData.flags&flagBackupState != 0,
+	}, nil
+}
+
+// VerifyAssertion verifies the browser's response to RequestOptions using the stored public key and
+// signature counter of the passkey that the User chose.
+// https://www.w3.org/TR/webauthn-2/#sctn-verifying-assertion
+func (rp RelyingParty) VerifyAssertion(challenge string, response AssertionResponse, publicKeyData []byte, signCount uint32, requireUserVerification bool) (Assertion, error) {
+
+	const location = "webauthn.RelyingParty.VerifyAssertion"
+
+	if response.Type != "public-key" {
+		return Assertion{}, derp.NewBadRequestError(location, "Invalid credential type", response.Type)
+	}
+
+	clientDataJSON, err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", challenge)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid client data")
+	}
+
+	rawAuthData, err := DecodeBase64(response.Response.AuthenticatorData)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid authenticator data encoding", derp.WithCode(http.StatusBadRequest))
+	}
+
+	authData, err := rp.verifyAuthenticatorData(rawAuthData, requireUserVerification)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid authenticator data")
+	}
+
+	signature, err := DecodeBase64(response.Response.Signature)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid signature encoding", derp.WithCode(http.StatusBadRequest))
+	}
+
+	publicKey, err := parsePublicKey(publicKeyData)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid public key")
+	}
+
+	// The authenticator signs its data followed by the hash of the client data
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append(make([]byte, 0, len(rawAuthData)+len(clientDataHash)), rawAuthData...), clientDataHash[:]...)
+
+	if err := publicKey.verify(signedData, signature); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Invalid signature")
+	}
+
+	// RULE: Signature counters must increase, unless the authenticator does not use them (always zero).
+	// Otherwise, the passkey may have been cloned.
+	if (authData.signCount != 0 || signCount != 0) && (authData.signCount <= signCount) {
+		return Assertion{}, derp.NewForbiddenError(location, "Signature counter did not increase.  This passkey may have been cloned.", signCount, authData.signCount)
+	}
+
+	return Assertion{
+		SignCount:    authData.signCount,
+		BackupState:  authData.flags&flagBackupState != 0,
+		UserVerified: authData.flags&flagUserVerified != 0,
+	}, nil
+}
+
+// verifyClientData decodes the client data JSON, validates its type, challenge, and origin,
+// and returns the raw bytes (which are included in assertion signatures)
+func (rp RelyingParty) verifyClientData(encoded string, expectedType string, challenge string) ([]byte, error) {
+
+	const location = "webauthn.RelyingParty.verifyClientData"
+
+	rawClientData, err := DecodeBase64(encoded)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid client data encoding", derp.WithCode(http.StatusBadRequest))
+	}
+
+	data := clientData{}
+
+	if err := json.Unmarshal(rawClientData, &data); err != nil {
+		return nil, derp.Wrap(err, location, "Invalid client data JSON", derp.WithCode(http.StatusBadRequest))
+	}
+
+	if data.Type != expectedType {
+		return nil, derp.NewBadRequestError(location, "Invalid ceremony type", data.Type)
+	}
+
+	if (challenge == "") || (subtle.ConstantTimeCompare([]byte(strings.TrimRight(data.Challenge, "=")), []byte(challenge)) != 1) {
+		return nil, derp.NewForbiddenError(location, "Invalid challenge")
+	}
+
+	if data.Origin != rp.Origin {
+		return nil, derp.NewForbiddenError(location, "Invalid origin", data.Origin, rp.Origin)
+	}
+
+	return rawClientData, nil
+}
+
+// verifyAuthenticatorData parses the authenticator data, and validates the relying party and flags
+func (rp RelyingParty) verifyAuthenticatorData(data []byte, requireUserVerification bool) (authenticatorData, error) {
+
+	const location = "webauthn.RelyingParty.verifyAuthenticatorData"
+
+	result, err := parseAuthenticatorData(data)
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Invalid authenticator data")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+
+	if subtle.ConstantTimeCompare(result.rpIDHash, rpIDHash[:]) != 1 {
+		return result, derp.NewForbiddenError(location, "Passkey was not created for this website", rp.ID)
+	}
+
+	if result.flags&flagUserPresent == 0 {
+		return result, derp.NewForbiddenError(location, "User was not present")
+	}
+
+	if requireUserVerification && (result.flags&flagUserVerified == 0) {
+		return result, derp.NewForbiddenError(location, "User was not verified")
+	}
+
+	return result, nil
+}
+
+// parseAuthenticatorData parses the binary authenticator data structure
+// https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+func parseAuthenticatorData(data []byte) (authenticatorData, error) {
+
+	const location = "webauthn.parseAuthenticatorData"
+
+	if len(data) < 37 {
+		return authenticatorData{}, derp.NewBadRequestError(location, "Authenticator data is too short")
+	}
+
+	result := authenticatorData{
+		rpIDHash:  data[0:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if result.flags&flagAttestedCredentialData == 0 {
+		return result, nil
+	}
+
+	// Attested credential data: AAGUID (16 bytes), credential ID length (2 bytes), credential ID, public key
+	rest := data[37:]
+
+	if len(rest) < 18 {
+		return result, derp.NewBadRequestError(location, "Attested credential data is too short")
+	}
+
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+
+	if (idLength == 0) || (idLength > maxCredentialIDLength) || (idLength > len(rest)) {
+		return result, derp.NewBadRequestError(location, "Invalid credential ID length", idLength)
+	}
+
+	result.credentialID = rest[:idLength]
+	rest = rest[idLength:]
+
+	// The public key is the next CBOR value.  Any remaining bytes are extensions.
+	_, extensions, err := decodeCBOR(rest)
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Invalid public key")
+	}
+
+	result.publicKey = rest[:len(rest)-len(extensions)]
+
+	return result, nil
+}
@@ -0,0 +1,327 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRelyingParty = RelyingParty{
+	ID:     "example.com",
+	Name:   "Example",
+	Origin: "https://example.com",
+}
+
+// testAuthenticator simulates a browser and authenticator that creates and uses a single passkey
+type testAuthenticator struct {
+	credentialID []byte
+	signer       crypto.Signer
+	coseKey      []byte
+	signCount    uint32
+	flags        byte
+}
+
+func newTestAuthenticator(t *testing.T, algorithm int) *testAuthenticator {
+
+	result := testAuthenticator{
+		credentialID: []byte("test-credential-id"),
+		flags:        flagUserPresent | flagUserVerified,
+	}
+
+	switch algorithm {
+
+	case AlgorithmES256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.Nil(t, err)
+		result.signer = key
+		result.coseKey = encodeCBOR(map[any]any{
+			coseKeyType:      coseKeyTypeEC2,
+			coseKeyAlgorithm: AlgorithmES256,
+			coseKeyCurve:     coseCurveP256,
+			coseKeyX:         key.X.FillBytes(make([]byte, 32)),
+			coseKeyY:         key.Y.FillBytes(make([]byte, 32)),
+		})
+
+	case AlgorithmEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.Nil(t, err)
+		result.signer = private
+		result.coseKey = encodeCBOR(map[any]any{
+			coseKeyType:      coseKeyTypeOKP,
+			coseKeyAlgorithm: AlgorithmEdDSA,
+			coseKeyCurve:     coseCurveEd25519,
+			coseKeyX:         []byte(public),
+		})
+	}
+
+	return &result
+}
+
+func (authenticator *testAuthenticator) authData(rpID string, attested bool) []byte {
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	result := append([]byte{}, rpIDHash[:]...)
+
+	flags := authenticator.flags
+	if attested {
+		flags |= flagAttestedCredentialData
+	}
+
+	result = append(result, flags)
+	result = binary.BigEndian.AppendUint32(result, authenticator.signCount)
+
+	if attested {
+		result = append(result, make([]byte, 16)...) // AAGUID
+		result = binary.BigEndian.AppendUint16(result, uint16(len(authenticator.credentialID)))
+		result = append(result, authenticator.credentialID...)
+		result = append(result, authenticator.coseKey...)
+	}
+
+	return result
+}
+
+func (authenticator *testAuthenticator) create(t *testing.T, challenge string, origin string) RegistrationResponse {
+
+	clientDataJSON, err := json.Marshal(clientData{Type: "webauthn.create", Challenge: challenge, Origin: origin})
+	require.Nil(t, err)
+
+	attestationObject := encodeCBOR(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authenticator.authData(testRelyingParty.ID, true),
+	})
+
+	result := RegistrationResponse{
+		ID:    EncodeBase64(authenticator.credentialID),
+		RawID: EncodeBase64(authenticator.credentialID),
+		Type:  "public-key",
+	}
+	result.Response.ClientDataJSON = EncodeBase64(clientDataJSON)
+	result.Response.AttestationObject = EncodeBase64(attestationObject)
+	result.Response.Transports = []string{"internal"}
+
+	return result
+}
+
+func (authenticator *testAuthenticator) get(t *testing.T, challenge string, rpID string) AssertionResponse {
+
+	authenticator.signCount++
+
+	clientDataJSON, err := json.Marshal(clientData{Type: "webauthn.get", Challenge: challenge, Origin: testRelyingParty.Origin})
+	require.Nil(t, err)
+
+	authData := authenticator.authData(rpID, false)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	var signature []byte
+
+	switch signer := authenticator.signer.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(signedData)
+		signature, err = ecdsa.SignASN1(rand.Reader, signer, digest[:])
+		require.Nil(t, err)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(signer, signedData)
+	}
+
+	result := AssertionResponse{
+		ID:    EncodeBase64(authenticator.credentialID),
+		RawID: EncodeBase64(authenticator.credentialID),
+		Type:  "public-key",
+	}
+	result.Response.ClientDataJSON = EncodeBase64(clientDataJSON)
+	result.Response.AuthenticatorData = EncodeBase64(authData)
+	result.Response.Signature = EncodeBase64(signature)
+
+	return result
+}
+
+func TestPasskey_ES256(t *testing.T) {
+	testPasskeyRoundTrip(t, AlgorithmES256)
+}
+
+func TestPasskey_EdDSA(t *testing.T) {
+	testPasskeyRoundTrip(t, AlgorithmEdDSA)
+}
+
+func testPasskeyRoundTrip(t *testing.T, algorithm int) {
+
+	authenticator := newTestAuthenticator(t, algorithm)
+
+	// Register a new passkey
+	challenge, err := NewChallenge()
+	require.Nil(t, err)
+
+	credential, err := testRelyingParty.VerifyRegistration(challenge, authenticator.create(t, challenge, testRelyingParty.Origin), true)
+	require.Nil(t, err)
+	require.Equal(t, EncodeBase64(authenticator.credentialID), credential.ID)
+	require.Equal(t, authenticator.coseKey, credential.PublicKey)
+	require.Equal(t, []string{"internal"}, credential.Transports)
+
+	// Sign in with the passkey
+	challenge, err = NewChallenge()
+	require.Nil(t, err)
+
+	assertion, err := testRelyingParty.VerifyAssertion(challenge, authenticator.get(t, challenge, testRelyingParty.ID), credential.PublicKey, credential.SignCount, true)
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), assertion.SignCount)
+	require.True(t, assertion.UserVerified)
+}
+
+func TestRegistration_Errors(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t, AlgorithmES256)
+	challenge, err := NewChallenge()
+	require.Nil(t, err)
+
+	// Wrong challenge
+	_, err = testRelyingParty.VerifyRegistration("other-challenge", authenticator.create(t, challenge, testRelyingParty.Origin), false)
+	require.NotNil(t, err)
+
+	// Wrong origin
+	_, err = testRelyingParty.VerifyRegistration(challenge, authenticator.create(t, challenge, "https://evil.example"), false)
+	require.NotNil(t, err)
+
+	// Wrong relying party
+	otherParty := RelyingParty{ID: "other.com", Origin: testRelyingParty.Origin}
+	_, err = otherParty.VerifyRegistration(challenge, authenticator.create(t, challenge, testRelyingParty.Origin), false)
+	require.NotNil(t, err)
+
+	// User verification required, but not performed
+	authenticator.flags = flagUserPresent
+	_, err = testRelyingParty.VerifyRegistration(challenge, authenticator.create(t, challenge, testRelyingParty.Origin), true)
+	require.NotNil(t, err)
+
+	_, err = testRelyingParty.VerifyRegistration(challenge, authenticator.create(t, challenge, testRelyingParty.Origin), false)
+	require.Nil(t, err)
+
+	// Mismatched credential ID
+	response := authenticator.create(t, challenge, testRelyingParty.Origin)
+	response.RawID = EncodeBase64([]byte("some-other-id"))
+	_, err = testRelyingParty.VerifyRegistration(challenge, response, false)
+	require.NotNil(t, err)
+}
+
+func TestAssertion_Errors(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t, AlgorithmES256)
+	challenge, err := NewChallenge()
+	require.Nil(t, err)
+
+	credential, err := testRelyingParty.VerifyRegistration(challenge, authenticator.create(t, challenge, testRelyingParty.Origin), true)
+	require.Nil(t, err)
+
+	// Wrong challenge
+	_, err = testRelyingParty.VerifyAssertion("other-challenge", authenticator.get(t, challenge, testRelyingParty.ID), credential.PublicKey, 0, false)
+	require.NotNil(t, err)
+
+	// Wrong relying party
+	_, err = testRelyingParty.VerifyAssertion(challenge, authenticator.get(t, challenge, "other.com"), credential.PublicKey, 0, false)
+	require.NotNil(t, err)
+
+	// Tampered signature
+	response := authenticator.get(t, challenge, testRelyingParty.ID)
+	response.Response.Signature = EncodeBase64([]byte("not a signature"))
+	_, err = testRelyingParty.VerifyAssertion(challenge, response, credential.PublicKey, 0, false)
+	require.NotNil(t, err)
+
+	// Different public key
+	other := newTestAuthenticator(t, AlgorithmES256)
+	_, err = testRelyingParty.VerifyAssertion(challenge, authenticator.get(t, challenge, testRelyingParty.ID), other.coseKey, 0, false)
+	require.NotNil(t, err)
+
+	// Signature counter must increase
+	_, err = testRelyingParty.VerifyAssertion(challenge, authenticator.get(t, challenge, testRelyingParty.ID), credential.PublicKey, authenticator.signCount+1, false)
+	require.NotNil(t, err)
+
+	// Valid assertion
+	_, err = testRelyingParty.VerifyAssertion(challenge, authenticator.get(t, challenge, testRelyingParty.ID), credential.PublicKey, 0, false)
+	require.Nil(t, err)
+}
+
+func TestOptions(t *testing.T) {
+
+	creation := testRelyingParty.CreationOptions("abc", User{ID: []byte{1, 2, 3}, Name: "alice", DisplayName: "Alice"})
+	require.Equal(t, "example.com", creation.RelyingParty.ID)
+	require.Equal(t, "AQID", creation.User.ID)
+	require.Equal(t, "none", creation.Attestation)
+	require.NotNil(t, creation.ExcludeCredentials)
+	require.Equal(t, len(Algorithms), len(creation.PubKeyCredParams))
+
+	request := testRelyingParty.RequestOptions("abc", UserVerificationRequired)
+	require.Equal(t, "example.com", request.RelyingPartyID)
+	require.NotNil(t, request.AllowCredentials)
+}
+
+func TestDecodeBase64(t *testing.T) {
+
+	value, err := DecodeBase64("AQID")
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 2, 3}, value)
+
+	value, err = DecodeBase64("AQI=")
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 2}, value)
+}
+
+// encodeCBOR is a minimal CBOR encoder used to simulate authenticators in tests
+func encodeCBOR(value any) []byte {
+
+	header := func(majorType byte, argument uint64) []byte {
+		switch {
+		case argument < 24:
+			return []byte{majorType<<5 | byte(argument)}
+		case argument < 256:
+			return []byte{majorType<<5 | 24, byte(argument)}
+		case argument < 65536:
+			return binary.BigEndian.AppendUint16([]byte{majorType<<5 | 25}, uint16(argument))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{majorType<<5 | 26}, uint32(argument))
+		}
+	}
+
+	switch typed := value.(type) {
+
+	case int:
+		if typed < 0 {
+			return header(1, uint64(-1-typed))
+		}
+		return header(0, uint64(typed))
+
+	case []byte:
+		return append(header(2, uint64(len(typed))), typed...)
+
+	case string:
+		return append(header(3, uint64(len(typed))), typed...)
+
+	case map[any]any:
+		// Sort keys so that the output is deterministic
+		keys := make([][]byte, 0, len(typed))
+		values := make(map[string][]byte, len(typed))
+		for key, item := range typed {
+			encodedKey := encodeCBOR(key)
+			keys = append(keys, encodedKey)
+			values[string(encodedKey)] = encodeCBOR(item)
+		}
+		sort.Slice(keys, func(i, j int) bool { return string(keys[i]) < string(keys[j]) })
+
+		result := header(5, uint64(len(typed)))
+		for _, key := range keys {
+			result = append(result, key...)
+			result = append(result, values[string(key)]...)
+		}
+		return result
+	}
+
+	panic("unsupported CBOR value")
+}